@@ -0,0 +1,545 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"vm/vm"
+)
+
+// monitor — интерактивный отладчик: между командами пользователя процессор
+// выполняет программу по шагам
+type monitor struct {
+	p     *vm.Processor
+	entry uint16   // Точка входа для команды restart
+	core  bool     // Исследуется дамп памяти: программу нельзя перезапустить
+	saved *vm.Core // Состояние, запомненное командой snap
+	in    *bufio.Reader
+	out   io.Writer
+}
+
+// Количество последних инструкций, которые монитор может отменить командой back
+const monitorHistory = 1000
+
+// Справка по командам монитора
+const monitorHelp = `Commands:
+  s, step [n]           execute n instructions (default 1)
+  n, next               execute one instruction, stepping over CALL
+  sd, stepdiff          execute one instruction and show what it changed
+  back, rs [n]          undo the last n instructions (also after a crash)
+  finish                run until the current subroutine returns
+  c, continue           run until a breakpoint or the end of the program
+  b, break [addr [if cond]]
+                        set a breakpoint at addr, stopping only when cond
+                        is nonzero (or list breakpoints)
+  d, delete addr        remove the breakpoint at addr
+  watch [expr]          show expr after every stop (or list watches)
+  unwatch id            remove the watch with the given id
+  r, regs               show registers and flags
+  x addr [count]        examine count words of data memory
+  l, list [addr [n]]    disassemble n instructions (default: around IP)
+  w, deposit addr value store an integer or a float (1.5) at addr
+  p, print expr         evaluate an expression
+  find int|float value  search memory for a data word
+  find bytes hh hh ...  search memory for a byte sequence
+  find jumps-to addr    search for jumps and calls to addr
+  snap                  remember the current state
+  diff                  show registers, flags and memory changed since snap
+  restart               reset the processor to the entry point
+  h, help               show this help
+  q, quit               leave the debugger
+Addresses and values are expressions: 0x10, loop+8, [sum], a1.`
+
+// debug выполняет подкоманду "debug file": загружает программу и запускает монитор.
+// "debug -core file" открывает в мониторе дамп памяти аварийно остановленной программы.
+func debug(args []string, harvard bool) int {
+	if len(args) == 2 && args[0] == "-core" {
+		return debugCore(args[1])
+	}
+	if len(args) == 2 && args[0] == "-tui" {
+		return debugTUI(args[1], harvard)
+	}
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: %s debug file\n       %s debug -core file\n       %s debug -tui file\n", os.Args[0], os.Args[0], os.Args[0])
+		return 2
+	}
+	processor, entry, err := loadOffline(args[0], harvard)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer processor.Close()
+	processor.SetHistory(monitorHistory)
+	processor.Reset(entry)
+
+	m := &monitor{p: processor, entry: entry, in: bufio.NewReader(os.Stdin), out: os.Stdout}
+	fmt.Fprintln(m.out, "Type h for help.")
+	m.showNext()
+	m.run()
+	return 0
+}
+
+// debugCore загружает дамп памяти и запускает монитор для посмертного исследования
+func debugCore(filename string) int {
+	core, err := vm.LoadCoreFile(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	newProcessor := vm.New
+	if core.Code != nil {
+		newProcessor = vm.NewHarvard // Гарвардский режим определяется дампом
+	}
+	processor, err := newProcessor()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to create processor: %v\n", err)
+		return 1
+	}
+	defer processor.Close()
+	if err := processor.RestoreCore(core); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	m := &monitor{p: processor, core: true, in: bufio.NewReader(os.Stdin), out: os.Stdout}
+	fmt.Fprintf(m.out, "Core file %s: %d instructions executed, stopped at 0x%04X\n", filename, core.Instructions, core.PSW.IP)
+	fmt.Fprintln(m.out, "Type h for help.")
+	m.showNext()
+	m.run()
+	return 0
+}
+
+// run читает и выполняет команды до quit или конца ввода
+func (m *monitor) run() {
+	for {
+		fmt.Fprint(m.out, "(vm) ")
+		line, err := m.in.ReadString('\n')
+		fields := strings.Fields(line)
+		if len(fields) > 0 {
+			if quit := m.execute(fields[0], fields[1:]); quit {
+				return
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(m.out)
+			return
+		}
+	}
+}
+
+// execute выполняет команду монитора и сообщает, нужно ли завершить работу
+func (m *monitor) execute(command string, args []string) bool {
+	var err error
+	switch command {
+	case "s", "step":
+		err = m.step(args)
+	case "n", "next":
+		err = m.resume(func() error { return m.reportBreak(m.p.StepOver()) })
+	case "sd", "stepdiff":
+		err = m.stepDiff()
+	case "back", "rs":
+		err = m.stepBack(args)
+	case "finish":
+		err = m.resume(func() error { return m.reportBreak(m.p.StepOut()) })
+	case "c", "continue":
+		err = m.resume(m.continueRun)
+	case "b", "break":
+		err = m.setBreakpoint(args)
+	case "d", "delete":
+		err = m.deleteBreakpoint(args)
+	case "watch":
+		err = m.watch(args)
+	case "unwatch":
+		err = m.unwatch(args)
+	case "r", "regs":
+		m.showRegisters()
+	case "x":
+		err = m.examine(args)
+	case "l", "list":
+		err = m.list(args)
+	case "w", "deposit":
+		err = m.deposit(args)
+	case "p", "print":
+		err = m.print(args)
+	case "find":
+		err = m.find(args)
+	case "snap":
+		m.saved = m.p.Core()
+		fmt.Fprintf(m.out, "State saved at 0x%04X after %d instructions\n", m.p.PSW().IP, m.saved.Instructions)
+	case "diff":
+		err = m.diff()
+	case "restart":
+		if m.core {
+			err = fmt.Errorf("a core file cannot be restarted")
+			break
+		}
+		m.p.Reset(m.entry)
+		m.showNext()
+	case "h", "help":
+		fmt.Fprintln(m.out, monitorHelp)
+	case "q", "quit":
+		return true
+	default:
+		err = fmt.Errorf("unknown command %q (type h for help)", command)
+	}
+	if err != nil {
+		fmt.Fprintf(m.out, "Error: %v\n", err)
+	}
+	return false
+}
+
+// address вычисляет выражение адреса
+func (m *monitor) address(expr string) (int, error) {
+	value, err := m.p.Evaluate(expr)
+	if err != nil {
+		return 0, err
+	}
+	if value < 0 || value >= int64(m.p.Memory().Size()) {
+		return 0, fmt.Errorf("address 0x%X is out of range", value)
+	}
+	return int(value), nil
+}
+
+// parseCount разбирает необязательное количество (по умолчанию def)
+func parseCount(args []string, i, def int) (int, error) {
+	if len(args) <= i {
+		return def, nil
+	}
+	n, err := strconv.Atoi(args[i])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid count %q", args[i])
+	}
+	return n, nil
+}
+
+// step выполняет n инструкций, останавливаясь на точках останова
+func (m *monitor) step(args []string) error {
+	n, err := parseCount(args, 0, 1)
+	if err != nil {
+		return err
+	}
+	return m.resume(func() error {
+		for i := 0; i < n && !m.p.Stopped(); i++ {
+			if i > 0 && m.p.ShouldBreak(m.p.PSW().IP) {
+				break
+			}
+			if _, _, err := m.p.Step(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// stepDiff выполняет одну инструкцию и выводит изменения, которые она сделала
+func (m *monitor) stepDiff() error {
+	if m.p.Stopped() {
+		return fmt.Errorf("program is not running (status: %s); use restart", m.p.Status())
+	}
+	d, err := m.p.StepDiff()
+	if d != nil {
+		printDiff(m.out, d, m.p.DebugInfo())
+	}
+	if err != nil {
+		return err
+	}
+	m.showNext()
+	return nil
+}
+
+// diff выводит изменения с момента команды snap
+func (m *monitor) diff() error {
+	if m.saved == nil {
+		return fmt.Errorf("no saved state; use snap first")
+	}
+	d, err := vm.DiffCores(m.saved, m.p.Core())
+	if err != nil {
+		return err
+	}
+	printDiff(m.out, d, m.p.DebugInfo())
+	return nil
+}
+
+// stepBack отменяет n последних инструкций
+func (m *monitor) stepBack(args []string) error {
+	n, err := parseCount(args, 0, 1)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if _, err := m.p.StepBack(); err != nil {
+			if i == 0 {
+				return err
+			}
+			break // История исчерпана: показываем, докуда удалось вернуться
+		}
+	}
+	m.showNext()
+	return nil
+}
+
+// continueRun выполняет программу до точки останова или остановки
+func (m *monitor) continueRun() error {
+	return m.reportBreak(m.p.Run())
+}
+
+// reportBreak сообщает о точке останова, прервавшей выполнение; другие ошибки
+// возвращаются без изменений
+func (m *monitor) reportBreak(err error) error {
+	var hit *vm.BreakpointHit
+	if errors.As(err, &hit) {
+		fmt.Fprintf(m.out, "Breakpoint at %s", m.p.DescribeAddress(int(hit.Address)))
+		if hit.Condition != "" {
+			fmt.Fprintf(m.out, " (%s)", hit.Condition)
+		}
+		fmt.Fprintln(m.out)
+		return nil
+	}
+	return err
+}
+
+// resume выполняет run и показывает состояние после него
+func (m *monitor) resume(run func() error) error {
+	if m.p.Stopped() {
+		return fmt.Errorf("program is not running (status: %s); use restart", m.p.Status())
+	}
+	if err := run(); err != nil {
+		return err
+	}
+	m.showNext()
+	return nil
+}
+
+// find ищет в памяти слова, байты или переходы и выводит совпадения с соседними словами
+func (m *monitor) find(args []string) error {
+	if len(args) == 2 && args[0] == "jumps-to" {
+		target, err := m.address(args[1]) // Адрес перехода может быть меткой
+		if err != nil {
+			return err
+		}
+		args = []string{args[0], strconv.Itoa(target)}
+	}
+	matches, err := m.p.Memory().Search(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	m.p.Memory().PrintSearchResults(m.out, matches, 1)
+	return nil
+}
+
+// setBreakpoint устанавливает точку останова (break addr [if condition]) или выводит
+// список точек
+func (m *monitor) setBreakpoint(args []string) error {
+	if len(args) == 0 {
+		for _, address := range m.p.Breakpoints() {
+			fmt.Fprintf(m.out, "  0x%04X %s", address, m.p.DescribeAddress(int(address)))
+			if condition := m.p.BreakpointCondition(address); condition != "" {
+				fmt.Fprintf(m.out, " if %s", condition)
+			}
+			fmt.Fprintln(m.out)
+		}
+		return nil
+	}
+	condition := ""
+	for i, arg := range args {
+		if arg == "if" {
+			args, condition = args[:i], strings.Join(args[i+1:], " ")
+			if condition == "" {
+				return fmt.Errorf("break requires a condition after if")
+			}
+			break
+		}
+	}
+	address, err := m.address(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	if err := m.p.AddConditionalBreakpoint(uint16(address), condition); err != nil {
+		return err
+	}
+	fmt.Fprintf(m.out, "Breakpoint at 0x%04X %s\n", address, m.p.DescribeAddress(address))
+	return nil
+}
+
+// deleteBreakpoint удаляет точку останова
+func (m *monitor) deleteBreakpoint(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("delete requires an address")
+	}
+	address, err := m.address(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	if !m.p.RemoveBreakpoint(uint16(address)) {
+		return fmt.Errorf("no breakpoint at 0x%04X", address)
+	}
+	return nil
+}
+
+// watch добавляет выражение наблюдения или выводит список выражений
+func (m *monitor) watch(args []string) error {
+	if len(args) == 0 {
+		m.showWatches()
+		return nil
+	}
+	id, err := m.p.AddWatch(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(m.out, "Watch #%d: %s\n", id, strings.Join(args, " "))
+	return nil
+}
+
+// unwatch удаляет выражение наблюдения по номеру
+func (m *monitor) unwatch(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: unwatch id")
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil {
+		return fmt.Errorf("invalid watch id %q", args[0])
+	}
+	if !m.p.RemoveWatch(id) {
+		return fmt.Errorf("no watch #%d", id)
+	}
+	return nil
+}
+
+// showWatches выводит значения выражений наблюдения
+func (m *monitor) showWatches() {
+	for _, w := range m.p.Watches() {
+		if w.Err != nil {
+			fmt.Fprintf(m.out, "  #%d %s = <%v>\n", w.ID, w.Source, w.Err)
+		} else {
+			fmt.Fprintf(m.out, "  #%d %s = %d\n", w.ID, w.Source, w.Value)
+		}
+	}
+}
+
+// showRegisters выводит регистры, флаги и состояние процессора
+func (m *monitor) showRegisters() {
+	psw := m.p.PSW()
+	a1, _ := m.p.GetRegister(0)
+	a2, _ := m.p.GetRegister(1)
+	fmt.Fprintf(m.out, "IP=0x%04X SP=0x%04X a1=%d a2=%d\n", psw.IP, psw.SP, a1, a2)
+	for i := uint8(0); i < vm.NUM_FLOAT_REGISTERS; i++ {
+		f, _ := m.p.GetFloatRegister(i)
+		fmt.Fprintf(m.out, "f%d=%g ", i, f)
+	}
+	fmt.Fprintln(m.out)
+	flag := func(name string, set bool) string {
+		if set {
+			return strings.ToUpper(name)
+		}
+		return strings.ToLower(name)
+	}
+	fmt.Fprintf(m.out, "flags: %s %s %s %s %s %s  status: %s\n",
+		flag("ZF", psw.ZeroFlag), flag("SF", psw.SignFlag), flag("CF", psw.CarryFlag),
+		flag("OF", psw.OverflowFlag), flag("IF", psw.InterruptFlag), flag("UM", psw.UserMode), m.p.Status())
+}
+
+// examine выводит слова памяти данных
+func (m *monitor) examine(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("x requires an address")
+	}
+	address, err := m.address(args[0])
+	if err != nil {
+		return err
+	}
+	n, err := parseCount(args, 1, 1)
+	if err != nil {
+		return err
+	}
+	return m.dump(m.p.Memory(), address, n)
+}
+
+// list дизассемблирует команды памяти команд
+func (m *monitor) list(args []string) error {
+	address := int(m.p.PSW().IP)
+	if len(args) > 0 {
+		var err error
+		if address, err = m.address(args[0]); err != nil {
+			return err
+		}
+	}
+	n, err := parseCount(args, 1, 5)
+	if err != nil {
+		return err
+	}
+	return m.dump(m.p.CodeMemory(), address, n)
+}
+
+// dump выводит n слов памяти mem начиная с address
+func (m *monitor) dump(mem *vm.Memory, address, n int) error {
+	for i := 0; i < n && address < mem.Size(); i++ {
+		word, err := mem.PeekWord(address)
+		if err != nil {
+			return err
+		}
+		marker := "  "
+		if address == int(m.p.PSW().IP) && mem == m.p.CodeMemory() {
+			marker = "=>"
+		}
+		fmt.Fprintf(m.out, "%s 0x%04X  %-24s ; %s\n", marker, address, vm.DisassembleWord(address, word), m.p.DescribeAddress(address))
+		address += vm.WORD_SIZE
+	}
+	return nil
+}
+
+// deposit записывает целое или вещественное число в память данных
+func (m *monitor) deposit(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("deposit requires an address and a value")
+	}
+	address, err := m.address(args[0])
+	if err != nil {
+		return err
+	}
+	text := strings.Join(args[1:], " ")
+	var word vm.Word
+	if strings.Contains(text, ".") {
+		value, err := strconv.ParseFloat(text, 32)
+		if err != nil {
+			return fmt.Errorf("invalid float %q", text)
+		}
+		word = vm.FloatWord(float32(value))
+	} else {
+		value, err := m.p.Evaluate(text)
+		if err != nil {
+			return err
+		}
+		word = vm.IntWord(int32(value))
+	}
+	return m.p.Memory().WriteWord(address, word)
+}
+
+// print вычисляет выражение
+func (m *monitor) print(args []string) error {
+	value, err := m.p.Evaluate(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(m.out, "%d (0x%X)\n", value, value)
+	return nil
+}
+
+// showNext выводит выражения наблюдения и следующую команду или состояние
+// остановленной программы
+func (m *monitor) showNext() {
+	m.showWatches()
+	if m.p.Stopped() {
+		fmt.Fprintf(m.out, "Program stopped: %s (exit code %d)\n", m.p.Status(), m.p.ExitCode())
+		if err := m.p.Err(); err != nil {
+			fmt.Fprintf(m.out, "%v\n", err)
+			m.dump(m.p.CodeMemory(), int(m.p.PSW().IP), 1) // Команда, вызвавшая ошибку
+		}
+		return
+	}
+	m.dump(m.p.CodeMemory(), int(m.p.PSW().IP), 1)
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"vm/vm"
+)
+
+// Расширения файлов теста рядом с программой name.ext
+const (
+	goldenInput  = ".in"     // Ввод программы, по одному значению в строке
+	goldenOutput = ".out"    // Ожидаемый вывод программы
+	goldenExpect = ".expect" // Проверки состояния после остановки
+)
+
+// goldenTest — программа каталога тестов и файлы ее ожиданий
+type goldenTest struct {
+	name    string // Имя программы без расширения
+	program string // Путь к программе
+	base    string // Путь без расширения для файлов .in, .out и .expect
+}
+
+// expectations — содержимое файла .expect
+type expectations struct {
+	status   vm.Status
+	exitCode *int32
+	asserts  []string // Выражения, которые должны быть истинны (не 0)
+}
+
+// testCommand выполняет подкоманду "test [флаги] каталог": запускает каждую программу
+// каталога, для которой есть файл .out или .expect, и сравнивает результат с ожиданиями
+func testCommand(args []string, harvard bool) int {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	update := fs.Bool("update", false, "write the actual output of each program to its .out file instead of comparing")
+	maxSteps := fs.Int("max-steps", 1000000, "fail a program that executes more instructions than this")
+	verbose := fs.Bool("v", false, "print the expected and actual output of failed programs")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: %s test [-update] [-max-steps n] [-v] dir\n", os.Args[0])
+		return 2
+	}
+	tests, err := findGoldenTests(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if len(tests) == 0 {
+		fmt.Fprintf(os.Stderr, "No programs with %s or %s files in %s\n", goldenOutput, goldenExpect, fs.Arg(0))
+		return 1
+	}
+
+	failed := 0
+	for _, t := range tests {
+		if err := t.run(harvard, *maxSteps, *update, *verbose); err != nil {
+			failed++
+			fmt.Printf("FAIL  %s: %v\n", t.name, err)
+			continue
+		}
+		fmt.Printf("ok    %s\n", t.name)
+	}
+	fmt.Printf("%d passed, %d failed\n", len(tests)-failed, failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// findGoldenTests находит программы каталога, у которых есть файл .out или .expect
+func findGoldenTests(dir string) ([]goldenTest, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var tests []goldenTest
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || ext == "" {
+			continue
+		}
+		switch ext {
+		case goldenInput, goldenOutput, goldenExpect, ".dbg":
+			continue // Файлы ожиданий и отладочной информации не являются программами
+		}
+		base := filepath.Join(dir, strings.TrimSuffix(e.Name(), ext))
+		if !fileExists(base+goldenOutput) && !fileExists(base+goldenExpect) {
+			continue
+		}
+		tests = append(tests, goldenTest{name: e.Name(), program: filepath.Join(dir, e.Name()), base: base})
+	}
+	sort.Slice(tests, func(i, j int) bool { return tests[i].name < tests[j].name })
+	return tests, nil
+}
+
+// fileExists сообщает, существует ли обычный файл
+func fileExists(name string) bool {
+	info, err := os.Stat(name)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// run запускает программу теста и проверяет вывод и состояние после остановки
+func (t goldenTest) run(harvard bool, maxSteps int, update, verbose bool) error {
+	expect, err := readExpectations(t.base + goldenExpect)
+	if err != nil {
+		return err
+	}
+	input := ""
+	if data, err := os.ReadFile(t.base + goldenInput); err == nil {
+		input = string(data)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	// Тест не пишет журналы и не зависит от часов хоста
+	processor, err := vm.NewWithOptions(vm.Options{Harvard: harvard, Quiet: true, ErrorOutput: io.Discard})
+	if err != nil {
+		return err
+	}
+	defer processor.Close()
+	processor.SetVirtualClock(true)
+	processor.SetQuotas(vm.Quotas{MaxInstructions: maxSteps})
+	entry, err := loadProgram(t.program, processor, vm.LoadOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to load program: %v", err)
+	}
+	processor.Reset(entry)
+	output, runErr := processor.RunScripted(input)
+
+	if processor.Status() != expect.status {
+		if runErr != nil {
+			return fmt.Errorf("status %s, want %s: %v", processor.Status(), expect.status, runErr)
+		}
+		return fmt.Errorf("status %s, want %s", processor.Status(), expect.status)
+	}
+	if expect.exitCode != nil && processor.ExitCode() != *expect.exitCode {
+		return fmt.Errorf("exit code %d, want %d", processor.ExitCode(), *expect.exitCode)
+	}
+	for _, assert := range expect.asserts {
+		value, err := processor.Evaluate(assert)
+		if err != nil {
+			return fmt.Errorf("%s: %v", assert, err)
+		}
+		if value == 0 {
+			return fmt.Errorf("assertion failed: %s", assert)
+		}
+	}
+
+	if update {
+		return os.WriteFile(t.base+goldenOutput, []byte(output), 0644)
+	}
+	want, err := os.ReadFile(t.base + goldenOutput)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil // Проверяется только состояние
+	}
+	if err != nil {
+		return err
+	}
+	if output != string(want) {
+		if verbose {
+			fmt.Printf("--- expected %s\n%s--- actual\n%s---\n", t.base+goldenOutput, want, output)
+		}
+		return fmt.Errorf("output differs from %s: %s", t.base+goldenOutput, firstDifference(string(want), output))
+	}
+	return nil
+}
+
+// firstDifference описывает первую несовпадающую строку вывода
+func firstDifference(want, got string) string {
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g || i >= len(wantLines) || i >= len(gotLines) {
+			return fmt.Sprintf("line %d: got %q, want %q", i+1, g, w)
+		}
+	}
+	return "outputs differ"
+}
+
+// readExpectations читает файл .expect. Каждая непустая строка, кроме комментариев
+// (#), — выражение отладчика, которое должно быть истинно после остановки программы,
+// например "[sum] == 15" или "a1 > 0". Строки "status имя" (halted, error, budget
+// exceeded) и "exit код" задают ожидаемый статус и код завершения. По умолчанию
+// программа должна завершиться командой STOP.
+func readExpectations(filename string) (expectations, error) {
+	e := expectations{status: vm.StatusHalted}
+	file, err := os.Open(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return e, nil
+	}
+	if err != nil {
+		return e, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keyword, rest, _ := strings.Cut(line, " ")
+		rest = strings.TrimSpace(rest)
+		switch keyword {
+		case "status":
+			status, ok := parseStatus(rest)
+			if !ok {
+				return e, fmt.Errorf("%s:%d: unknown status %q", filename, n, rest)
+			}
+			e.status = status
+		case "exit":
+			code, err := strconv.ParseInt(rest, 0, 32)
+			if err != nil {
+				return e, fmt.Errorf("%s:%d: invalid exit code %q", filename, n, rest)
+			}
+			exitCode := int32(code)
+			e.exitCode = &exitCode
+		default:
+			if _, err := vm.ParseExpr(line); err != nil {
+				return e, fmt.Errorf("%s:%d: %v", filename, n, err)
+			}
+			e.asserts = append(e.asserts, line)
+		}
+	}
+	return e, scanner.Err()
+}
+
+// parseStatus возвращает статус по его строковому представлению
+func parseStatus(name string) (vm.Status, bool) {
+	for _, s := range []vm.Status{vm.StatusHalted, vm.StatusError, vm.StatusResourceLimit, vm.StatusBudgetExceeded} {
+		if s.String() == name {
+			return s, true
+		}
+	}
+	return 0, false
+}
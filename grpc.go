@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"vm/vm"
+	"vm/vmpb"
+)
+
+// grpcService реализует службу VirtualMachine (vmpb/vm.proto) поверх того же
+// процессора и операций, что и HTTP API подкоманды serve
+type grpcService struct {
+	vmpb.UnimplementedVirtualMachineServer
+	s *vmServer
+}
+
+// grpcCommand выполняет подкоманду "grpc [-addr адрес] [file]": запускает сервер
+// gRPC управления процессором, при необходимости загрузив программу file
+func grpcCommand(args []string, harvard bool, quotas vm.Quotas) int {
+	fs := flag.NewFlagSet("grpc", flag.ContinueOnError)
+	addr := fs.String("addr", "localhost:9090", "address to listen on")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 1 {
+		fmt.Fprintf(os.Stderr, "Usage: %s grpc [-addr host:port] [file]\n", os.Args[0])
+		return 2
+	}
+	s := &vmServer{harvard: harvard, quotas: quotas}
+	if fs.NArg() == 1 {
+		if err := s.loadFile(fs.Arg(0)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+	defer func() {
+		s.stop()
+		if s.p != nil {
+			s.p.Close()
+		}
+	}()
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	server := grpc.NewServer()
+	vmpb.RegisterVirtualMachineServer(server, &grpcService{s: s})
+	fmt.Fprintf(os.Stderr, "Serving the vm.v1.VirtualMachine gRPC service on %s\n", listener.Addr())
+	if err := server.Serve(listener); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// grpcError переводит ошибку запроса в статус gRPC
+func grpcError(err error) error {
+	var httpErr *httpError
+	if !errors.As(err, &httpErr) {
+		return status.Error(codes.Internal, err.Error())
+	}
+	switch httpErr.code {
+	case http.StatusBadRequest:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case http.StatusConflict:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// state выполняет операцию op под блокировкой сервера и возвращает состояние процессора
+func (g *grpcService) state(op func() error) (*vmpb.State, error) {
+	g.s.mu.Lock()
+	defer g.s.mu.Unlock()
+	if err := op(); err != nil {
+		return nil, grpcError(err)
+	}
+	return protoState(g.s.snapshot()), nil
+}
+
+// protoState переводит состояние процессора в сообщение State
+func protoState(st serveState) *vmpb.State {
+	msg := &vmpb.State{
+		Status:    st.Status,
+		Running:   st.Running,
+		Ip:        uint32(st.IP),
+		Sp:        uint32(st.SP),
+		Registers: st.Registers[:],
+		Flags: &vmpb.Flags{Zf: st.Flags.Zero, Sf: st.Flags.Sign, Cf: st.Flags.Carry,
+			Of: st.Flags.Overflow, If: st.Flags.Interrupt, Um: st.Flags.User},
+		Next:         st.Next,
+		CallDepth:    int32(st.CallDepth),
+		Instructions: int64(st.Instructions),
+		ExitCode:     st.ExitCode,
+		Error:        st.Error,
+		Output:       st.Output,
+	}
+	for _, f := range st.Float {
+		msg.FloatRegisters = append(msg.FloatRegisters, float32(f))
+	}
+	return msg
+}
+
+// memorySpaceName возвращает имя памяти, принятое операциями сервера
+func memorySpaceName(space vmpb.MemorySpace) (string, error) {
+	switch space {
+	case vmpb.MemorySpace_MEMORY_SPACE_DATA:
+		return "data", nil
+	case vmpb.MemorySpace_MEMORY_SPACE_CODE:
+		return "code", nil
+	default:
+		return "", status.Errorf(codes.InvalidArgument, "unknown memory space %v", space)
+	}
+}
+
+// LoadProgram загружает программу в новый процессор
+func (g *grpcService) LoadProgram(_ context.Context, req *vmpb.LoadProgramRequest) (*vmpb.State, error) {
+	format := "asm"
+	switch req.GetFormat() {
+	case vmpb.ProgramFormat_PROGRAM_FORMAT_ASM:
+	case vmpb.ProgramFormat_PROGRAM_FORMAT_LOADER:
+		format = "loader"
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown program format %v", req.GetFormat())
+	}
+	return g.state(func() error {
+		return g.s.loadSource(loadRequest{Source: req.GetSource(), Format: format, Input: req.GetInput()})
+	})
+}
+
+// Run запускает программу в фоне
+func (g *grpcService) Run(context.Context, *vmpb.RunRequest) (*vmpb.State, error) {
+	return g.state(g.s.start)
+}
+
+// Pause останавливает фоновое выполнение
+func (g *grpcService) Pause(context.Context, *vmpb.PauseRequest) (*vmpb.State, error) {
+	return g.state(func() error {
+		if err := g.s.loaded(); err != nil {
+			return err
+		}
+		g.s.stop()
+		return nil
+	})
+}
+
+// Step выполняет команды остановленной программы
+func (g *grpcService) Step(_ context.Context, req *vmpb.StepRequest) (*vmpb.State, error) {
+	count := max(int(req.GetCount()), 1)
+	return g.state(func() error { return g.s.stepProgram(count) })
+}
+
+// Reset перезапускает программу с точки входа
+func (g *grpcService) Reset(context.Context, *vmpb.ResetRequest) (*vmpb.State, error) {
+	return g.state(func() error {
+		if err := g.s.loaded(); err != nil {
+			return err
+		}
+		g.s.stop()
+		g.s.restart()
+		return nil
+	})
+}
+
+// GetState возвращает регистры, флаги и состояние программы
+func (g *grpcService) GetState(context.Context, *vmpb.GetStateRequest) (*vmpb.State, error) {
+	return g.state(g.s.loaded)
+}
+
+// ReadMemory читает последовательные слова памяти
+func (g *grpcService) ReadMemory(_ context.Context, req *vmpb.ReadMemoryRequest) (*vmpb.ReadMemoryResponse, error) {
+	space, err := memorySpaceName(req.GetSpace())
+	if err != nil {
+		return nil, err
+	}
+	g.s.mu.Lock()
+	words, err := g.s.readWords(space, int(req.GetAddress()), max(int(req.GetCount()), 1))
+	g.s.mu.Unlock()
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	resp := &vmpb.ReadMemoryResponse{}
+	for _, w := range words {
+		tag := vmpb.WordTag_WORD_TAG_INT
+		switch w.Tag {
+		case "float":
+			tag = vmpb.WordTag_WORD_TAG_FLOAT
+		case "command":
+			tag = vmpb.WordTag_WORD_TAG_COMMAND
+		}
+		resp.Words = append(resp.Words, &vmpb.Word{Address: uint32(w.Addr), Tag: tag, Int: w.Int, Float: float32(w.Float), Text: w.Text})
+	}
+	return resp, nil
+}
+
+// WriteMemory записывает значения в последовательные слова памяти
+func (g *grpcService) WriteMemory(_ context.Context, req *vmpb.WriteMemoryRequest) (*vmpb.WriteMemoryResponse, error) {
+	space, err := memorySpaceName(req.GetSpace())
+	if err != nil {
+		return nil, err
+	}
+	words := make([]vm.Word, len(req.GetValues()))
+	for i, v := range req.GetValues() {
+		switch value := v.GetValue().(type) {
+		case *vmpb.Value_Int:
+			words[i] = vm.IntWord(value.Int)
+		case *vmpb.Value_Float:
+			words[i] = vm.FloatWord(value.Float)
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "value %d is not set", i)
+		}
+	}
+	g.s.mu.Lock()
+	err = g.s.writeWords(space, int(req.GetAddress()), words)
+	g.s.mu.Unlock()
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &vmpb.WriteMemoryResponse{Written: uint32(len(words))}, nil
+}
+
+// Events передает клиенту событие о каждой выполненной команде с той же очередью
+// и режимами при ее заполнении, что и /api/events
+func (g *grpcService) Events(req *vmpb.EventsRequest, stream grpc.ServerStreamingServer[vmpb.Event]) error {
+	size := defaultEventBuffer
+	if n := int(req.GetBuffer()); n > 0 {
+		if n > maxEventBuffer {
+			return status.Errorf(codes.InvalidArgument, "invalid buffer %d (1-%d)", n, maxEventBuffer)
+		}
+		size = n
+	}
+	sub := g.s.subscribe(size, req.GetMode() != vmpb.Backpressure_BACKPRESSURE_DROP)
+	defer g.s.unsubscribe(sub)
+	ctx := stream.Context()
+	for {
+		select {
+		case msg := <-sub.events:
+			if n := sub.dropped.Swap(0); n > 0 {
+				dropped := &vmpb.Event{Event: &vmpb.Event_Dropped{Dropped: &vmpb.DroppedEvent{Count: n}}}
+				if err := stream.Send(dropped); err != nil {
+					return err
+				}
+			}
+			if err := stream.Send(protoEvent(msg)); err != nil {
+				return err
+			}
+		case <-sub.done:
+			return status.Error(codes.ResourceExhausted, "client did not receive events in time")
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// protoEvent переводит событие о команде в сообщение Event
+func protoEvent(msg *stepMessage) *vmpb.Event {
+	step := &vmpb.StepEvent{Ip: uint32(msg.IP), Opcode: msg.Opcode, Text: msg.Text, Output: msg.Output, Error: msg.Error}
+	for _, c := range msg.Changes {
+		step.Changes = append(step.Changes, &vmpb.RegisterChange{Name: c.Name, Old: float64(c.Old), New: float64(c.New)})
+	}
+	return &vmpb.Event{Event: &vmpb.Event_Step{Step: step}}
+}
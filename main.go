@@ -2,29 +2,140 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
 	"strings"
+
+	"vm/vm"
 )
 
-func loadProgram(filename string, memory *Memory) (uint16, error) {
-	file, err := os.Open(filename)
-	if err != nil {
-		return 0, fmt.Errorf("unable to open file: %v", err)
+func main() {
+	strictAlign := flag.Bool("strict-align", false, "trap on word accesses not aligned to the word size")
+	harvard := flag.Bool("harvard", false, "use separate code and data memories")
+	console := flag.Bool("console", false, "map the console device at 0xF000")
+	timer := flag.Bool("timer", false, "map the instruction timer at 0xF020")
+	mmu := flag.Bool("mmu", false, "map the paging MMU registers at 0xF040")
+	disk := flag.String("disk", "", "host file backing the disk device at 0xF070")
+	diskSectors := flag.Int("disk-sectors", vm.DefaultDiskGeometry.Sectors, "number of disk sectors")
+	diskSectorSize := flag.Int("disk-sector-size", vm.DefaultDiskGeometry.SectorSize, "disk sector size in bytes")
+	seed := flag.Int64("seed", vm.DEFAULT_SEED, "seed for the RND pseudo-random number generator")
+	virtualClock := flag.Bool("virtual-clock", false, "derive TIME milliseconds from the instruction count (deterministic)")
+	keyboard := flag.Bool("keyboard", false, "map the non-blocking keyboard device at 0xF0A0")
+	raw := flag.Bool("raw", false, "put the terminal in raw mode while the program runs (use with -keyboard)")
+	banks := flag.Int("banks", 0, "number of switchable memory banks in the 0x8000 window (select register at 0xF060)")
+	maxSteps := flag.Int("max-steps", 0, "halt with \"budget exceeded\" after this many instructions (0 means no limit)")
+	maxOutput := flag.Int("max-output", 0, "stop the program with an error after it writes this many bytes of output (0 means no limit)")
+	maxInterrupts := flag.Int("max-interrupts", 0, "stop the program with an error after this many device interrupts (0 means no limit)")
+	maxDescriptors := flag.Int("max-descriptors", 0, "limit the host files (disk images) open at once (0 means no limit)")
+	timeout := flag.Duration("timeout", 0, "interrupt the program after this wall-clock time, e.g. 5s (0 means no limit)")
+	strictLoad := flag.String("strict-load", "", "report overlapping loader writes and a non-command entry point: warn or error")
+	record := flag.String("record", "", "record stdin input, RND values, TIME readings and input device reads to this file")
+	replay := flag.String("replay", "", "re-execute the program with the input recorded by -record")
+	trace := flag.String("trace", "", "write a binary execution trace to this file (view it with the trace dump subcommand)")
+	heatmap := flag.String("heatmap", "", "write a report of hot instructions and data addresses to this file after the run (- for stderr)")
+	heatmapTop := flag.Int("heatmap-top", 10, "number of addresses in each heatmap table (0 means all)")
+	cache := flag.String("cache", "", "simulate a data cache of size:line[:ways] bytes, e.g. 1024:32:2, and report hits and misses")
+	cacheReport := flag.String("cache-report", "-", "write the -cache report to this file after the run (- for stderr)")
+	coverage := flag.String("coverage", "", "write a listing of executed and never executed instructions to this file after the run (- for stderr)")
+	coverageMin := flag.Float64("coverage-min", 0, "exit with status 3 if less than this percentage of instructions was executed")
+	stats := flag.Bool("stats", false, "print execution statistics to stderr after the run")
+	coreFile := flag.String("core", "", "write a core file with memory and registers to this file if the program stops with an error (inspect it with debug -core)")
+	logSpec := flag.String("log", "info", "log levels, e.g. debug or warn,memory=debug,devices=off (levels: debug, info, warn, error, off; subsystems: processor, memory, loader, devices)")
+	logFormat := flag.String("log-format", "text", "log line format: text or json (one object per event with time, level, subsystem, ip, opcode, message and fields)")
+	logSync := flag.Bool("log-sync", false, "write log lines before the logging call returns instead of from a background goroutine")
+	quiet := flag.Bool("quiet", false, "disable the execution log; vm_execution.log is not created")
+	logFile := flag.String("log-file", vm.DEFAULT_EXECUTION_LOG, "execution log file (- for stderr)")
+	errorLogFile := flag.String("error-log-file", vm.DEFAULT_ERROR_LOG, "error log file, appended to (- for stderr)")
+	traceFilter := flag.String("trace-filter", "", "log and trace only matching instructions, e.g. opcode=IADD,ip=0x100-0x140")
+	inputScript := flag.String("input", "", "read program input from this file, one value per line; requesting more input is an error")
+	outputFile := flag.String("output", "", "write program output, including input prompts, to this file instead of stdout")
+	checkInvariants := flag.Bool("check-invariants", false, "validate machine invariants after every instruction and halt with a report on violation")
+	snapshotOnExit := flag.String("snapshot-on-exit", "", "save the machine state to this file when the run ends, including an interrupted run")
+	cores := flag.Int("cores", 1, "number of cores sharing memory; CPUID loads the core number")
+	coreEntries := flag.String("core-entry", "", "comma-separated entry points of cores 0, 1, ..., e.g. start,worker (default: the program entry for every core)")
+	quantum := flag.Int("quantum", vm.DEFAULT_QUANTUM, "instructions a core executes before the next core runs (with -cores)")
+	schedule := flag.String("schedule", "rr", "order in which cores run: rr (round robin) or random (seeded by -seed)")
+	resume := flag.String("resume", "", "continue a program from a state file saved by -snapshot-on-exit instead of loading a program (map the same devices)")
+	flag.Parse()
+
+	loadOptions := vm.LoadOptions{Warnings: os.Stderr}
+	switch *strictLoad {
+	case "":
+	case "warn":
+		loadOptions.Overlap = vm.OverlapWarn
+	case "error":
+		loadOptions.Overlap = vm.OverlapError
+	default:
+		fmt.Fprintf(os.Stderr, "Error: -strict-load must be warn or error, got %q\n", *strictLoad)
+		os.Exit(2)
 	}
-	defer file.Close()
 
-	return readProgramFromFile(file, memory)
-}
+	if *record != "" && *replay != "" {
+		fmt.Fprintf(os.Stderr, "Error: -record and -replay cannot be used together\n")
+		os.Exit(2)
+	}
+	if *cores > 1 && (*record != "" || *replay != "" || *resume != "" || *snapshotOnExit != "") {
+		fmt.Fprintf(os.Stderr, "Error: -cores cannot be used with -record, -replay, -resume or -snapshot-on-exit\n")
+		os.Exit(2)
+	}
+	if *schedule != "rr" && *schedule != "random" {
+		fmt.Fprintf(os.Stderr, "Error: -schedule must be rr or random, got %q\n", *schedule)
+		os.Exit(2)
+	}
 
-func main() {
-	scanner := bufio.NewScanner(os.Stdin)
+	quotas := vm.Quotas{
+		MaxOutputBytes:  *maxOutput,
+		MaxDescriptors:  *maxDescriptors,
+		MaxInterrupts:   *maxInterrupts,
+		MaxInstructions: *maxSteps,
+	}
+
+	if flag.Arg(0) == "asm" {
+		os.Exit(assemble(flag.Args()[1:]))
+	}
+	if flag.Arg(0) == "link" {
+		os.Exit(link(flag.Args()[1:]))
+	}
+	if flag.Arg(0) == "export" {
+		os.Exit(export(flag.Args()[1:], *harvard))
+	}
+	if flag.Arg(0) == "disasm" {
+		os.Exit(disassemble(flag.Args()[1:], *harvard))
+	}
+	if flag.Arg(0) == "trace" {
+		os.Exit(traceCommand(flag.Args()[1:]))
+	}
+	if flag.Arg(0) == "test" {
+		os.Exit(testCommand(flag.Args()[1:], *harvard))
+	}
+	if flag.Arg(0) == "diff" {
+		os.Exit(diffCommand(flag.Args()[1:]))
+	}
+	if flag.Arg(0) == "debug" {
+		os.Exit(debug(flag.Args()[1:], *harvard))
+	}
+	if flag.Arg(0) == "serve" {
+		os.Exit(serve(flag.Args()[1:], *harvard, quotas))
+	}
+	if flag.Arg(0) == "grpc" {
+		os.Exit(grpcCommand(flag.Args()[1:], *harvard, quotas))
+	}
+
+	// Один буферизованный читатель на весь процесс: консоль продолжает чтение после имени файла
+	stdin := bufio.NewReader(os.Stdin)
 	var filename string
 
-	for {
+	for *resume == "" {
 		fmt.Print("Enter program filename: ")
-		scanner.Scan()
-		filename = strings.TrimSpace(scanner.Text())
+		line, _ := stdin.ReadString('\n')
+		filename = strings.TrimSpace(line)
 
 		if filename == "" {
 			fmt.Fprintf(os.Stderr, "Error: Filename cannot be empty\n")
@@ -36,8 +147,8 @@ func main() {
 		if os.IsNotExist(err) {
 			fmt.Fprintf(os.Stderr, "Error: File '%s' does not exist.\n", filename)
 			fmt.Print("Would you like to try again? (y/n): ")
-			scanner.Scan()
-			response := strings.ToLower(strings.TrimSpace(scanner.Text()))
+			line, _ := stdin.ReadString('\n')
+			response := strings.ToLower(strings.TrimSpace(line))
 			if response != "y" && response != "yes" {
 				fmt.Println("Exiting program.")
 				os.Exit(0)
@@ -48,19 +159,595 @@ func main() {
 		break
 	}
 
-	processor, err := NewProcessor()
+	options := vm.Options{Harvard: *harvard, Quiet: *quiet, LogFile: *logFile, ErrorLogFile: *errorLogFile}
+	options.In = stdin // Программа читает ввод после имени файла из того же буфера
+	if *logFile == "-" {
+		options.LogOutput = os.Stderr
+	}
+	if *errorLogFile == "-" {
+		options.ErrorOutput = os.Stderr
+	}
+	processor, err := vm.NewWithOptions(options)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create processor: %v\n", err)
 		os.Exit(1)
 	}
 	defer processor.Close()
-
-	initialIP, err := loadProgram(filename, processor.memory)
+	if err := processor.Logger().Configure(*logSpec); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -log: %v\n", err)
+		os.Exit(2)
+	}
+	format, err := vm.ParseLogFormat(*logFormat)
 	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -log-format: %v\n", err)
+		os.Exit(2)
+	}
+	processor.Logger().SetFormat(format)
+	processor.Logger().SetAsync(!*logSync)
+	processor.SetStrictAlignment(*strictAlign)
+	processor.SetCheckInvariants(*checkInvariants)
+	processor.SetSeed(*seed)
+	processor.SetVirtualClock(*virtualClock)
+	processor.SetQuotas(quotas)
+	processor.SetHeatmap(*heatmap != "")
+	if *cache != "" {
+		config, err := vm.ParseCacheConfig(*cache)
+		if err == nil {
+			err = processor.SetCache(&config)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: -cache: %v\n", err)
+			os.Exit(2)
+		}
+	}
+	processor.SetCoverage(*coverage != "" || *coverageMin > 0)
+	switch {
+	case *record != "":
+		processor.SetRecording(vm.NewRecording())
+	case *replay != "":
+		recording, err := vm.LoadRecordingFile(*replay)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load recording: %v\n", err)
+			os.Exit(1)
+		}
+		processor.SetRecording(recording)
+	}
+	if *console {
+		if err := processor.MapConsole(vm.CONSOLE_BASE, vm.NewConsole(stdin, os.Stdout)); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to map console: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *timer {
+		if _, err := processor.MapTimer(vm.TIMER_BASE); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to map timer: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *mmu {
+		if _, err := processor.MapMMU(vm.MMU_BASE); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to map MMU: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *banks > 0 {
+		if _, err := processor.MapBanks(vm.BANK_CTRL_BASE, *banks); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to map memory banks: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *keyboard {
+		if err := processor.MapKeyboard(vm.KBD_BASE, vm.NewKeyboard(stdin)); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to map keyboard: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *disk != "" {
+		d, err := vm.OpenDisk(*disk, vm.DiskGeometry{SectorSize: *diskSectorSize, Sectors: *diskSectors})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open disk: %v\n", err)
+			os.Exit(1)
+		}
+		defer d.Close()
+		if err := processor.MapDisk(vm.DISK_BASE, d); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to map disk: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *inputScript != "" {
+		script, err := vm.LoadScriptedInput(*inputScript)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: -input: %v\n", err)
+			os.Exit(1)
+		}
+		processor.In = script
+	}
+	if *outputFile != "" {
+		output, err := os.Create(*outputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: -output: %v\n", err)
+			os.Exit(1)
+		}
+		defer output.Close()
+		processor.Out = output
+	}
+	if *traceFilter != "" {
+		filter, err := vm.ParseTraceFilter(*traceFilter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: -trace-filter: %v\n", err)
+			os.Exit(2)
+		}
+		processor.SetTraceFilter(filter)
+	}
+	if *trace != "" {
+		traceFile, err := os.Create(*trace)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create trace: %v\n", err)
+			os.Exit(1)
+		}
+		defer traceFile.Close()
+		if err := processor.SetTrace(traceFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create trace: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var initialIP uint16
+	if *resume != "" {
+		if err := processor.LoadStateFile(*resume); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to resume: %v\n", err)
+			os.Exit(1)
+		}
+	} else if initialIP, err = loadProgram(filename, processor, loadOptions); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to load program: %v\n", err)
 		os.Exit(1)
 	}
 
-	processor.Reset(initialIP)
-	processor.Run()
+	restoreTerminal := func() {}
+	if *raw {
+		if restoreTerminal, err = setRawMode(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to enable raw terminal mode: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Ctrl+C и истечение -timeout прерывают программу между инструкциями
+	ctx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stopSignals()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	var machine *vm.Machine
+	if *cores > 1 {
+		if machine, err = newMachine(processor, *cores, *coreEntries, initialIP, *quantum, *schedule, *seed); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	} else if *resume == "" {
+		processor.Reset(initialIP) // Восстановленная программа продолжается с сохраненной команды
+	}
+	var runErr error
+	if machine != nil {
+		runErr = machine.RunContext(ctx)
+	} else {
+		runErr = processor.RunContext(ctx)
+	}
+	restoreTerminal() // Возвращаем терминал в обычный режим до вывода результата
+	if *snapshotOnExit != "" {
+		if err := processor.SaveStateFile(*snapshotOnExit); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to save state: %v\n", err)
+		} else {
+			fmt.Fprintf(os.Stderr, "State saved to %s\n", *snapshotOnExit)
+		}
+	}
+	saveRecording(processor, *record)
+	if err := processor.FlushTrace(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+	}
+	if *heatmap != "" {
+		writeReport(*heatmap, "heatmap", func(w io.Writer) error { return processor.WriteHeatmap(w, *heatmapTop) })
+	}
+	if *cache != "" {
+		writeReport(*cacheReport, "cache report", processor.WriteCacheReport)
+	}
+	if *stats {
+		printStats(processor.Stats())
+	}
+	if *coverage != "" {
+		writeReport(*coverage, "coverage", processor.WriteCoverage)
+	}
+
+	if errors.Is(runErr, context.Canceled) || errors.Is(runErr, context.DeadlineExceeded) {
+		fmt.Fprintf(os.Stderr, "Program interrupted at 0x%04X: %v\n", processor.PSW().IP, runErr)
+		processor.Close() // os.Exit не выполняет отложенные вызовы
+		os.Exit(1)
+	}
+
+	// Результат определяет ядро, остановившее машину ошибкой, или первое ядро
+	result := processor
+	var coreErr *vm.CoreError
+	if errors.As(runErr, &coreErr) {
+		result = machine.Cores()[coreErr.Core]
+		fmt.Fprintf(os.Stderr, "Core %d stopped the machine\n", coreErr.Core)
+	}
+
+	// Передаем результат выполнения вызывающему процессу
+	switch result.Status() {
+	case vm.StatusResourceLimit, vm.StatusBudgetExceeded, vm.StatusError:
+		fmt.Fprintf(os.Stderr, "Program halted: %s\n", result.Status())
+		if result.Status() == vm.StatusError {
+			result.WriteCrashReport(os.Stderr) // Ошибка, регистры и команды вокруг IP
+		} else if err := result.Err(); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+		}
+		if *coreFile != "" {
+			if err := vm.WriteCoreFile(*coreFile, result.Core()); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to write core file: %v\n", err)
+			} else {
+				fmt.Fprintf(os.Stderr, "Core dumped to %s\n", *coreFile)
+			}
+		}
+		processor.Close() // os.Exit не выполняет отложенные вызовы
+		os.Exit(1)
+	case vm.StatusHalted:
+		if report, ok := processor.Coverage(); ok && report.Percent() < *coverageMin {
+			fmt.Fprintf(os.Stderr, "Coverage %.1f%% is below the required %.1f%%\n", report.Percent(), *coverageMin)
+			processor.Close() // os.Exit не выполняет отложенные вызовы
+			os.Exit(3)
+		}
+		processor.Close() // os.Exit не выполняет отложенные вызовы
+		os.Exit(int(processor.ExitCode()))
+	}
+}
+
+// newMachine создает машину из count ядер, разделяющих память processor. Точки входа
+// entries — выражения через запятую для ядер 0, 1, ...; остальные ядра начинают с entry.
+func newMachine(processor *vm.Processor, count int, entries string, entry uint16, quantum int, schedule string, seed int64) (*vm.Machine, error) {
+	machine, err := vm.NewMachine(processor, count)
+	if err != nil {
+		return nil, fmt.Errorf("-cores: %v", err)
+	}
+	if err := machine.SetQuantum(quantum); err != nil {
+		return nil, fmt.Errorf("-quantum: %v", err)
+	}
+	if schedule == "random" {
+		machine.SetRandomSchedule(seed)
+	}
+	starts := make([]uint16, count)
+	for i := range starts {
+		starts[i] = entry
+	}
+	if entries != "" {
+		exprs := strings.Split(entries, ",")
+		if len(exprs) > count {
+			return nil, fmt.Errorf("-core-entry: %d entry points for %d cores", len(exprs), count)
+		}
+		for i, expr := range exprs {
+			value, err := processor.Evaluate(strings.TrimSpace(expr))
+			if err != nil {
+				return nil, fmt.Errorf("-core-entry: core %d: %v", i, err)
+			}
+			if value < 0 || value >= int64(processor.CodeMemory().Size()) {
+				return nil, fmt.Errorf("-core-entry: core %d: address 0x%X is out of range", i, value)
+			}
+			starts[i] = uint16(value)
+		}
+	}
+	if err := machine.Reset(starts...); err != nil {
+		return nil, err
+	}
+	return machine, nil
+}
+
+// isAssemblySource сообщает, является ли файл исходным текстом на языке ассемблера
+func isAssemblySource(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	return ext == ".s" || ext == ".asm"
+}
+
+// isObjectFile сообщает, является ли файл объектным модулем
+func isObjectFile(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	return ext == ".o" || ext == ".obj"
+}
+
+// loadProgram загружает программу в формате загрузчика, образ Intel HEX или S-record,
+// объектный модуль либо ассемблирует исходный текст. Проверки options применяются
+// к программам в формате загрузчика. Отладочная информация берется из исходного
+// текста или файла-спутника .dbg и подключается к процессору.
+func loadProgram(filename string, processor *vm.Processor, options vm.LoadOptions) (uint16, error) {
+	if _, ok := vm.DetectHexFormat(filename); ok && !isAssemblySource(filename) {
+		if err := loadDebugInfo(filename, processor); err != nil {
+			return 0, err
+		}
+		return vm.LoadHexFile(filename, processor.CodeMemory(), processor.Memory())
+	}
+	var img *vm.Image
+	switch {
+	case isObjectFile(filename):
+		obj, err := vm.ReadObjectFile(filename)
+		if err != nil {
+			return 0, err
+		}
+		if img, err = linkProgram([]*vm.Object{obj}); err != nil {
+			return 0, err
+		}
+	case isAssemblySource(filename):
+		var err error
+		if img, err = vm.AssembleFile(filename); err != nil {
+			return 0, err
+		}
+	default:
+		file, err := os.Open(filename)
+		if err != nil {
+			return 0, fmt.Errorf("unable to open file: %v", err)
+		}
+		defer file.Close()
+		if err := loadDebugInfo(filename, processor); err != nil {
+			return 0, err
+		}
+		return vm.LoadProgramWithOptions(file, processor.CodeMemory(), processor.Memory(), options)
+	}
+	if err := img.Load(processor.CodeMemory(), processor.Memory()); err != nil {
+		return 0, err
+	}
+	processor.SetDebugInfo(img.Debug)
+	return img.Entry, nil
+}
+
+// loadDebugInfo подключает к процессору файл отладочной информации программы, если он есть
+func loadDebugInfo(filename string, processor *vm.Processor) error {
+	debugFile := vm.DebugFileName(filename)
+	if debugFile == filename {
+		return nil
+	}
+	if _, err := os.Stat(debugFile); err != nil {
+		return nil // Отладочной информации нет
+	}
+	d, err := vm.LoadDebugInfoFile(debugFile)
+	if err != nil {
+		return fmt.Errorf("%s: %v", debugFile, err)
+	}
+	processor.SetDebugInfo(d)
+	return nil
+}
+
+// assemble выполняет подкоманду "asm source [output]": переводит исходный текст
+// в формат загрузчика и записывает его в output (с отладочной информацией в файле .dbg)
+// или на стандартный вывод.
+// Если output имеет расширение .o или .obj, создается объектный модуль.
+func assemble(args []string) int {
+	if len(args) < 1 || len(args) > 2 {
+		fmt.Fprintf(os.Stderr, "Usage: %s asm source [output]\n", os.Args[0])
+		return 2
+	}
+	if len(args) == 2 && isObjectFile(args[1]) {
+		obj, err := vm.AssembleObjectFile(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to assemble %s: %v\n", args[0], err)
+			return 1
+		}
+		return writeOutput(args[1:], obj.WriteObject)
+	}
+	img, err := vm.AssembleFile(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to assemble %s: %v\n", args[0], err)
+		return 1
+	}
+	return writeProgram(args[1:], img)
+}
+
+// linkProgram компонует модули программы со стандартной библиотекой
+func linkProgram(objects []*vm.Object) (*vm.Image, error) {
+	library, err := vm.StandardLibrary()
+	if err != nil {
+		return nil, err
+	}
+	return vm.LinkWithLibrary(objects, library)
+}
+
+// link выполняет подкоманду "link output object...": компонует объектные модули
+// (или исходные тексты, которые ассемблируются в модули) и нужные модули стандартной
+// библиотеки в программу формата загрузчика
+func link(args []string) int {
+	if len(args) < 2 {
+		fmt.Fprintf(os.Stderr, "Usage: %s link output object...\n", os.Args[0])
+		return 2
+	}
+	var objects []*vm.Object
+	for _, filename := range args[1:] {
+		readObject := vm.ReadObjectFile
+		if isAssemblySource(filename) {
+			readObject = vm.AssembleObjectFile
+		}
+		obj, err := readObject(filename)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read %s: %v\n", filename, err)
+			return 1
+		}
+		objects = append(objects, obj)
+	}
+	img, err := linkProgram(objects)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to link: %v\n", err)
+		return 1
+	}
+	return writeProgram(args[:1], img)
+}
+
+// writeProgram записывает образ в формате загрузчика и, если вывод идет в файл,
+// отладочную информацию в файл-спутник .dbg
+func writeProgram(args []string, img *vm.Image) int {
+	if status := writeOutput(args, img.WriteLoaderFormat); status != 0 || len(args) == 0 || args[0] == "-" {
+		return status
+	}
+	if debugFile := vm.DebugFileName(args[0]); debugFile != args[0] {
+		return writeOutput([]string{debugFile}, img.Debug.Write)
+	}
+	return 0
+}
+
+// writeOutput записывает результат подкоманды в файл args[0] или на стандартный вывод
+// (если файл не указан или равен "-")
+func writeOutput(args []string, write func(io.Writer) error) int {
+	out := os.Stdout
+	if len(args) > 0 && args[0] != "-" {
+		var err error
+		if out, err = os.Create(args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create output file: %v\n", err)
+			return 1
+		}
+		defer out.Close()
+	}
+	if err := write(out); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write program: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// disassemble выполняет подкоманду "disasm file [start [end]]": загружает программу
+// в новый процессор и печатает листинг памяти из диапазона (адреса шестнадцатеричные)
+func disassemble(args []string, harvard bool) int {
+	if len(args) < 1 || len(args) > 3 {
+		fmt.Fprintf(os.Stderr, "Usage: %s disasm file [start [end]]\n", os.Args[0])
+		return 2
+	}
+	processor, entry, err := loadOffline(args[0], harvard)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer processor.Close()
+
+	bounds := []int{0, processor.Memory().Size()}
+	for i, arg := range args[1:] {
+		value, err := strconv.ParseUint(arg, 16, 32)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid address %q: %v\n", arg, err)
+			return 2
+		}
+		bounds[i] = int(value)
+	}
+
+	fmt.Printf("; entry 0x%04X\n", entry)
+	if harvard {
+		fmt.Println("; code memory")
+		if err := processor.CodeMemory().Disassemble(os.Stdout, bounds[0], bounds[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to disassemble: %v\n", err)
+			return 1
+		}
+		fmt.Println("; data memory")
+	}
+	if err := processor.Memory().Disassemble(os.Stdout, bounds[0], bounds[1]); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to disassemble: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// loadOffline создает процессор без устройств и загружает в него программу
+// для подкоманд disasm, export и debug
+func loadOffline(filename string, harvard bool) (*vm.Processor, uint16, error) {
+	newProcessor := vm.New
+	if harvard {
+		newProcessor = vm.NewHarvard
+	}
+	processor, err := newProcessor()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create processor: %v", err)
+	}
+	entry, err := loadProgram(filename, processor, vm.LoadOptions{})
+	if err != nil {
+		processor.Close()
+		return nil, 0, fmt.Errorf("failed to load program: %v", err)
+	}
+	return processor, entry, nil
+}
+
+// export выполняет подкоманду "export file output": загружает программу и записывает
+// образ памяти в формате Intel HEX или S-record, выбранном по расширению output
+func export(args []string, harvard bool) int {
+	if len(args) != 2 {
+		fmt.Fprintf(os.Stderr, "Usage: %s export file output.hex|output.srec\n", os.Args[0])
+		return 2
+	}
+	format, ok := vm.HexFormatByExtension(args[1])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown image format of %s: use .hex or .srec\n", args[1])
+		return 2
+	}
+	processor, entry, err := loadOffline(args[0], harvard)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer processor.Close()
+	out, err := os.Create(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create output file: %v\n", err)
+		return 1
+	}
+	defer out.Close()
+	if err := vm.WriteHex(out, format, processor.CodeMemory(), processor.Memory(), entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write %s image: %v\n", format, err)
+		return 1
+	}
+	return 0
+}
+
+// saveRecording сохраняет запись ввода после запуска с -record или сообщает о
+// неиспользованных событиях воспроизводимой записи. Запись сохраняется при любом
+// исходе, в том числе после ошибки, чтобы ее можно было воспроизвести.
+func saveRecording(processor *vm.Processor, filename string) {
+	recording := processor.Recording()
+	if recording == nil {
+		return
+	}
+	if recording.Replaying() {
+		if n := recording.Remaining(); n > 0 {
+			fmt.Fprintf(os.Stderr, "warning: replay finished with %d unused events\n", n)
+		}
+		return
+	}
+	if err := vm.WriteRecordingFile(filename, recording); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save recording: %v\n", err)
+	}
+}
+
+// printStats выводит статистику выполнения в stderr
+func printStats(s vm.Stats) {
+	fmt.Fprintf(os.Stderr, "\nStatistics:\n")
+	fmt.Fprintf(os.Stderr, "  instructions    %d\n", s.Instructions)
+	fmt.Fprintf(os.Stderr, "  memory reads    %d (%d words, %d bytes)\n", s.MemoryReads, s.Memory.WordReads, s.Memory.ByteReads)
+	fmt.Fprintf(os.Stderr, "  memory writes   %d (%d words, %d bytes)\n", s.MemoryWrites, s.Memory.WordWrites, s.Memory.ByteWrites)
+	fmt.Fprintf(os.Stderr, "  memory errors   %d\n", s.Memory.Errors)
+	fmt.Fprintf(os.Stderr, "  branches taken  %d\n", s.Branches)
+	fmt.Fprintf(os.Stderr, "  interrupts      %d\n", s.Interrupts)
+	fmt.Fprintf(os.Stderr, "  wall time       %v\n", s.WallTime)
+	fmt.Fprintf(os.Stderr, "  instructions/s  %.0f\n", s.InstructionsPerSecond())
+}
+
+// writeReport записывает отчет после запуска в файл или, для "-", в stderr
+func writeReport(filename, name string, write func(io.Writer) error) {
+	if filename == "-" {
+		fmt.Fprintln(os.Stderr)
+		if err := write(os.Stderr); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write %s: %v\n", name, err)
+		}
+		return
+	}
+	file, err := os.Create(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write %s: %v\n", name, err)
+		return
+	}
+	defer file.Close()
+	if err := write(file); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write %s: %v\n", name, err)
+	}
 }
@@ -0,0 +1,821 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vm/vm"
+)
+
+// Наибольшее число слов, которое возвращает один запрос чтения памяти
+const maxServeWords = 4096
+
+// Параметры потока событий /api/events
+const (
+	defaultEventBuffer = 256              // Очередь событий клиента по умолчанию
+	maxEventBuffer     = 65536            // Наибольшая очередь событий клиента
+	eventWriteTimeout  = 10 * time.Second // Клиент, не принимающий события дольше, отключается
+)
+
+// vmServer — HTTP API управления процессором для внешнего интерфейса, например
+// учебной страницы в браузере. Запросы выполняются по одному; программа, запущенная
+// /api/run, выполняется в отдельной горутине до остановки или /api/pause.
+type vmServer struct {
+	harvard bool
+	quotas  vm.Quotas  // Квоты ресурсов каждого загруженного процессора
+	cors    string     // Разрешенный источник запросов из браузера
+	mu      sync.Mutex // Сериализует запросы
+
+	p      *vm.Processor
+	entry  uint16       // Точка входа для /api/reset
+	input  string       // Ввод программы, заданный при загрузке
+	output *serveOutput // Вывод программы с момента загрузки или сброса
+
+	cancel context.CancelFunc // Прерывает выполнение, запущенное /api/run
+	done   chan struct{}      // Закрывается, когда выполнение завершилось
+	runErr error              // Ошибка, остановившая последнее выполнение
+
+	subMu       sync.Mutex                // Защищает subscribers: события публикует горутина выполнения
+	subscribers map[*eventSubscriber]bool // Клиенты потока событий
+}
+
+// serveOutput — буфер вывода, который программа пополняет во время выполнения
+type serveOutput struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	taken int // Часть вывода, уже отправленная в событиях
+}
+
+// Write реализует интерфейс io.Writer для serveOutput
+func (o *serveOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.buf.Write(p)
+}
+
+// String возвращает накопленный вывод
+func (o *serveOutput) String() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.buf.String()
+}
+
+// take возвращает вывод, появившийся после предыдущего вызова
+func (o *serveOutput) take() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.buf.Len() == o.taken {
+		return ""
+	}
+	s := string(o.buf.Bytes()[o.taken:])
+	o.taken = o.buf.Len()
+	return s
+}
+
+// httpError — ошибка запроса с кодом ответа HTTP
+type httpError struct {
+	code int
+	err  error
+}
+
+// Error реализует интерфейс error для httpError
+func (e *httpError) Error() string {
+	return e.err.Error()
+}
+
+// conflict сообщает, что запрос недопустим в текущем состоянии процессора
+func conflict(format string, args ...any) error {
+	return &httpError{code: http.StatusConflict, err: fmt.Errorf(format, args...)}
+}
+
+// badRequest сообщает о неверных параметрах запроса
+func badRequest(format string, args ...any) error {
+	return &httpError{code: http.StatusBadRequest, err: fmt.Errorf(format, args...)}
+}
+
+// Справка по запросам API
+const serveHelp = `Endpoints (JSON):
+  POST /api/load      {"source": "...", "format": "asm|loader", "input": "..."}
+  POST /api/run       run in the background until STOP, an error or /api/pause
+  POST /api/pause     stop a background run before the next instruction
+  POST /api/step      execute ?count=n instructions (default 1)
+  POST /api/reset     restart the program from its entry point
+  GET  /api/state     registers, flags, status and program output
+  GET  /api/memory    ?addr=0x40&count=8&space=data|code
+  PUT  /api/memory    {"addr": 64, "values": [1, 2.5]}
+  GET  /api/events    WebSocket stream of executed instructions
+                      (?buffer=256&mode=block|drop)`
+
+// serve выполняет подкоманду "serve [-addr адрес] [-cors источник] [file]": запускает
+// HTTP-сервер API управления процессором, при необходимости загрузив программу file
+func serve(args []string, harvard bool, quotas vm.Quotas) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	cors := fs.String("cors", "", "allow browser requests from this origin, e.g. http://localhost:3000 (* for any)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 1 {
+		fmt.Fprintf(os.Stderr, "Usage: %s serve [-addr host:port] [-cors origin] [file]\n", os.Args[0])
+		return 2
+	}
+	s := &vmServer{harvard: harvard, quotas: quotas, cors: *cors}
+	if fs.NArg() == 1 {
+		if err := s.loadFile(fs.Arg(0)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+	defer func() {
+		s.stop()
+		if s.p != nil {
+			s.p.Close()
+		}
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving the VM API on http://%s\n%s\n", *addr, serveHelp)
+	if err := http.ListenAndServe(*addr, s.handler(*cors)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// handler возвращает обработчик запросов API; cors задает разрешенный источник
+// запросов из браузера ("" — только тот же источник)
+func (s *vmServer) handler(cors string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/load", s.handle(s.load))
+	mux.HandleFunc("POST /api/run", s.handle(s.run))
+	mux.HandleFunc("POST /api/pause", s.handle(s.pause))
+	mux.HandleFunc("POST /api/step", s.handle(s.step))
+	mux.HandleFunc("POST /api/reset", s.handle(s.reset))
+	mux.HandleFunc("GET /api/state", s.handle(s.state))
+	mux.HandleFunc("GET /api/memory", s.handle(s.readMemory))
+	mux.HandleFunc("PUT /api/memory", s.handle(s.writeMemory))
+	mux.HandleFunc("GET /api/events", s.events)
+	if cors == "" {
+		return mux
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", cors)
+		if r.Method == http.MethodOptions { // Предварительный запрос браузера
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// handle оборачивает обработчик запроса: выполняет его под блокировкой сервера и
+// записывает результат или ошибку {"error": "..."} в формате JSON
+func (s *vmServer) handle(f func(r *http.Request) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		result, err := f(r)
+		s.mu.Unlock()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// writeError записывает ответ {"error": "..."} с кодом ошибки запроса
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	var httpErr *httpError
+	if errors.As(err, &httpErr) {
+		code = httpErr.code
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// loadRequest — тело запроса /api/load
+type loadRequest struct {
+	Source string `json:"source"` // Текст программы
+	Format string `json:"format"` // asm (по умолчанию) или loader — формат загрузчика
+	Input  string `json:"input"`  // Ввод программы, по одному значению в строке
+}
+
+// load загружает программу в новый процессор
+func (s *vmServer) load(r *http.Request) (any, error) {
+	var req loadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, badRequest("invalid request: %v", err)
+	}
+	if err := s.loadSource(req); err != nil {
+		return nil, err
+	}
+	return s.snapshot(), nil
+}
+
+// loadSource ассемблирует или загружает текст программы в новый процессор
+func (s *vmServer) loadSource(req loadRequest) error {
+	var img *vm.Image
+	switch req.Format {
+	case "", "asm":
+		var err error
+		if img, err = vm.Assemble(strings.NewReader(req.Source)); err != nil {
+			return badRequest("%v", err)
+		}
+	case "loader":
+	default:
+		return badRequest("unknown format %q (use asm or loader)", req.Format)
+	}
+
+	p, err := s.newProcessor()
+	if err != nil {
+		return err
+	}
+	var entry uint16
+	if img != nil {
+		err = img.Load(p.CodeMemory(), p.Memory())
+		p.SetDebugInfo(img.Debug)
+		entry = img.Entry
+	} else {
+		entry, err = vm.LoadProgramWithOptions(strings.NewReader(req.Source), p.CodeMemory(), p.Memory(), vm.LoadOptions{})
+	}
+	if err != nil {
+		p.Close()
+		return badRequest("failed to load program: %v", err)
+	}
+	s.replace(p, entry, req.Input)
+	return nil
+}
+
+// loadFile загружает программу из файла, указанного при запуске сервера
+func (s *vmServer) loadFile(filename string) error {
+	p, err := s.newProcessor()
+	if err != nil {
+		return err
+	}
+	entry, err := loadProgram(filename, p, vm.LoadOptions{})
+	if err != nil {
+		p.Close()
+		return fmt.Errorf("failed to load program: %v", err)
+	}
+	s.replace(p, entry, "")
+	return nil
+}
+
+// newProcessor создает процессор без журналов: состояние доступно через API
+func (s *vmServer) newProcessor() (*vm.Processor, error) {
+	p, err := vm.NewWithOptions(vm.Options{Harvard: s.harvard, Quiet: true, ErrorOutput: io.Discard})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create processor: %v", err)
+	}
+	p.SetQuotas(s.quotas)
+	p.SetStepHandler(s.publish)
+	return p, nil
+}
+
+// replace останавливает текущую программу и заменяет процессор загруженным
+func (s *vmServer) replace(p *vm.Processor, entry uint16, input string) {
+	s.stop()
+	if s.p != nil {
+		s.p.Close()
+	}
+	s.p, s.entry, s.input = p, entry, input
+	s.restart()
+}
+
+// restart сбрасывает процессор к точке входа с исходным вводом и пустым выводом
+func (s *vmServer) restart() {
+	s.output = &serveOutput{}
+	s.p.In = vm.NewScriptedInput(s.input) // Ввод не блокирует выполнение: его конец — ошибка
+	s.p.Out = s.output
+	s.p.Reset(s.entry)
+	s.runErr = nil
+}
+
+// loaded проверяет, что программа загружена
+func (s *vmServer) loaded() error {
+	if s.p == nil {
+		return conflict("no program loaded")
+	}
+	return nil
+}
+
+// running сообщает, выполняется ли программа в фоне
+func (s *vmServer) running() bool {
+	if s.done == nil {
+		return false
+	}
+	select {
+	case <-s.done:
+		s.done, s.cancel = nil, nil
+		return false
+	default:
+		return true
+	}
+}
+
+// stop прерывает фоновое выполнение и ждет его завершения
+func (s *vmServer) stop() {
+	if s.done == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+	s.done, s.cancel = nil, nil
+}
+
+// run запускает программу в фоне
+func (s *vmServer) run(*http.Request) (any, error) {
+	if err := s.start(); err != nil {
+		return nil, err
+	}
+	return s.snapshot(), nil
+}
+
+// start запускает выполнение программы в отдельной горутине
+func (s *vmServer) start() error {
+	if err := s.loaded(); err != nil {
+		return err
+	}
+	if s.running() {
+		return conflict("program is already running")
+	}
+	if s.p.Stopped() {
+		return conflict("program has stopped: %s", s.p.Status())
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	s.cancel, s.done, s.runErr = cancel, done, nil
+	go func(p *vm.Processor) {
+		defer close(done)
+		err := p.RunContext(ctx)
+		if !errors.Is(err, context.Canceled) {
+			s.runErr = err // Читается после закрытия done
+		}
+	}(s.p)
+	return nil
+}
+
+// pause останавливает фоновое выполнение перед очередной инструкцией
+func (s *vmServer) pause(*http.Request) (any, error) {
+	if err := s.loaded(); err != nil {
+		return nil, err
+	}
+	s.stop()
+	return s.snapshot(), nil
+}
+
+// step выполняет count инструкций или меньше, если программа остановится раньше
+func (s *vmServer) step(r *http.Request) (any, error) {
+	count := 1
+	if text := r.URL.Query().Get("count"); text != "" {
+		n, err := strconv.Atoi(text)
+		if err != nil || n < 1 {
+			return nil, badRequest("invalid count %q", text)
+		}
+		count = n
+	}
+	if err := s.stepProgram(count); err != nil {
+		return nil, err
+	}
+	return s.snapshot(), nil
+}
+
+// stepProgram выполняет count инструкций остановленной программы
+func (s *vmServer) stepProgram(count int) error {
+	if err := s.loaded(); err != nil {
+		return err
+	}
+	if s.running() {
+		return conflict("program is running; pause it first")
+	}
+	if s.p.Stopped() {
+		return conflict("program has stopped: %s", s.p.Status())
+	}
+	for i := 0; i < count && !s.p.Stopped(); i++ {
+		if _, _, err := s.p.Step(); err != nil {
+			s.runErr = err
+			break
+		}
+	}
+	return nil
+}
+
+// reset перезапускает программу с точки входа
+func (s *vmServer) reset(*http.Request) (any, error) {
+	if err := s.loaded(); err != nil {
+		return nil, err
+	}
+	s.stop()
+	s.restart()
+	return s.snapshot(), nil
+}
+
+// serveFlags — флаги PSW в ответе /api/state
+type serveFlags struct {
+	Zero      bool `json:"zf"`
+	Sign      bool `json:"sf"`
+	Carry     bool `json:"cf"`
+	Overflow  bool `json:"of"`
+	Interrupt bool `json:"if"`
+	User      bool `json:"um"`
+}
+
+// serveState — ответ /api/state и команд управления
+type serveState struct {
+	Status       string       `json:"status"`
+	Running      bool         `json:"running"` // Программа выполняется в фоне
+	IP           uint16       `json:"ip"`
+	SP           uint16       `json:"sp"`
+	Registers    [2]int32     `json:"registers"` // a1, a2
+	Float        []serveFloat `json:"float"`     // f0-f3
+	Flags        serveFlags   `json:"flags"`
+	Next         string       `json:"next"` // Команда по адресу IP
+	CallDepth    int          `json:"callDepth"`
+	Instructions int          `json:"instructions"`
+	ExitCode     int32        `json:"exitCode"`
+	Error        string       `json:"error,omitempty"`
+	Output       string       `json:"output"`
+}
+
+// state возвращает регистры, флаги и состояние программы
+func (s *vmServer) state(*http.Request) (any, error) {
+	if err := s.loaded(); err != nil {
+		return nil, err
+	}
+	return s.snapshot(), nil
+}
+
+// snapshot собирает состояние процессора. Фоновое выполнение приостанавливается
+// на время чтения.
+func (s *vmServer) snapshot() serveState {
+	running := s.running()
+	if running {
+		s.p.Pause()
+		defer s.p.Resume()
+	}
+	psw := s.p.PSW()
+	st := serveState{
+		Status:  s.p.Status().String(),
+		Running: running,
+		IP:      psw.IP,
+		SP:      psw.SP,
+		Flags: serveFlags{Zero: psw.ZeroFlag, Sign: psw.SignFlag, Carry: psw.CarryFlag,
+			Overflow: psw.OverflowFlag, Interrupt: psw.InterruptFlag, User: psw.UserMode},
+		CallDepth:    s.p.CallDepth(),
+		Instructions: s.p.Stats().Instructions,
+		ExitCode:     s.p.ExitCode(),
+		Output:       s.output.String(),
+	}
+	for i := range st.Registers {
+		st.Registers[i], _ = s.p.GetRegister(uint8(i))
+	}
+	for i := uint8(0); i < vm.NUM_FLOAT_REGISTERS; i++ {
+		f, _ := s.p.GetFloatRegister(i)
+		st.Float = append(st.Float, serveFloat(f))
+	}
+	if word, err := s.p.CodeMemory().PeekWord(int(psw.IP)); err == nil {
+		st.Next = vm.DisassembleWord(int(psw.IP), word)
+	}
+	if err := s.p.Err(); err != nil {
+		st.Error = err.Error()
+	} else if !running && s.runErr != nil {
+		st.Error = s.runErr.Error()
+	}
+	return st
+}
+
+// serveFloat — вещественное число в ответе; NaN и бесконечности, которых нет
+// в JSON, записываются строками
+type serveFloat float64
+
+// MarshalJSON реализует интерфейс json.Marshaler для serveFloat
+func (f serveFloat) MarshalJSON() ([]byte, error) {
+	if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
+		return json.Marshal(fmt.Sprint(float64(f)))
+	}
+	if float64(float32(f)) == float64(f) {
+		return []byte(strconv.FormatFloat(float64(f), 'g', -1, 32)), nil // Значения регистров и памяти без лишних цифр
+	}
+	return json.Marshal(float64(f))
+}
+
+// serveWord — слово памяти в ответе /api/memory
+type serveWord struct {
+	Addr  int        `json:"addr"`
+	Tag   string     `json:"tag"` // int, float или command
+	Int   int32      `json:"int"`
+	Float serveFloat `json:"float"`
+	Text  string     `json:"text"` // Дизассемблированное слово
+}
+
+// memorySpace возвращает память по параметру space: data (по умолчанию) или code
+func (s *vmServer) memorySpace(space string) (*vm.Memory, error) {
+	switch space {
+	case "", "data":
+		return s.p.Memory(), nil
+	case "code":
+		return s.p.CodeMemory(), nil
+	default:
+		return nil, badRequest("unknown memory space %q (use data or code)", space)
+	}
+}
+
+// readMemory возвращает count слов памяти начиная с addr
+func (s *vmServer) readMemory(r *http.Request) (any, error) {
+	query := r.URL.Query()
+	address, err := strconv.ParseUint(query.Get("addr"), 0, 16)
+	if err != nil {
+		return nil, badRequest("invalid address %q", query.Get("addr"))
+	}
+	count := 1
+	if text := query.Get("count"); text != "" {
+		if count, err = strconv.Atoi(text); err != nil {
+			return nil, badRequest("invalid count %q", text)
+		}
+	}
+	return s.readWords(query.Get("space"), int(address), count)
+}
+
+// readWords возвращает count слов памяти space начиная с address
+func (s *vmServer) readWords(space string, address, count int) ([]serveWord, error) {
+	if err := s.loaded(); err != nil {
+		return nil, err
+	}
+	mem, err := s.memorySpace(space)
+	if err != nil {
+		return nil, err
+	}
+	if count < 1 || count > maxServeWords {
+		return nil, badRequest("invalid count %d (1-%d)", count, maxServeWords)
+	}
+	if s.running() {
+		s.p.Pause()
+		defer s.p.Resume()
+	}
+	words := []serveWord{}
+	for a := address; len(words) < count && a < mem.Size(); a += vm.WORD_SIZE {
+		word, err := mem.PeekWord(a)
+		if err != nil {
+			return nil, badRequest("%v", err)
+		}
+		tag := "int"
+		switch word.Tag {
+		case vm.TagFloat:
+			tag = "float"
+		case vm.TagCommand:
+			tag = "command"
+		}
+		words = append(words, serveWord{Addr: a, Tag: tag, Int: word.D.I, Float: serveFloat(word.D.F), Text: vm.DisassembleWord(a, word)})
+	}
+	return words, nil
+}
+
+// writeRequest — тело запроса PUT /api/memory
+type writeRequest struct {
+	Addr   uint16        `json:"addr"`
+	Space  string        `json:"space"`  // data (по умолчанию) или code
+	Values []json.Number `json:"values"` // Числа с точкой или экспонентой записываются как вещественные
+}
+
+// writeMemory записывает значения в последовательные слова памяти начиная с addr
+func (s *vmServer) writeMemory(r *http.Request) (any, error) {
+	var req writeRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&req); err != nil {
+		return nil, badRequest("invalid request: %v", err)
+	}
+	words := make([]vm.Word, len(req.Values))
+	for i, v := range req.Values {
+		if strings.ContainsAny(v.String(), ".eE") {
+			f, err := strconv.ParseFloat(v.String(), 32)
+			if err != nil {
+				return nil, badRequest("invalid float %s", v)
+			}
+			words[i] = vm.FloatWord(float32(f))
+			continue
+		}
+		n, err := strconv.ParseInt(v.String(), 10, 32)
+		if err != nil {
+			return nil, badRequest("invalid integer %s", v)
+		}
+		words[i] = vm.IntWord(int32(n))
+	}
+	if err := s.writeWords(req.Space, int(req.Addr), words); err != nil {
+		return nil, err
+	}
+	return map[string]int{"written": len(words)}, nil
+}
+
+// writeWords записывает слова в последовательные адреса памяти space начиная с address
+func (s *vmServer) writeWords(space string, address int, words []vm.Word) error {
+	if err := s.loaded(); err != nil {
+		return err
+	}
+	mem, err := s.memorySpace(space)
+	if err != nil {
+		return err
+	}
+	if s.running() {
+		s.p.Pause()
+		defer s.p.Resume()
+	}
+	for i, word := range words {
+		if err := mem.WriteWord(address+i*vm.WORD_SIZE, word); err != nil {
+			return badRequest("%v", err)
+		}
+	}
+	return nil
+}
+
+// eventSubscriber — клиент потока событий /api/events
+type eventSubscriber struct {
+	events  chan *stepMessage // События, ожидающие отправки
+	block   bool              // При заполненной очереди выполнение ждет клиента, а не пропускает события
+	dropped atomic.Int64      // События, пропущенные с последней отправки
+	done    chan struct{}     // Закрывается при отключении клиента
+	once    sync.Once
+}
+
+// close отмечает отключение клиента и освобождает ожидающую горутину выполнения
+func (sub *eventSubscriber) close() {
+	sub.once.Do(func() { close(sub.done) })
+}
+
+// serveChange — изменение регистра в событии
+type serveChange struct {
+	Name string     `json:"name"`
+	Old  serveFloat `json:"old"`
+	New  serveFloat `json:"new"`
+}
+
+// stepMessage — событие о выполненной команде
+type stepMessage struct {
+	Type    string        `json:"type"` // step
+	IP      uint16        `json:"ip"`
+	Opcode  string        `json:"opcode"`
+	Text    string        `json:"text"` // Дизассемблированная команда
+	Changes []serveChange `json:"changes"`
+	Output  string        `json:"output,omitempty"` // Вывод команды
+	Error   string        `json:"error,omitempty"`
+}
+
+// droppedMessage сообщает, сколько событий пропущено из-за заполненной очереди клиента
+type droppedMessage struct {
+	Type  string `json:"type"` // dropped
+	Count int64  `json:"count"`
+}
+
+// events выполняет запрос /api/events: переключает соединение на WebSocket и
+// отправляет клиенту событие о каждой выполненной команде. Параметр buffer задает
+// очередь событий клиента; при ее заполнении в режиме block (по умолчанию)
+// выполнение ждет клиента, в режиме drop события пропускаются, и перед следующим
+// отправленным событием клиент получает {"type": "dropped", "count": n}.
+func (s *vmServer) events(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	size := defaultEventBuffer
+	if text := query.Get("buffer"); text != "" {
+		n, err := strconv.Atoi(text)
+		if err != nil || n < 1 || n > maxEventBuffer {
+			writeError(w, badRequest("invalid buffer %q (1-%d)", text, maxEventBuffer))
+			return
+		}
+		size = n
+	}
+	mode := query.Get("mode")
+	if mode != "" && mode != "block" && mode != "drop" {
+		writeError(w, badRequest("unknown mode %q (use block or drop)", mode))
+		return
+	}
+	if !s.allowedOrigin(r) {
+		writeError(w, &httpError{code: http.StatusForbidden, err: fmt.Errorf("origin %q is not allowed", r.Header.Get("Origin"))})
+		return
+	}
+	conn, err := upgradeWebSocket(w, r, eventWriteTimeout)
+	if err != nil {
+		writeError(w, badRequest("%v", err))
+		return
+	}
+	defer conn.Close()
+
+	sub := s.subscribe(size, mode != "drop")
+	defer s.unsubscribe(sub)
+	go func() {
+		conn.readLoop()
+		sub.close() // Клиент закрыл соединение
+	}()
+	for {
+		select {
+		case msg := <-sub.events:
+			if n := sub.dropped.Swap(0); n > 0 {
+				dropped, _ := json.Marshal(droppedMessage{Type: "dropped", Count: n})
+				if conn.WriteText(dropped) != nil {
+					return
+				}
+			}
+			data, err := json.Marshal(msg)
+			if err != nil || conn.WriteText(data) != nil {
+				return // Клиент не принимает события: отключаем его
+			}
+		case <-sub.done:
+			return
+		}
+	}
+}
+
+// allowedOrigin сообщает, можно ли открыть поток событий со страницы источника
+// запроса: браузер не применяет к WebSocket правила CORS
+func (s *vmServer) allowedOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || s.cors == "*" || origin == s.cors {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// subscribe добавляет клиента потока событий с очередью size; block выбирает
+// ожидание клиента вместо пропуска событий при заполненной очереди
+func (s *vmServer) subscribe(size int, block bool) *eventSubscriber {
+	sub := &eventSubscriber{events: make(chan *stepMessage, size), block: block, done: make(chan struct{})}
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	if s.subscribers == nil {
+		s.subscribers = make(map[*eventSubscriber]bool)
+	}
+	s.subscribers[sub] = true
+	return sub
+}
+
+// unsubscribe удаляет клиента потока событий
+func (s *vmServer) unsubscribe(sub *eventSubscriber) {
+	sub.close()
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	delete(s.subscribers, sub)
+}
+
+// publish отправляет событие о выполненной команде всем клиентам потока.
+// Вызывается горутиной выполнения после каждой команды.
+func (s *vmServer) publish(e vm.StepEvent) {
+	output := s.output.take() // Вывод забирается и без клиентов, чтобы не попасть в чужое событие
+	s.subMu.Lock()
+	subs := make([]*eventSubscriber, 0, len(s.subscribers))
+	for sub := range s.subscribers {
+		subs = append(subs, sub)
+	}
+	s.subMu.Unlock()
+	if len(subs) == 0 {
+		return
+	}
+
+	msg := &stepMessage{Type: "step", IP: e.IP, Opcode: vm.OpCode(e.Cmd.Opcode).String(),
+		Text: vm.DisassembleWord(int(e.IP), vm.CommandWord(e.Cmd)), Changes: []serveChange{}, Output: output}
+	for _, c := range e.Changes {
+		msg.Changes = append(msg.Changes, serveChange{Name: c.Name, Old: serveFloat(c.Old), New: serveFloat(c.New)})
+	}
+	if e.Err != nil {
+		msg.Error = e.Err.Error()
+	}
+	for _, sub := range subs {
+		if sub.block {
+			select {
+			case sub.events <- msg:
+				continue
+			default:
+			}
+			timer := time.NewTimer(eventWriteTimeout)
+			select {
+			case sub.events <- msg:
+			case <-sub.done: // Клиент отключился, пока выполнение ждало места в очереди
+			case <-timer.C:
+				sub.close() // Клиент не принимает события: отключаем его, и выполнение продолжается
+			}
+			timer.Stop()
+			continue
+		}
+		select {
+		case sub.events <- msg:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"vm/vm"
+)
+
+// diffCommand выполняет подкоманду "diff before after": сравнивает два дампа памяти
+// (-core) или снимка состояния (-snapshot-on-exit) и выводит измененные регистры,
+// флаги и слова памяти
+func diffCommand(args []string) int {
+	if len(args) != 2 {
+		fmt.Fprintf(os.Stderr, "Usage: %s diff before after\n", os.Args[0])
+		return 2
+	}
+	var states [2]*vm.Core
+	for i, filename := range args {
+		c, err := vm.LoadSnapshotFile(filename)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", filename, err)
+			return 1
+		}
+		states[i] = c
+	}
+	d, err := vm.DiffCores(states[0], states[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	debug := states[1].Debug
+	if debug == nil {
+		debug = states[0].Debug
+	}
+	printDiff(os.Stdout, d, debug)
+	return 0
+}
+
+// printDiff выводит изменения регистров, флагов и памяти; адреса памяти
+// сопровождаются метками и строками исходного текста из debug
+func printDiff(w io.Writer, d *vm.StateDiff, debug *vm.DebugInfo) {
+	if d.Empty() {
+		fmt.Fprintln(w, "No changes")
+		return
+	}
+	for _, c := range d.Registers {
+		fmt.Fprintf(w, "  %-4s %s -> %s\n", c.Name, formatRegister(c.Name, c.Old), formatRegister(c.Name, c.New))
+	}
+	for _, c := range d.Memory {
+		space := "data"
+		if c.Code {
+			space = "code"
+		}
+		fmt.Fprintf(w, "  %s 0x%04X  %s -> %s", space, c.Address, vm.DisassembleWord(c.Address, c.Old), vm.DisassembleWord(c.Address, c.New))
+		if debug != nil {
+			fmt.Fprintf(w, "  ; %s", debug.Describe(c.Address))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// formatRegister форматирует значение регистра, указателя или флага из RegisterChange
+func formatRegister(name string, value float64) string {
+	switch name {
+	case "IP", "SP":
+		return fmt.Sprintf("0x%04X", int(value))
+	case "f0", "f1", "f2", "f3":
+		return strconv.FormatFloat(value, 'g', -1, 32)
+	}
+	return strconv.FormatInt(int64(value), 10)
+}
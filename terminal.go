@@ -0,0 +1,27 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// setRawMode переводит терминал stdin в неканонический режим без эха, чтобы клавиатура
+// получала нажатия сразу. Возвращает функцию, восстанавливающую прежний режим.
+func setRawMode() (func(), error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+	return func() { stty(strings.TrimSpace(string(saved))) }, nil
+}
+
+// stty выполняет команду stty для терминала stdin
+func stty(args ...string) ([]byte, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin // stty управляет терминалом, с которым связан его stdin
+	return cmd.Output()
+}
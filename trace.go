@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"vm/vm"
+)
+
+// traceCommand выполняет подкоманду "trace dump [флаги] файл": печатает двоичную
+// трассу, записанную с флагом -trace, отбирая записи по адресу, команде и ошибке
+func traceCommand(args []string) int {
+	if len(args) == 0 || args[0] != "dump" {
+		fmt.Fprintf(os.Stderr, "Usage: %s trace dump [-from addr] [-to addr] [-op name] [-faults] [-limit n] file\n", os.Args[0])
+		return 2
+	}
+	fs := flag.NewFlagSet("trace dump", flag.ContinueOnError)
+	from := fs.String("from", "0", "first instruction address to show (hex)")
+	to := fs.String("to", "FFFF", "last instruction address to show (hex)")
+	op := fs.String("op", "", "show only this instruction, e.g. CALL")
+	faults := fs.Bool("faults", false, "show only instructions that failed")
+	limit := fs.Int("limit", 0, "stop after this many shown records (0 means no limit)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: %s trace dump [-from addr] [-to addr] [-op name] [-faults] [-limit n] file\n", os.Args[0])
+		return 2
+	}
+
+	var bounds [2]uint16
+	for i, arg := range []string{*from, *to} {
+		value, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(arg), "0x"), 16, 16)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid address %q: %v\n", arg, err)
+			return 2
+		}
+		bounds[i] = uint16(value)
+	}
+	opcode := -1
+	if *op != "" {
+		code, ok := vm.ParseOpCode(*op)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Unknown instruction %q\n", *op)
+			return 2
+		}
+		opcode = int(code)
+	}
+
+	file, err := os.Open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer file.Close()
+	tr, err := vm.NewTraceReader(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	out := bufio.NewWriter(os.Stdout) // Трасса может содержать миллионы записей
+	defer out.Flush()
+	shown := 0
+	for n := 1; ; n++ {
+		r, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: record %d: %v\n", n, err)
+			return 1
+		}
+		if r.IP < bounds[0] || r.IP > bounds[1] || (opcode >= 0 && int(r.Cmd.Opcode) != opcode) || (*faults && !r.Fault()) {
+			continue
+		}
+		fmt.Fprintf(out, "%8d  %s\n", n, r)
+		if shown++; *limit > 0 && shown >= *limit {
+			break
+		}
+	}
+	return 0
+}
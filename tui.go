@@ -0,0 +1,576 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"vm/vm"
+)
+
+// Управляющие последовательности терминала
+const (
+	ansiAltScreen  = "\x1b[?1049h\x1b[?25l" // Отдельный экран без курсора
+	ansiMainScreen = "\x1b[?25h\x1b[?1049l" // Возврат к обычному экрану
+	ansiHome       = "\x1b[H"
+	ansiClearLine  = "\x1b[K"
+	ansiClearBelow = "\x1b[J"
+	ansiReverse    = "\x1b[7m"
+	ansiBold       = "\x1b[1m"
+	ansiReset      = "\x1b[0m"
+)
+
+// Размеры панелей полноэкранного отладчика
+const (
+	tuiSideWidth  = 30 // Панель регистров справа от дизассемблера
+	tuiMemoryRows = 4  // Строки панели памяти
+	tuiWordWidth  = 12 // Ширина слова в панели памяти
+)
+
+// Задержка, после которой отладчик сообщает, что программа выполняется
+const tuiRunningDelay = 100 * time.Millisecond
+
+// Подсказка по клавишам в строке состояния
+const tuiKeys = "s step  n next  f finish  c continue  u back  b break  w/W watch/unwatch  j/k move  m memory  [/] scroll  r restart  q quit"
+
+// tui — полноэкранный отладчик: дизассемблированный код вокруг IP, регистры и
+// флаги, панель памяти данных и консоль программы. Программа выполняется в
+// отдельной горутине, чтобы любая клавиша могла прервать continue.
+type tui struct {
+	p     *vm.Processor
+	entry uint16 // Точка входа для restart
+	out   *bufio.Writer
+	keys  chan string // Нажатия клавиш; закрывается в конце ввода
+
+	console  bytes.Buffer       // Вывод программы
+	inputReq chan chan<- string // Запросы строки ввода от выполняемой программы
+
+	cursor  int    // Адрес выделенной команды
+	memAddr int    // Первый адрес панели памяти
+	message string // Сообщение строки состояния
+
+	width, height int
+}
+
+// tuiInput — ввод программы: строка запрашивается у пользователя в строке состояния
+type tuiInput struct {
+	t       *tui
+	pending []byte
+}
+
+// Read реализует интерфейс io.Reader для tuiInput
+func (in *tuiInput) Read(b []byte) (int, error) {
+	if len(in.pending) == 0 {
+		reply := make(chan string)
+		in.t.inputReq <- reply
+		line, ok := <-reply
+		if !ok {
+			return 0, io.EOF
+		}
+		in.t.console.WriteString(line + "\n") // Эхо ввода в консоли
+		in.pending = []byte(line + "\n")
+	}
+	n := copy(b, in.pending)
+	in.pending = in.pending[n:]
+	return n, nil
+}
+
+// debugTUI выполняет подкоманду "debug -tui file": загружает программу и открывает
+// полноэкранный отладчик
+func debugTUI(filename string, harvard bool) int {
+	processor, entry, err := loadOffline(filename, harvard)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer processor.Close()
+	processor.SetHistory(monitorHistory)
+	processor.Reset(entry)
+
+	restore, err := setRawMode()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: the terminal UI needs an interactive terminal: %v\n", err)
+		return 1
+	}
+	defer restore()
+
+	t := &tui{p: processor, entry: entry, out: bufio.NewWriter(os.Stdout), keys: make(chan string), inputReq: make(chan chan<- string)}
+	processor.In = &tuiInput{t: t}
+	processor.Out = &t.console
+	t.cursor = int(processor.PSW().IP)
+	t.width, t.height = terminalSize()
+	go readKeys(os.Stdin, t.keys)
+
+	t.out.WriteString(ansiAltScreen)
+	defer func() {
+		t.out.WriteString(ansiMainScreen)
+		t.out.Flush()
+	}()
+	t.run()
+	return 0
+}
+
+// terminalSize возвращает число столбцов и строк терминала (80x24, если неизвестно)
+func terminalSize() (int, int) {
+	out, err := stty("size")
+	if err != nil {
+		return 80, 24
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return 80, 24
+	}
+	rows, err1 := strconv.Atoi(fields[0])
+	cols, err2 := strconv.Atoi(fields[1])
+	if err1 != nil || err2 != nil || rows < 16 || cols < 60 {
+		return max(cols, 80), max(rows, 24)
+	}
+	return cols, rows
+}
+
+// readKeys передает нажатия клавиш: escape-последовательность целиком, остальные
+// символы по одному
+func readKeys(r io.Reader, keys chan<- string) {
+	defer close(keys)
+	buf := make([]byte, 64)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 && buf[0] == 0x1b {
+			keys <- string(buf[:n])
+		} else {
+			for _, c := range string(buf[:n]) {
+				keys <- string(c)
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// run обрабатывает клавиши до q или конца ввода
+func (t *tui) run() {
+	for {
+		t.draw("")
+		key, ok := <-t.keys
+		if !ok || t.handle(key) {
+			return
+		}
+	}
+}
+
+// handle выполняет действие клавиши и сообщает, нужно ли завершить работу
+func (t *tui) handle(key string) bool {
+	t.message = ""
+	switch key {
+	case "q", "\x03":
+		return true
+	case "s", " ":
+		t.execute(func(context.Context) error {
+			_, _, err := t.p.Step()
+			return err
+		})
+	case "n":
+		t.execute(t.p.StepOverContext)
+	case "f":
+		t.execute(t.p.StepOutContext)
+	case "c":
+		t.execute(t.p.RunContext)
+	case "u":
+		if _, err := t.p.StepBack(); err != nil {
+			t.message = fmt.Sprintf("Error: %v", err)
+		}
+		t.cursor = int(t.p.PSW().IP)
+	case "b":
+		t.toggleBreakpoint()
+	case "w":
+		t.addWatch()
+	case "W":
+		t.removeWatch()
+	case "j", "\x1b[B":
+		t.moveCursor(1)
+	case "k", "\x1b[A":
+		t.moveCursor(-1)
+	case ".":
+		t.cursor = int(t.p.PSW().IP)
+	case "m":
+		t.gotoMemory()
+	case "[", "\x1b[5~":
+		t.memAddr = max(t.memAddr-t.memoryPage(), 0)
+	case "]", "\x1b[6~":
+		t.memAddr = min(t.memAddr+t.memoryPage(), t.p.Memory().Size()-vm.WORD_SIZE)
+	case "r":
+		t.p.Reset(t.entry)
+		t.console.Reset()
+		t.cursor = int(t.p.PSW().IP)
+		t.message = "Restarted"
+	case "h", "?":
+		t.message = tuiKeys
+	default:
+		t.message = "Unknown key (press h for help)"
+	}
+	return false
+}
+
+// execute выполняет run в отдельной горутине. Пока программа выполняется, отладчик
+// отвечает на ее запросы ввода, а любая клавиша прерывает выполнение.
+func (t *tui) execute(run func(ctx context.Context) error) {
+	if t.p.Stopped() {
+		t.message = fmt.Sprintf("Program is not running (status: %s); press r to restart", t.p.Status())
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- run(ctx) }()
+
+	keys := t.keys
+	slow := time.NewTimer(tuiRunningDelay) // Короткие шаги не мигают строкой состояния
+	defer slow.Stop()
+	for {
+		select {
+		case err := <-done:
+			t.report(err)
+			t.cursor = int(t.p.PSW().IP)
+			return
+		case reply := <-t.inputReq:
+			if line, ok := t.prompt("Input: "); ok { // Программа ждет ввода: ее состояние можно показать
+				reply <- line
+			} else {
+				close(reply)
+			}
+		case _, ok := <-keys:
+			if !ok {
+				keys = nil // Конец ввода: ждем остановки программы
+			}
+			cancel() // Любая клавиша прерывает выполнение перед следующей командой
+		case <-slow.C:
+			// Состояние выполняемой программы не читается: показываем только строку состояния
+			t.drawStatus("Running... press any key to pause")
+		}
+	}
+}
+
+// report показывает результат выполнения в строке состояния
+func (t *tui) report(err error) {
+	var hit *vm.BreakpointHit
+	switch {
+	case errors.As(err, &hit):
+		t.message = fmt.Sprintf("Breakpoint at 0x%04X", hit.Address)
+		if hit.Condition != "" {
+			t.message += " (" + hit.Condition + ")"
+		}
+	case errors.Is(err, context.Canceled):
+		t.message = fmt.Sprintf("Paused at 0x%04X", t.p.PSW().IP)
+	case t.p.Stopped():
+		t.message = fmt.Sprintf("Program stopped: %s (exit code %d)", t.p.Status(), t.p.ExitCode())
+		if err := t.p.Err(); err != nil {
+			t.message += ": " + err.Error()
+		}
+	case err != nil:
+		t.message = fmt.Sprintf("Error: %v", err)
+	}
+}
+
+// toggleBreakpoint устанавливает или снимает точку останова на выделенной команде
+func (t *tui) toggleBreakpoint() {
+	address := uint16(t.cursor)
+	if t.p.RemoveBreakpoint(address) {
+		t.message = fmt.Sprintf("Breakpoint at 0x%04X removed", address)
+		return
+	}
+	if err := t.p.AddBreakpoint(address); err != nil {
+		t.message = fmt.Sprintf("Error: %v", err)
+		return
+	}
+	t.message = fmt.Sprintf("Breakpoint at 0x%04X", address)
+}
+
+// addWatch запрашивает выражение наблюдения; его значение показывается на панели регистров
+func (t *tui) addWatch() {
+	expr, ok := t.prompt("Watch: ")
+	if !ok || strings.TrimSpace(expr) == "" {
+		return
+	}
+	id, err := t.p.AddWatch(expr)
+	if err != nil {
+		t.message = fmt.Sprintf("Error: %v", err)
+		return
+	}
+	t.message = fmt.Sprintf("Watch #%d: %s", id, expr)
+}
+
+// removeWatch запрашивает номер выражения наблюдения и удаляет его
+func (t *tui) removeWatch() {
+	text, ok := t.prompt("Unwatch #: ")
+	if !ok || strings.TrimSpace(text) == "" {
+		return
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(text), "#"))
+	if err != nil || !t.p.RemoveWatch(id) {
+		t.message = fmt.Sprintf("Error: no watch %q", text)
+		return
+	}
+	t.message = fmt.Sprintf("Watch #%d removed", id)
+}
+
+// moveCursor перемещает выделение на delta команд
+func (t *tui) moveCursor(delta int) {
+	next := t.cursor + delta*vm.WORD_SIZE
+	if next >= 0 && next+vm.WORD_SIZE <= t.p.CodeMemory().Size() {
+		t.cursor = next
+	}
+}
+
+// gotoMemory запрашивает выражение адреса для панели памяти
+func (t *tui) gotoMemory() {
+	expr, ok := t.prompt("Memory address: ")
+	if !ok || strings.TrimSpace(expr) == "" {
+		return
+	}
+	value, err := t.p.Evaluate(expr)
+	if err != nil {
+		t.message = fmt.Sprintf("Error: %v", err)
+		return
+	}
+	if value < 0 || value >= int64(t.p.Memory().Size()) {
+		t.message = fmt.Sprintf("Error: address 0x%X is out of range", value)
+		return
+	}
+	t.memAddr = int(value)
+}
+
+// prompt читает строку в строке состояния; Escape или конец ввода отменяют ввод
+func (t *tui) prompt(label string) (string, bool) {
+	var line []rune
+	for {
+		t.draw(label + string(line) + "_")
+		key, ok := <-t.keys
+		if !ok {
+			return "", false
+		}
+		switch key {
+		case "\r", "\n":
+			return string(line), true
+		case "\x1b", "\x03":
+			return "", false
+		case "\x7f", "\b":
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+			}
+		default:
+			if r, _ := utf8.DecodeRuneInString(key); len(key) == utf8.RuneLen(r) && r >= ' ' {
+				line = append(line, r)
+			}
+		}
+	}
+}
+
+// memoryColumns возвращает число слов в строке панели памяти
+func (t *tui) memoryColumns() int {
+	return max(min((t.width-8)/tuiWordWidth, 8), 1)
+}
+
+// memoryPage возвращает число байт, показываемых панелью памяти
+func (t *tui) memoryPage() int {
+	return t.memoryColumns() * tuiMemoryRows * vm.WORD_SIZE
+}
+
+// draw перерисовывает экран; status заменяет сообщение в строке состояния
+func (t *tui) draw(status string) {
+	consoleRows := max(t.height/5, 3)
+	codeRows := t.height - consoleRows - tuiMemoryRows - 4 // Заголовки панелей и строка состояния
+	codeWidth := t.width - tuiSideWidth - 1
+
+	var lines []string
+	lines = append(lines, ansiBold+fit(" Code", codeWidth)+" "+fit(" Registers", tuiSideWidth)+ansiReset)
+	code := t.codeLines(codeRows, codeWidth)
+	side := t.registerLines()
+	for i := 0; i < codeRows; i++ {
+		right := ""
+		if i < len(side) {
+			right = side[i]
+		}
+		lines = append(lines, code[i]+" "+fit(right, tuiSideWidth))
+	}
+	lines = append(lines, ansiBold+fit(fmt.Sprintf(" Memory at 0x%04X", t.memAddr), t.width)+ansiReset)
+	lines = append(lines, t.memoryLines()...)
+	lines = append(lines, ansiBold+fit(" Console", t.width)+ansiReset)
+	lines = append(lines, t.consoleLines(consoleRows)...)
+	if status == "" {
+		status = t.message
+	}
+	if status == "" {
+		status = tuiKeys
+	}
+	lines = append(lines, ansiReverse+fit(status, t.width)+ansiReset)
+
+	t.out.WriteString(ansiHome)
+	for i, line := range lines {
+		if i > 0 {
+			t.out.WriteString("\r\n")
+		}
+		t.out.WriteString(line + ansiClearLine)
+	}
+	t.out.WriteString(ansiClearBelow)
+	t.out.Flush()
+}
+
+// drawStatus перерисовывает только строку состояния
+func (t *tui) drawStatus(status string) {
+	fmt.Fprintf(t.out, "\x1b[%d;1H%s%s%s", t.height, ansiReverse, fit(status, t.width), ansiReset)
+	t.out.Flush()
+}
+
+// codeLines дизассемблирует rows команд вокруг выделенной
+func (t *tui) codeLines(rows, width int) []string {
+	code := t.p.CodeMemory()
+	last := code.Size()/vm.WORD_SIZE*vm.WORD_SIZE - vm.WORD_SIZE
+	start := t.cursor - rows/2*vm.WORD_SIZE
+	start = max(min(start, last-(rows-1)*vm.WORD_SIZE), 0)
+	ip := int(t.p.PSW().IP)
+	lines := make([]string, rows)
+	for i := range lines {
+		address := start + i*vm.WORD_SIZE
+		if address > last {
+			lines[i] = fit("", width)
+			continue
+		}
+		marker := "  "
+		if address == ip {
+			marker = "=>"
+		}
+		bp := " "
+		if t.p.HasBreakpoint(uint16(address)) {
+			bp = "*"
+		}
+		text := "??"
+		if word, err := code.PeekWord(address); err == nil {
+			text = vm.DisassembleWord(address, word)
+		}
+		line := fmt.Sprintf("%s%s 0x%04X  %-24s", bp, marker, address, text)
+		if t.p.DebugInfo() != nil {
+			line += " ; " + t.p.DescribeAddress(address)
+		}
+		line = fit(line, width)
+		if address == t.cursor {
+			line = ansiReverse + line + ansiReset
+		}
+		lines[i] = line
+	}
+	return lines
+}
+
+// registerLines описывает регистры, флаги и состояние программы
+func (t *tui) registerLines() []string {
+	psw := t.p.PSW()
+	a1, _ := t.p.GetRegister(0)
+	a2, _ := t.p.GetRegister(1)
+	lines := []string{
+		fmt.Sprintf(" IP  0x%04X   SP  0x%04X", psw.IP, psw.SP),
+		fmt.Sprintf(" a1  %d", a1),
+		fmt.Sprintf(" a2  %d", a2),
+	}
+	for i := uint8(0); i < vm.NUM_FLOAT_REGISTERS; i++ {
+		f, _ := t.p.GetFloatRegister(i)
+		lines = append(lines, fmt.Sprintf(" f%d  %g", i, f))
+	}
+	flag := func(name string, set bool) string {
+		if set {
+			return strings.ToUpper(name)
+		}
+		return strings.ToLower(name)
+	}
+	lines = append(lines,
+		fmt.Sprintf(" %s %s %s %s %s %s", flag("ZF", psw.ZeroFlag), flag("SF", psw.SignFlag), flag("CF", psw.CarryFlag),
+			flag("OF", psw.OverflowFlag), flag("IF", psw.InterruptFlag), flag("UM", psw.UserMode)),
+		"",
+		fmt.Sprintf(" status  %s", t.p.Status()),
+		fmt.Sprintf(" depth   %d", t.p.CallDepth()),
+		fmt.Sprintf(" steps   %d", t.p.Stats().Instructions),
+	)
+	if t.p.Stopped() {
+		lines = append(lines, fmt.Sprintf(" exit    %d", t.p.ExitCode()))
+	}
+	if watches := t.p.Watches(); len(watches) > 0 {
+		lines = append(lines, "")
+		for _, w := range watches {
+			if w.Err != nil {
+				lines = append(lines, fmt.Sprintf(" #%d %s = <%v>", w.ID, w.Source, w.Err))
+			} else {
+				lines = append(lines, fmt.Sprintf(" #%d %s = %d", w.ID, w.Source, w.Value))
+			}
+		}
+	}
+	return lines
+}
+
+// memoryLines показывает слова памяти данных начиная с memAddr
+func (t *tui) memoryLines() []string {
+	mem := t.p.Memory()
+	columns := t.memoryColumns()
+	lines := make([]string, tuiMemoryRows)
+	for row := range lines {
+		address := t.memAddr + row*columns*vm.WORD_SIZE
+		if address >= mem.Size() {
+			lines[row] = ""
+			continue
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, " 0x%04X", address)
+		for col := 0; col < columns && address < mem.Size(); col++ {
+			word, err := mem.PeekWord(address)
+			value := "??"
+			switch {
+			case err != nil:
+			case word.Tag == vm.TagFloat:
+				value = strconv.FormatFloat(float64(word.D.F), 'g', 6, 32)
+			case word.IsCommand():
+				value = vm.OpCode(word.Cmd.Opcode).String()
+			default:
+				value = strconv.Itoa(int(word.D.I))
+			}
+			fmt.Fprintf(&b, "%*s", tuiWordWidth, value)
+			address += vm.WORD_SIZE
+		}
+		lines[row] = fit(b.String(), t.width)
+	}
+	return lines
+}
+
+// consoleLines возвращает последние rows строк вывода программы
+func (t *tui) consoleLines(rows int) []string {
+	text := strings.ReplaceAll(t.console.String(), "\r", "")
+	all := strings.Split(text, "\n")
+	if len(all) > rows {
+		all = all[len(all)-rows:]
+	}
+	lines := make([]string, rows)
+	for i := range lines {
+		if i < len(all) {
+			lines[i] = fit(" "+all[i], t.width)
+		}
+	}
+	return lines
+}
+
+// fit обрезает или дополняет пробелами строку до width символов
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	n := utf8.RuneCountInString(s)
+	if n > width {
+		runes := []rune(s)
+		return string(runes[:width])
+	}
+	return s + strings.Repeat(" ", width-n)
+}
@@ -0,0 +1,698 @@
+package vm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Ассемблер переводит текст с мнемониками в слова памяти. Синтаксис:
+//
+//	; комментарий (также #)
+//	.org 0x100          ; текущий адрес
+//	start:              ; метка — адрес следующего слова
+//	    IADD [x], [y]   ; операнды через запятую: [expr] — адрес в памяти
+//	    LOAD a1, [x]    ; a1/a2 (r0/r1) и f0-f3 — номера регистров
+//	    IOUT [x + a1]   ; адрес, модифицированный регистром a1 (BB=01)
+//	    IOUT [a2]       ; адрес из регистра (BB=10)
+//	    JZ start        ; выражения могут ссылаться на метки и константы
+//	x:  .int 10, -3     ; целые числа (выражения)
+//	y:  .float 2.5      ; вещественные числа
+//	msg: .string "hi\n" ; упакованная строка (см. PackString)
+//	buf: .space 4       ; резерв из 4 слов
+//	N   .equ 8          ; константа (также .equ N, 8)
+//	.entry start        ; точка входа; по умолчанию — первая команда
+//
+// Операнды записываются в поля Address1 и Address2 в порядке следования.
+// Выражения вычисляются тем же мини-языком, что и выражения наблюдения (ParseExpr).
+//
+// Объектный режим (AssembleObject) дополнительно понимает директивы .text и .data,
+// переключающие секцию, и .global name[, name...], экспортирующую метки. Метки
+// отсчитываются от начала своей секции, .org недопустима, а неопределенные символы
+// считаются внешними и разрешаются компоновщиком (см. Link).
+
+// ImageWord — слово образа памяти по заданному адресу
+type ImageWord struct {
+	Address int  // Адрес слова
+	Word    Word // Содержимое слова
+}
+
+// Image — результат ассемблирования
+type Image struct {
+	Entry  uint16           // Точка входа
+	Words  []ImageWord      // Слова в порядке следования в исходном тексте
+	Labels map[string]int64 // Метки и константы
+	Debug  *DebugInfo       // Строки исходного текста слов и метки
+}
+
+// Секции объектного файла
+const (
+	SectionText = "text" // Команды
+	SectionData = "data" // Данные
+)
+
+// asmStatement — разобранная строка исходного текста
+type asmStatement struct {
+	lineNumber int      // Номер строки
+	line       string   // Исходная строка
+	section    string   // Секция (только в объектном режиме)
+	address    int      // Адрес первого слова (смещение в секции в объектном режиме)
+	name       string   // Мнемоника или директива
+	operands   []string // Операнды
+}
+
+// asmSymbol — метка или константа
+type asmSymbol struct {
+	value   int64  // Адрес, смещение в секции или значение константы
+	section string // Секция метки; "" — абсолютное значение
+}
+
+// Регистры, допустимые в операндах
+var asmRegisters = map[string]int64{
+	"a1": 0, "a2": 1, "r0": 0, "r1": 1,
+	"f0": 0, "f1": 1, "f2": 2, "f3": 3,
+}
+
+// asmOperandKind — вид операнда команды для проверки при ассемблировании
+type asmOperandKind int
+
+const (
+	operandValue         asmOperandKind = iota // Адрес, число или метка; регистр недопустим
+	operandRegister                            // Целочисленный регистр a1 или a2
+	operandFloatRegister                       // Вещественный регистр f0-f3
+)
+
+// asmRegisterOperands задает позиции операндов-регистров; остальные операнды всех
+// команд — адреса, числа или метки
+var asmRegisterOperands = map[OpCode][2]asmOperandKind{
+	LOAD:   {operandRegister, operandValue},
+	STORE:  {operandValue, operandRegister},
+	ADDR:   {operandRegister, operandRegister},
+	SUBR:   {operandRegister, operandRegister},
+	MOVR:   {operandRegister, operandRegister},
+	PUSHR:  {operandRegister},
+	POPR:   {operandRegister},
+	INCR:   {operandRegister},
+	DECR:   {operandRegister},
+	NEGR:   {operandRegister},
+	TIME:   {operandRegister},
+	CPUID:  {operandRegister},
+	XCHG:   {operandRegister, operandValue},
+	XADD:   {operandRegister, operandValue},
+	FLOAD:  {operandFloatRegister, operandValue},
+	FSTORE: {operandValue, operandFloatRegister},
+	FADDR:  {operandFloatRegister, operandFloatRegister},
+	FSUBR:  {operandFloatRegister, operandFloatRegister},
+	FMULR:  {operandFloatRegister, operandFloatRegister},
+	FDIVR:  {operandFloatRegister, operandFloatRegister},
+	FMOVR:  {operandFloatRegister, operandFloatRegister},
+}
+
+// checkOperandKinds проверяет, что регистры указаны там и только там, где команда
+// op ожидает регистр
+func checkOperandKinds(op OpCode, operands []string) error {
+	for i, operand := range operands {
+		name := strings.ToLower(operand)
+		_, isRegister := asmRegisters[name]
+		isFloat := isRegister && strings.HasPrefix(name, "f")
+		switch asmRegisterOperands[op][i] {
+		case operandRegister:
+			if !isRegister || isFloat {
+				return fmt.Errorf("%s operand %d must be register a1 or a2, got %q", op, i+1, operand)
+			}
+		case operandFloatRegister:
+			if !isFloat {
+				return fmt.Errorf("%s operand %d must be register f0-f3, got %q", op, i+1, operand)
+			}
+		default:
+			if isRegister {
+				return fmt.Errorf("%s operand %d must be an address or value, got register %s", op, i+1, operand)
+			}
+		}
+	}
+	return nil
+}
+
+// Операнд вида [expr + a1] или [a1 + expr]
+var (
+	asmIndexedSuffix = regexp.MustCompile(`(?i)^(.+?)\s*\+\s*(a1|r0)$`)
+	asmIndexedPrefix = regexp.MustCompile(`(?i)^(a1|r0)\s*\+\s*(.+)$`)
+)
+
+// Сдвиг базы секции или внешнего символа при проверке перемещаемости выражения
+const relocationProbe = 1 << 20
+
+// assembler хранит состояние ассемблирования одного исходного текста
+type assembler struct {
+	relocatable bool                 // Объектный режим
+	symbols     map[string]asmSymbol // Метки и константы
+	globals     []string             // Символы, объявленные .global
+	labels      []string             // Метки в порядке определения (без констант .equ)
+	section     string               // Текущая секция
+	counters    map[string]int       // Счетчики адресов секций
+	statements  []asmStatement       // Строки, порождающие слова
+	entry       asmStatement         // Строка директивы .entry
+}
+
+// asmEnv предоставляет выражениям значения меток. В объектном режиме база секции
+// или внешний символ из shift сдвигается, чтобы выяснить, зависит ли от них значение.
+type asmEnv struct {
+	a       *assembler
+	shift   map[string]int64 // Сдвиг секций (".text", ".data") и внешних символов
+	externs map[string]bool  // Встреченные неопределенные символы (объектный режим)
+}
+
+// Identifier возвращает значение метки или константы
+func (env asmEnv) Identifier(name string) (int64, error) {
+	if sym, ok := env.a.symbols[name]; ok {
+		if sym.section == "" {
+			return sym.value, nil // Абсолютная константа
+		}
+		return sym.value + env.shift["."+sym.section], nil
+	}
+	if env.a.relocatable && env.externs != nil {
+		env.externs[name] = true
+		return env.shift[name], nil // Внешний символ разрешит компоновщик
+	}
+	return 0, fmt.Errorf("undefined symbol %q", name)
+}
+
+// Memory отклоняет чтение памяти: при ассемблировании память еще не заполнена
+func (env asmEnv) Memory(address int64) (int64, error) {
+	return 0, fmt.Errorf("memory references are not allowed in assembler expressions")
+}
+
+// AssembleFile ассемблирует файл с исходным текстом
+func AssembleFile(filename string) (*Image, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file: %v", err)
+	}
+	defer file.Close()
+	img, err := Assemble(file)
+	if err != nil {
+		return nil, err
+	}
+	img.Debug.setFile(filename)
+	return img, nil
+}
+
+// Assemble ассемблирует исходный текст в образ с абсолютными адресами
+func Assemble(r io.Reader) (*Image, error) {
+	a := &assembler{symbols: make(map[string]asmSymbol)}
+	if err := a.scan(r); err != nil {
+		return nil, err
+	}
+	img := &Image{Labels: make(map[string]int64), Debug: &DebugInfo{}}
+	for name, sym := range a.symbols {
+		img.Labels[name] = sym.value
+	}
+	for _, name := range a.labels {
+		img.Debug.Labels = append(img.Debug.Labels, DebugLabel{Name: name, Address: int(a.symbols[name].value)})
+	}
+	err := a.encode(func(st asmStatement, i int, word Word, relocs []Relocation) {
+		address := st.address + i*WORD_SIZE
+		img.Words = append(img.Words, ImageWord{Address: address, Word: word})
+		img.Debug.Lines = append(img.Debug.Lines, DebugLine{Address: address, Line: st.lineNumber})
+	})
+	if err != nil {
+		return nil, err
+	}
+	img.Debug.sort()
+
+	// Точка входа
+	switch {
+	case a.entry.name != "":
+		value, _, err := a.resolve(a.entry.operands[0])
+		if err == nil && (value < 0 || value > 0xFFFF) {
+			err = fmt.Errorf("entry point 0x%X is out of range", value)
+		}
+		if err != nil {
+			return nil, a.entry.fail(err)
+		}
+		img.Entry = uint16(value)
+	default:
+		for _, st := range a.statements {
+			if !strings.HasPrefix(st.name, ".") {
+				img.Entry = uint16(st.address) // Первая команда
+				return img, nil
+			}
+		}
+		return nil, fmt.Errorf("program has no instructions and no .entry directive")
+	}
+	return img, nil
+}
+
+// AssembleObject ассемблирует исходный текст в перемещаемый объектный модуль
+func AssembleObject(r io.Reader) (*Object, error) {
+	a := &assembler{relocatable: true, symbols: make(map[string]asmSymbol), section: SectionText}
+	if err := a.scan(r); err != nil {
+		return nil, err
+	}
+	obj := &Object{
+		Text: make([]Word, a.counters[SectionText]/WORD_SIZE),
+		Data: make([]Word, a.counters[SectionData]/WORD_SIZE),
+	}
+	err := a.encode(func(st asmStatement, i int, word Word, relocs []Relocation) {
+		index := st.address/WORD_SIZE + i
+		if st.section == SectionText {
+			obj.Text[index] = word
+		} else {
+			obj.Data[index] = word
+		}
+		for _, r := range relocs {
+			r.Section, r.Offset = st.section, st.address+i*WORD_SIZE
+			obj.Relocations = append(obj.Relocations, r)
+		}
+		obj.Lines = append(obj.Lines, ObjectLine{Section: st.section, Offset: st.address + i*WORD_SIZE, Line: st.lineNumber})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exported := make(map[string]bool)
+	for _, name := range a.globals {
+		exported[name] = true
+	}
+	for name, sym := range a.symbols {
+		obj.Symbols = append(obj.Symbols, ObjectSymbol{Name: name, Section: sym.section, Value: sym.value, Global: exported[name]})
+	}
+	sortSymbols(obj.Symbols)
+
+	if a.entry.name != "" {
+		name := a.entry.operands[0]
+		if _, ok := a.symbols[name]; !ok && !isIdentifier(name) {
+			return nil, a.entry.fail(fmt.Errorf(".entry requires a symbol name in object files"))
+		}
+		obj.Entry = name
+	}
+	return obj, nil
+}
+
+// fail создает ошибку с номером и текстом строки
+func (st asmStatement) fail(err error) error {
+	return &CommandError{LineNumber: st.lineNumber, Line: st.line, Message: err.Error()}
+}
+
+// scan выполняет первый проход: назначает адреса меткам и запоминает строки
+func (a *assembler) scan(r io.Reader) error {
+	a.counters = make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		text := strings.TrimSpace(stripAsmComment(line))
+		fail := func(format string, args ...interface{}) error {
+			return &CommandError{LineNumber: lineNumber, Line: line, Message: fmt.Sprintf(format, args...)}
+		}
+
+		// Метки в начале строки
+		for {
+			colon := strings.Index(text, ":")
+			if colon < 0 || strings.ContainsAny(text[:colon], " \t\"[") {
+				break
+			}
+			label := text[:colon]
+			if err := a.define(label, int64(a.counters[a.section]), a.section); err != nil {
+				return fail("%v", err)
+			}
+			a.labels = append(a.labels, label)
+			text = strings.TrimSpace(text[colon+1:])
+		}
+		if text == "" {
+			continue
+		}
+
+		name, rest := splitFirst(text)
+		// Форма "NAME .equ expr"
+		if next, value := splitFirst(rest); strings.EqualFold(next, ".equ") {
+			rest = name + ", " + value
+			name = next
+		}
+		st := asmStatement{lineNumber: lineNumber, line: line, section: a.section, address: a.counters[a.section], name: strings.ToLower(name)}
+		if rest != "" {
+			st.operands = splitOperands(rest)
+		}
+
+		switch st.name {
+		case ".text", ".data":
+			if a.relocatable {
+				a.section = st.name[1:] // В абсолютном режиме секции не разделяются
+			}
+			continue
+		case ".global":
+			if !a.relocatable {
+				continue // В абсолютном режиме все символы видны в Image.Labels
+			}
+			if len(st.operands) == 0 {
+				return fail(".global requires at least one symbol name")
+			}
+			a.globals = append(a.globals, st.operands...)
+			continue
+		case ".org":
+			if a.relocatable {
+				return fail(".org is not allowed in object files")
+			}
+			value, err := a.absolute(st.operands, 0)
+			if err != nil {
+				return fail("%v", err)
+			}
+			if value < 0 {
+				return fail("negative origin %d", value)
+			}
+			a.counters[a.section] = int(value)
+			continue
+		case ".equ":
+			if len(st.operands) != 2 {
+				return fail(".equ requires a name and a value")
+			}
+			value, err := a.absolute(st.operands, 1)
+			if err != nil {
+				return fail("%v", err)
+			}
+			if err := a.define(st.operands[0], value, ""); err != nil {
+				return fail("%v", err)
+			}
+			continue
+		case ".entry":
+			if len(st.operands) != 1 {
+				return fail(".entry requires a value")
+			}
+			a.entry = st
+			continue
+		case ".int", ".float":
+			if len(st.operands) == 0 {
+				return fail("%s requires at least one value", st.name)
+			}
+			a.counters[a.section] += len(st.operands) * WORD_SIZE
+		case ".string":
+			if len(st.operands) != 1 {
+				return fail(".string requires one quoted string")
+			}
+			text, err := strconv.Unquote(st.operands[0])
+			if err != nil {
+				return fail("invalid string format: %v", err)
+			}
+			st.operands[0] = text
+			a.counters[a.section] += len(PackString(text)) * WORD_SIZE
+		case ".space":
+			value, err := a.absolute(st.operands, 0)
+			if err != nil {
+				return fail("%v", err)
+			}
+			if value < 0 {
+				return fail("negative space size %d", value)
+			}
+			a.counters[a.section] += int(value) * WORD_SIZE
+			continue
+		default:
+			if strings.HasPrefix(st.name, ".") {
+				return fail("unknown directive %s", name)
+			}
+			op, ok := ParseOpCode(st.name)
+			if !ok {
+				return fail("unknown mnemonic %q", name)
+			}
+			if len(st.operands) > 2 {
+				return fail("%s takes at most 2 operands, got %d", strings.ToUpper(name), len(st.operands))
+			}
+			if err := checkOperandKinds(op, st.operands); err != nil {
+				return fail("%v", err)
+			}
+			a.counters[a.section] += WORD_SIZE
+		}
+		a.statements = append(a.statements, st)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading file: %v", err)
+	}
+	for _, name := range a.globals {
+		if _, ok := a.symbols[name]; !ok {
+			return fmt.Errorf("global symbol %q is not defined", name)
+		}
+	}
+	return nil
+}
+
+// encode выполняет второй проход: кодирует команды и данные и передает слова emit
+// вместе с перемещением, если значение слова зависит от адреса секции или внешнего символа
+func (a *assembler) encode(emit func(st asmStatement, i int, word Word, relocs []Relocation)) error {
+	for _, st := range a.statements {
+		if err := a.encodeStatement(st, emit); err != nil {
+			return st.fail(err)
+		}
+	}
+	return nil
+}
+
+// encodeStatement кодирует команду или данные одной строки
+func (a *assembler) encodeStatement(st asmStatement, emit func(st asmStatement, i int, word Word, relocs []Relocation)) error {
+	switch st.name {
+	case ".int":
+		for i, operand := range st.operands {
+			value, target, err := a.resolve(operand)
+			if err != nil {
+				return err
+			}
+			if target != "" {
+				emit(st, i, IntWord(0), []Relocation{{Field: RelocWord, Symbol: target, Addend: value}})
+				continue
+			}
+			if value < -1<<31 || value > 1<<32-1 {
+				return fmt.Errorf("integer %d does not fit in 32 bits", value)
+			}
+			emit(st, i, IntWord(int32(value)), nil)
+		}
+	case ".float":
+		for i, operand := range st.operands {
+			value, err := strconv.ParseFloat(operand, 32)
+			if err != nil {
+				return fmt.Errorf("invalid float format: %v", err)
+			}
+			emit(st, i, FloatWord(float32(value)), nil)
+		}
+	case ".string":
+		for i, word := range PackString(st.operands[0]) {
+			emit(st, i, word, nil)
+		}
+	default:
+		op, _ := ParseOpCode(st.name)
+		cmd := CommandData{Opcode: uint8(op)}
+		var relocs []Relocation
+		for i, operand := range st.operands {
+			value, bb, target, err := a.encodeOperand(operand)
+			if err != nil {
+				return err
+			}
+			field := RelocAddress1
+			if i == 0 {
+				cmd.Address1 = uint16(value)
+			} else {
+				cmd.Address2, field = uint16(value), RelocAddress2
+			}
+			if bb != 0 {
+				if cmd.BB != 0 && cmd.BB != bb {
+					return fmt.Errorf("operands use conflicting addressing modes")
+				}
+				cmd.BB = bb
+			}
+			if target != "" {
+				relocs = append(relocs, Relocation{Field: field, Symbol: target, Addend: value})
+			}
+		}
+		emit(st, 0, CommandWord(cmd), relocs)
+	}
+	return nil
+}
+
+// encodeOperand кодирует операнд команды в значение поля адреса и режим адресации BB.
+// Для перемещаемого операнда target называет секцию или внешний символ, а значение
+// содержит слагаемое, которое компоновщик прибавит к их адресу.
+func (a *assembler) encodeOperand(operand string) (int64, uint8, string, error) {
+	var bb uint8
+	text := operand
+	if strings.HasPrefix(text, "[") {
+		if !strings.HasSuffix(text, "]") {
+			return 0, 0, "", fmt.Errorf("missing ']' in operand %q", operand)
+		}
+		text = strings.TrimSpace(text[1 : len(text)-1])
+		if m := asmIndexedSuffix.FindStringSubmatch(text); m != nil {
+			text, bb = m[1], 0x01 // Адрес модифицируется регистром a1
+		} else if m := asmIndexedPrefix.FindStringSubmatch(text); m != nil {
+			text, bb = m[2], 0x01
+		} else if index, ok := asmRegisters[strings.ToLower(text)]; ok && index < NUM_REGISTERS {
+			return index, 0x02, "", nil // Адрес берется из регистра
+		}
+	} else if index, ok := asmRegisters[strings.ToLower(text)]; ok {
+		return index, 0, "", nil // Номер регистра
+	}
+	value, target, err := a.resolve(text)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	if value < -0x8000 || value > 0xFFFF {
+		return 0, 0, "", fmt.Errorf("operand value %d does not fit in 16 bits", value)
+	}
+	return value, bb, target, nil
+}
+
+// resolve вычисляет выражение. В объектном режиме значение может зависеть от адреса
+// одной секции или одного внешнего символа с коэффициентом 1: тогда target называет
+// их (секция — с точкой: ".text", ".data"), а value — слагаемое.
+func (a *assembler) resolve(text string) (value int64, target string, err error) {
+	expr, err := ParseExpr(text)
+	if err != nil {
+		return 0, "", err
+	}
+	externs := make(map[string]bool)
+	if value, err = expr.Eval(asmEnv{a: a, externs: externs}); err != nil || !a.relocatable {
+		return value, "", err
+	}
+	targets := []string{"." + SectionText, "." + SectionData}
+	for name := range externs {
+		targets = append(targets, name)
+	}
+	for _, t := range targets {
+		shifted, err := expr.Eval(asmEnv{a: a, shift: map[string]int64{t: relocationProbe}, externs: externs})
+		if err != nil {
+			return 0, "", err
+		}
+		switch shifted - value {
+		case 0:
+			continue
+		case relocationProbe:
+			if target != "" {
+				return 0, "", fmt.Errorf("expression %q refers to more than one relocatable symbol", text)
+			}
+			target = t
+		default:
+			return 0, "", fmt.Errorf("expression %q is not relocatable", text)
+		}
+	}
+	return value, target, nil
+}
+
+// absolute вычисляет операнд с номером i, который не должен зависеть от адресов секций
+func (a *assembler) absolute(operands []string, i int) (int64, error) {
+	if i >= len(operands) {
+		return 0, fmt.Errorf("missing operand")
+	}
+	value, target, err := a.resolve(operands[i])
+	if err == nil && target != "" {
+		err = fmt.Errorf("expression %q must be absolute", operands[i])
+	}
+	return value, err
+}
+
+// define добавляет метку или константу
+func (a *assembler) define(name string, value int64, section string) error {
+	if !isIdentifier(name) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid symbol name %q", name)
+	}
+	if _, ok := asmRegisters[strings.ToLower(name)]; ok {
+		return fmt.Errorf("symbol name %q is reserved for a register", name)
+	}
+	if _, ok := a.symbols[name]; ok {
+		return fmt.Errorf("symbol %q is already defined", name)
+	}
+	a.symbols[name] = asmSymbol{value: value, section: section}
+	return nil
+}
+
+// isIdentifier проверяет, является ли строка именем символа
+func isIdentifier(name string) bool {
+	if name == "" || !isIdentStart(rune(name[0])) {
+		return false
+	}
+	for _, c := range name {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_' && c != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// stripAsmComment удаляет комментарий, начинающийся с ';' или '#' вне строки в кавычках
+func stripAsmComment(line string) string {
+	inString := false
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && inString:
+			i++ // Пропускаем экранированный символ
+		case c == '"':
+			inString = !inString
+		case (c == ';' || c == '#') && !inString:
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// splitFirst отделяет первое слово строки от остатка
+func splitFirst(text string) (string, string) {
+	text = strings.TrimSpace(text)
+	if i := strings.IndexAny(text, " \t"); i >= 0 {
+		return text[:i], strings.TrimSpace(text[i+1:])
+	}
+	return text, ""
+}
+
+// splitOperands разделяет операнды по запятым вне скобок и строк
+func splitOperands(text string) []string {
+	var operands []string
+	depth, start, inString := 0, 0, false
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '\\' && inString:
+			i++
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			operands = append(operands, strings.TrimSpace(text[start:i]))
+			start = i + 1
+		}
+	}
+	return append(operands, strings.TrimSpace(text[start:]))
+}
+
+// Load записывает образ в память: команды — в code, данные — в data
+func (img *Image) Load(code, data *Memory) error {
+	for _, w := range img.Words {
+		mem := data
+		if w.Word.IsCommand() {
+			mem = code
+		}
+		if err := mem.WriteWord(w.Address, w.Word); err != nil {
+			return fmt.Errorf("failed to load word at 0x%X: %w", w.Address, err)
+		}
+	}
+	code.log.Logf(SubsystemLoader, LogInfo, "Loaded image: %d words, entry 0x%X", len(img.Words), img.Entry)
+	return nil
+}
+
+// WriteLoaderFormat записывает образ в текстовом формате загрузчика (a/i/r/k/x/e/s)
+func (img *Image) WriteLoaderFormat(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	next := -1 // Адрес, по которому загрузчик запишет следующее слово
+	for _, iw := range img.Words {
+		if iw.Address != next {
+			fmt.Fprintf(bw, "a %04x\n", iw.Address)
+		}
+		fmt.Fprintln(bw, encodeWord(iw.Word))
+		next = iw.Address + WORD_SIZE
+	}
+	fmt.Fprintf(bw, "x %08x\n", Checksum(img.Words)) // Загрузчик проверит слова по контрольной сумме
+	fmt.Fprintf(bw, "e %04x\ns\n", img.Entry)
+	return bw.Flush()
+}
@@ -0,0 +1,62 @@
+package vm
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// updateWord атомарно читает и изменяет слово по адресу address: update получает
+// текущее значение и возвращает новое и признак записи. Регион удерживается на время
+// всей операции, поэтому ни другое ядро, ни хост, работающий с разделяемым окном, не
+// могут вклиниться между чтением и записью. Возвращается прежнее значение слова.
+// Окна устройств атомарных операций не поддерживают.
+func (m *Memory) updateWord(address int, update func(old Word) (Word, bool)) (Word, error) {
+	if err := m.checkAlignment("write", address); err != nil {
+		m.countError(-1, WORD_SIZE) // Физический адрес еще не известен
+		return Word{}, err
+	}
+	m.heatmap.countRead(address)
+	m.heatmap.countWrite(address)
+	address, err := m.translate("write", address, WORD_SIZE, PermWrite)
+	if err != nil {
+		m.countError(-1, WORD_SIZE)
+		return Word{}, err
+	}
+	for _, need := range []Permission{PermRead, PermWrite} {
+		if err := m.checkPermission("write", address, WORD_SIZE, need); err != nil {
+			m.countError(address, WORD_SIZE) // Увеличиваем счетчик ошибок
+			return Word{}, err
+		}
+	}
+	if err := m.checkRange("write", address, WORD_SIZE); err != nil {
+		m.countError(address, WORD_SIZE)
+		return Word{}, err
+	}
+	r := m.findRegion(address, WORD_SIZE)
+	switch {
+	case r == nil:
+		err = &MemoryError{Operation: "write", Address: address, Message: "no region mapped"}
+	case r.device != nil:
+		err = &MemoryError{Operation: "write", Address: address, Message: fmt.Sprintf("device %q does not support atomic operations", r.Name)}
+	case r.Kind == RegionROM:
+		err = &MemoryError{Operation: "write", Address: address, Message: fmt.Sprintf("region %q is read-only", r.Name)}
+	}
+	if err != nil {
+		m.countError(address, WORD_SIZE)
+		return Word{}, err
+	}
+
+	r.mu.Lock()
+	data := r.data[address-r.base : address-r.base+WORD_SIZE]
+	old := wordFromBits(binary.LittleEndian.Uint64(data))
+	if word, write := update(old); write {
+		if m.journal != nil {
+			m.journal(m, address, append([]byte(nil), data...)) // Прежние байты для отмены инструкции (StepBack)
+		}
+		binary.LittleEndian.PutUint64(data, wordBits(word))
+	}
+	r.mu.Unlock()
+	m.countAccess(accessWordRead, address, WORD_SIZE)
+	m.countAccess(accessWordWrite, address, WORD_SIZE)
+	return old, nil
+}
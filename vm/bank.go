@@ -0,0 +1,105 @@
+package vm
+
+import "fmt"
+
+// Окно переключаемых банков памяти по умолчанию
+const (
+	BANK_WINDOW_BASE = 0x8000 // Начальный адрес окна
+	BANK_WINDOW_SIZE = 0x4000 // Размер окна и каждого банка в байтах
+)
+
+// Регистры контроллера банков (смещения относительно начала окна устройства)
+const (
+	BANK_SELECT = 0 * WORD_SIZE // Номер банка, видимого в окне
+	BANK_COUNT  = 1 * WORD_SIZE // Число банков (только чтение)
+
+	BANK_CTRL_SIZE = 2 * WORD_SIZE // Размер окна контроллера в байтах
+	BANK_CTRL_BASE = 0xF060        // Адрес окна контроллера по умолчанию
+)
+
+// AddBanks создает count банков по size байт, видимых через окно [base, base+size).
+// Адресное пространство остается 16-битным, а общий объем памяти растет на count*size байт;
+// после создания в окне виден банк 0.
+func (m *Memory) AddBanks(base, size, count int) error {
+	if m.bankWindow != nil {
+		return fmt.Errorf("memory banks are already configured")
+	}
+	if count <= 0 || size <= 0 {
+		return fmt.Errorf("invalid bank configuration: %d bank(s) of %d bytes", count, size)
+	}
+	banks := make([][]byte, count)
+	for i := range banks {
+		banks[i] = make([]byte, size)
+	}
+	r, err := m.AddRegion("banks", RegionRAM, base, banks[0])
+	if err != nil {
+		return err
+	}
+	m.banks = banks
+	m.bankWindow = r
+	m.bank = 0
+	return nil
+}
+
+// SelectBank делает банк n видимым в окне банков
+func (m *Memory) SelectBank(n int) error {
+	if m.bankWindow == nil {
+		return fmt.Errorf("memory has no banks")
+	}
+	if n < 0 || n >= len(m.banks) {
+		return fmt.Errorf("bank %d is out of range [0-%d]", n, len(m.banks)-1)
+	}
+	m.bankWindow.mu.Lock()
+	m.bankWindow.data = m.banks[n] // Окно указывает на хранилище выбранного банка
+	m.bankWindow.mu.Unlock()
+	m.bank = n
+	m.log.Logf(SubsystemMemory, LogDebug, "Selected bank %d", n)
+	return nil
+}
+
+// Bank возвращает номер банка, видимого в окне
+func (m *Memory) Bank() int {
+	return m.bank
+}
+
+// BankCount возвращает число банков; 0 — банки не настроены
+func (m *Memory) BankCount() int {
+	return len(m.banks)
+}
+
+// BankController — регистр выбора банка, отображаемый в память
+type BankController struct {
+	mem *Memory // Память с банками
+}
+
+// Read читает регистр контроллера банков
+func (b *BankController) Read(addr int) (Word, error) {
+	switch addr {
+	case BANK_SELECT:
+		return IntWord(int32(b.mem.Bank())), nil
+	case BANK_COUNT:
+		return IntWord(int32(b.mem.BankCount())), nil
+	}
+	return Word{}, fmt.Errorf("bank register 0x%X does not exist", addr)
+}
+
+// Write записывает регистр выбора банка
+func (b *BankController) Write(addr int, word Word) error {
+	if addr != BANK_SELECT {
+		return fmt.Errorf("bank register 0x%X is not writable", addr)
+	}
+	return b.mem.SelectBank(int(word.D.I))
+}
+
+// MapBanks создает count банков в окне памяти данных по умолчанию и отображает
+// регистр выбора банка по адресу base
+func (p *Processor) MapBanks(base, count int) (*BankController, error) {
+	if err := p.memory.AddBanks(BANK_WINDOW_BASE, BANK_WINDOW_SIZE, count); err != nil {
+		return nil, err
+	}
+	b := &BankController{mem: p.memory}
+	if _, err := p.memory.MapDevice("bank", base, BANK_CTRL_SIZE, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
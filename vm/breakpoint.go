@@ -0,0 +1,106 @@
+package vm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BreakpointHit сообщает, что Run остановился перед командой с точкой останова.
+// Программа не завершена: повторный Run продолжает выполнение с этой команды.
+type BreakpointHit struct {
+	Address   uint16 // Адрес команды с точкой останова
+	Condition string // Условие точки останова; "" — безусловная
+}
+
+// breakpoint — точка останова с необязательным условием
+type breakpoint struct {
+	source    string // Текст условия
+	condition Expr   // Разобранное условие; nil — безусловная точка
+}
+
+// Error реализует интерфейс error для BreakpointHit
+func (b *BreakpointHit) Error() string {
+	return fmt.Sprintf("breakpoint at 0x%X", b.Address)
+}
+
+// AddBreakpoint устанавливает точку останова на команде по адресу address
+func (p *Processor) AddBreakpoint(address uint16) error {
+	return p.AddConditionalBreakpoint(address, "")
+}
+
+// AddConditionalBreakpoint устанавливает точку останова, которая срабатывает, только
+// если условие (выражение над регистрами, флагами и памятью, например
+// "R1 == 5 && [0x40] > 100") отлично от нуля. Пустое условие — безусловная точка.
+// Окна устройств в условии не читаются: их регистры могут измениться от чтения.
+func (p *Processor) AddConditionalBreakpoint(address uint16, condition string) error {
+	if !p.code.IsValidAddress(int(address)) {
+		return fmt.Errorf("breakpoint address 0x%X is out of range", address)
+	}
+	b := &breakpoint{source: strings.TrimSpace(condition)}
+	if b.source != "" {
+		e, err := ParseExpr(b.source)
+		if err != nil {
+			return fmt.Errorf("invalid breakpoint condition: %v", err)
+		}
+		b.condition = e
+	}
+	if p.breakpoints == nil {
+		p.breakpoints = make(map[uint16]*breakpoint)
+	}
+	p.breakpoints[address] = b
+	return nil
+}
+
+// RemoveBreakpoint удаляет точку останова и сообщает, была ли она установлена
+func (p *Processor) RemoveBreakpoint(address uint16) bool {
+	if p.breakpoints[address] == nil {
+		return false
+	}
+	delete(p.breakpoints, address)
+	return true
+}
+
+// HasBreakpoint сообщает, установлена ли точка останова по адресу address
+func (p *Processor) HasBreakpoint(address uint16) bool {
+	return p.breakpoints[address] != nil
+}
+
+// BreakpointCondition возвращает условие точки останова; "" — безусловная
+func (p *Processor) BreakpointCondition(address uint16) string {
+	if b := p.breakpoints[address]; b != nil {
+		return b.source
+	}
+	return ""
+}
+
+// ShouldBreak сообщает, срабатывает ли точка останова по адресу address в текущем
+// состоянии. Условие проверяется перед каждой командой и читает память без побочных
+// эффектов (см. Memory.peekData), поэтому не меняет статистику, кэш и устройства.
+// Ошибка вычисления условия, в том числе чтение окна устройства, записывается в лог
+// ошибок, а точка срабатывает, чтобы ошибку можно было исследовать.
+func (p *Processor) ShouldBreak(address uint16) bool {
+	b := p.breakpoints[address]
+	if b == nil {
+		return false
+	}
+	if b.condition == nil {
+		return true
+	}
+	value, err := b.condition.Eval(processorEnv{p})
+	if err != nil {
+		p.logf(LogWarn, "Breakpoint condition at 0x%X: %v", address, err)
+		return true
+	}
+	return value != 0
+}
+
+// Breakpoints возвращает адреса точек останова по возрастанию
+func (p *Processor) Breakpoints() []uint16 {
+	addresses := make([]uint16, 0, len(p.breakpoints))
+	for address := range p.breakpoints {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i] < addresses[j] })
+	return addresses
+}
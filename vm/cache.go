@@ -0,0 +1,218 @@
+package vm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CacheConfig — параметры модели кэша данных
+type CacheConfig struct {
+	Size     int // Емкость в байтах
+	LineSize int // Размер строки в байтах
+	Ways     int // Строк в наборе (1 — прямое отображение)
+}
+
+// Sets возвращает число наборов кэша
+func (c CacheConfig) Sets() int {
+	return c.Size / (c.LineSize * c.Ways)
+}
+
+// validate проверяет, что параметры — степени двойки, строка вмещает слово, а
+// емкость — хотя бы один набор и не больше памяти данных размером memorySize
+func (c CacheConfig) validate(memorySize int) error {
+	for _, v := range []struct {
+		name  string
+		value int
+	}{{"size", c.Size}, {"line size", c.LineSize}, {"associativity", c.Ways}} {
+		if v.value <= 0 || v.value&(v.value-1) != 0 {
+			return fmt.Errorf("cache %s %d is not a power of two", v.name, v.value)
+		}
+	}
+	if c.LineSize < WORD_SIZE {
+		return fmt.Errorf("cache line size %d is smaller than a word (%d bytes)", c.LineSize, WORD_SIZE)
+	}
+	if c.Size > memorySize {
+		return fmt.Errorf("cache size %d exceeds data memory size %d", c.Size, memorySize)
+	}
+	if c.LineSize > c.Size {
+		return fmt.Errorf("cache line size %d exceeds cache size %d", c.LineSize, c.Size)
+	}
+	if c.Ways > c.Size/c.LineSize {
+		return fmt.Errorf("cache size %d is smaller than one set of %d %d-byte lines", c.Size, c.Ways, c.LineSize)
+	}
+	return nil
+}
+
+// ParseCacheConfig разбирает параметры кэша вида "size:line:ways", например
+// "1024:32:2"; без ways кэш прямого отображения. Параметры проверяются по размеру
+// памяти данных в SetCache.
+func ParseCacheConfig(spec string) (CacheConfig, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return CacheConfig{}, fmt.Errorf("invalid cache %q, expected size:line[:ways]", spec)
+	}
+	values := []int{0, 0, 1}
+	for i, part := range parts {
+		n, err := ParseNumber(strings.TrimSpace(part))
+		if err != nil {
+			return CacheConfig{}, fmt.Errorf("invalid cache %q: %v", spec, err)
+		}
+		values[i] = int(n)
+	}
+	return CacheConfig{Size: values[0], LineSize: values[1], Ways: values[2]}, nil
+}
+
+// CacheStats — счетчики модели кэша
+type CacheStats struct {
+	ReadHits    int // Чтения, нашедшие строку в кэше
+	ReadMisses  int // Чтения, загрузившие строку
+	WriteHits   int // Записи в строку, находящуюся в кэше
+	WriteMisses int // Записи, загрузившие строку
+	Evictions   int // Вытеснения действительных строк
+	Writebacks  int // Вытеснения измененных строк, требующие записи в память
+}
+
+// Hits возвращает общее число попаданий
+func (s CacheStats) Hits() int {
+	return s.ReadHits + s.WriteHits
+}
+
+// Misses возвращает общее число промахов
+func (s CacheStats) Misses() int {
+	return s.ReadMisses + s.WriteMisses
+}
+
+// HitRate возвращает долю попаданий в процентах (0, если обращений не было)
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits() + s.Misses()
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits()) * 100 / float64(total)
+}
+
+// cacheLine — строка кэша: хранится только тег, данные остаются в памяти
+type cacheLine struct {
+	tag   int
+	valid bool
+	dirty bool   // Строка изменена и при вытеснении записывается в память
+	used  uint64 // Время последнего обращения для LRU
+}
+
+// Cache — модель наборно-ассоциативного кэша данных с обратной записью, выделением
+// строки при записи и вытеснением давно не использованной строки (LRU). Кэш хранит
+// только теги и не влияет на результат программы: он лишь считает, какие обращения
+// попали бы в кэш. Учитываются чтения и записи данных программой; выборки команд,
+// окна устройств и обмен диска с памятью идут мимо кэша.
+type Cache struct {
+	config CacheConfig
+	sets   [][]cacheLine
+	clock  uint64 // Счетчик обращений для LRU
+	stats  CacheStats
+}
+
+// newCache создает пустой кэш с проверенными параметрами config
+func newCache(config CacheConfig) *Cache {
+	c := &Cache{config: config, sets: make([][]cacheLine, config.Sets())}
+	for i := range c.sets {
+		c.sets[i] = make([]cacheLine, config.Ways)
+	}
+	return c
+}
+
+// Config возвращает параметры кэша
+func (c *Cache) Config() CacheConfig {
+	return c.config
+}
+
+// Stats возвращает счетчики кэша с начала запуска
+func (c *Cache) Stats() CacheStats {
+	return c.stats
+}
+
+// reset очищает строки и счетчики
+func (c *Cache) reset() {
+	if c == nil {
+		return
+	}
+	for _, set := range c.sets {
+		clear(set)
+	}
+	c.clock = 0
+	c.stats = CacheStats{}
+}
+
+// access учитывает чтение или запись по физическому адресу address
+func (c *Cache) access(address int, write bool) {
+	c.clock++
+	line := address / c.config.LineSize
+	set := c.sets[line%len(c.sets)]
+	tag := line / len(c.sets)
+	victim := 0
+	for i := range set {
+		if set[i].valid && set[i].tag == tag {
+			set[i].used = c.clock
+			set[i].dirty = set[i].dirty || write
+			if write {
+				c.stats.WriteHits++
+			} else {
+				c.stats.ReadHits++
+			}
+			return
+		}
+		if !set[i].valid || (set[victim].valid && set[i].used < set[victim].used) {
+			victim = i // Свободная строка или давно не использованная
+		}
+	}
+	if write {
+		c.stats.WriteMisses++
+	} else {
+		c.stats.ReadMisses++
+	}
+	if set[victim].valid {
+		c.stats.Evictions++
+		if set[victim].dirty {
+			c.stats.Writebacks++
+		}
+	}
+	set[victim] = cacheLine{tag: tag, valid: true, dirty: write, used: c.clock}
+}
+
+// SetCache включает модель кэша данных с параметрами config или выключает ее (nil).
+// Кэш общий для ядер машины; строки и счетчики сбрасываются при включении и при Reset.
+func (p *Processor) SetCache(config *CacheConfig) error {
+	if config == nil {
+		p.cache = nil
+		p.memory.cache = nil
+		return nil
+	}
+	if err := config.validate(p.memory.Size()); err != nil {
+		return err
+	}
+	p.cache = newCache(*config)
+	p.memory.cache = p.cache
+	return nil
+}
+
+// Cache возвращает модель кэша или nil, если она выключена
+func (p *Processor) Cache() *Cache {
+	return p.cache
+}
+
+// WriteCacheReport выводит параметры кэша и попадания, промахи и вытеснения за запуск
+func (p *Processor) WriteCacheReport(w io.Writer) error {
+	c := p.cache
+	if c == nil {
+		return fmt.Errorf("cache is not enabled")
+	}
+	bw := bufio.NewWriter(w)
+	cfg, s := c.config, c.stats
+	fmt.Fprintf(bw, "Cache: %d bytes, %d-byte lines, %d-way, %d set(s)\n", cfg.Size, cfg.LineSize, cfg.Ways, cfg.Sets())
+	fmt.Fprintf(bw, "  reads      %d hits, %d misses\n", s.ReadHits, s.ReadMisses)
+	fmt.Fprintf(bw, "  writes     %d hits, %d misses\n", s.WriteHits, s.WriteMisses)
+	fmt.Fprintf(bw, "  hit rate   %.2f%% (%d of %d accesses)\n", s.HitRate(), s.Hits(), s.Hits()+s.Misses())
+	fmt.Fprintf(bw, "  evictions  %d (%d dirty, written back)\n", s.Evictions, s.Writebacks)
+	return bw.Flush()
+}
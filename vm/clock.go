@@ -0,0 +1,49 @@
+package vm
+
+import "time"
+
+// Единицы времени команды TIME (поле Address2)
+const (
+	TIME_INSTRUCTIONS = 0 // Число инструкций, выполненных с запуска программы
+	TIME_MILLIS       = 1 // Миллисекунды, прошедшие с запуска программы
+)
+
+// Скорость виртуальных часов
+const VIRTUAL_INSTRUCTIONS_PER_MS = 1000 // Одна виртуальная миллисекунда — тысяча инструкций
+
+// SetVirtualClock включает детерминированные виртуальные часы: время в миллисекундах
+// вычисляется по числу выполненных инструкций и не зависит от скорости хоста
+func (p *Processor) SetVirtualClock(enabled bool) {
+	p.virtualClock = enabled
+}
+
+// VirtualClock сообщает, используются ли виртуальные часы
+func (p *Processor) VirtualClock() bool {
+	return p.virtualClock
+}
+
+// ElapsedMillis возвращает время в миллисекундах, прошедшее с запуска программы
+func (p *Processor) ElapsedMillis() int64 {
+	if p.virtualClock {
+		return int64(p.usage.Instructions/VIRTUAL_INSTRUCTIONS_PER_MS) + p.sleptMillis
+	}
+	return time.Since(p.startTime).Milliseconds()
+}
+
+// sleep приостанавливает выполнение на ms миллисекунд. Виртуальные часы просто
+// переводятся вперед; по часам хоста горутина ждет, не занимая процессор, и
+// просыпается раньше, если хост доставил событие.
+func (p *Processor) sleep(ms int64) error {
+	if p.virtualClock {
+		p.sleptMillis += ms
+		return nil
+	}
+	timer := time.NewTimer(time.Duration(ms) * time.Millisecond)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case ev := <-p.events:
+		return p.acceptEvent(ev) // Прерывание будет доставлено перед следующей командой
+	}
+}
@@ -0,0 +1,3247 @@
+package vm
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+type Command interface {
+	Execute(*Processor) error
+}
+
+func calculateAddress(p *Processor, bb uint8, address uint16, regIndex uint8) (uint16, error) {
+	// Формат BB:
+	// Бит 0: Флаг модификации адреса
+	// Бит 1: Флаг режима регистра
+
+	effectiveAddr := address // Изначально эффективный адрес равен переданному адресу
+
+	if bb&0x02 != 0 { // Если установлен флаг режима регистра
+		regValue, err := p.GetRegister(regIndex) // Получаем значение регистра по индексу
+		if err != nil {
+			return 0, err // Возвращаем ошибку, если не удалось получить значение регистра
+		}
+		if bb&0x01 != 0 { // Если установлен флаг модификации адреса
+			effectiveAddr = uint16(int32(address) + regValue) // Модифицируем адрес с использованием значения регистра
+		} else {
+			effectiveAddr = uint16(regValue) // Устанавливаем эффективный адрес равным значению регистра
+		}
+	} else if bb&0x01 != 0 { // Если установлен флаг модификации адреса без использования регистра
+		regValue, err := p.GetRegister(0) // Используем R0 для обратной совместимости
+		if err != nil {
+			return 0, err // Возвращаем ошибку, если не удалось получить значение регистра R0
+		}
+		effectiveAddr = uint16(int32(address) + regValue) // Модифицируем адрес с использованием значения регистра R0
+	}
+
+	return effectiveAddr, nil // Возвращаем эффективный адрес и nil (без ошибок)
+}
+
+// Флаг поля Address2 команд перехода: Address1 содержит знаковое смещение относительно IP
+const JUMP_RELATIVE = 0x01
+
+// calculateJumpTarget вычисляет адрес перехода для команд GO, JZ, JG, JL, JNE, JGE, JLE и CALL.
+// В относительном режиме Address1 интерпретируется как знаковое смещение от адреса
+// текущей команды, что позволяет загружать программу по любому базовому адресу.
+func calculateJumpTarget(p *Processor, c CommandData) (uint16, error) {
+	if c.Address2&JUMP_RELATIVE != 0 {
+		offset := signExtendAddress1(c.Address1)     // Восстанавливаем знак смещения
+		return uint16(int32(p.psw.IP) + offset), nil // Адрес перехода отсчитывается от текущего IP
+	}
+	return calculateAddress(p, c.BB, c.Address1, 0) // Абсолютный адрес с учетом режима BB
+}
+
+// signExtendAddress1 расширяет знак смещения, закодированного в поле Address1
+func signExtendAddress1(value uint16) int32 {
+	shift := 32 - ADDRESS1_BITS
+	return int32(uint32(value)<<shift) >> shift
+}
+
+// Jump реализация команды GO (безусловный переход)
+type Jump struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewJump создает новый экземпляр Jump с заданными параметрами
+func NewJump(bb uint8, addr1, addr2 uint16) *Jump {
+	return &Jump{CommandData{
+		Opcode:   uint8(GO), // Устанавливаем код операции для Jump
+		BB:       bb,        // Устанавливаем значение BB
+		Address1: addr1,     // Устанавливаем адрес перехода
+		Address2: addr2,     // Флаг относительного перехода JUMP_RELATIVE
+	}}
+}
+
+// Execute выполняет команду Jump
+func (j *Jump) Execute(p *Processor) error {
+	effectiveAddr, err := calculateJumpTarget(p, j.CommandData) // Вычисляем адрес перехода
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+	p.jumpTo(effectiveAddr)                                          // Обновляем указатель команд (IP) процессора на эффективный адрес
+	p.logf(LogDebug, "Jump: Jumping to address 0x%X", effectiveAddr) // Логируем информацию о переходе
+	return nil                                                       // Возвращаем nil (без ошибок)
+}
+
+// JumpZero реализация команды JumpZero
+type JumpZero struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewJumpZero создает новый экземпляр JumpZero с заданными параметрами
+func NewJumpZero(bb uint8, addr1, addr2 uint16) *JumpZero {
+	return &JumpZero{CommandData{
+		Opcode:   uint8(JZ), // Устанавливаем код операции для JumpZero
+		BB:       bb,        // Устанавливаем значение BB
+		Address1: addr1,     // Устанавливаем первый адрес
+		Address2: addr2,     // Устанавливаем второй адрес
+	}}
+}
+
+// Execute выполняет команду JumpZero
+func (j *JumpZero) Execute(p *Processor) error {
+	if p.psw.ZeroFlag { // Переходим, если результат последней операции равен нулю
+		effectiveAddr, err := calculateJumpTarget(p, j.CommandData) // Вычисляем адрес перехода
+		if err != nil {
+			return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+		}
+		p.jumpTo(effectiveAddr)                                              // Обновляем указатель команд (IP) процессора на эффективный адрес
+		p.logf(LogDebug, "JumpZero: Jumping to address 0x%X", effectiveAddr) // Логируем информацию о переходе
+	} else {
+		p.logf(LogDebug, "JumpZero: Condition not met, continuing") // Логируем информацию о том, что условие не выполнено
+	}
+	return nil // Возвращаем nil (без ошибок)
+}
+
+// JumpGreater реализация команды JumpGreater
+type JumpGreater struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewJumpGreater создает новый экземпляр JumpGreater с заданными параметрами
+func NewJumpGreater(bb uint8, addr1, addr2 uint16) *JumpGreater {
+	return &JumpGreater{CommandData{ // Возвращаем новый объект JumpGreater, инициализируя его CommandData
+		Opcode:   uint8(JG), // Устанавливаем код операции для JumpGreater
+		BB:       bb,        // Устанавливаем значение BB (биты управления)
+		Address1: addr1,     // Устанавливаем первый адрес для перехода
+		Address2: addr2,     // Устанавливаем второй адрес (может использоваться в других командах)
+	}}
+}
+
+// Execute выполняет команду JumpGreater
+func (j *JumpGreater) Execute(p *Processor) error {
+	if !p.psw.ZeroFlag && p.psw.SignFlag == p.psw.OverflowFlag { // Переходим, если результат больше нуля (со знаком)
+		effectiveAddr, err := calculateJumpTarget(p, j.CommandData) // Вычисляем адрес перехода
+		if err != nil {
+			return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+		}
+		p.jumpTo(effectiveAddr)                                                 // Обновляем указатель команд (IP) процессора на эффективный адрес
+		p.logf(LogDebug, "JumpGreater: Jumping to address 0x%X", effectiveAddr) // Логируем информацию о переходе
+	} else {
+		p.logf(LogDebug, "JumpGreater: Condition not met, continuing") // Логируем информацию о том, что условие не выполнено
+	}
+	return nil // Возвращаем nil (без ошибок)
+}
+
+// JumpLess реализация команды JumpLess
+type JumpLess struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewJumpLess создает новый экземпляр JumpLess с заданными параметрами
+func NewJumpLess(bb uint8, addr1, addr2 uint16) *JumpLess {
+	return &JumpLess{CommandData{ // Возвращаем новый объект JumpLess, инициализируя его CommandData
+		Opcode:   uint8(JL), // Устанавливаем код операции для JumpLess
+		BB:       bb,        // Устанавливаем значение BB (биты управления)
+		Address1: addr1,     // Устанавливаем первый адрес для перехода
+		Address2: addr2,     // Устанавливаем второй адрес (может использоваться в других командах)
+	}}
+}
+
+// Execute выполняет команду JumpLess
+func (j *JumpLess) Execute(p *Processor) error {
+	if p.psw.SignFlag != p.psw.OverflowFlag { // Переходим, если результат меньше нуля (со знаком)
+		effectiveAddr, err := calculateJumpTarget(p, j.CommandData) // Вычисляем адрес перехода
+		if err != nil {
+			return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+		}
+		p.jumpTo(effectiveAddr)                                              // Обновляем указатель команд (IP) процессора на эффективный адрес
+		p.logf(LogDebug, "JumpLess: Jumping to address 0x%X", effectiveAddr) // Логируем информацию о переходе
+	} else {
+		p.logf(LogDebug, "JumpLess: Condition not met, continuing") // Логируем информацию о том, что условие не выполнено
+	}
+	return nil // Возвращаем nil (без ошибок)
+}
+
+// JumpNotZero реализация команды JumpNotZero
+type JumpNotZero struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewJumpNotZero создает новый экземпляр JumpNotZero с заданными параметрами
+func NewJumpNotZero(bb uint8, addr1, addr2 uint16) *JumpNotZero {
+	return &JumpNotZero{CommandData{ // Возвращаем новый объект JumpNotZero, инициализируя его CommandData
+		Opcode:   uint8(JNE), // Устанавливаем код операции для JumpNotZero
+		BB:       bb,         // Устанавливаем значение BB (биты управления)
+		Address1: addr1,      // Устанавливаем первый адрес для перехода
+		Address2: addr2,      // Устанавливаем второй адрес (может использоваться в других командах)
+	}}
+}
+
+// Execute выполняет команду JumpNotZero
+func (j *JumpNotZero) Execute(p *Processor) error {
+	if !p.psw.ZeroFlag { // Переходим, если результат последней операции не равен нулю
+		effectiveAddr, err := calculateJumpTarget(p, j.CommandData) // Вычисляем адрес перехода
+		if err != nil {
+			return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+		}
+		p.jumpTo(effectiveAddr)                                                 // Обновляем указатель команд (IP) процессора на эффективный адрес
+		p.logf(LogDebug, "JumpNotZero: Jumping to address 0x%X", effectiveAddr) // Логируем информацию о переходе
+	} else {
+		p.logf(LogDebug, "JumpNotZero: Condition not met, continuing") // Логируем информацию о том, что условие не выполнено
+	}
+	return nil // Возвращаем nil (без ошибок)
+}
+
+// JumpGreaterEqual реализация команды JumpGreaterEqual
+type JumpGreaterEqual struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewJumpGreaterEqual создает новый экземпляр JumpGreaterEqual с заданными параметрами
+func NewJumpGreaterEqual(bb uint8, addr1, addr2 uint16) *JumpGreaterEqual {
+	return &JumpGreaterEqual{CommandData{ // Возвращаем новый объект JumpGreaterEqual, инициализируя его CommandData
+		Opcode:   uint8(JGE), // Устанавливаем код операции для JumpGreaterEqual
+		BB:       bb,         // Устанавливаем значение BB (биты управления)
+		Address1: addr1,      // Устанавливаем первый адрес для перехода
+		Address2: addr2,      // Устанавливаем второй адрес (может использоваться в других командах)
+	}}
+}
+
+// Execute выполняет команду JumpGreaterEqual
+func (j *JumpGreaterEqual) Execute(p *Processor) error {
+	if p.psw.SignFlag == p.psw.OverflowFlag { // Переходим, если результат больше или равен нулю (со знаком)
+		effectiveAddr, err := calculateJumpTarget(p, j.CommandData) // Вычисляем адрес перехода
+		if err != nil {
+			return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+		}
+		p.jumpTo(effectiveAddr)                                                      // Обновляем указатель команд (IP) процессора на эффективный адрес
+		p.logf(LogDebug, "JumpGreaterEqual: Jumping to address 0x%X", effectiveAddr) // Логируем информацию о переходе
+	} else {
+		p.logf(LogDebug, "JumpGreaterEqual: Condition not met, continuing") // Логируем информацию о том, что условие не выполнено
+	}
+	return nil // Возвращаем nil (без ошибок)
+}
+
+// JumpLessEqual реализация команды JumpLessEqual
+type JumpLessEqual struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewJumpLessEqual создает новый экземпляр JumpLessEqual с заданными параметрами
+func NewJumpLessEqual(bb uint8, addr1, addr2 uint16) *JumpLessEqual {
+	return &JumpLessEqual{CommandData{ // Возвращаем новый объект JumpLessEqual, инициализируя его CommandData
+		Opcode:   uint8(JLE), // Устанавливаем код операции для JumpLessEqual
+		BB:       bb,         // Устанавливаем значение BB (биты управления)
+		Address1: addr1,      // Устанавливаем первый адрес для перехода
+		Address2: addr2,      // Устанавливаем второй адрес (может использоваться в других командах)
+	}}
+}
+
+// Execute выполняет команду JumpLessEqual
+func (j *JumpLessEqual) Execute(p *Processor) error {
+	if p.psw.ZeroFlag || p.psw.SignFlag != p.psw.OverflowFlag { // Переходим, если результат меньше или равен нулю (со знаком)
+		effectiveAddr, err := calculateJumpTarget(p, j.CommandData) // Вычисляем адрес перехода
+		if err != nil {
+			return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+		}
+		p.jumpTo(effectiveAddr)                                                   // Обновляем указатель команд (IP) процессора на эффективный адрес
+		p.logf(LogDebug, "JumpLessEqual: Jumping to address 0x%X", effectiveAddr) // Логируем информацию о переходе
+	} else {
+		p.logf(LogDebug, "JumpLessEqual: Condition not met, continuing") // Логируем информацию о том, что условие не выполнено
+	}
+	return nil // Возвращаем nil (без ошибок)
+}
+
+// Halt command implementation
+type Halt struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewHalt создает новый экземпляр Halt с заданными параметрами
+func NewHalt(bb uint8, addr1, addr2 uint16) *Halt {
+	return &Halt{CommandData{ // Возвращаем новый объект Halt, инициализируя его CommandData
+		Opcode:   uint8(STOP), // Устанавливаем код операции для команды остановки
+		BB:       bb,          // Устанавливаем значение BB (биты управления)
+		Address1: addr1,       // Устанавливаем первый адрес (может быть использован в других командах)
+		Address2: addr2,       // Устанавливаем второй адрес (может быть использован в других командах)
+	}}
+}
+
+// Execute выполняет команду Halt.
+// Код завершения берется из Address1, а в режиме регистра (бит 1 BB) — из регистра,
+// индекс которого задан младшими 3 битами Address1.
+func (h *Halt) Execute(p *Processor) error {
+	exitCode := int32(h.Address1) // По умолчанию код завершения задан непосредственно
+	if h.BB&0x02 != 0 {           // Если установлен флаг режима регистра
+		value, err := p.GetRegister(uint8(h.Address1 & 0x07))
+		if err != nil {
+			return err // Возвращаем ошибку, если получение значения из регистра не удалось
+		}
+		exitCode = value
+	}
+	p.exitCode = exitCode                                               // Сохраняем код завершения программы
+	p.stop = true                                                       // Устанавливаем флаг остановки процессора в true
+	p.logf(LogInfo, "Halt: Stopping processor, exit code %d", exitCode) // Логируем сообщение о том, что процессор останавливается
+	return nil                                                          // Возвращаем nil (без ошибок)
+}
+
+type AddInt struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewAddInt создает новый экземпляр AddInt с заданными параметрами
+func NewAddInt(bb uint8, addr1, addr2 uint16) *AddInt {
+	return &AddInt{CommandData{ // Возвращаем новый объект AddInt, инициализируя его CommandData
+		Opcode:   uint8(IADD), // Устанавливаем код операции для сложения целых чисел
+		BB:       bb,          // Устанавливаем значение BB (биты управления)
+		Address1: addr1,       // Устанавливаем первый адрес для первого операнда
+		Address2: addr2,       // Устанавливаем второй адрес для второго операнда
+	}}
+}
+
+// Execute выполняет команду AddInt
+func (a *AddInt) Execute(p *Processor) error {
+	// Получаем индекс регистра из младших 3 битов, если в режиме работы с регистрами
+	regIndex := uint8(a.Address1 & 0x07)
+	// Вычисляем адрес первого операнда
+	addr1, err := calculateAddress(p, a.BB, a.Address1, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+	// Вычисляем адрес второго операнда
+	addr2, err := calculateAddress(p, a.BB, a.Address2, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+	// Читаем первое слово из памяти по адресу addr1
+	word1, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при чтении слова из памяти
+	}
+	// Читаем второе слово из памяти по адресу addr2
+	word2, err := p.memory.ReadWord(int(addr2))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при чтении слова из памяти
+	}
+	// Выполняем сложение двух целых чисел
+	result := word1.D.I + word2.D.I
+	word1.SetInt(result) // Обновляем первое слово с результатом сложения
+	// Записываем обновленное слово обратно в память по адресу addr1
+	err = p.memory.WriteWord(int(addr1), word1)
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при записи слова в память
+	}
+	// Обновляем флаги на основе результата сложения
+	hasOverflow := (word1.D.I > 0 && word2.D.I > 0 && result < 0) ||
+		(word1.D.I < 0 && word2.D.I < 0 && result > 0) // Проверка на переполнение
+	hasCarry := uint32(word1.D.I)+uint32(word2.D.I) > uint32(0x7FFFFFFF) // Проверка на перенос
+	p.UpdateArithmeticFlags(result, hasCarry, hasOverflow)               // Обновляем арифметические флаги процессора
+	// Логируем информацию о выполненной операции сложения
+	p.logf(LogDebug, "AddInt: %d + %d = %d", word1.D.I, word2.D.I, result)
+	return nil // Возвращаем nil (без ошибок)
+}
+
+// SubInt command implementation
+type SubInt struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewSubInt создает новый экземпляр SubInt с заданными параметрами
+func NewSubInt(bb uint8, addr1, addr2 uint16) *SubInt {
+	return &SubInt{CommandData{ // Возвращаем новый объект SubInt, инициализируя его CommandData
+		Opcode:   uint8(ISUB), // Устанавливаем код операции для вычитания целых чисел
+		BB:       bb,          // Устанавливаем значение BB (биты управления)
+		Address1: addr1,       // Устанавливаем первый адрес для первого операнда
+		Address2: addr2,       // Устанавливаем второй адрес для второго операнда
+	}}
+}
+
+// Execute выполняет команду SubInt
+func (s *SubInt) Execute(p *Processor) error {
+	regIndex := uint8(s.Address1 & 0x07) // Получаем индекс регистра из младших 3 битов адреса
+
+	// Вычисляем адрес первого операнда
+	addr1, err := calculateAddress(p, s.BB, s.Address1, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+
+	// Вычисляем адрес второго операнда
+	addr2, err := calculateAddress(p, s.BB, s.Address2, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+
+	// Читаем первое слово из памяти по адресу addr1
+	word1, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при чтении слова из памяти
+	}
+
+	// Читаем второе слово из памяти по адресу addr2
+	word2, err := p.memory.ReadWord(int(addr2))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при чтении слова из памяти
+	}
+
+	// Выполняем вычитание двух целых чисел
+	result := word1.D.I - word2.D.I
+	word1.SetInt(result) // Обновляем первое слово с результатом вычитания
+
+	// Записываем обновленное слово обратно в память по адресу addr1
+	err = p.memory.WriteWord(int(addr1), word1)
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при записи слова в память
+	}
+
+	// Обновляем флаги на основе результата вычитания
+	hasOverflow := (word1.D.I > 0 && word2.D.I < 0 && result < 0) ||
+		(word1.D.I < 0 && word2.D.I > 0 && result > 0) // Проверка на переполнение
+	hasCarry := uint32(word1.D.I) < uint32(word2.D.I)      // Проверка на заимствование
+	p.UpdateArithmeticFlags(result, hasCarry, hasOverflow) // Обновляем арифметические флаги процессора
+
+	// Логируем информацию о выполненной операции вычитания
+	p.logf(LogDebug, "SubInt: %d - %d = %d", word1.D.I, word2.D.I, result)
+	return nil // Возвращаем nil (без ошибок)
+}
+
+type MulInt struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewMulInt создает новый экземпляр MulInt с заданными параметрами
+func NewMulInt(bb uint8, addr1, addr2 uint16) *MulInt {
+	return &MulInt{CommandData{ // Возвращаем новый объект MulInt, инициализируя его CommandData
+		Opcode:   uint8(IMUL), // Устанавливаем код операции для умножения целых чисел
+		BB:       bb,          // Устанавливаем значение BB (биты управления)
+		Address1: addr1,       // Устанавливаем первый адрес для первого операнда
+		Address2: addr2,       // Устанавливаем второй адрес для второго операнда
+	}}
+}
+
+// Execute выполняет команду MulInt
+func (m *MulInt) Execute(p *Processor) error {
+	regIndex := uint8(m.Address1 & 0x07) // Получаем индекс регистра из младших 3 битов адреса
+
+	// Вычисляем адрес первого операнда
+	addr1, err := calculateAddress(p, m.BB, m.Address1, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+
+	// Вычисляем адрес второго операнда
+	addr2, err := calculateAddress(p, m.BB, m.Address2, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+
+	// Читаем первое слово из памяти по адресу addr1
+	word1, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при чтении слова из памяти
+	}
+
+	// Читаем второе слово из памяти по адресу addr2
+	word2, err := p.memory.ReadWord(int(addr2))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при чтении слова из памяти
+	}
+
+	// Выполняем умножение двух целых чисел
+	result := word1.D.I * word2.D.I
+	word1.SetInt(result) // Обновляем первое слово с результатом умножения
+
+	// Записываем обновленное слово обратно в память по адресу addr1
+	err = p.memory.WriteWord(int(addr1), word1)
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при записи слова в память
+	}
+
+	// Обновляем флаги на основе результата умножения
+	hasOverflow := result/word2.D.I != word1.D.I           // Проверка на переполнение (если результат делится на второй операнд)
+	hasCarry := false                                      // Флаг переноса не имеет смысла для умножения
+	p.UpdateArithmeticFlags(result, hasCarry, hasOverflow) // Обновляем арифметические флаги процессора
+
+	// Логируем информацию о выполненной операции умножения
+	p.logf(LogDebug, "MulInt: %d * %d = %d", word1.D.I, word2.D.I, result)
+	return nil // Возвращаем nil (без ошибок)
+}
+
+// DivInt command implementation
+type DivInt struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewDivInt создает новый экземпляр DivInt с заданными параметрами
+func NewDivInt(bb uint8, addr1, addr2 uint16) *DivInt {
+	return &DivInt{CommandData{ // Возвращаем новый объект DivInt, инициализируя его CommandData
+		Opcode:   uint8(IDIV), // Устанавливаем код операции для целочисленного деления
+		BB:       bb,          // Устанавливаем значение BB (биты управления)
+		Address1: addr1,       // Устанавливаем первый адрес для делимого
+		Address2: addr2,       // Устанавливаем второй адрес для делителя
+	}}
+}
+
+// Execute выполняет команду DivInt
+func (d *DivInt) Execute(p *Processor) error {
+	regIndex := uint8(d.Address1 & 0x07) // Получаем индекс регистра из младших 3 битов адреса
+
+	// Вычисляем адрес первого операнда (делимого)
+	addr1, err := calculateAddress(p, d.BB, d.Address1, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+
+	// Вычисляем адрес второго операнда (делителя)
+	addr2, err := calculateAddress(p, d.BB, d.Address2, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+
+	// Читаем первое слово из памяти по адресу addr1 (делимое)
+	word1, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при чтении слова из памяти
+	}
+
+	// Читаем второе слово из памяти по адресу addr2 (делитель)
+	word2, err := p.memory.ReadWord(int(addr2))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при чтении слова из памяти
+	}
+
+	// Проверяем делитель на ноль
+	if word2.D.I == 0 {
+		p.logf(LogDebug, "DivInt: Division by zero error") // Логируем сообщение об ошибке деления на ноль
+		return errDivideByZero()                           // Возвращаем ошибку деления на ноль
+	}
+
+	// Выполняем деление двух целых чисел
+	result := word1.D.I / word2.D.I
+	word1.SetInt(result) // Обновляем первое слово с результатом деления
+
+	// Записываем обновленное слово обратно в память по адресу addr1
+	err = p.memory.WriteWord(int(addr1), word1)
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при записи слова в память
+	}
+
+	// Обновляем флаги на основе результата деления
+	hasOverflow := false                                   // Деление не может привести к переполнению в целочисленной арифметике
+	hasCarry := false                                      // Флаг переноса не имеет смысла для деления
+	p.UpdateArithmeticFlags(result, hasCarry, hasOverflow) // Обновляем арифметические флаги процессора
+
+	// Логируем информацию о выполненной операции деления
+	p.logf(LogDebug, "DivInt: %d / %d = %d", word1.D.I, word2.D.I, result)
+	return nil // Возвращаем nil (без ошибок)
+}
+
+// CompareInt command implementation
+type CompareInt struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewCompareInt создает новый экземпляр CompareInt с заданными параметрами
+func NewCompareInt(bb uint8, addr1, addr2 uint16) *CompareInt {
+	return &CompareInt{CommandData{ // Возвращаем новый объект CompareInt, инициализируя его CommandData
+		Opcode:   uint8(CMP), // Устанавливаем код операции для сравнения целых чисел
+		BB:       bb,         // Устанавливаем значение BB (биты управления)
+		Address1: addr1,      // Устанавливаем первый адрес для первого операнда
+		Address2: addr2,      // Устанавливаем второй адрес для второго операнда
+	}}
+}
+
+// Execute выполняет команду CompareInt: вычисляет разность операндов только для установки флагов
+func (c *CompareInt) Execute(p *Processor) error {
+	regIndex := uint8(c.Address1 & 0x07) // Получаем индекс регистра из младших 3 битов адреса
+
+	// Вычисляем адреса операндов
+	addr1, err := calculateAddress(p, c.BB, c.Address1, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+	addr2, err := calculateAddress(p, c.BB, c.Address2, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+
+	// Читаем операнды из памяти
+	word1, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при чтении слова из памяти
+	}
+	word2, err := p.memory.ReadWord(int(addr2))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при чтении слова из памяти
+	}
+
+	// Вычисляем разность, не записывая ее в память
+	a, b := word1.D.I, word2.D.I
+	result := compareInts(p, a, b)
+
+	p.logf(LogDebug, "CompareInt: %d ? %d (diff %d)", a, b, result)
+	return nil // Возвращаем nil (без ошибок)
+}
+
+// compareInts устанавливает флаги по разности a-b так же, как команда CMP, и возвращает разность
+func compareInts(p *Processor, a, b int32) int32 {
+	result := a - b
+	hasOverflow := (a >= 0 && b < 0 && result < 0) ||
+		(a < 0 && b > 0 && result >= 0) // Проверка на переполнение
+	hasCarry := uint32(a) < uint32(b)                      // Проверка на заимствование
+	p.UpdateArithmeticFlags(result, hasCarry, hasOverflow) // Обновляем арифметические флаги процессора
+	return result
+}
+
+// CompareFloat command implementation
+type CompareFloat struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewCompareFloat создает новый экземпляр CompareFloat с заданными параметрами
+func NewCompareFloat(bb uint8, addr1, addr2 uint16) *CompareFloat {
+	return &CompareFloat{CommandData{ // Возвращаем новый объект CompareFloat, инициализируя его CommandData
+		Opcode:   uint8(RCMP), // Устанавливаем код операции для сравнения вещественных чисел
+		BB:       bb,          // Устанавливаем значение BB (биты управления)
+		Address1: addr1,       // Устанавливаем первый адрес для первого операнда
+		Address2: addr2,       // Устанавливаем второй адрес для второго операнда
+	}}
+}
+
+// Execute выполняет команду CompareFloat: вычисляет разность операндов только для установки флагов
+func (c *CompareFloat) Execute(p *Processor) error {
+	regIndex := uint8(c.Address1 & 0x07) // Получаем индекс регистра из младших 3 битов адреса
+
+	// Вычисляем адреса операндов
+	addr1, err := calculateAddress(p, c.BB, c.Address1, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+	addr2, err := calculateAddress(p, c.BB, c.Address2, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+
+	// Читаем операнды из памяти
+	word1, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при чтении слова из памяти
+	}
+	word2, err := p.memory.ReadWord(int(addr2))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при чтении слова из памяти
+	}
+
+	// Вычисляем разность, не записывая ее в память
+	result := word1.D.F - word2.D.F
+	p.UpdateFloatFlags(result) // Обновляем флаги процессора на основе разности
+
+	p.logf(LogDebug, "CompareFloat: %f ? %f (diff %f)", word1.D.F, word2.D.F, result)
+	return nil // Возвращаем nil (без ошибок)
+}
+
+// BitwiseAnd command implementation
+type BitwiseAnd struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewBitwiseAnd создает новый экземпляр BitwiseAnd с заданными параметрами
+func NewBitwiseAnd(bb uint8, addr1, addr2 uint16) *BitwiseAnd {
+	return &BitwiseAnd{CommandData{ // Возвращаем новый объект BitwiseAnd, инициализируя его CommandData
+		Opcode:   uint8(AND), // Устанавливаем код операции для поразрядного И
+		BB:       bb,         // Устанавливаем значение BB (биты управления)
+		Address1: addr1,      // Устанавливаем первый адрес для первого операнда и результата
+		Address2: addr2,      // Устанавливаем второй адрес для второго операнда
+	}}
+}
+
+// Execute выполняет команду BitwiseAnd
+func (b *BitwiseAnd) Execute(p *Processor) error {
+	return executeBitwise(p, b.CommandData, "BitwiseAnd", "&", func(x, y int32) int32 { return x & y })
+}
+
+// BitwiseOr command implementation
+type BitwiseOr struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewBitwiseOr создает новый экземпляр BitwiseOr с заданными параметрами
+func NewBitwiseOr(bb uint8, addr1, addr2 uint16) *BitwiseOr {
+	return &BitwiseOr{CommandData{ // Возвращаем новый объект BitwiseOr, инициализируя его CommandData
+		Opcode:   uint8(OR), // Устанавливаем код операции для поразрядного ИЛИ
+		BB:       bb,        // Устанавливаем значение BB (биты управления)
+		Address1: addr1,     // Устанавливаем первый адрес для первого операнда и результата
+		Address2: addr2,     // Устанавливаем второй адрес для второго операнда
+	}}
+}
+
+// Execute выполняет команду BitwiseOr
+func (b *BitwiseOr) Execute(p *Processor) error {
+	return executeBitwise(p, b.CommandData, "BitwiseOr", "|", func(x, y int32) int32 { return x | y })
+}
+
+// BitwiseXor command implementation
+type BitwiseXor struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewBitwiseXor создает новый экземпляр BitwiseXor с заданными параметрами
+func NewBitwiseXor(bb uint8, addr1, addr2 uint16) *BitwiseXor {
+	return &BitwiseXor{CommandData{ // Возвращаем новый объект BitwiseXor, инициализируя его CommandData
+		Opcode:   uint8(XOR), // Устанавливаем код операции для поразрядного исключающего ИЛИ
+		BB:       bb,         // Устанавливаем значение BB (биты управления)
+		Address1: addr1,      // Устанавливаем первый адрес для первого операнда и результата
+		Address2: addr2,      // Устанавливаем второй адрес для второго операнда
+	}}
+}
+
+// Execute выполняет команду BitwiseXor
+func (b *BitwiseXor) Execute(p *Processor) error {
+	return executeBitwise(p, b.CommandData, "BitwiseXor", "^", func(x, y int32) int32 { return x ^ y })
+}
+
+// executeBitwise выполняет поразрядную операцию над двумя словами памяти и записывает результат по addr1
+func executeBitwise(p *Processor, c CommandData, name, symbol string, op func(x, y int32) int32) error {
+	regIndex := uint8(c.Address1 & 0x07) // Получаем индекс регистра из младших 3 битов адреса
+
+	// Вычисляем адреса операндов
+	addr1, err := calculateAddress(p, c.BB, c.Address1, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+	addr2, err := calculateAddress(p, c.BB, c.Address2, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+
+	// Читаем операнды из памяти
+	word1, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при чтении слова из памяти
+	}
+	word2, err := p.memory.ReadWord(int(addr2))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при чтении слова из памяти
+	}
+
+	// Выполняем операцию и записываем результат по адресу addr1
+	a := word1.D.I
+	result := op(a, word2.D.I)
+	word1.SetInt(result)
+	if err := p.memory.WriteWord(int(addr1), word1); err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при записи слова в память
+	}
+
+	// Поразрядные операции не приводят к переносу и переполнению
+	p.UpdateArithmeticFlags(result, false, false)
+	p.logf(LogDebug, "%s: 0x%08X %s 0x%08X = 0x%08X", name, uint32(a), symbol, uint32(word2.D.I), uint32(result))
+	return nil // Возвращаем nil (без ошибок)
+}
+
+// BitwiseNot command implementation
+type BitwiseNot struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewBitwiseNot создает новый экземпляр BitwiseNot с заданными параметрами
+func NewBitwiseNot(bb uint8, addr1, addr2 uint16) *BitwiseNot {
+	return &BitwiseNot{CommandData{ // Возвращаем новый объект BitwiseNot, инициализируя его CommandData
+		Opcode:   uint8(NOT), // Устанавливаем код операции для поразрядного отрицания
+		BB:       bb,         // Устанавливаем значение BB (биты управления)
+		Address1: addr1,      // Устанавливаем адрес единственного операнда
+		Address2: addr2,      // Не используется
+	}}
+}
+
+// Execute выполняет команду BitwiseNot, инвертируя все биты слова по адресу addr1
+func (b *BitwiseNot) Execute(p *Processor) error {
+	// Вычисляем адрес операнда
+	addr1, err := calculateAddress(p, b.BB, b.Address1, uint8(b.Address1&0x07))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+	word, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при чтении слова из памяти
+	}
+
+	// Инвертируем биты и записываем результат обратно
+	a := word.D.I
+	word.SetInt(^a)
+	if err := p.memory.WriteWord(int(addr1), word); err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при записи слова в память
+	}
+
+	p.UpdateArithmeticFlags(word.D.I, false, false) // Обновляем арифметические флаги процессора
+	p.logf(LogDebug, "BitwiseNot: ~0x%08X = 0x%08X", uint32(a), uint32(word.D.I))
+	return nil // Возвращаем nil (без ошибок)
+}
+
+// ModInt command implementation
+type ModInt struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewModInt создает новый экземпляр ModInt с заданными параметрами
+func NewModInt(bb uint8, addr1, addr2 uint16) *ModInt {
+	return &ModInt{CommandData{ // Возвращаем новый объект ModInt, инициализируя его CommandData
+		Opcode:   uint8(IMOD), // Устанавливаем код операции для остатка от деления
+		BB:       bb,          // Устанавливаем значение BB (биты управления)
+		Address1: addr1,       // Устанавливаем первый адрес для делимого
+		Address2: addr2,       // Устанавливаем второй адрес для делителя
+	}}
+}
+
+// Execute выполняет команду ModInt, записывая остаток от деления по адресу addr1
+func (m *ModInt) Execute(p *Processor) error {
+	addr1, _, word1, word2, err := readDivisionOperands(p, m.CommandData, "ModInt")
+	if err != nil {
+		return err // Возвращаем ошибку чтения операндов или деления на ноль
+	}
+
+	// Вычисляем остаток от деления и записываем его по адресу addr1
+	dividend := word1.D.I
+	result := dividend % word2.D.I
+	word1.SetInt(result)
+	if err := p.memory.WriteWord(int(addr1), word1); err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при записи слова в память
+	}
+
+	p.UpdateArithmeticFlags(result, false, false) // Обновляем арифметические флаги процессора
+	p.logf(LogDebug, "ModInt: %d %% %d = %d", dividend, word2.D.I, result)
+	return nil // Возвращаем nil (без ошибок)
+}
+
+// DivModInt command implementation
+type DivModInt struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewDivModInt создает новый экземпляр DivModInt с заданными параметрами
+func NewDivModInt(bb uint8, addr1, addr2 uint16) *DivModInt {
+	return &DivModInt{CommandData{ // Возвращаем новый объект DivModInt, инициализируя его CommandData
+		Opcode:   uint8(DIVMOD), // Устанавливаем код операции для деления с остатком
+		BB:       bb,            // Устанавливаем значение BB (биты управления)
+		Address1: addr1,         // Делимое; сюда записывается частное
+		Address2: addr2,         // Делитель; сюда записывается остаток
+	}}
+}
+
+// Execute выполняет команду DivModInt: частное записывается по addr1, остаток — по addr2
+func (d *DivModInt) Execute(p *Processor) error {
+	addr1, addr2, word1, word2, err := readDivisionOperands(p, d.CommandData, "DivModInt")
+	if err != nil {
+		return err // Возвращаем ошибку чтения операндов или деления на ноль
+	}
+
+	// Вычисляем частное и остаток
+	dividend, divisor := word1.D.I, word2.D.I
+	quotient, remainder := dividend/divisor, dividend%divisor
+	word1.SetInt(quotient)
+	word2.SetInt(remainder)
+
+	// Записываем частное и остаток обратно в память
+	if err := p.memory.WriteWord(int(addr1), word1); err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при записи слова в память
+	}
+	if err := p.memory.WriteWord(int(addr2), word2); err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при записи слова в память
+	}
+
+	p.UpdateArithmeticFlags(quotient, false, false) // Флаги отражают частное
+	p.logf(LogDebug, "DivModInt: %d / %d = %d rem %d", dividend, divisor, quotient, remainder)
+	return nil // Возвращаем nil (без ошибок)
+}
+
+// readDivisionOperands вычисляет адреса и читает операнды целочисленного деления,
+// обрабатывая деление на ноль так же, как команда IDIV
+func readDivisionOperands(p *Processor, c CommandData, name string) (uint16, uint16, Word, Word, error) {
+	regIndex := uint8(c.Address1 & 0x07) // Получаем индекс регистра из младших 3 битов адреса
+
+	// Вычисляем адреса делимого и делителя
+	addr1, err := calculateAddress(p, c.BB, c.Address1, regIndex)
+	if err != nil {
+		return 0, 0, Word{}, Word{}, err
+	}
+	addr2, err := calculateAddress(p, c.BB, c.Address2, regIndex)
+	if err != nil {
+		return 0, 0, Word{}, Word{}, err
+	}
+
+	// Читаем делимое и делитель из памяти
+	word1, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return 0, 0, Word{}, Word{}, err
+	}
+	word2, err := p.memory.ReadWord(int(addr2))
+	if err != nil {
+		return 0, 0, Word{}, Word{}, err
+	}
+
+	// Проверяем делитель на ноль
+	if word2.D.I == 0 {
+		p.logf(LogDebug, "%s: Division by zero error", name) // Логируем сообщение об ошибке деления на ноль
+		return 0, 0, Word{}, Word{}, errDivideByZero()       // Возвращаем ошибку деления на ноль
+	}
+	return addr1, addr2, word1, word2, nil
+}
+
+// IncrementInt command implementation
+type IncrementInt struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewIncrementInt создает новый экземпляр IncrementInt с заданными параметрами
+func NewIncrementInt(bb uint8, addr1, addr2 uint16) *IncrementInt {
+	return &IncrementInt{CommandData{ // Возвращаем новый объект IncrementInt, инициализируя его CommandData
+		Opcode:   uint8(INC), // Устанавливаем код операции для инкремента слова памяти
+		BB:       bb,         // Устанавливаем значение BB (биты управления)
+		Address1: addr1,      // Устанавливаем адрес операнда и результата
+		Address2: addr2,      // Не используется
+	}}
+}
+
+// Execute выполняет команду IncrementInt
+func (u *IncrementInt) Execute(p *Processor) error {
+	return executeUnary(p, u.CommandData, "IncrementInt", false, incrementInt)
+}
+
+// DecrementInt command implementation
+type DecrementInt struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewDecrementInt создает новый экземпляр DecrementInt с заданными параметрами
+func NewDecrementInt(bb uint8, addr1, addr2 uint16) *DecrementInt {
+	return &DecrementInt{CommandData{ // Возвращаем новый объект DecrementInt, инициализируя его CommandData
+		Opcode:   uint8(DEC), // Устанавливаем код операции для декремента слова памяти
+		BB:       bb,         // Устанавливаем значение BB (биты управления)
+		Address1: addr1,      // Устанавливаем адрес операнда и результата
+		Address2: addr2,      // Не используется
+	}}
+}
+
+// Execute выполняет команду DecrementInt
+func (u *DecrementInt) Execute(p *Processor) error {
+	return executeUnary(p, u.CommandData, "DecrementInt", false, decrementInt)
+}
+
+// NegateInt command implementation
+type NegateInt struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewNegateInt создает новый экземпляр NegateInt с заданными параметрами
+func NewNegateInt(bb uint8, addr1, addr2 uint16) *NegateInt {
+	return &NegateInt{CommandData{ // Возвращаем новый объект NegateInt, инициализируя его CommandData
+		Opcode:   uint8(NEG), // Устанавливаем код операции для смены знака слова памяти
+		BB:       bb,         // Устанавливаем значение BB (биты управления)
+		Address1: addr1,      // Устанавливаем адрес операнда и результата
+		Address2: addr2,      // Не используется
+	}}
+}
+
+// Execute выполняет команду NegateInt
+func (u *NegateInt) Execute(p *Processor) error {
+	return executeUnary(p, u.CommandData, "NegateInt", false, negateInt)
+}
+
+// IncrementRegister command implementation
+type IncrementRegister struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewIncrementRegister создает новый экземпляр IncrementRegister с заданными параметрами
+func NewIncrementRegister(bb uint8, addr1, addr2 uint16) *IncrementRegister {
+	return &IncrementRegister{CommandData{ // Возвращаем новый объект IncrementRegister, инициализируя его CommandData
+		Opcode:   uint8(INCR), // Устанавливаем код операции для инкремента регистра
+		BB:       bb,          // Устанавливаем значение BB (биты управления)
+		Address1: addr1,       // Индекс регистра в младших 3 битах
+		Address2: addr2,       // Не используется
+	}}
+}
+
+// Execute выполняет команду IncrementRegister
+func (u *IncrementRegister) Execute(p *Processor) error {
+	return executeUnary(p, u.CommandData, "IncrementRegister", true, incrementInt)
+}
+
+// DecrementRegister command implementation
+type DecrementRegister struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewDecrementRegister создает новый экземпляр DecrementRegister с заданными параметрами
+func NewDecrementRegister(bb uint8, addr1, addr2 uint16) *DecrementRegister {
+	return &DecrementRegister{CommandData{ // Возвращаем новый объект DecrementRegister, инициализируя его CommandData
+		Opcode:   uint8(DECR), // Устанавливаем код операции для декремента регистра
+		BB:       bb,          // Устанавливаем значение BB (биты управления)
+		Address1: addr1,       // Индекс регистра в младших 3 битах
+		Address2: addr2,       // Не используется
+	}}
+}
+
+// Execute выполняет команду DecrementRegister
+func (u *DecrementRegister) Execute(p *Processor) error {
+	return executeUnary(p, u.CommandData, "DecrementRegister", true, decrementInt)
+}
+
+// NegateRegister command implementation
+type NegateRegister struct {
+	CommandData // Встраиваем структуру CommandData для хранения данных команды
+}
+
+// NewNegateRegister создает новый экземпляр NegateRegister с заданными параметрами
+func NewNegateRegister(bb uint8, addr1, addr2 uint16) *NegateRegister {
+	return &NegateRegister{CommandData{ // Возвращаем новый объект NegateRegister, инициализируя его CommandData
+		Opcode:   uint8(NEGR), // Устанавливаем код операции для смены знака регистра
+		BB:       bb,          // Устанавливаем значение BB (биты управления)
+		Address1: addr1,       // Индекс регистра в младших 3 битах
+		Address2: addr2,       // Не используется
+	}}
+}
+
+// Execute выполняет команду NegateRegister
+func (u *NegateRegister) Execute(p *Processor) error {
+	return executeUnary(p, u.CommandData, "NegateRegister", true, negateInt)
+}
+
+// incrementInt увеличивает значение на 1 и сообщает о переносе и переполнении
+func incrementInt(a int32) (int32, bool, bool) {
+	return a + 1, uint32(a) == 0xFFFFFFFF, a == math.MaxInt32
+}
+
+// decrementInt уменьшает значение на 1 и сообщает о заимствовании и переполнении
+func decrementInt(a int32) (int32, bool, bool) {
+	return a - 1, a == 0, a == math.MinInt32
+}
+
+// negateInt меняет знак значения; перенос устанавливается для ненулевого операнда
+func negateInt(a int32) (int32, bool, bool) {
+	return -a, a != 0, a == math.MinInt32
+}
+
+// executeUnary применяет одноместную операцию к слову памяти или регистру и обновляет флаги
+func executeUnary(p *Processor, c CommandData, name string, register bool, op func(int32) (int32, bool, bool)) error {
+	if register {
+		regIndex := uint8(c.Address1 & 0x07) // Получаем индекс регистра из младших 3 битов адреса
+		value, err := p.GetRegister(regIndex)
+		if err != nil {
+			return err // Возвращаем ошибку, если получение значения из регистра не удалось
+		}
+		result, hasCarry, hasOverflow := op(value)
+		if err := p.SetRegister(regIndex, result); err != nil {
+			return err // Возвращаем ошибку, если установка регистра не удалась
+		}
+		p.UpdateArithmeticFlags(result, hasCarry, hasOverflow) // Обновляем арифметические флаги процессора
+		p.logf(LogDebug, "%s: R%d %d -> %d", name, regIndex, value, result)
+		return nil
+	}
+
+	// Вычисляем адрес операнда и читаем слово из памяти
+	addr1, err := calculateAddress(p, c.BB, c.Address1, uint8(c.Address1&0x07))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+	word, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при чтении слова из памяти
+	}
+
+	// Применяем операцию и записываем результат обратно
+	value := word.D.I
+	result, hasCarry, hasOverflow := op(value)
+	word.SetInt(result)
+	if err := p.memory.WriteWord(int(addr1), word); err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при записи слова в память
+	}
+	p.UpdateArithmeticFlags(result, hasCarry, hasOverflow) // Обновляем арифметические флаги процессора
+	p.logf(LogDebug, "%s: [0x%X] %d -> %d", name, addr1, value, result)
+	return nil
+}
+
+// Реализация команды AddFloat
+type AddFloat struct {
+	CommandData // Встраиваем структуру CommandData, содержащую данные команды
+}
+
+// Конструктор для создания нового объекта AddFloat
+func NewAddFloat(bb uint8, addr1, addr2 uint16) *AddFloat {
+	// Возвращаем указатель на новый объект AddFloat с заданными параметрами
+	return &AddFloat{CommandData{
+		Opcode:   uint8(RADD), // Устанавливаем опкод для команды RADD (сложение)
+		BB:       bb,          // Устанавливаем значение bb (базовый регистр)
+		Address1: addr1,       // Устанавливаем адрес первого операнда
+		Address2: addr2,       // Устанавливаем адрес второго операнда
+	}}
+}
+
+// Метод Execute выполняет команду AddFloat
+func (a *AddFloat) Execute(p *Processor) error {
+	// Получаем индекс регистра из Address1 (нижние 3 бита), если в режиме регистра
+	regIndex := uint8(a.Address1 & 0x07)
+
+	// Вычисляем адрес для первого операнда с помощью функции calculateAddress
+	addr1, err := calculateAddress(p, a.BB, a.Address1, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+
+	// Вычисляем адрес для второго операнда аналогично первому
+	addr2, err := calculateAddress(p, a.BB, a.Address2, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+
+	// Читаем слово из памяти по адресу addr1
+	word1, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение слова не удалось
+	}
+
+	// Читаем слово из памяти по адресу addr2
+	word2, err := p.memory.ReadWord(int(addr2))
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение слова не удалось
+	}
+
+	// Выполняем сложение значений с плавающей точкой
+	result := word1.D.F + word2.D.F
+	word1.SetFloat(result) // Обновляем значение первого операнда с результатом сложения
+
+	// Записываем обновленное значение обратно в память по адресу addr1
+	err = p.memory.WriteWord(int(addr1), word1)
+	if err != nil {
+		return err // Возвращаем ошибку, если запись слова не удалась
+	}
+
+	// Обновляем флаги процессора на основе результата сложения
+	p.UpdateFloatFlags(result)
+
+	// Логируем сообщение о выполнении операции сложения
+	p.logf(LogDebug, "AddFloat: %f + %f = %f", word1.D.F, word2.D.F, result)
+	return nil // Завершаем выполнение функции без ошибок
+}
+
+// Реализация команды SubFloat
+type SubFloat struct {
+	CommandData // Встраиваем структуру CommandData, содержащую данные команды
+}
+
+// Конструктор для создания нового объекта SubFloat
+func NewSubFloat(bb uint8, addr1, addr2 uint16) *SubFloat {
+	// Возвращаем указатель на новый объект SubFloat с заданными параметрами
+	return &SubFloat{CommandData{
+		Opcode:   uint8(RSUB), // Устанавливаем опкод для команды RSUB (вычитание)
+		BB:       bb,          // Устанавливаем значение bb (базовый регистр)
+		Address1: addr1,       // Устанавливаем адрес первого операнда
+		Address2: addr2,       // Устанавливаем адрес второго операнда
+	}}
+}
+
+// Метод Execute выполняет команду SubFloat
+func (s *SubFloat) Execute(p *Processor) error {
+	// Получаем индекс регистра из Address1 (нижние 3 бита), если в режиме регистра
+	regIndex := uint8(s.Address1 & 0x07)
+
+	// Вычисляем адрес для первого операнда с помощью функции calculateAddress
+	addr1, err := calculateAddress(p, s.BB, s.Address1, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+
+	// Вычисляем адрес для второго операнда аналогично первому
+	addr2, err := calculateAddress(p, s.BB, s.Address2, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+
+	// Читаем слово из памяти по адресу addr1
+	word1, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение слова не удалось
+	}
+
+	// Читаем слово из памяти по адресу addr2
+	word2, err := p.memory.ReadWord(int(addr2))
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение слова не удалось
+	}
+
+	// Выполняем вычитание значений с плавающей точкой
+	result := word1.D.F - word2.D.F
+	word1.SetFloat(result) // Обновляем значение первого операнда с результатом вычитания
+
+	// Записываем обновленное значение обратно в память по адресу addr1
+	err = p.memory.WriteWord(int(addr1), word1)
+	if err != nil {
+		return err // Возвращаем ошибку, если запись слова не удалась
+	}
+
+	// Обновляем флаги процессора на основе результата вычитания
+	p.UpdateFloatFlags(result)
+
+	// Логируем сообщение о выполнении операции вычитания
+	p.logf(LogDebug, "SubFloat: %f - %f = %f", word1.D.F, word2.D.F, result)
+	return nil // Завершаем выполнение функции без ошибок
+}
+
+// Реализация команды MulFloat
+type MulFloat struct {
+	CommandData // Встраиваем структуру CommandData, содержащую данные команды
+}
+
+// Конструктор для создания нового объекта MulFloat
+func NewMulFloat(bb uint8, addr1, addr2 uint16) *MulFloat {
+	// Возвращаем указатель на новый объект MulFloat с заданными параметрами
+	return &MulFloat{CommandData{
+		Opcode:   uint8(RMUL), // Устанавливаем опкод для команды RMUL (умножение)
+		BB:       bb,          // Устанавливаем значение bb (базовый регистр)
+		Address1: addr1,       // Устанавливаем адрес первого операнда
+		Address2: addr2,       // Устанавливаем адрес второго операнда
+	}}
+}
+
+// Метод Execute выполняет команду MulFloat
+func (m *MulFloat) Execute(p *Processor) error {
+	// Получаем индекс регистра из Address1 (нижние 3 бита), если в режиме регистра
+	regIndex := uint8(m.Address1 & 0x07)
+
+	// Вычисляем адрес для первого операнда с помощью функции calculateAddress
+	addr1, err := calculateAddress(p, m.BB, m.Address1, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+
+	// Вычисляем адрес для второго операнда аналогично первому
+	addr2, err := calculateAddress(p, m.BB, m.Address2, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+
+	// Читаем слово из памяти по адресу addr1
+	word1, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение слова не удалось
+	}
+
+	// Читаем слово из памяти по адресу addr2
+	word2, err := p.memory.ReadWord(int(addr2))
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение слова не удалось
+	}
+
+	// Выполняем умножение значений с плавающей точкой
+	result := word1.D.F * word2.D.F
+	word1.SetFloat(result) // Обновляем значение первого операнда с результатом умножения
+
+	// Записываем обновленное значение обратно в память по адресу addr1
+	err = p.memory.WriteWord(int(addr1), word1)
+	if err != nil {
+		return err // Возвращаем ошибку, если запись слова не удалась
+	}
+
+	// Обновляем флаги процессора на основе результата умножения
+	p.UpdateFloatFlags(result)
+
+	// Логируем сообщение о выполнении операции умножения
+	p.logf(LogDebug, "MulFloat: %f * %f = %f", word1.D.F, word2.D.F, result)
+	return nil // Завершаем выполнение функции без ошибок
+}
+
+// Реализация команды DivFloat
+type DivFloat struct {
+	CommandData // Встраиваем структуру CommandData, содержащую данные команды
+}
+
+// Конструктор для создания нового объекта DivFloat
+func NewDivFloat(bb uint8, addr1, addr2 uint16) *DivFloat {
+	// Возвращаем указатель на новый объект DivFloat с заданными параметрами
+	return &DivFloat{CommandData{
+		Opcode:   uint8(RDIV), // Устанавливаем опкод для команды RDIV (деление)
+		BB:       bb,          // Устанавливаем значение bb (базовый регистр)
+		Address1: addr1,       // Устанавливаем адрес первого операнда
+		Address2: addr2,       // Устанавливаем адрес второго операнда
+	}}
+}
+
+// Метод Execute выполняет команду DivFloat
+func (d *DivFloat) Execute(p *Processor) error {
+	// Получаем индекс регистра из Address1 (нижние 3 бита), если в режиме регистра
+	regIndex := uint8(d.Address1 & 0x07)
+
+	// Вычисляем адрес для первого операнда с помощью функции calculateAddress
+	addr1, err := calculateAddress(p, d.BB, d.Address1, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+
+	// Вычисляем адрес для второго операнда аналогично первому
+	addr2, err := calculateAddress(p, d.BB, d.Address2, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+
+	// Читаем слово из памяти по адресу addr1
+	word1, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение слова не удалось
+	}
+
+	// Читаем слово из памяти по адресу addr2
+	word2, err := p.memory.ReadWord(int(addr2))
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение слова не удалось
+	}
+
+	// Проверяем на деление на ноль
+	if word2.D.F == 0 {
+		p.logf(LogDebug, "DivFloat: Division by zero error") // Логируем сообщение об ошибке
+		return errDivideByZero()                             // Возвращаем ошибку деления на ноль
+	}
+
+	// Выполняем деление значений с плавающей точкой
+	result := word1.D.F / word2.D.F
+	word1.SetFloat(result) // Обновляем значение первого операнда с результатом деления
+
+	// Записываем обновленное значение обратно в память по адресу addr1
+	err = p.memory.WriteWord(int(addr1), word1)
+	if err != nil {
+		return err // Возвращаем ошибку, если запись слова не удалась
+	}
+
+	// Обновляем флаги процессора на основе результата деления
+	p.UpdateFloatFlags(result)
+
+	// Логируем сообщение о выполнении операции деления
+	p.logf(LogDebug, "DivFloat: %f / %f = %f", word1.D.F, word2.D.F, result)
+	return nil // Завершаем выполнение функции без ошибок
+}
+
+// Режимы округления команды FTOI, задаваемые полем Address2
+const (
+	ROUND_NEAREST = 0 // Округление к ближайшему целому (половины — к четному)
+	ROUND_TRUNC   = 1 // Отбрасывание дробной части (к нулю)
+	ROUND_FLOOR   = 2 // Округление вниз
+	ROUND_CEIL    = 3 // Округление вверх
+)
+
+// IntToFloat command implementation
+type IntToFloat struct {
+	CommandData // Встраиваем структуру CommandData, содержащую данные команды
+}
+
+// NewIntToFloat создает новый экземпляр IntToFloat с заданными параметрами
+func NewIntToFloat(bb uint8, addr1, addr2 uint16) *IntToFloat {
+	return &IntToFloat{CommandData{
+		Opcode:   uint8(ITOF), // Устанавливаем опкод для команды ITOF
+		BB:       bb,          // Устанавливаем значение bb (режим адресации)
+		Address1: addr1,       // Адрес преобразуемого слова
+		Address2: addr2,       // Не используется
+	}}
+}
+
+// Execute выполняет команду IntToFloat, заменяя целое значение слова вещественным
+func (c *IntToFloat) Execute(p *Processor) error {
+	addr1, err := calculateAddress(p, c.BB, c.Address1, uint8(c.Address1&0x07))
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+	word, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение слова не удалось
+	}
+
+	// Преобразование int32 -> float32 округляет к ближайшему представимому значению
+	value := word.D.I
+	result := float32(value)
+	if err := p.memory.WriteWord(int(addr1), FloatWord(result)); err != nil {
+		return err // Возвращаем ошибку, если запись слова не удалась
+	}
+
+	p.UpdateFloatFlags(result) // Обновляем флаги процессора по результату
+	p.logf(LogDebug, "IntToFloat: %d -> %f", value, result)
+	return nil // Завершаем выполнение функции без ошибок
+}
+
+// FloatToInt command implementation
+type FloatToInt struct {
+	CommandData // Встраиваем структуру CommandData, содержащую данные команды
+}
+
+// NewFloatToInt создает новый экземпляр FloatToInt с заданными параметрами
+func NewFloatToInt(bb uint8, addr1, addr2 uint16) *FloatToInt {
+	return &FloatToInt{CommandData{
+		Opcode:   uint8(FTOI), // Устанавливаем опкод для команды FTOI
+		BB:       bb,          // Устанавливаем значение bb (режим адресации)
+		Address1: addr1,       // Адрес преобразуемого слова
+		Address2: addr2,       // Режим округления (ROUND_NEAREST, ROUND_TRUNC, ROUND_FLOOR, ROUND_CEIL)
+	}}
+}
+
+// Execute выполняет команду FloatToInt, заменяя вещественное значение слова целым.
+// Если значение не помещается в int32 или не является числом, результат насыщается
+// до ближайшей границы диапазона и устанавливается флаг переполнения.
+func (c *FloatToInt) Execute(p *Processor) error {
+	addr1, err := calculateAddress(p, c.BB, c.Address1, uint8(c.Address1&0x07))
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+	word, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение слова не удалось
+	}
+
+	// Округляем значение в соответствии с режимом
+	value := float64(word.D.F)
+	var rounded float64
+	switch c.Address2 {
+	case ROUND_NEAREST:
+		rounded = math.RoundToEven(value)
+	case ROUND_TRUNC:
+		rounded = math.Trunc(value)
+	case ROUND_FLOOR:
+		rounded = math.Floor(value)
+	case ROUND_CEIL:
+		rounded = math.Ceil(value)
+	default:
+		return fmt.Errorf("invalid rounding mode: %d", c.Address2)
+	}
+
+	// Проверяем, помещается ли результат в int32
+	var result int32
+	hasOverflow := false
+	switch {
+	case math.IsNaN(rounded):
+		hasOverflow = true
+	case rounded > math.MaxInt32:
+		result, hasOverflow = math.MaxInt32, true
+	case rounded < math.MinInt32:
+		result, hasOverflow = math.MinInt32, true
+	default:
+		result = int32(rounded)
+	}
+
+	if err := p.memory.WriteWord(int(addr1), IntWord(result)); err != nil {
+		return err // Возвращаем ошибку, если запись слова не удалась
+	}
+
+	p.UpdateArithmeticFlags(result, false, hasOverflow) // Обновляем флаги, отмечая переполнение
+	p.logf(LogDebug, "FloatToInt: %f -> %d (mode %d, overflow %t)", value, result, c.Address2, hasOverflow)
+	return nil // Завершаем выполнение функции без ошибок
+}
+
+// SqrtFloat command implementation
+type SqrtFloat struct {
+	CommandData // Встраиваем структуру CommandData, содержащую данные команды
+}
+
+// NewSqrtFloat создает новый экземпляр SqrtFloat с заданными параметрами
+func NewSqrtFloat(bb uint8, addr1, addr2 uint16) *SqrtFloat {
+	return &SqrtFloat{CommandData{
+		Opcode:   uint8(RSQRT), // Устанавливаем опкод для команды RSQRT
+		BB:       bb,           // Устанавливаем значение bb (режим адресации)
+		Address1: addr1,        // Адрес операнда и результата
+		Address2: addr2,        // Не используется
+	}}
+}
+
+// Execute выполняет команду SqrtFloat
+func (f *SqrtFloat) Execute(p *Processor) error {
+	return executeFloatMath(p, f.CommandData, "SqrtFloat", false, func(x, y float64) float64 { return math.Sqrt(x) })
+}
+
+// PowFloat command implementation
+type PowFloat struct {
+	CommandData // Встраиваем структуру CommandData, содержащую данные команды
+}
+
+// NewPowFloat создает новый экземпляр PowFloat с заданными параметрами
+func NewPowFloat(bb uint8, addr1, addr2 uint16) *PowFloat {
+	return &PowFloat{CommandData{
+		Opcode:   uint8(RPOW), // Устанавливаем опкод для команды RPOW
+		BB:       bb,          // Устанавливаем значение bb (режим адресации)
+		Address1: addr1,       // Адрес операнда и результата
+		Address2: addr2,       // Адрес показателя степени
+	}}
+}
+
+// Execute выполняет команду PowFloat
+func (f *PowFloat) Execute(p *Processor) error {
+	return executeFloatMath(p, f.CommandData, "PowFloat", true, func(x, y float64) float64 { return math.Pow(x, y) })
+}
+
+// LogFloat command implementation
+type LogFloat struct {
+	CommandData // Встраиваем структуру CommandData, содержащую данные команды
+}
+
+// NewLogFloat создает новый экземпляр LogFloat с заданными параметрами
+func NewLogFloat(bb uint8, addr1, addr2 uint16) *LogFloat {
+	return &LogFloat{CommandData{
+		Opcode:   uint8(RLOG), // Устанавливаем опкод для команды RLOG
+		BB:       bb,          // Устанавливаем значение bb (режим адресации)
+		Address1: addr1,       // Адрес операнда и результата
+		Address2: addr2,       // Не используется
+	}}
+}
+
+// Execute выполняет команду LogFloat
+func (f *LogFloat) Execute(p *Processor) error {
+	return executeFloatMath(p, f.CommandData, "LogFloat", false, func(x, y float64) float64 { return math.Log(x) })
+}
+
+// ExpFloat command implementation
+type ExpFloat struct {
+	CommandData // Встраиваем структуру CommandData, содержащую данные команды
+}
+
+// NewExpFloat создает новый экземпляр ExpFloat с заданными параметрами
+func NewExpFloat(bb uint8, addr1, addr2 uint16) *ExpFloat {
+	return &ExpFloat{CommandData{
+		Opcode:   uint8(REXP), // Устанавливаем опкод для команды REXP
+		BB:       bb,          // Устанавливаем значение bb (режим адресации)
+		Address1: addr1,       // Адрес операнда и результата
+		Address2: addr2,       // Не используется
+	}}
+}
+
+// Execute выполняет команду ExpFloat
+func (f *ExpFloat) Execute(p *Processor) error {
+	return executeFloatMath(p, f.CommandData, "ExpFloat", false, func(x, y float64) float64 { return math.Exp(x) })
+}
+
+// executeFloatMath применяет функцию пакета math к вещественному слову по адресу addr1
+// (и, для двуместных функций, к слову по адресу addr2) и записывает результат по addr1.
+// Выход за область определения дает NaN, как и в пакете math.
+func executeFloatMath(p *Processor, c CommandData, name string, binary bool, fn func(x, y float64) float64) error {
+	regIndex := uint8(c.Address1 & 0x07) // Получаем индекс регистра из младших 3 битов адреса
+
+	// Вычисляем адрес и читаем первый операнд
+	addr1, err := calculateAddress(p, c.BB, c.Address1, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+	word1, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение слова не удалось
+	}
+
+	// Читаем второй операнд для двуместных функций
+	var y float32
+	if binary {
+		addr2, err := calculateAddress(p, c.BB, c.Address2, regIndex)
+		if err != nil {
+			return err // Возвращаем ошибку, если вычисление адреса не удалось
+		}
+		word2, err := p.memory.ReadWord(int(addr2))
+		if err != nil {
+			return err // Возвращаем ошибку, если чтение слова не удалось
+		}
+		y = word2.D.F
+	}
+
+	// Вычисляем функцию и записываем результат по адресу addr1
+	x := word1.D.F
+	result := float32(fn(float64(x), float64(y)))
+	word1.SetFloat(result)
+	if err := p.memory.WriteWord(int(addr1), word1); err != nil {
+		return err // Возвращаем ошибку, если запись слова не удалась
+	}
+
+	p.UpdateFloatFlags(result) // Обновляем флаги процессора на основе результата
+	if binary {
+		p.logf(LogDebug, "%s: f(%f, %f) = %f", name, x, y, result)
+	} else {
+		p.logf(LogDebug, "%s: f(%f) = %f", name, x, result)
+	}
+	return nil // Завершаем выполнение функции без ошибок
+}
+
+// Структура InputInt, которая содержит данные команды
+type InputInt struct {
+	CommandData // Встраиваем структуру CommandData, содержащую данные команды
+}
+
+// Конструктор для создания нового объекта InputInt
+func NewInputInt(bb uint8, addr1, addr2 uint16) *InputInt {
+	// Возвращаем указатель на новый объект InputInt с заданными параметрами
+	return &InputInt{CommandData{
+		Opcode:   uint8(IIN), // Устанавливаем опкод для команды IIN (ввод целого числа)
+		BB:       bb,         // Устанавливаем значение bb (базовый регистр)
+		Address1: addr1,      // Устанавливаем адрес первого операнда
+		Address2: addr2,      // Устанавливаем адрес второго операнда (не используется)
+	}}
+}
+
+// Метод Execute выполняет команду InputInt
+func (i *InputInt) Execute(p *Processor) error {
+	fmt.Fprint(p.Out, "Enter integer value: ") // Запрашиваем ввод целого числа у пользователя
+	input, err := p.input(InputIIN, p.readInputLine)
+	if err != nil {
+		return err // Возвращаем ошибку, если воспроизводимая запись не совпала с программой
+	}
+	value, err := strconv.ParseInt(input.Value, 10, 32) // Преобразуем введенное значение в целое число
+	if err != nil {
+		return fmt.Errorf("invalid integer input: %v", err) // Возвращаем ошибку, если ввод некорректен
+	}
+
+	// Получаем индекс регистра из Address1 (нижние 3 бита), если в режиме регистра
+	regIndex := uint8(i.Address1 & 0x07)
+
+	// Вычисляем адрес для записи значения с помощью функции calculateAddress
+	addr1, err := calculateAddress(p, i.BB, i.Address1, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+
+	// Создаем новое слово с данными целого числа
+	word := IntWord(int32(value))
+
+	// Записываем слово в память по вычисленному адресу
+	err = p.memory.WriteWord(int(addr1), word)
+	if err != nil {
+		return err // Возвращаем ошибку, если запись слова не удалась
+	}
+
+	// Логируем сообщение о введенном значении
+	p.logf(LogDebug, "InputInt: Read value %d", value)
+	return nil // Завершаем выполнение функции без ошибок
+}
+
+// Структура OutputInt, которая содержит данные команды
+type OutputInt struct {
+	CommandData // Встраиваем структуру CommandData, содержащую данные команды
+}
+
+// Конструктор для создания нового объекта OutputInt
+func NewOutputInt(bb uint8, addr1, addr2 uint16) *OutputInt {
+	// Возвращаем указатель на новый объект OutputInt с заданными параметрами
+	return &OutputInt{CommandData{
+		Opcode:   uint8(IOUT), // Устанавливаем опкод для команды IOUT (вывод целого числа)
+		BB:       bb,          // Устанавливаем значение bb (базовый регистр)
+		Address1: addr1,       // Устанавливаем адрес первого операнда
+		Address2: addr2,       // Устанавливаем адрес второго операнда (не используется)
+	}}
+}
+
+// Метод Execute выполняет команду OutputInt
+func (o *OutputInt) Execute(p *Processor) error {
+	// Получаем индекс регистра из Address1 (нижние 3 бита), если в режиме регистра
+	regIndex := uint8(o.Address1 & 0x07)
+
+	// Вычисляем адрес для чтения значения с помощью функции calculateAddress
+	addr1, err := calculateAddress(p, o.BB, o.Address1, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+
+	// Читаем слово из памяти по адресу addr1
+	word, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение слова не удалось
+	}
+
+	// Учитываем вывод в квоте и выводим значение на экран
+	output := fmt.Sprintf("Output: %d\n", word.D.I)
+	if err := p.chargeOutput(len(output)); err != nil {
+		return err // Возвращаем ошибку, если превышена квота вывода
+	}
+	fmt.Fprint(p.Out, output)
+
+	// Логируем сообщение о выведенном значении
+	p.logf(LogDebug, "OutputInt: Value %d", word.D.I)
+	return nil // Завершаем выполнение функции без ошибок
+}
+
+// Структура InputFloat, которая содержит данные команды
+type InputFloat struct {
+	CommandData // Встраиваем структуру CommandData, содержащую данные команды
+}
+
+// Конструктор для создания нового объекта InputFloat
+func NewInputFloat(bb uint8, addr1, addr2 uint16) *InputFloat {
+	// Возвращаем указатель на новый объект InputFloat с заданными параметрами
+	return &InputFloat{CommandData{
+		Opcode:   uint8(RIN), // Устанавливаем опкод для команды RIN (ввод числа с плавающей точкой)
+		BB:       bb,         // Устанавливаем значение bb (базовый регистр)
+		Address1: addr1,      // Устанавливаем адрес первого операнда
+		Address2: addr2,      // Устанавливаем адрес второго операнда (не используется)
+	}}
+}
+
+// Метод Execute выполняет команду InputFloat
+func (i *InputFloat) Execute(p *Processor) error {
+	fmt.Fprint(p.Out, "Enter float value: ") // Запрашиваем ввод числа с плавающей точкой у пользователя
+	input, err := p.input(InputRIN, p.readInputLine)
+	if err != nil {
+		return err // Возвращаем ошибку, если воспроизводимая запись не совпала с программой
+	}
+	value, err := strconv.ParseFloat(input.Value, 32) // Преобразуем введенное значение в число с плавающей точкой (32 бита)
+	if err != nil {
+		return fmt.Errorf("invalid float input: %v", err) // Возвращаем ошибку, если ввод некорректен
+	}
+
+	// Получаем индекс регистра из Address1 (нижние 3 бита), если в режиме регистра
+	regIndex := uint8(i.Address1 & 0x07)
+
+	// Вычисляем адрес для записи значения с помощью функции calculateAddress
+	addr1, err := calculateAddress(p, i.BB, i.Address1, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+
+	// Создаем новое слово с данными числа с плавающей точкой
+	word := FloatWord(float32(value))          // Преобразуем значение в float32 и оборачиваем в структуру Word
+	err = p.memory.WriteWord(int(addr1), word) // Записываем слово в память по вычисленному адресу
+	if err != nil {
+		return err // Возвращаем ошибку, если запись слова не удалась
+	}
+
+	// Логируем сообщение о введенном значении
+	p.logf(LogDebug, "InputFloat: Read value %f", value)
+	return nil // Завершаем выполнение функции без ошибок
+}
+
+// Структура OutputFloat, которая содержит данные команды
+type OutputFloat struct {
+	CommandData // Встраиваем структуру CommandData, содержащую данные команды
+}
+
+// Конструктор для создания нового объекта OutputFloat
+func NewOutputFloat(bb uint8, addr1, addr2 uint16) *OutputFloat {
+	// Возвращаем указатель на новый объект OutputFloat с заданными параметрами
+	return &OutputFloat{CommandData{
+		Opcode:   uint8(ROUT), // Устанавливаем опкод для команды ROUT (вывод числа с плавающей точкой)
+		BB:       bb,          // Устанавливаем значение bb (базовый регистр)
+		Address1: addr1,       // Устанавливаем адрес первого операнда
+		Address2: addr2,       // Устанавливаем адрес второго операнда (не используется)
+	}}
+}
+
+// Метод Execute выполняет команду OutputFloat
+func (o *OutputFloat) Execute(p *Processor) error {
+	// Получаем индекс регистра из Address1 (нижние 3 бита), если в режиме регистра
+	regIndex := uint8(o.Address1 & 0x07)
+
+	// Вычисляем адрес для чтения значения с помощью функции calculateAddress
+	addr1, err := calculateAddress(p, o.BB, o.Address1, regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+
+	// Читаем слово из памяти по адресу addr1
+	word, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение слова не удалось
+	}
+
+	// Учитываем вывод в квоте и выводим значение на экран
+	output := fmt.Sprintf("Output: %f\n", word.D.F)
+	if err := p.chargeOutput(len(output)); err != nil {
+		return err // Возвращаем ошибку, если превышена квота вывода
+	}
+	fmt.Fprint(p.Out, output)
+
+	// Логируем сообщение о выведенном значении
+	p.logf(LogDebug, "OutputFloat: Value %f", word.D.F)
+	return nil // Завершаем выполнение функции без ошибок
+}
+
+// Структура InputChar, которая содержит данные команды
+type InputChar struct {
+	CommandData // Встраиваем структуру CommandData, содержащую данные команды
+}
+
+// Конструктор для создания нового объекта InputChar
+func NewInputChar(bb uint8, addr1, addr2 uint16) *InputChar {
+	return &InputChar{CommandData{
+		Opcode:   uint8(CIN), // Устанавливаем опкод для команды CIN (ввод символа)
+		BB:       bb,         // Устанавливаем значение bb (базовый регистр)
+		Address1: addr1,      // Устанавливаем адрес первого операнда
+		Address2: addr2,      // Устанавливаем адрес второго операнда (не используется)
+	}}
+}
+
+// Метод Execute выполняет команду InputChar: читает один байт ввода без приглашения
+// и записывает его код в память; в конце ввода записывается -1
+func (i *InputChar) Execute(p *Processor) error {
+	// Читаем ровно один байт, чтобы не забрать из ввода данные следующих команд
+	code, err := p.inputInt(InputCIN, func() (int64, error) {
+		var buf [1]byte
+		n, err := p.In.Read(buf[:])
+		if n == 1 {
+			return int64(buf[0]), nil
+		} else if err != nil && err != io.EOF {
+			return 0, fmt.Errorf("failed to read character: %w", err) // Возвращаем ошибку чтения, отличную от конца ввода
+		}
+		return -1, nil
+	})
+	if err != nil {
+		return err
+	}
+	value := int32(code)
+
+	// Вычисляем адрес для записи значения с помощью функции calculateAddress
+	addr1, err := calculateAddress(p, i.BB, i.Address1, uint8(i.Address1&0x07))
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+	if err := p.memory.WriteWord(int(addr1), IntWord(value)); err != nil {
+		return err // Возвращаем ошибку, если запись слова не удалась
+	}
+
+	p.logf(LogDebug, "InputChar: Read value %d", value)
+	return nil // Завершаем выполнение функции без ошибок
+}
+
+// Структура OutputChar, которая содержит данные команды
+type OutputChar struct {
+	CommandData // Встраиваем структуру CommandData, содержащую данные команды
+}
+
+// Конструктор для создания нового объекта OutputChar
+func NewOutputChar(bb uint8, addr1, addr2 uint16) *OutputChar {
+	return &OutputChar{CommandData{
+		Opcode:   uint8(COUT), // Устанавливаем опкод для команды COUT (вывод символа)
+		BB:       bb,          // Устанавливаем значение bb (базовый регистр)
+		Address1: addr1,       // Устанавливаем адрес первого операнда
+		Address2: addr2,       // Устанавливаем адрес второго операнда (не используется)
+	}}
+}
+
+// Метод Execute выполняет команду OutputChar: выводит символ с кодом из слова памяти
+// без префикса и перевода строки, чтобы программа могла собирать текст по символу
+func (o *OutputChar) Execute(p *Processor) error {
+	addr1, err := calculateAddress(p, o.BB, o.Address1, uint8(o.Address1&0x07))
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+	word, err := p.memory.ReadWord(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение слова не удалось
+	}
+
+	// Учитываем вывод в квоте и выводим символ
+	output := fmt.Sprintf("%c", rune(word.D.I))
+	if err := p.chargeOutput(len(output)); err != nil {
+		return err // Возвращаем ошибку, если превышена квота вывода
+	}
+	fmt.Fprint(p.Out, output)
+
+	p.logf(LogDebug, "OutputChar: Value %d", word.D.I)
+	return nil // Завершаем выполнение функции без ошибок
+}
+
+// Структура OutputString, которая содержит данные команды
+type OutputString struct {
+	CommandData // Встраиваем структуру CommandData, содержащую данные команды
+}
+
+// Конструктор для создания нового объекта OutputString
+func NewOutputString(bb uint8, addr1, addr2 uint16) *OutputString {
+	return &OutputString{CommandData{
+		Opcode:   uint8(SOUT), // Устанавливаем опкод для команды SOUT (вывод строки)
+		BB:       bb,          // Устанавливаем значение bb (базовый регистр)
+		Address1: addr1,       // Устанавливаем адрес первого слова строки
+		Address2: addr2,       // Устанавливаем адрес второго операнда (не используется)
+	}}
+}
+
+// Метод Execute выполняет команду OutputString: выводит упакованную строку, начиная
+// со слова по адресу Address1, до нулевого байта (см. PackString)
+func (o *OutputString) Execute(p *Processor) error {
+	addr1, err := calculateAddress(p, o.BB, o.Address1, uint8(o.Address1&0x07))
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+	text, err := p.readString(int(addr1))
+	if err != nil {
+		return err // Возвращаем ошибку, если строка не завершена или адрес недопустим
+	}
+
+	// Учитываем вывод в квоте и выводим строку
+	if err := p.chargeOutput(len(text)); err != nil {
+		return err // Возвращаем ошибку, если превышена квота вывода
+	}
+	fmt.Fprint(p.Out, text)
+
+	p.logf(LogDebug, "OutputString: [0x%X] %q", addr1, text)
+	return nil // Завершаем выполнение функции без ошибок
+}
+
+// Структура InputString, которая содержит данные команды
+type InputString struct {
+	CommandData // Встраиваем структуру CommandData, содержащую данные команды
+}
+
+// Конструктор для создания нового объекта InputString
+func NewInputString(bb uint8, addr1, addr2 uint16) *InputString {
+	return &InputString{CommandData{
+		Opcode:   uint8(SIN), // Устанавливаем опкод для команды SIN (ввод строки)
+		BB:       bb,         // Устанавливаем значение bb (базовый регистр)
+		Address1: addr1,      // Устанавливаем адрес буфера строки
+		Address2: addr2,      // Максимальная длина строки в символах
+	}}
+}
+
+// Метод Execute выполняет команду InputString: читает строку ввода без перевода строки
+// и записывает ее, упакованной, начиная с адреса Address1. Сохраняется не более Address2
+// символов; буфер должен вмещать Address2/CHARS_PER_WORD+1 слов. Фактическая длина
+// помещается в регистр R0 (a1), в конце ввода — -1.
+func (i *InputString) Execute(p *Processor) error {
+	input, err := p.input(InputSIN, func() (InputEvent, error) {
+		line, eof, err := readLine(p.In, int(i.Address2))
+		if err != nil {
+			return InputEvent{}, fmt.Errorf("failed to read string: %w", err) // Возвращаем ошибку чтения, отличную от конца ввода
+		}
+		return InputEvent{Value: line, EOF: eof}, nil
+	})
+	if err != nil {
+		return err
+	}
+	line, eof := input.Value, input.EOF
+
+	length := int32(len(line))
+	if eof {
+		length = -1 // Конец ввода: буфер получает пустую строку
+	}
+	addr1, err := calculateAddress(p, i.BB, i.Address1, uint8(i.Address1&0x07))
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+	if err := p.writeString(int(addr1), line); err != nil {
+		return err // Возвращаем ошибку, если буфер выходит за пределы памяти
+	}
+	p.registers[0] = length
+
+	p.logf(LogDebug, "InputString: [0x%X] %q, length %d", addr1, line, length)
+	return nil // Завершаем выполнение функции без ошибок
+}
+
+// LoadRegister command implementation
+type LoadRegister struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewLoadRegister создает новый экземпляр LoadRegister с заданными параметрами
+func NewLoadRegister(bb uint8, addr1, addr2 uint16) *LoadRegister {
+	return &LoadRegister{CommandData{
+		Opcode:   uint8(LOAD), // Устанавливаем код операции (Opcode) для команды LOAD
+		BB:       bb,          // Устанавливаем значение bb (возможно, это флаг или дополнительный байт)
+		Address1: addr1,       // Устанавливаем первый адрес (Address1)
+		Address2: addr2,       // Устанавливаем второй адрес (Address2)
+	}}
+}
+
+// Execute выполняет команду LoadRegister, загружая значение из памяти в регистр
+func (l *LoadRegister) Execute(p *Processor) error {
+	// Получаем индекс регистра из Address1 (используем младшие 3 бита)
+	regIndex := uint8(l.Address1 & 0x07)
+
+	// Загружаем слово из памяти по адресу Address2
+	word, err := p.memory.ReadWord(int(l.Address2))
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение из памяти не удалось
+	}
+
+	// Устанавливаем значение загруженного слова в указанный регистр
+	err = p.SetRegister(regIndex, word.D.I)
+	if err != nil {
+		return err // Возвращаем ошибку, если установка регистра не удалась
+	}
+
+	// Логируем сообщение о загрузке значения в регистр
+	p.logf(LogDebug, "LoadRegister: R%d = %d", regIndex, word.D.I)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// StoreRegister command implementation
+type StoreRegister struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewStoreRegister создает новый экземпляр StoreRegister с заданными параметрами
+func NewStoreRegister(bb uint8, addr1, addr2 uint16) *StoreRegister {
+	return &StoreRegister{CommandData{
+		Opcode:   uint8(STORE), // Устанавливаем код операции (Opcode) для команды STORE
+		BB:       bb,           // Устанавливаем значение bb (возможно, это флаг или дополнительный байт)
+		Address1: addr1,        // Устанавливаем адрес для записи (Address1)
+		Address2: addr2,        // Устанавливаем адрес для получения индекса регистра (Address2)
+	}}
+}
+
+// Execute выполняет команду StoreRegister, сохраняя значение из регистра в память
+func (s *StoreRegister) Execute(p *Processor) error {
+	// Получаем индекс регистра из Address2 (используем младшие 3 бита)
+	regIndex := uint8(s.Address2 & 0x07)
+
+	// Получаем значение из указанного регистра
+	value, err := p.GetRegister(regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если получение значения из регистра не удалось
+	}
+
+	// Создаем объект Word с загружаемым значением
+	word := IntWord(value)
+
+	// Записываем значение в память по адресу Address1
+	err = p.memory.WriteWord(int(s.Address1), word)
+	if err != nil {
+		return err // Возвращаем ошибку, если запись в память не удалась
+	}
+
+	// Логируем сообщение о сохранении значения в памяти
+	p.logf(LogDebug, "StoreRegister: [0x%X] = R%d (%d)", s.Address1, regIndex, value)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// AddRegisters command implementation
+type AddRegisters struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewAddRegisters создает новый экземпляр AddRegisters с заданными параметрами
+func NewAddRegisters(bb uint8, addr1, addr2 uint16) *AddRegisters {
+	return &AddRegisters{CommandData{
+		Opcode:   uint8(ADDR), // Устанавливаем код операции (Opcode) для команды ADDR
+		BB:       bb,          // Устанавливаем значение bb (возможно, это флаг или дополнительный байт)
+		Address1: addr1,       // Устанавливаем адрес для назначения результата (Address1)
+		Address2: addr2,       // Устанавливаем адрес источника (Address2)
+	}}
+}
+
+// Execute выполняет команду AddRegisters, складывая значения из двух регистров
+func (a *AddRegisters) Execute(p *Processor) error {
+	// Получаем индексы регистров из адресов (используем младшие 3 бита)
+	regDest := uint8(a.Address1 & 0x07) // Индекс регистра назначения
+	regSrc := uint8(a.Address2 & 0x07)  // Индекс регистра источника
+
+	// Получаем значение из регистра назначения
+	val1, err := p.GetRegister(regDest)
+	if err != nil {
+		return err // Возвращаем ошибку, если получение значения из регистра не удалось
+	}
+
+	// Получаем значение из регистра источника
+	val2, err := p.GetRegister(regSrc)
+	if err != nil {
+		return err // Возвращаем ошибку, если получение значения из регистра не удалось
+	}
+
+	// Складываем два значения
+	result := val1 + val2
+
+	// Устанавливаем результат в регистр назначения
+	err = p.SetRegister(regDest, result)
+	if err != nil {
+		return err // Возвращаем ошибку, если установка значения в регистр не удалась
+	}
+
+	// Обновляем флаги арифметических операций
+	hasOverflow := (val1 > 0 && val2 > 0 && result < 0) || // Проверка на переполнение
+		(val1 < 0 && val2 < 0 && result > 0) // Проверка на переполнение при отрицательных значениях
+	hasCarry := uint32(val1)+uint32(val2) > uint32(0x7FFFFFFF) // Проверка на перенос
+
+	// Обновляем флаги в процессоре
+	p.UpdateArithmeticFlags(result, hasCarry, hasOverflow)
+
+	// Логируем сообщение о результате сложения
+	p.logf(LogDebug, "AddRegisters: R%d = R%d + R%d (%d = %d + %d)",
+		regDest, regDest, regSrc, result, val1, val2)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// SubtractRegisters command implementation
+type SubtractRegisters struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewSubtractRegisters создает новый экземпляр SubtractRegisters с заданными параметрами
+func NewSubtractRegisters(bb uint8, addr1, addr2 uint16) *SubtractRegisters {
+	return &SubtractRegisters{CommandData{
+		Opcode:   uint8(SUBR), // Устанавливаем код операции (Opcode) для команды SUBR
+		BB:       bb,          // Устанавливаем значение bb (возможно, это флаг или дополнительный байт)
+		Address1: addr1,       // Устанавливаем адрес для назначения результата (Address1)
+		Address2: addr2,       // Устанавливаем адрес источника (Address2)
+	}}
+}
+
+// Execute выполняет команду SubtractRegisters, вычитая значения из двух регистров
+func (s *SubtractRegisters) Execute(p *Processor) error {
+	// Получаем индексы регистров из адресов (используем младшие 3 бита)
+	regDest := uint8(s.Address1 & 0x07) // Индекс регистра назначения
+	regSrc := uint8(s.Address2 & 0x07)  // Индекс регистра источника
+
+	// Получаем значение из регистра назначения
+	val1, err := p.GetRegister(regDest)
+	if err != nil {
+		return err // Возвращаем ошибку, если получение значения из регистра не удалось
+	}
+
+	// Получаем значение из регистра источника
+	val2, err := p.GetRegister(regSrc)
+	if err != nil {
+		return err // Возвращаем ошибку, если получение значения из регистра не удалось
+	}
+
+	// Вычитаем значение из регистра источника из значения регистра назначения
+	result := val1 - val2
+
+	// Устанавливаем результат в регистр назначения
+	err = p.SetRegister(regDest, result)
+	if err != nil {
+		return err // Возвращаем ошибку, если установка значения в регистр не удалась
+	}
+
+	// Обновляем флаги арифметических операций
+	hasOverflow := (val1 > 0 && val2 < 0 && result < 0) || // Проверка на переполнение
+		(val1 < 0 && val2 > 0 && result > 0) // Проверка на переполнение при различных знаках
+	hasCarry := uint32(val1) < uint32(val2) // Проверка на заимствование
+
+	// Обновляем флаги в процессоре
+	p.UpdateArithmeticFlags(result, hasCarry, hasOverflow)
+
+	// Логируем сообщение о результате вычитания
+	p.logf(LogDebug, "SubtractRegisters: R%d = R%d - R%d (%d = %d - %d)",
+		regDest, regDest, regSrc, result, val1, val2)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// MoveRegister command implementation
+type MoveRegister struct {
+	CommandData
+}
+
+func NewMoveRegister(bb uint8, addr1, addr2 uint16) *MoveRegister {
+	return &MoveRegister{CommandData{
+		Opcode:   uint8(MOVR),
+		BB:       bb,
+		Address1: addr1,
+		Address2: addr2,
+	}}
+}
+
+func (m *MoveRegister) Execute(p *Processor) error {
+	// Get register indices from addresses (lower 3 bits)
+	regDest := uint8(m.Address1 & 0x07)
+	regSrc := uint8(m.Address2 & 0x07)
+
+	// Move value from one register to another
+	value, err := p.GetRegister(regSrc)
+	if err != nil {
+		return err
+	}
+
+	err = p.SetRegister(regDest, value)
+	if err != nil {
+		return err
+	}
+
+	p.logf(LogDebug, "MoveRegister: R%d = R%d (%d)", regDest, regSrc, value)
+	return nil
+}
+
+// LoadFloatRegister реализация команды FLOAD
+type LoadFloatRegister struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewLoadFloatRegister создает новый экземпляр LoadFloatRegister с заданными параметрами
+func NewLoadFloatRegister(bb uint8, addr1, addr2 uint16) *LoadFloatRegister {
+	return &LoadFloatRegister{CommandData{
+		Opcode:   uint8(FLOAD), // Устанавливаем код операции (Opcode) для команды FLOAD
+		BB:       bb,           // Устанавливаем значение bb
+		Address1: addr1,        // Индекс вещественного регистра (Address1)
+		Address2: addr2,        // Адрес загружаемого слова (Address2)
+	}}
+}
+
+// Execute выполняет команду LoadFloatRegister, загружая вещественное число из памяти в регистр
+func (l *LoadFloatRegister) Execute(p *Processor) error {
+	regIndex := uint8(l.Address1 & 0x07) // Получаем индекс регистра из младших 3 битов адреса
+
+	word, err := p.memory.ReadWord(int(l.Address2))
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение из памяти не удалось
+	}
+	if err := p.SetFloatRegister(regIndex, word.D.F); err != nil {
+		return err // Возвращаем ошибку, если установка регистра не удалась
+	}
+
+	p.logf(LogDebug, "LoadFloatRegister: F%d = %f", regIndex, word.D.F)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// StoreFloatRegister реализация команды FSTORE
+type StoreFloatRegister struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewStoreFloatRegister создает новый экземпляр StoreFloatRegister с заданными параметрами
+func NewStoreFloatRegister(bb uint8, addr1, addr2 uint16) *StoreFloatRegister {
+	return &StoreFloatRegister{CommandData{
+		Opcode:   uint8(FSTORE), // Устанавливаем код операции (Opcode) для команды FSTORE
+		BB:       bb,            // Устанавливаем значение bb
+		Address1: addr1,         // Адрес для записи (Address1)
+		Address2: addr2,         // Индекс вещественного регистра (Address2)
+	}}
+}
+
+// Execute выполняет команду StoreFloatRegister, сохраняя вещественный регистр в память
+func (s *StoreFloatRegister) Execute(p *Processor) error {
+	regIndex := uint8(s.Address2 & 0x07) // Получаем индекс регистра из младших 3 битов адреса
+
+	value, err := p.GetFloatRegister(regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если получение значения из регистра не удалось
+	}
+	if err := p.memory.WriteWord(int(s.Address1), FloatWord(value)); err != nil {
+		return err // Возвращаем ошибку, если запись в память не удалась
+	}
+
+	p.logf(LogDebug, "StoreFloatRegister: [0x%X] = F%d (%f)", s.Address1, regIndex, value)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// AddFloatRegisters реализация команды FADDR
+type AddFloatRegisters struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewAddFloatRegisters создает новый экземпляр AddFloatRegisters с заданными параметрами
+func NewAddFloatRegisters(bb uint8, addr1, addr2 uint16) *AddFloatRegisters {
+	return &AddFloatRegisters{CommandData{
+		Opcode:   uint8(FADDR), // Устанавливаем код операции (Opcode) для команды FADDR
+		BB:       bb,           // Устанавливаем значение bb
+		Address1: addr1,        // Регистр назначения (Address1)
+		Address2: addr2,        // Регистр источника (Address2)
+	}}
+}
+
+// Execute выполняет команду AddFloatRegisters: Fd = Fd + Fs
+func (a *AddFloatRegisters) Execute(p *Processor) error {
+	return executeFloatRegisters(p, a.CommandData, "AddFloatRegisters", "+", func(x, y float32) float32 { return x + y })
+}
+
+// SubFloatRegisters реализация команды FSUBR
+type SubFloatRegisters struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewSubFloatRegisters создает новый экземпляр SubFloatRegisters с заданными параметрами
+func NewSubFloatRegisters(bb uint8, addr1, addr2 uint16) *SubFloatRegisters {
+	return &SubFloatRegisters{CommandData{
+		Opcode:   uint8(FSUBR), // Устанавливаем код операции (Opcode) для команды FSUBR
+		BB:       bb,           // Устанавливаем значение bb
+		Address1: addr1,        // Регистр назначения (Address1)
+		Address2: addr2,        // Регистр источника (Address2)
+	}}
+}
+
+// Execute выполняет команду SubFloatRegisters: Fd = Fd - Fs
+func (s *SubFloatRegisters) Execute(p *Processor) error {
+	return executeFloatRegisters(p, s.CommandData, "SubFloatRegisters", "-", func(x, y float32) float32 { return x - y })
+}
+
+// MulFloatRegisters реализация команды FMULR
+type MulFloatRegisters struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewMulFloatRegisters создает новый экземпляр MulFloatRegisters с заданными параметрами
+func NewMulFloatRegisters(bb uint8, addr1, addr2 uint16) *MulFloatRegisters {
+	return &MulFloatRegisters{CommandData{
+		Opcode:   uint8(FMULR), // Устанавливаем код операции (Opcode) для команды FMULR
+		BB:       bb,           // Устанавливаем значение bb
+		Address1: addr1,        // Регистр назначения (Address1)
+		Address2: addr2,        // Регистр источника (Address2)
+	}}
+}
+
+// Execute выполняет команду MulFloatRegisters: Fd = Fd * Fs
+func (m *MulFloatRegisters) Execute(p *Processor) error {
+	return executeFloatRegisters(p, m.CommandData, "MulFloatRegisters", "*", func(x, y float32) float32 { return x * y })
+}
+
+// DivFloatRegisters реализация команды FDIVR
+type DivFloatRegisters struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewDivFloatRegisters создает новый экземпляр DivFloatRegisters с заданными параметрами
+func NewDivFloatRegisters(bb uint8, addr1, addr2 uint16) *DivFloatRegisters {
+	return &DivFloatRegisters{CommandData{
+		Opcode:   uint8(FDIVR), // Устанавливаем код операции (Opcode) для команды FDIVR
+		BB:       bb,           // Устанавливаем значение bb
+		Address1: addr1,        // Регистр назначения (Address1)
+		Address2: addr2,        // Регистр источника (Address2)
+	}}
+}
+
+// Execute выполняет команду DivFloatRegisters: Fd = Fd / Fs
+func (d *DivFloatRegisters) Execute(p *Processor) error {
+	divisor, err := p.GetFloatRegister(uint8(d.Address2 & 0x07))
+	if err != nil {
+		return err // Возвращаем ошибку, если получение значения из регистра не удалось
+	}
+	// Деление на ноль обрабатывается так же, как в команде RDIV
+	if divisor == 0 {
+		p.logf(LogDebug, "DivFloatRegisters: Division by zero error") // Логируем сообщение об ошибке
+		return errDivideByZero()                                      // Возвращаем ошибку деления на ноль
+	}
+	return executeFloatRegisters(p, d.CommandData, "DivFloatRegisters", "/", func(x, y float32) float32 { return x / y })
+}
+
+// MoveFloatRegister реализация команды FMOVR
+type MoveFloatRegister struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewMoveFloatRegister создает новый экземпляр MoveFloatRegister с заданными параметрами
+func NewMoveFloatRegister(bb uint8, addr1, addr2 uint16) *MoveFloatRegister {
+	return &MoveFloatRegister{CommandData{
+		Opcode:   uint8(FMOVR), // Устанавливаем код операции (Opcode) для команды FMOVR
+		BB:       bb,           // Устанавливаем значение bb
+		Address1: addr1,        // Регистр назначения (Address1)
+		Address2: addr2,        // Регистр источника (Address2)
+	}}
+}
+
+// Execute выполняет команду MoveFloatRegister: Fd = Fs
+func (m *MoveFloatRegister) Execute(p *Processor) error {
+	regDest := uint8(m.Address1 & 0x07) // Индекс регистра назначения
+	regSrc := uint8(m.Address2 & 0x07)  // Индекс регистра источника
+
+	value, err := p.GetFloatRegister(regSrc)
+	if err != nil {
+		return err // Возвращаем ошибку, если получение значения из регистра не удалось
+	}
+	if err := p.SetFloatRegister(regDest, value); err != nil {
+		return err // Возвращаем ошибку, если установка регистра не удалась
+	}
+
+	p.logf(LogDebug, "MoveFloatRegister: F%d = F%d (%f)", regDest, regSrc, value)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// executeFloatRegisters выполняет бинарную операцию над двумя вещественными регистрами
+// и сохраняет результат в регистре назначения
+func executeFloatRegisters(p *Processor, c CommandData, name, symbol string, op func(x, y float32) float32) error {
+	regDest := uint8(c.Address1 & 0x07) // Индекс регистра назначения
+	regSrc := uint8(c.Address2 & 0x07)  // Индекс регистра источника
+
+	val1, err := p.GetFloatRegister(regDest)
+	if err != nil {
+		return err // Возвращаем ошибку, если получение значения из регистра не удалось
+	}
+	val2, err := p.GetFloatRegister(regSrc)
+	if err != nil {
+		return err // Возвращаем ошибку, если получение значения из регистра не удалось
+	}
+
+	result := op(val1, val2)
+	if err := p.SetFloatRegister(regDest, result); err != nil {
+		return err // Возвращаем ошибку, если установка регистра не удалась
+	}
+
+	p.UpdateFloatFlags(result) // Обновляем флаги процессора так же, как для вещественных операций в памяти
+	p.logf(LogDebug, "%s: F%d = F%d %s F%d (%f = %f %s %f)",
+		name, regDest, regDest, symbol, regSrc, result, val1, symbol, val2)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// HostCall command implementation
+type HostCall struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewHostCall создает новый экземпляр HostCall с заданными параметрами
+func NewHostCall(bb uint8, addr1, addr2 uint16) *HostCall {
+	return &HostCall{CommandData{
+		Opcode:   uint8(HCALL), // Устанавливаем код операции (Opcode) для команды HCALL
+		BB:       bb,           // Устанавливаем значение bb (режим адресации аргумента)
+		Address1: addr1,        // Номер сервиса хоста (Address1)
+		Address2: addr2,        // Адрес аргумента, передаваемого обработчику (Address2)
+	}}
+}
+
+// Execute выполняет команду HostCall, передавая управление обработчику хоста
+func (h *HostCall) Execute(p *Processor) error {
+	// Ищем обработчик, зарегистрированный для номера сервиса
+	handler, exists := p.hostCalls[h.Address1]
+	if !exists {
+		return fmt.Errorf("no host call handler registered for service %d", h.Address1)
+	}
+
+	// Вычисляем эффективный адрес аргумента (индекс регистра в младших 3 битах)
+	arg, err := calculateAddress(p, h.BB, h.Address2, uint8(h.Address2&0x07))
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+
+	p.logf(LogDebug, "HostCall: service %d, arg 0x%X", h.Address1, arg)
+	if err := handler(p, arg); err != nil {
+		return fmt.Errorf("host call %d failed: %w", h.Address1, err) // Возвращаем ошибку обработчика
+	}
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// Операции команды MAP, задаваемые первым словом управляющего блока
+const (
+	MAP_DETACH = 0 // Отключить регион
+	MAP_ATTACH = 1 // Подключить регион по адресу из второго слова блока
+	MAP_REBASE = 2 // Перенести регион на адрес из второго слова блока
+)
+
+// MapRegion command implementation
+type MapRegion struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewMapRegion создает новый экземпляр MapRegion с заданными параметрами
+func NewMapRegion(bb uint8, addr1, addr2 uint16) *MapRegion {
+	return &MapRegion{CommandData{
+		Opcode:   uint8(MAP), // Устанавливаем код операции (Opcode) для команды MAP
+		BB:       bb,         // Устанавливаем значение bb (режим адресации управляющего блока)
+		Address1: addr1,      // Индекс региона в таблице (Address1)
+		Address2: addr2,      // Адрес управляющего блока: операция и новый базовый адрес (Address2)
+	}}
+}
+
+// Execute выполняет команду MapRegion, изменяя карту памяти
+func (m *MapRegion) Execute(p *Processor) error {
+	// Находим регион по индексу в таблице
+	regions := p.memory.Regions()
+	if int(m.Address1) >= len(regions) {
+		return fmt.Errorf("invalid region index: %d", m.Address1)
+	}
+	region := regions[m.Address1]
+
+	// Вычисляем адрес управляющего блока и читаем операцию и базовый адрес
+	block, err := calculateAddress(p, m.BB, m.Address2, uint8(m.Address2&0x07))
+	if err != nil {
+		return err // Возвращаем ошибку, если вычисление адреса не удалось
+	}
+	opWord, err := p.memory.ReadWord(int(block))
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение операции не удалось
+	}
+	baseWord, err := p.memory.ReadWord(int(block) + WORD_SIZE)
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение базового адреса не удалось
+	}
+
+	switch opWord.D.I {
+	case MAP_DETACH:
+		err = p.memory.DetachRegion(region.Name)
+	case MAP_ATTACH:
+		err = p.memory.AttachRegion(region.Name, int(baseWord.D.I))
+	case MAP_REBASE:
+		err = p.memory.RebaseRegion(region.Name, int(baseWord.D.I))
+	default:
+		return fmt.Errorf("invalid MAP operation: %d", opWord.D.I)
+	}
+	if err != nil {
+		return err // Возвращаем ошибку изменения карты памяти
+	}
+
+	p.logf(LogDebug, "MapRegion: region %q op %d base 0x%X", region.Name, opWord.D.I, baseWord.D.I)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// CallSubroutine command implementation
+type CallSubroutine struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewCallSubroutine создает новый экземпляр CallSubroutine с заданными параметрами
+func NewCallSubroutine(bb uint8, addr1, addr2 uint16) *CallSubroutine {
+	return &CallSubroutine{CommandData{
+		Opcode:   uint8(CALL), // Устанавливаем код операции (Opcode) для команды CALL
+		BB:       bb,          // Устанавливаем значение bb (режим адресации)
+		Address1: addr1,       // Адрес подпрограммы (Address1)
+		Address2: addr2,       // Не используется
+	}}
+}
+
+// Execute выполняет команду CallSubroutine: сохраняет адрес возврата в стеке и переходит к подпрограмме
+func (c *CallSubroutine) Execute(p *Processor) error {
+	// Вычисляем адрес подпрограммы
+	target, err := calculateJumpTarget(p, c.CommandData)
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+
+	// Помещаем в стек адрес следующей за CALL команды
+	returnIP := uint16((int(p.psw.IP) + WORD_SIZE) % p.code.Size())
+	if err := p.push(IntWord(int32(returnIP))); err != nil {
+		return err // Возвращаем ошибку переполнения стека
+	}
+
+	p.callDepth++    // Увеличиваем глубину вложенности подпрограмм
+	p.jumpTo(target) // Передаем управление подпрограмме
+	p.logf(LogDebug, "CallSubroutine: Calling 0x%X, return to 0x%X, SP=0x%X", target, returnIP, p.psw.SP)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// Return command implementation
+type Return struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewReturn создает новый экземпляр Return с заданными параметрами
+func NewReturn(bb uint8, addr1, addr2 uint16) *Return {
+	return &Return{CommandData{
+		Opcode:   uint8(RET), // Устанавливаем код операции (Opcode) для команды RET
+		BB:       bb,         // Не используется
+		Address1: addr1,      // Не используется
+		Address2: addr2,      // Не используется
+	}}
+}
+
+// Execute выполняет команду Return: извлекает адрес возврата из стека и переходит по нему
+func (r *Return) Execute(p *Processor) error {
+	word, err := p.pop()
+	if err != nil {
+		return err // Возвращаем ошибку опустошения стека
+	}
+
+	if p.callDepth > 0 {
+		p.callDepth-- // Уменьшаем глубину вложенности подпрограмм
+	}
+	p.jumpTo(uint16(word.D.I)) // Возвращаемся по сохраненному адресу
+	p.logf(LogDebug, "Return: Returning to 0x%X, SP=0x%X", uint16(word.D.I), p.psw.SP)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// InterruptReturn command implementation
+type InterruptReturn struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewInterruptReturn создает новый экземпляр InterruptReturn с заданными параметрами
+func NewInterruptReturn(bb uint8, addr1, addr2 uint16) *InterruptReturn {
+	return &InterruptReturn{CommandData{
+		Opcode:   uint8(IRET), // Устанавливаем код операции (Opcode) для команды IRET
+		BB:       bb,          // Не используется
+		Address1: addr1,       // Не используется
+		Address2: addr2,       // Не используется
+	}}
+}
+
+// Execute выполняет команду InterruptReturn: восстанавливает IP и FLAGS, сохраненные при входе в прерывание
+func (r *InterruptReturn) Execute(p *Processor) error {
+	ipWord, err := p.pop()
+	if err != nil {
+		return err // Возвращаем ошибку опустошения стека
+	}
+	flagsWord, err := p.pop()
+	if err != nil {
+		return err // Возвращаем ошибку опустошения стека
+	}
+
+	p.SetFlags(uint16(flagsWord.D.I)) // Восстанавливаем флаги, включая разрешение прерываний
+	p.jumpTo(uint16(ipWord.D.I))      // Возвращаемся к прерванной команде
+	p.logf(LogDebug, "InterruptReturn: Returning to 0x%X, FLAGS=0x%04X", uint16(ipWord.D.I), uint16(flagsWord.D.I))
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// SoftwareInterrupt command implementation
+type SoftwareInterrupt struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewSoftwareInterrupt создает новый экземпляр SoftwareInterrupt с заданными параметрами
+func NewSoftwareInterrupt(bb uint8, addr1, addr2 uint16) *SoftwareInterrupt {
+	return &SoftwareInterrupt{CommandData{
+		Opcode:   uint8(INT), // Устанавливаем код операции (Opcode) для команды INT
+		BB:       bb,         // Не используется
+		Address1: addr1,      // Номер вектора в таблице прерываний (Address1)
+		Address2: addr2,      // Не используется
+	}}
+}
+
+// Execute выполняет команду SoftwareInterrupt: вызывает обработчик вектора Address1 так же,
+// как аппаратное прерывание. Номер сервиса обработчик читает из регистра R0 (a1),
+// после IRET выполнение продолжается со следующей команды.
+func (s *SoftwareInterrupt) Execute(p *Processor) error {
+	if int(s.Address1) >= NUM_VECTORS {
+		return fmt.Errorf("invalid interrupt vector: %d", s.Address1)
+	}
+	vector := uint8(s.Address1)
+	handler, err := p.vectorFor(vector)
+	if err != nil {
+		return err
+	}
+	if handler == 0 {
+		return fmt.Errorf("no handler installed for interrupt vector %d", vector)
+	}
+
+	p.logf(LogDebug, "SoftwareInterrupt: vector %d, service %d", vector, p.registers[0])
+	returnIP := uint16((int(p.psw.IP) + WORD_SIZE) % p.code.Size())
+	return p.enterInterrupt(vector, handler, returnIP)
+}
+
+// EnableInterrupts command implementation
+type EnableInterrupts struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewEnableInterrupts создает новый экземпляр EnableInterrupts с заданными параметрами
+func NewEnableInterrupts(bb uint8, addr1, addr2 uint16) *EnableInterrupts {
+	return &EnableInterrupts{CommandData{
+		Opcode:   uint8(EI), // Устанавливаем код операции (Opcode) для команды EI
+		BB:       bb,        // Не используется
+		Address1: addr1,     // Не используется
+		Address2: addr2,     // Не используется
+	}}
+}
+
+// Execute выполняет команду EnableInterrupts, разрешая доставку прерываний
+func (e *EnableInterrupts) Execute(p *Processor) error {
+	p.psw.InterruptFlag = true
+	p.logf(LogDebug, "EnableInterrupts: interrupts enabled")
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// DisableInterrupts command implementation
+type DisableInterrupts struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewDisableInterrupts создает новый экземпляр DisableInterrupts с заданными параметрами
+func NewDisableInterrupts(bb uint8, addr1, addr2 uint16) *DisableInterrupts {
+	return &DisableInterrupts{CommandData{
+		Opcode:   uint8(DI), // Устанавливаем код операции (Opcode) для команды DI
+		BB:       bb,        // Не используется
+		Address1: addr1,     // Не используется
+		Address2: addr2,     // Не используется
+	}}
+}
+
+// Execute выполняет команду DisableInterrupts, запрещая доставку прерываний
+func (d *DisableInterrupts) Execute(p *Processor) error {
+	p.psw.InterruptFlag = false
+	p.logf(LogDebug, "DisableInterrupts: interrupts disabled")
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// LoadSegment command implementation
+type LoadSegment struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewLoadSegment создает новый экземпляр LoadSegment с заданными параметрами
+func NewLoadSegment(bb uint8, addr1, addr2 uint16) *LoadSegment {
+	return &LoadSegment{CommandData{
+		Opcode:   uint8(SETSEG), // Устанавливаем код операции (Opcode) для команды SETSEG
+		BB:       bb,            // Не используется
+		Address1: addr1,         // Не используется
+		Address2: addr2,         // Не используется
+	}}
+}
+
+// Execute выполняет команду LoadSegment: база сегмента данных берется из регистра R0 (a1),
+// предел — из регистра R1 (a2). Нулевой предел выключает сегментацию.
+func (l *LoadSegment) Execute(p *Processor) error {
+	if err := p.SetSegment(int(p.registers[0]), int(p.registers[1])); err != nil {
+		return err // Возвращаем ошибку, если сегмент выходит за пределы памяти
+	}
+	p.logf(LogDebug, "LoadSegment: base=0x%X, limit=0x%X", p.registers[0], p.registers[1])
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// RandomNumber command implementation
+type RandomNumber struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewRandomNumber создает новый экземпляр RandomNumber с заданными параметрами
+func NewRandomNumber(bb uint8, addr1, addr2 uint16) *RandomNumber {
+	return &RandomNumber{CommandData{
+		Opcode:   uint8(RND), // Устанавливаем код операции (Opcode) для команды RND
+		BB:       bb,         // Устанавливаем значение bb (режим адресации)
+		Address1: addr1,      // Адрес результата (Address1)
+		Address2: addr2,      // Флаги: RND_FLOAT выбирает вещественный результат
+	}}
+}
+
+// Execute выполняет команду RandomNumber: записывает по адресу Address1 неотрицательное
+// целое число или, если в Address2 установлен RND_FLOAT, вещественное число из [0, 1)
+func (r *RandomNumber) Execute(p *Processor) error {
+	addr, err := calculateAddress(p, r.BB, r.Address1, uint8(r.Address1&0x07))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+	float := r.Address2&RND_FLOAT != 0
+	input, err := p.input(InputRND, func() (InputEvent, error) {
+		if float {
+			return InputEvent{Value: strconv.FormatFloat(float64(p.rng.Float32()), 'g', -1, 32)}, nil
+		}
+		return InputEvent{Value: strconv.FormatInt(int64(p.rng.Int31()), 10)}, nil
+	})
+	if err != nil {
+		return err // Возвращаем ошибку, если воспроизводимая запись не совпала с программой
+	}
+	var word Word
+	if float {
+		value, err := strconv.ParseFloat(input.Value, 32)
+		if err != nil {
+			return fmt.Errorf("invalid recorded rnd value %q", input.Value)
+		}
+		word = FloatWord(float32(value))
+	} else {
+		value, err := strconv.ParseInt(input.Value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid recorded rnd value %q", input.Value)
+		}
+		word = IntWord(int32(value))
+	}
+	if err := p.memory.WriteWord(int(addr), word); err != nil {
+		return err // Возвращаем ошибку, если запись в память не удалась
+	}
+	p.logf(LogDebug, "RandomNumber: [0x%X] = %s", addr, describeWord(word))
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// ReadClock command implementation
+type ReadClock struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewReadClock создает новый экземпляр ReadClock с заданными параметрами
+func NewReadClock(bb uint8, addr1, addr2 uint16) *ReadClock {
+	return &ReadClock{CommandData{
+		Opcode:   uint8(TIME), // Устанавливаем код операции (Opcode) для команды TIME
+		BB:       bb,          // Не используется
+		Address1: addr1,       // Индекс регистра результата (младшие 3 бита)
+		Address2: addr2,       // Единица времени: TIME_INSTRUCTIONS или TIME_MILLIS
+	}}
+}
+
+// Execute выполняет команду ReadClock, загружая в регистр число выполненных инструкций
+// или миллисекунды, прошедшие с запуска программы
+func (c *ReadClock) Execute(p *Processor) error {
+	regIndex := uint8(c.Address1 & 0x07)
+	var value int64
+	switch c.Address2 {
+	case TIME_INSTRUCTIONS:
+		value = int64(p.usage.Instructions)
+	case TIME_MILLIS:
+		millis, err := p.inputInt(InputTIME, func() (int64, error) { return p.ElapsedMillis(), nil })
+		if err != nil {
+			return err // Возвращаем ошибку, если воспроизводимая запись не совпала с программой
+		}
+		value = millis
+	default:
+		return fmt.Errorf("invalid clock selector: %d", c.Address2)
+	}
+	if err := p.SetRegister(regIndex, int32(value)); err != nil {
+		return err // Возвращаем ошибку, если установка регистра не удалась
+	}
+	p.logf(LogDebug, "ReadClock: R%d = %d", regIndex, int32(value))
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// Sleep command implementation
+type Sleep struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewSleep создает новый экземпляр Sleep с заданными параметрами
+func NewSleep(bb uint8, addr1, addr2 uint16) *Sleep {
+	return &Sleep{CommandData{
+		Opcode:   uint8(SLEEP), // Устанавливаем код операции (Opcode) для команды SLEEP
+		BB:       bb,           // Устанавливаем значение bb (режим адресации)
+		Address1: addr1,        // Адрес слова с длительностью в миллисекундах (Address1)
+		Address2: addr2,        // Не используется
+	}}
+}
+
+// Execute выполняет команду Sleep, приостанавливая программу на число миллисекунд
+// из слова по адресу Address1 (по виртуальным часам — переводя их вперед)
+func (s *Sleep) Execute(p *Processor) error {
+	addr, err := calculateAddress(p, s.BB, s.Address1, uint8(s.Address1&0x07))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+	word, err := p.memory.ReadWord(int(addr))
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение из памяти не удалось
+	}
+	if word.D.I < 0 {
+		return fmt.Errorf("negative sleep duration: %d", word.D.I)
+	}
+	p.logf(LogDebug, "Sleep: %d ms", word.D.I)
+	return p.sleep(int64(word.D.I))
+}
+
+// MemoryCopy command implementation
+type MemoryCopy struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewMemoryCopy создает новый экземпляр MemoryCopy с заданными параметрами
+func NewMemoryCopy(bb uint8, addr1, addr2 uint16) *MemoryCopy {
+	return &MemoryCopy{CommandData{
+		Opcode:   uint8(MEMCPY), // Устанавливаем код операции (Opcode) для команды MEMCPY
+		BB:       bb,            // Устанавливаем значение bb (режим адресации)
+		Address1: addr1,         // Адрес первого слова приемника
+		Address2: addr2,         // Адрес первого слова источника
+	}}
+}
+
+// Execute выполняет команду MemoryCopy: копирует R1 (a2) слов из Address2 в Address1.
+// Перекрывающиеся блоки копируются так, как если бы источник сначала был сохранен целиком.
+func (c *MemoryCopy) Execute(p *Processor) error {
+	dst, src, count, err := blockOperands(p, c.CommandData)
+	if err != nil {
+		return err
+	}
+	if err := checkBlock(p, "copy", src, count); err != nil {
+		return err
+	}
+	if err := checkBlock(p, "copy", dst, count); err != nil {
+		return err
+	}
+	// Если приемник начинается внутри источника, копируем с конца, чтобы не затереть непрочитанные слова
+	first, last, step := 0, count, 1
+	if dst > src && dst < src+count*WORD_SIZE {
+		first, last, step = count-1, -1, -1
+	}
+	for i := first; i != last; i += step {
+		word, err := p.memory.ReadWord(src + i*WORD_SIZE)
+		if err != nil {
+			return err // Возвращаем ошибку, если чтение из памяти не удалось
+		}
+		if err := p.memory.WriteWord(dst+i*WORD_SIZE, word); err != nil {
+			return err // Возвращаем ошибку, если запись в память не удалась
+		}
+	}
+	p.logf(LogDebug, "MemoryCopy: %d word(s) 0x%X -> 0x%X", count, src, dst)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// MemoryFill command implementation
+type MemoryFill struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewMemoryFill создает новый экземпляр MemoryFill с заданными параметрами
+func NewMemoryFill(bb uint8, addr1, addr2 uint16) *MemoryFill {
+	return &MemoryFill{CommandData{
+		Opcode:   uint8(MEMSET), // Устанавливаем код операции (Opcode) для команды MEMSET
+		BB:       bb,            // Устанавливаем значение bb (режим адресации)
+		Address1: addr1,         // Адрес первого слова блока
+		Address2: addr2,         // Адрес слова со значением заполнения
+	}}
+}
+
+// Execute выполняет команду MemoryFill: записывает слово из Address2 в R1 (a2) слов,
+// начиная с Address1
+func (c *MemoryFill) Execute(p *Processor) error {
+	dst, src, count, err := blockOperands(p, c.CommandData)
+	if err != nil {
+		return err
+	}
+	if err := checkBlock(p, "fill", dst, count); err != nil {
+		return err
+	}
+	word, err := p.memory.ReadWord(src)
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение значения не удалось
+	}
+	for i := 0; i < count; i++ {
+		if err := p.memory.WriteWord(dst+i*WORD_SIZE, word); err != nil {
+			return err // Возвращаем ошибку, если запись в память не удалась
+		}
+	}
+	p.logf(LogDebug, "MemoryFill: %d word(s) at 0x%X = %s", count, dst, describeWord(word))
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// blockOperands вычисляет адреса блочной команды и число слов из регистра R1 (a2)
+func blockOperands(p *Processor, c CommandData) (dst, src, count int, err error) {
+	regIndex := uint8(c.Address1 & 0x07)
+	addr1, err := calculateAddress(p, c.BB, c.Address1, regIndex)
+	if err != nil {
+		return 0, 0, 0, err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+	addr2, err := calculateAddress(p, c.BB, c.Address2, regIndex)
+	if err != nil {
+		return 0, 0, 0, err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+	if p.registers[1] < 0 {
+		return 0, 0, 0, fmt.Errorf("negative block length: %d", p.registers[1])
+	}
+	return int(addr1), int(addr2), int(p.registers[1]), nil
+}
+
+// checkBlock проверяет до начала операции, что блок из count слов с адреса address
+// целиком лежит в памяти, чтобы команда не оставила блок записанным частично
+func checkBlock(p *Processor, operation string, address, count int) error {
+	if count > 0 && address+count*WORD_SIZE > p.memory.Size() {
+		return &MemoryError{
+			Operation: operation,
+			Address:   address,
+			Message:   fmt.Sprintf("block of %d word(s) is out of range [0x0-0x%X]", count, p.memory.Size()-1),
+		}
+	}
+	return nil
+}
+
+// CompareBlock command implementation
+type CompareBlock struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewCompareBlock создает новый экземпляр CompareBlock с заданными параметрами
+func NewCompareBlock(bb uint8, addr1, addr2 uint16) *CompareBlock {
+	return &CompareBlock{CommandData{
+		Opcode:   uint8(SCMP), // Устанавливаем код операции (Opcode) для команды SCMP
+		BB:       bb,          // Устанавливаем значение bb (режим адресации)
+		Address1: addr1,       // Адрес первого блока
+		Address2: addr2,       // Адрес второго блока
+	}}
+}
+
+// Execute выполняет команду CompareBlock: сравнивает R1 (a2) слов блоков Address1 и Address2
+// и устанавливает флаги, как CMP для первой пары различающихся слов. Если блоки равны,
+// устанавливается только ZF. Индекс первого различия (или длина блока) помещается в R0 (a1).
+func (c *CompareBlock) Execute(p *Processor) error {
+	addr1, addr2, count, err := blockOperands(p, c.CommandData)
+	if err != nil {
+		return err
+	}
+	if err := checkBlock(p, "compare", addr1, count); err != nil {
+		return err
+	}
+	if err := checkBlock(p, "compare", addr2, count); err != nil {
+		return err
+	}
+	index := 0
+	var a, b int32
+	for ; index < count; index++ {
+		word1, err := p.memory.ReadWord(addr1 + index*WORD_SIZE)
+		if err != nil {
+			return err // Возвращаем ошибку, если чтение из памяти не удалось
+		}
+		word2, err := p.memory.ReadWord(addr2 + index*WORD_SIZE)
+		if err != nil {
+			return err // Возвращаем ошибку, если чтение из памяти не удалось
+		}
+		if word1.D.I != word2.D.I {
+			a, b = word1.D.I, word2.D.I
+			break // Первое различие определяет результат
+		}
+	}
+	compareInts(p, a, b) // Для равных блоков a == b == 0: устанавливается только ZF
+	p.registers[0] = int32(index)
+	p.logf(LogDebug, "CompareBlock: %d word(s) 0x%X ? 0x%X, first difference at %d", count, addr1, addr2, index)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// PushMemory command implementation
+type PushMemory struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewPushMemory создает новый экземпляр PushMemory с заданными параметрами
+func NewPushMemory(bb uint8, addr1, addr2 uint16) *PushMemory {
+	return &PushMemory{CommandData{
+		Opcode:   uint8(PUSH), // Устанавливаем код операции (Opcode) для команды PUSH
+		BB:       bb,          // Устанавливаем значение bb (режим адресации)
+		Address1: addr1,       // Адрес слова, помещаемого в стек (Address1)
+		Address2: addr2,       // Не используется
+	}}
+}
+
+// Execute выполняет команду PushMemory, помещая слово памяти в стек
+func (c *PushMemory) Execute(p *Processor) error {
+	addr, err := calculateAddress(p, c.BB, c.Address1, uint8(c.Address1&0x07))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+	word, err := p.memory.ReadWord(int(addr))
+	if err != nil {
+		return err // Возвращаем ошибку, если чтение из памяти не удалось
+	}
+	if err := p.push(word); err != nil {
+		return err // Возвращаем ошибку переполнения стека
+	}
+	p.logf(LogDebug, "PushMemory: [0x%X] -> stack, SP=0x%X", addr, p.psw.SP)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// PopMemory command implementation
+type PopMemory struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewPopMemory создает новый экземпляр PopMemory с заданными параметрами
+func NewPopMemory(bb uint8, addr1, addr2 uint16) *PopMemory {
+	return &PopMemory{CommandData{
+		Opcode:   uint8(POP), // Устанавливаем код операции (Opcode) для команды POP
+		BB:       bb,         // Устанавливаем значение bb (режим адресации)
+		Address1: addr1,      // Адрес, куда записывается слово из стека (Address1)
+		Address2: addr2,      // Не используется
+	}}
+}
+
+// Execute выполняет команду PopMemory, извлекая слово из стека в память
+func (c *PopMemory) Execute(p *Processor) error {
+	addr, err := calculateAddress(p, c.BB, c.Address1, uint8(c.Address1&0x07))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+	word, err := p.pop()
+	if err != nil {
+		return err // Возвращаем ошибку опустошения стека
+	}
+	if err := p.memory.WriteWord(int(addr), word); err != nil {
+		return err // Возвращаем ошибку, если запись в память не удалась
+	}
+	p.logf(LogDebug, "PopMemory: stack -> [0x%X], SP=0x%X", addr, p.psw.SP)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// PushRegister command implementation
+type PushRegister struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewPushRegister создает новый экземпляр PushRegister с заданными параметрами
+func NewPushRegister(bb uint8, addr1, addr2 uint16) *PushRegister {
+	return &PushRegister{CommandData{
+		Opcode:   uint8(PUSHR), // Устанавливаем код операции (Opcode) для команды PUSHR
+		BB:       bb,           // Не используется
+		Address1: addr1,        // Индекс регистра в младших 3 битах (Address1)
+		Address2: addr2,        // Не используется
+	}}
+}
+
+// Execute выполняет команду PushRegister, помещая значение регистра в стек
+func (c *PushRegister) Execute(p *Processor) error {
+	regIndex := uint8(c.Address1 & 0x07) // Получаем индекс регистра из младших 3 битов адреса
+	value, err := p.GetRegister(regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если получение значения из регистра не удалось
+	}
+	if err := p.push(IntWord(value)); err != nil {
+		return err // Возвращаем ошибку переполнения стека
+	}
+	p.logf(LogDebug, "PushRegister: R%d (%d) -> stack, SP=0x%X", regIndex, value, p.psw.SP)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// PopRegister command implementation
+type PopRegister struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewPopRegister создает новый экземпляр PopRegister с заданными параметрами
+func NewPopRegister(bb uint8, addr1, addr2 uint16) *PopRegister {
+	return &PopRegister{CommandData{
+		Opcode:   uint8(POPR), // Устанавливаем код операции (Opcode) для команды POPR
+		BB:       bb,          // Не используется
+		Address1: addr1,       // Индекс регистра в младших 3 битах (Address1)
+		Address2: addr2,       // Не используется
+	}}
+}
+
+// Execute выполняет команду PopRegister, извлекая значение из стека в регистр
+func (c *PopRegister) Execute(p *Processor) error {
+	regIndex := uint8(c.Address1 & 0x07) // Получаем индекс регистра из младших 3 битов адреса
+	word, err := p.pop()
+	if err != nil {
+		return err // Возвращаем ошибку опустошения стека
+	}
+	if err := p.SetRegister(regIndex, word.D.I); err != nil {
+		return err // Возвращаем ошибку, если установка регистра не удалась
+	}
+	p.logf(LogDebug, "PopRegister: stack -> R%d (%d), SP=0x%X", regIndex, word.D.I, p.psw.SP)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// CoreID command implementation
+type CoreID struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewCoreID создает новый экземпляр CoreID с заданными параметрами
+func NewCoreID(bb uint8, addr1, addr2 uint16) *CoreID {
+	return &CoreID{CommandData{
+		Opcode:   uint8(CPUID), // Устанавливаем код операции (Opcode) для команды CPUID
+		BB:       bb,           // Не используется
+		Address1: addr1,        // Индекс регистра результата (младшие 3 бита)
+		Address2: addr2,        // Не используется
+	}}
+}
+
+// Execute выполняет команду CoreID, загружая в регистр номер ядра (0 — первое ядро)
+func (c *CoreID) Execute(p *Processor) error {
+	regIndex := uint8(c.Address1 & 0x07)
+	if err := p.SetRegister(regIndex, int32(p.coreID)); err != nil {
+		return err // Возвращаем ошибку, если установка регистра не удалась
+	}
+	p.logf(LogDebug, "CoreID: R%d = %d", regIndex, p.coreID)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// Exchange command implementation
+type Exchange struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewExchange создает новый экземпляр Exchange с заданными параметрами
+func NewExchange(bb uint8, addr1, addr2 uint16) *Exchange {
+	return &Exchange{CommandData{
+		Opcode:   uint8(XCHG), // Устанавливаем код операции (Opcode) для команды XCHG
+		BB:       bb,          // Не используется
+		Address1: addr1,       // Индекс регистра в младших 3 битах (Address1)
+		Address2: addr2,       // Адрес слова памяти (Address2)
+	}}
+}
+
+// Execute выполняет команду Exchange: регистр получает старое значение слова памяти,
+// а слово — значение регистра. Другие ядра и хост не могут вклиниться между чтением
+// и записью, поэтому XCHG подходит для спин-блокировок.
+func (x *Exchange) Execute(p *Processor) error {
+	regIndex := uint8(x.Address1 & 0x07)
+	value, err := p.GetRegister(regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если получение значения из регистра не удалось
+	}
+	word, err := p.memory.updateWord(int(x.Address2), func(Word) (Word, bool) { return IntWord(value), true })
+	if err != nil {
+		return err // Возвращаем ошибку, если обращение к памяти не удалось
+	}
+	if err := p.SetRegister(regIndex, word.D.I); err != nil {
+		return err // Возвращаем ошибку, если установка регистра не удалась
+	}
+	p.logf(LogDebug, "Exchange: R%d = %d, [0x%X] = %d", regIndex, word.D.I, x.Address2, value)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// CompareAndSwap command implementation
+type CompareAndSwap struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewCompareAndSwap создает новый экземпляр CompareAndSwap с заданными параметрами
+func NewCompareAndSwap(bb uint8, addr1, addr2 uint16) *CompareAndSwap {
+	return &CompareAndSwap{CommandData{
+		Opcode:   uint8(CAS), // Устанавливаем код операции (Opcode) для команды CAS
+		BB:       bb,         // Устанавливаем значение bb (режим адресации)
+		Address1: addr1,      // Адрес слова памяти (Address1)
+		Address2: addr2,      // Не используется
+	}}
+}
+
+// Execute выполняет команду CompareAndSwap: если слово по адресу Address1 равно a1,
+// в него записывается a2, иначе прежнее значение слова загружается в a1. Флаги
+// устанавливаются, как CMP слова и a1: ZF = 1 означает, что обмен выполнен.
+// Сравнение и запись атомарны относительно других ядер и хоста.
+func (c *CompareAndSwap) Execute(p *Processor) error {
+	addr, err := calculateAddress(p, c.BB, c.Address1, uint8(c.Address1&0x07))
+	if err != nil {
+		return err // Возвращаем ошибку, если произошла ошибка при вычислении адреса
+	}
+	expected, desired := p.registers[0], p.registers[1]
+	word, err := p.memory.updateWord(int(addr), func(old Word) (Word, bool) {
+		return IntWord(desired), old.D.I == expected
+	})
+	if err != nil {
+		return err // Возвращаем ошибку, если обращение к памяти не удалось
+	}
+	compareInts(p, word.D.I, expected) // ZF показывает, выполнен ли обмен
+	if word.D.I != expected {
+		p.registers[0] = word.D.I // Текущее значение для следующей попытки
+	}
+	p.logf(LogDebug, "CompareAndSwap: [0x%X] = %d, expected %d, swapped %t", addr, word.D.I, expected, word.D.I == expected)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// FetchAdd command implementation
+type FetchAdd struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewFetchAdd создает новый экземпляр FetchAdd с заданными параметрами
+func NewFetchAdd(bb uint8, addr1, addr2 uint16) *FetchAdd {
+	return &FetchAdd{CommandData{
+		Opcode:   uint8(XADD), // Устанавливаем код операции (Opcode) для команды XADD
+		BB:       bb,          // Не используется
+		Address1: addr1,       // Индекс регистра в младших 3 битах (Address1)
+		Address2: addr2,       // Адрес слова памяти (Address2)
+	}}
+}
+
+// Execute выполняет команду FetchAdd: слово по адресу Address2 увеличивается на
+// значение регистра, а регистр получает прежнее значение слова. Флаги
+// устанавливаются по сумме, как у IADD. Сложение атомарно относительно других ядер
+// и хоста.
+func (f *FetchAdd) Execute(p *Processor) error {
+	regIndex := uint8(f.Address1 & 0x07)
+	value, err := p.GetRegister(regIndex)
+	if err != nil {
+		return err // Возвращаем ошибку, если получение значения из регистра не удалось
+	}
+	word, err := p.memory.updateWord(int(f.Address2), func(old Word) (Word, bool) {
+		return IntWord(old.D.I + value), true
+	})
+	if err != nil {
+		return err // Возвращаем ошибку, если обращение к памяти не удалось
+	}
+	result := word.D.I + value
+	hasOverflow := (word.D.I > 0 && value > 0 && result < 0) ||
+		(word.D.I < 0 && value < 0 && result > 0) // Проверка на переполнение
+	hasCarry := uint32(word.D.I)+uint32(value) > uint32(0x7FFFFFFF) // Проверка на перенос
+	p.UpdateArithmeticFlags(result, hasCarry, hasOverflow)          // Обновляем арифметические флаги процессора
+	if err := p.SetRegister(regIndex, word.D.I); err != nil {
+		return err // Возвращаем ошибку, если установка регистра не удалась
+	}
+	p.logf(LogDebug, "FetchAdd: R%d = %d, [0x%X] = %d", regIndex, word.D.I, f.Address2, result)
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
+
+// Fence command implementation
+type Fence struct {
+	CommandData // Встраиваемый тип CommandData, который содержит общие данные команды
+}
+
+// NewFence создает новый экземпляр Fence с заданными параметрами
+func NewFence(bb uint8, addr1, addr2 uint16) *Fence {
+	return &Fence{CommandData{
+		Opcode:   uint8(FENCE), // Устанавливаем код операции (Opcode) для команды FENCE
+		BB:       bb,           // Не используется
+		Address1: addr1,        // Не используется
+		Address2: addr2,        // Не используется
+	}}
+}
+
+// Execute выполняет команду Fence: все обращения к памяти и устройствам до FENCE
+// становятся видимыми другим ядрам и устройствам раньше любого обращения после
+// нее. Ядра и встроенные устройства и так выполняют обращения по порядку, поэтому
+// команда лишь завершает отложенные записи устройств, реализующих Fencer.
+func (f *Fence) Execute(p *Processor) error {
+	if err := p.memory.fence(); err != nil {
+		return err // Возвращаем ошибку, если устройство не смогло завершить записи
+	}
+	p.logf(LogDebug, "Fence")
+	return nil // Возвращаем nil, указывая на успешное выполнение команды
+}
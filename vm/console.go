@@ -0,0 +1,83 @@
+package vm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Регистры консоли (смещения относительно начала окна устройства)
+const (
+	CONSOLE_CHAR_OUT = 0 * WORD_SIZE // Запись выводит символ с кодом из слова
+	CONSOLE_INT_OUT  = 1 * WORD_SIZE // Запись выводит целое число в десятичном виде
+	CONSOLE_CHAR_IN  = 2 * WORD_SIZE // Чтение возвращает следующий байт ввода или -1 в конце ввода
+	CONSOLE_INT_IN   = 3 * WORD_SIZE // Чтение возвращает следующее целое число из ввода
+
+	CONSOLE_SIZE = 4 * WORD_SIZE // Размер окна консоли в байтах
+	CONSOLE_BASE = 0xF000        // Адрес окна консоли по умолчанию
+)
+
+// Console — устройство консоли, отображаемое в память. Ввод буферизуется, поэтому
+// программа может читать его по символу без блокирующих приглашений IIN/RIN.
+type Console struct {
+	in     *bufio.Reader     // Буферизованный ввод
+	out    io.Writer         // Вывод
+	charge func(n int) error // Учет вывода в квоте процессора; nil — консоль не отображена
+}
+
+// NewConsole создает консоль, читающую из in и пишущую в out
+func NewConsole(in io.Reader, out io.Writer) *Console {
+	return &Console{in: bufio.NewReader(in), out: out}
+}
+
+// Read читает регистр ввода консоли
+func (c *Console) Read(addr int) (Word, error) {
+	switch addr {
+	case CONSOLE_CHAR_IN:
+		b, err := c.in.ReadByte()
+		if err == io.EOF {
+			return IntWord(-1), nil // Конец ввода
+		}
+		if err != nil {
+			return Word{}, err
+		}
+		return IntWord(int32(b)), nil
+	case CONSOLE_INT_IN:
+		var value int32
+		if _, err := fmt.Fscan(c.in, &value); err != nil {
+			return Word{}, fmt.Errorf("invalid integer input: %v", err)
+		}
+		return IntWord(value), nil
+	}
+	return Word{}, fmt.Errorf("console register 0x%X is not readable", addr)
+}
+
+// Write записывает слово в регистр вывода консоли. Вывод учитывается в квоте
+// Quotas.MaxOutputBytes, как вывод команд COUT, IOUT и SOUT.
+func (c *Console) Write(addr int, word Word) error {
+	var output string
+	switch addr {
+	case CONSOLE_CHAR_OUT:
+		output = fmt.Sprintf("%c", rune(word.D.I))
+	case CONSOLE_INT_OUT:
+		output = fmt.Sprintf("%d", word.D.I)
+	default:
+		return fmt.Errorf("console register 0x%X is not writable", addr)
+	}
+	if c.charge != nil {
+		if err := c.charge(len(output)); err != nil {
+			return err // Квота вывода исчерпана: вывод не выполняется
+		}
+	}
+	_, err := io.WriteString(c.out, output)
+	return err
+}
+
+// MapConsole отображает консоль в память процессора по адресу base
+func (p *Processor) MapConsole(base int, console *Console) error {
+	if err := p.mapInputDevice("console", base, CONSOLE_SIZE, console); err != nil {
+		return err
+	}
+	console.charge = p.chargeOutput
+	return nil
+}
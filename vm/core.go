@@ -0,0 +1,245 @@
+package vm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Сигнатура и версия файла дампа памяти
+const CORE_MAGIC = "VMCORE\x01\x00"
+
+// Core — дамп состояния процессора после аварийной остановки: регистры, PSW,
+// причина остановки и полное содержимое памяти. Загрузив дамп в отладчик
+// (RestoreCore), можно исследовать аварию без повторного запуска программы.
+type Core struct {
+	PSW          PSW
+	Registers    [NUM_REGISTERS]int32
+	FRegisters   [NUM_FLOAT_REGISTERS]float32
+	Segment      Segment
+	CallDepth    int
+	Status       Status
+	ExitCode     int32
+	Error        string     // Текст ошибки, остановившей программу
+	Instructions int        // Выполненные инструкции
+	Memory       []byte     // Образ памяти данных; окна устройств записаны нулями
+	Code         []byte     // Образ памяти команд в гарвардском режиме, иначе nil
+	Debug        *DebugInfo // Отладочная информация программы или nil
+}
+
+// coreHeader — заголовок файла дампа фиксированного размера (little-endian). За ним
+// следуют текст ошибки, отладочная информация в формате DebugInfo.Write, образ памяти
+// данных и, в гарвардском режиме, образ памяти команд.
+type coreHeader struct {
+	Magic        [8]byte
+	IP, SP       uint16
+	Flags        uint8 // Флаги PSW в кодировке трассы (TRACE_ZF...TRACE_UM)
+	Status       uint8
+	Registers    [NUM_REGISTERS]int32
+	FRegisters   [NUM_FLOAT_REGISTERS]float32
+	ExitCode     int32
+	SegmentBase  int32
+	SegmentLimit int32
+	CallDepth    int32
+	Instructions uint64
+	ErrorLen     uint32
+	DebugLen     uint32
+	MemorySize   uint32
+	CodeSize     uint32 // 0 — память команд совпадает с памятью данных
+}
+
+// Core снимает дамп текущего состояния процессора
+func (p *Processor) Core() *Core {
+	c := &Core{
+		PSW:          p.psw,
+		Registers:    p.registers,
+		FRegisters:   p.fregisters,
+		Segment:      p.memory.segment,
+		CallDepth:    p.callDepth,
+		Status:       p.status,
+		ExitCode:     p.exitCode,
+		Instructions: p.usage.Instructions,
+		Memory:       p.memory.image(),
+		Debug:        p.debugInfo,
+	}
+	if p.runErr != nil {
+		c.Error = p.runErr.Error()
+	}
+	if p.IsHarvard() {
+		c.Code = p.code.image()
+	}
+	return c
+}
+
+// RestoreCore загружает дамп в процессор для посмертного исследования: память,
+// регистры, PSW и причину остановки. Процессор остается остановленным.
+func (p *Processor) RestoreCore(c *Core) error {
+	if (c.Code != nil) != p.IsHarvard() {
+		return fmt.Errorf("core and processor memory layouts differ: use a harvard processor for a harvard core and vice versa")
+	}
+	if len(c.Memory) != p.memory.Size() || (c.Code != nil && len(c.Code) != p.code.Size()) {
+		return fmt.Errorf("core memory size %d does not match processor memory size %d", len(c.Memory), p.memory.Size())
+	}
+	p.memory.restoreImage(c.Memory)
+	if c.Code != nil {
+		p.code.restoreImage(c.Code)
+	}
+	p.psw = c.PSW
+	p.setUserMode(c.PSW.UserMode)
+	p.registers, p.fregisters = c.Registers, c.FRegisters
+	p.memory.segment = c.Segment
+	p.callDepth = c.CallDepth
+	p.status, p.exitCode = c.Status, c.ExitCode
+	p.usage = ResourceUsage{Instructions: c.Instructions, OpenDescriptors: p.usage.OpenDescriptors}
+	p.stop = true // Дамп исследуется, а не выполняется
+	p.error = c.Status == StatusError
+	p.runErr = nil
+	if c.Error != "" {
+		p.runErr = errors.New(c.Error)
+	}
+	if c.Debug != nil {
+		p.debugInfo = c.Debug
+	}
+	return nil
+}
+
+// image возвращает содержимое адресного пространства памяти по карте регионов;
+// окна устройств и неотображенные адреса читаются как нули
+func (m *Memory) image() []byte {
+	buf := make([]byte, m.size)
+	for address := 0; address < m.size; address += WORD_SIZE {
+		end := min(address+WORD_SIZE, m.size)
+		m.readBytes(address, buf[address:end]) // Ошибка означает окно устройства: оставляем нули
+	}
+	return buf
+}
+
+// restoreImage записывает образ в память, пропуская окна устройств и постоянную память
+func (m *Memory) restoreImage(image []byte) {
+	for address := 0; address < len(image) && address < m.size; address += WORD_SIZE {
+		end := min(address+WORD_SIZE, len(image), m.size)
+		m.writeBytes(address, image[address:end]) // Окна устройств и ROM не восстанавливаются
+	}
+}
+
+// Write записывает дамп в двоичном формате
+func (c *Core) Write(w io.Writer) error {
+	var debug bytes.Buffer
+	if c.Debug != nil {
+		if err := c.Debug.Write(&debug); err != nil {
+			return err
+		}
+	}
+	h := coreHeader{
+		IP:           c.PSW.IP,
+		SP:           c.PSW.SP,
+		Flags:        c.PSW.traceFlags(),
+		Status:       uint8(c.Status),
+		Registers:    c.Registers,
+		FRegisters:   c.FRegisters,
+		ExitCode:     c.ExitCode,
+		SegmentBase:  int32(c.Segment.Base),
+		SegmentLimit: int32(c.Segment.Limit),
+		CallDepth:    int32(c.CallDepth),
+		Instructions: uint64(c.Instructions),
+		ErrorLen:     uint32(len(c.Error)),
+		DebugLen:     uint32(debug.Len()),
+		MemorySize:   uint32(len(c.Memory)),
+		CodeSize:     uint32(len(c.Code)),
+	}
+	copy(h.Magic[:], CORE_MAGIC)
+	if err := binary.Write(w, binary.LittleEndian, &h); err != nil {
+		return err
+	}
+	for _, part := range [][]byte{[]byte(c.Error), debug.Bytes(), c.Memory, c.Code} {
+		if _, err := w.Write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadCore читает дамп, записанный Core.Write
+func ReadCore(r io.Reader) (*Core, error) {
+	var h coreHeader
+	if err := binary.Read(r, binary.LittleEndian, &h); err != nil || string(h.Magic[:]) != CORE_MAGIC {
+		return nil, fmt.Errorf("not a vm core file")
+	}
+	read := func(n uint32, what string) ([]byte, error) {
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, fmt.Errorf("truncated core file: %s: %v", what, err)
+		}
+		return buf, nil
+	}
+	c := &Core{
+		PSW:          PSW{IP: h.IP, SP: h.SP},
+		Registers:    h.Registers,
+		FRegisters:   h.FRegisters,
+		Segment:      Segment{Base: int(h.SegmentBase), Limit: int(h.SegmentLimit)},
+		CallDepth:    int(h.CallDepth),
+		Status:       Status(h.Status),
+		ExitCode:     h.ExitCode,
+		Instructions: int(h.Instructions),
+	}
+	c.PSW.setTraceFlags(h.Flags)
+	message, err := read(h.ErrorLen, "error message")
+	if err != nil {
+		return nil, err
+	}
+	c.Error = string(message)
+	debug, err := read(h.DebugLen, "debug info")
+	if err != nil {
+		return nil, err
+	}
+	if len(debug) > 0 {
+		if c.Debug, err = ReadDebugInfo(bytes.NewReader(debug)); err != nil {
+			return nil, fmt.Errorf("invalid debug info in core file: %v", err)
+		}
+	}
+	if c.Memory, err = read(h.MemorySize, "memory"); err != nil {
+		return nil, err
+	}
+	if h.CodeSize > 0 {
+		if c.Code, err = read(h.CodeSize, "code memory"); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// WriteCoreFile записывает дамп в файл
+func WriteCoreFile(filename string, c *Core) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("unable to create file: %v", err)
+	}
+	if err := c.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// LoadCoreFile читает файл дампа
+func LoadCoreFile(filename string) (*Core, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file: %v", err)
+	}
+	defer file.Close()
+	return ReadCore(file)
+}
+
+// setTraceFlags восстанавливает флаги PSW из кодировки трассы
+func (psw *PSW) setTraceFlags(f uint8) {
+	psw.ZeroFlag = f&TRACE_ZF != 0
+	psw.SignFlag = f&TRACE_SF != 0
+	psw.CarryFlag = f&TRACE_CF != 0
+	psw.OverflowFlag = f&TRACE_OF != 0
+	psw.InterruptFlag = f&TRACE_IF != 0
+	psw.UserMode = f&TRACE_UM != 0
+}
@@ -0,0 +1,97 @@
+package vm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// coverage отмечает выполненные адреса команд
+type coverage struct {
+	loaded   []int  // Адреса команд, загруженных к запуску
+	executed []bool // Адрес команды был выполнен хотя бы раз
+}
+
+// CoverageReport — покрытие программы: какие из загруженных команд выполнялись
+type CoverageReport struct {
+	Covered   []int // Адреса выполненных команд
+	Uncovered []int // Адреса команд, которые не выполнялись ни разу
+}
+
+// Total возвращает количество загруженных команд
+func (c CoverageReport) Total() int {
+	return len(c.Covered) + len(c.Uncovered)
+}
+
+// Percent возвращает долю выполненных команд в процентах (100 для пустой программы)
+func (c CoverageReport) Percent() float64 {
+	if c.Total() == 0 {
+		return 100
+	}
+	return 100 * float64(len(c.Covered)) / float64(c.Total())
+}
+
+// SetCoverage включает или выключает учет покрытия. Набор команд программы
+// определяется при Reset по словам-командам памяти команд, поэтому включать учет
+// нужно до Reset.
+func (p *Processor) SetCoverage(enabled bool) {
+	if !enabled {
+		p.coverage = nil
+		return
+	}
+	p.coverage = &coverage{executed: make([]bool, p.code.Size())}
+	p.coverage.start(p.code)
+}
+
+// start запоминает загруженные команды и сбрасывает отметки выполнения
+func (c *coverage) start(code *Memory) {
+	if c == nil {
+		return
+	}
+	clear(c.executed)
+	c.loaded = c.loaded[:0]
+	for address := 0; address+WORD_SIZE <= code.Size(); address += WORD_SIZE {
+		if word, err := code.PeekWord(address); err == nil && word.IsCommand() {
+			c.loaded = append(c.loaded, address)
+		}
+	}
+}
+
+// Coverage возвращает покрытие текущего запуска; ok = false, если учет выключен
+func (p *Processor) Coverage() (report CoverageReport, ok bool) {
+	c := p.coverage
+	if c == nil {
+		return CoverageReport{}, false
+	}
+	for _, address := range c.loaded {
+		if c.executed[address] {
+			report.Covered = append(report.Covered, address)
+		} else {
+			report.Uncovered = append(report.Uncovered, address)
+		}
+	}
+	return report, true
+}
+
+// WriteCoverage выводит итог покрытия и листинг загруженных команд, в котором
+// выполненные команды отмечены "+", а невыполненные — "-"
+func (p *Processor) WriteCoverage(w io.Writer) error {
+	report, ok := p.Coverage()
+	if !ok {
+		return fmt.Errorf("coverage is not enabled")
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "Coverage: %d of %d instructions executed (%.1f%%)\n", len(report.Covered), report.Total(), report.Percent())
+	for _, address := range p.coverage.loaded {
+		mark := "-"
+		if p.coverage.executed[address] {
+			mark = "+"
+		}
+		text := ""
+		if word, err := p.code.PeekWord(address); err == nil {
+			text = DisassembleWord(address, word)
+		}
+		fmt.Fprintf(bw, "%s 0x%04X  %-24s ; %s\n", mark, address, text, p.DescribeAddress(address))
+	}
+	return bw.Flush()
+}
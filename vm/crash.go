@@ -0,0 +1,58 @@
+package vm
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Число команд до и после IP в окне дизассемблирования отчета об аварии
+const crashWindow = 3
+
+// WriteCrashReport выводит отчет об аварийной остановке: ошибку, команду по IP,
+// регистры, флаги и дизассемблирование команд вокруг IP
+func (p *Processor) WriteCrashReport(w io.Writer) {
+	ip := int(p.psw.IP)
+	if p.runErr != nil {
+		fmt.Fprintf(w, "Fatal error: %v\n", p.runErr)
+	} else {
+		fmt.Fprintf(w, "Processor stopped: %s\n", p.status)
+	}
+	if word, err := p.code.PeekWord(ip); err == nil {
+		fmt.Fprintf(w, "  at 0x%04X  %s  ; %s\n", ip, DisassembleWord(ip, word), p.DescribeAddress(ip))
+	}
+	fmt.Fprintf(w, "Registers: a1=%d (0x%08X) a2=%d (0x%08X) SP=0x%04X\n",
+		p.registers[0], uint32(p.registers[0]), p.registers[1], uint32(p.registers[1]), p.psw.SP)
+	fmt.Fprint(w, "Float registers:")
+	for i, f := range p.fregisters {
+		fmt.Fprintf(w, " f%d=%g", i, f)
+	}
+	fmt.Fprintln(w)
+	mode := "supervisor"
+	if p.psw.UserMode {
+		mode = "user"
+	}
+	fmt.Fprintf(w, "Flags: %s (%s mode), call depth %d, %d instructions executed\n",
+		describeFlags(p.psw.traceFlags()), mode, p.callDepth, p.usage.Instructions)
+	fmt.Fprintln(w, "Code around IP:")
+	start := max(ip-crashWindow*WORD_SIZE, 0)
+	end := min(ip+(crashWindow+1)*WORD_SIZE, p.code.Size())
+	for address := start; address+WORD_SIZE <= end; address += WORD_SIZE {
+		word, err := p.code.PeekWord(address)
+		if err != nil {
+			continue // Окно устройства не дизассемблируется
+		}
+		marker := "  "
+		if address == ip {
+			marker = "=>"
+		}
+		fmt.Fprintf(w, "%s 0x%04X  %-24s ; %s\n", marker, address, DisassembleWord(address, word), p.DescribeAddress(address))
+	}
+}
+
+// CrashReport возвращает отчет WriteCrashReport строкой
+func (p *Processor) CrashReport() string {
+	var b strings.Builder
+	p.WriteCrashReport(&b)
+	return b.String()
+}
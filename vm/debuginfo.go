@@ -0,0 +1,237 @@
+package vm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DebugLine связывает слово программы со строкой исходного текста
+type DebugLine struct {
+	Address int    // Адрес слова
+	File    string // Файл исходного текста
+	Line    int    // Номер строки
+}
+
+// DebugLabel — метка программы и ее адрес
+type DebugLabel struct {
+	Name    string // Имя метки
+	Address int    // Адрес метки
+}
+
+// DebugInfo — отладочная информация программы: строки исходного текста слов и метки.
+// Хранится в файле-спутнике программы (см. DebugFileName) и позволяет описывать адреса
+// как "loop+0x10 (prog.asm:37)".
+type DebugInfo struct {
+	Lines  []DebugLine  // Строки слов, упорядоченные по адресу
+	Labels []DebugLabel // Метки, упорядоченные по адресу
+}
+
+// SourceError — ошибка выполнения, дополненная местом в исходном тексте программы
+type SourceError struct {
+	Address  int    // Адрес команды, вызвавшей ошибку
+	Location string // Описание адреса: "loop+0x10 (prog.asm:37)"
+	Err      error  // Исходная ошибка
+}
+
+// Error реализует интерфейс error для SourceError
+func (e *SourceError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Location, e.Err)
+}
+
+// Unwrap возвращает исходную ошибку
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+// DebugFileName возвращает имя файла отладочной информации для файла программы
+func DebugFileName(program string) string {
+	return strings.TrimSuffix(program, filepath.Ext(program)) + ".dbg"
+}
+
+// SetDebugInfo подключает отладочную информацию загруженной программы; nil отключает ее
+func (p *Processor) SetDebugInfo(d *DebugInfo) {
+	p.debugInfo = d
+}
+
+// DebugInfo возвращает подключенную отладочную информацию или nil
+func (p *Processor) DebugInfo() *DebugInfo {
+	return p.debugInfo
+}
+
+// DescribeAddress описывает адрес по отладочной информации процессора
+func (p *Processor) DescribeAddress(address int) string {
+	return p.debugInfo.Describe(address)
+}
+
+// sourceError дополняет ошибку команды по адресу address местом в исходном тексте,
+// если подключена отладочная информация
+func (p *Processor) sourceError(address int, err error) error {
+	if p.debugInfo == nil {
+		return err
+	}
+	return &SourceError{Address: address, Location: p.debugInfo.Describe(address), Err: err}
+}
+
+// sort упорядочивает строки и метки по адресу
+func (d *DebugInfo) sort() {
+	sort.SliceStable(d.Lines, func(i, j int) bool { return d.Lines[i].Address < d.Lines[j].Address })
+	sort.SliceStable(d.Labels, func(i, j int) bool {
+		if d.Labels[i].Address != d.Labels[j].Address {
+			return d.Labels[i].Address < d.Labels[j].Address
+		}
+		return d.Labels[i].Name < d.Labels[j].Name
+	})
+}
+
+// setFile задает файл исходного текста строкам, для которых он не известен
+func (d *DebugInfo) setFile(file string) {
+	for i := range d.Lines {
+		if d.Lines[i].File == "" {
+			d.Lines[i].File = file
+		}
+	}
+}
+
+// Line возвращает строку исходного текста слова, содержащего адрес
+func (d *DebugInfo) Line(address int) (DebugLine, bool) {
+	if d == nil {
+		return DebugLine{}, false
+	}
+	i := sort.Search(len(d.Lines), func(i int) bool { return d.Lines[i].Address > address })
+	if i == 0 || address >= d.Lines[i-1].Address+WORD_SIZE {
+		return DebugLine{}, false
+	}
+	return d.Lines[i-1], true
+}
+
+// Label возвращает ближайшую метку с адресом не больше address
+func (d *DebugInfo) Label(address int) (DebugLabel, bool) {
+	if d == nil {
+		return DebugLabel{}, false
+	}
+	i := sort.Search(len(d.Labels), func(i int) bool { return d.Labels[i].Address > address })
+	if i == 0 {
+		return DebugLabel{}, false
+	}
+	return d.Labels[i-1], true
+}
+
+// LabelAddress возвращает адрес метки по имени
+func (d *DebugInfo) LabelAddress(name string) (int, bool) {
+	if d == nil {
+		return 0, false
+	}
+	for _, l := range d.Labels {
+		if l.Name == name {
+			return l.Address, true
+		}
+	}
+	return 0, false
+}
+
+// Describe описывает адрес как "метка+смещение (файл:строка)". Без отладочной
+// информации возвращается шестнадцатеричный адрес.
+func (d *DebugInfo) Describe(address int) string {
+	var b strings.Builder
+	if label, ok := d.Label(address); ok {
+		b.WriteString(label.Name)
+		if offset := address - label.Address; offset != 0 {
+			fmt.Fprintf(&b, "+0x%X", offset)
+		}
+	} else {
+		fmt.Fprintf(&b, "0x%X", address)
+	}
+	if line, ok := d.Line(address); ok {
+		fmt.Fprintf(&b, " (%s:%d)", line.File, line.Line)
+	}
+	return b.String()
+}
+
+// Write записывает отладочную информацию в текстовом формате:
+//
+//	; vm debug info
+//	label loop 0x0008
+//	line 0x0008 37 "prog.asm"
+func (d *DebugInfo) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "; vm debug info")
+	for _, l := range d.Labels {
+		fmt.Fprintf(bw, "label %s 0x%04x\n", l.Name, l.Address)
+	}
+	for _, l := range d.Lines {
+		fmt.Fprintf(bw, "line 0x%04x %d %s\n", l.Address, l.Line, strconv.Quote(l.File))
+	}
+	return bw.Flush()
+}
+
+// LoadDebugInfoFile читает файл отладочной информации
+func LoadDebugInfoFile(filename string) (*DebugInfo, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file: %v", err)
+	}
+	defer file.Close()
+	return ReadDebugInfo(file)
+}
+
+// ReadDebugInfo читает отладочную информацию в формате DebugInfo.Write
+func ReadDebugInfo(r io.Reader) (*DebugInfo, error) {
+	d := &DebugInfo{}
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		text := strings.TrimSpace(line)
+		if text == "" || strings.HasPrefix(text, ";") {
+			continue
+		}
+		fail := func(format string, args ...interface{}) error {
+			return &CommandError{LineNumber: lineNumber, Line: line, Message: fmt.Sprintf(format, args...)}
+		}
+		kind, rest := splitFirst(text)
+		switch kind {
+		case "label":
+			fields := strings.Fields(rest)
+			if len(fields) != 2 {
+				return nil, fail("label record requires a name and an address")
+			}
+			address, err := strconv.ParseUint(fields[1], 0, 16)
+			if err != nil {
+				return nil, fail("invalid address: %v", err)
+			}
+			d.Labels = append(d.Labels, DebugLabel{Name: fields[0], Address: int(address)})
+		case "line":
+			fields := strings.SplitN(rest, " ", 3)
+			if len(fields) != 3 {
+				return nil, fail("line record requires an address, a line number and a file name")
+			}
+			address, err := strconv.ParseUint(fields[0], 0, 16)
+			if err != nil {
+				return nil, fail("invalid address: %v", err)
+			}
+			number, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, fail("invalid line number: %v", err)
+			}
+			file, err := strconv.Unquote(fields[2])
+			if err != nil {
+				return nil, fail("invalid file name: %v", err)
+			}
+			d.Lines = append(d.Lines, DebugLine{Address: int(address), File: file, Line: number})
+		default:
+			return nil, fail("unknown debug record %q", kind)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading file: %v", err)
+	}
+	d.sort()
+	return d, nil
+}
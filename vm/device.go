@@ -0,0 +1,81 @@
+package vm
+
+import "fmt"
+
+// Device — периферийное устройство, отображенное в адресное пространство (MMIO).
+// addr — смещение слова относительно начала окна устройства.
+type Device interface {
+	Read(addr int) (Word, error)     // Чтение регистра устройства
+	Write(addr int, word Word) error // Запись в регистр устройства
+}
+
+// MapDevice отображает устройство dev в окно [base, base+size). Чтения и записи слов
+// в этом окне передаются устройству вместо памяти; побайтовый доступ к окну запрещен.
+func (m *Memory) MapDevice(name string, base, size int, dev Device) (*Region, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("device %q: cannot map empty window", name)
+	}
+	if dev == nil {
+		return nil, fmt.Errorf("device %q: device is nil", name)
+	}
+	return m.addRegion(&Region{Name: name, Kind: RegionDevice, size: size, device: dev}, base)
+}
+
+// Device возвращает устройство, обслуживающее регион, или nil для обычной памяти
+func (r *Region) Device() Device {
+	return r.device
+}
+
+// deviceAt возвращает окно устройства, в которое целиком попадает слово по адресу address
+func (m *Memory) deviceAt(address int) *Region {
+	if r := m.findRegion(address, WORD_SIZE); r != nil && r.device != nil {
+		return r
+	}
+	return nil
+}
+
+// readDevice читает слово из регистра устройства под мьютексом региона
+func (r *Region) readDevice(address int) (Word, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	word, err := r.device.Read(address - r.base)
+	if err != nil {
+		return Word{}, fmt.Errorf("device %q read at 0x%X: %w", r.Name, address, err)
+	}
+	return word, nil
+}
+
+// writeDevice записывает слово в регистр устройства под мьютексом региона
+func (r *Region) writeDevice(address int, word Word) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.device.Write(address-r.base, word); err != nil {
+		return fmt.Errorf("device %q write at 0x%X: %w", r.Name, address, err)
+	}
+	return nil
+}
+
+// Fencer — необязательный интерфейс устройства, откладывающего записи (например,
+// буферизующего вывод хоста). Команда FENCE вызывает Fence каждого отображенного
+// устройства, и оно должно завершить отложенные записи до возврата. Встроенные
+// устройства выполняют записи синхронно и Fencer не реализуют.
+type Fencer interface {
+	Fence() error
+}
+
+// fence завершает отложенные записи устройств, реализующих Fencer
+func (m *Memory) fence() error {
+	for _, r := range m.regions {
+		fencer, ok := r.device.(Fencer)
+		if !ok {
+			continue
+		}
+		r.mu.Lock()
+		err := fencer.Fence()
+		r.mu.Unlock()
+		if err != nil {
+			return fmt.Errorf("device %q fence: %w", r.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package vm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Названия режимов адресации BB для листинга
+var bbModeNames = [4]string{
+	"direct",          // 00: адрес из команды
+	"modified by a1",  // 01: адрес + регистр a1
+	"register",        // 10: адрес из регистра
+	"register+offset", // 11: адрес + регистр
+}
+
+// DisassembleWord возвращает текст слова по адресу address в синтаксисе ассемблера.
+// Для команд с режимом BB, отличным от прямого, в комментарии указывается режим;
+// для относительных переходов — вычисленный адрес цели.
+func DisassembleWord(address int, w Word) string {
+	switch w.Tag {
+	case TagFloat:
+		return ".float " + strconv.FormatFloat(float64(w.D.F), 'g', -1, 32)
+	case TagInt:
+		return fmt.Sprintf(".int %d", w.D.I)
+	}
+	c := w.Cmd
+	text := fmt.Sprintf("%-6s 0x%04X, 0x%04X", OpCode(c.Opcode), c.Address1, c.Address2)
+	comment := ""
+	if c.BB != 0 {
+		comment = fmt.Sprintf("bb=%d %s", c.BB, bbModeNames[c.BB])
+	}
+	if isJumpOpcode(OpCode(c.Opcode)) && c.Address2&JUMP_RELATIVE != 0 {
+		offset := int(int16(c.Address1))
+		text = fmt.Sprintf("%-6s %d, 0x%04X", OpCode(c.Opcode), offset, c.Address2)
+		if comment != "" {
+			comment += ", "
+		}
+		comment += fmt.Sprintf("-> 0x%04X", uint16(address+offset))
+	}
+	if comment != "" {
+		text += " ; " + comment
+	}
+	return text
+}
+
+// Disassemble печатает листинг слов памяти из диапазона [start, end): адрес, кодировку
+// в формате загрузчика и текст слова. Серии нулевых слов данных сворачиваются в одну
+// строку, а адреса без памяти (окна устройств, неотображенные области) пропускаются.
+func (m *Memory) Disassemble(w io.Writer, start, end int) error {
+	if start < 0 || end > m.size || start > end {
+		return fmt.Errorf("range 0x%X-0x%X is out of valid range [0-%d]", start, end, m.size)
+	}
+	bw := bufio.NewWriter(w)
+	zeroStart, zeros := 0, 0
+	flushZeros := func() {
+		if zeros > 0 {
+			fmt.Fprintf(bw, "0x%04X  ; %d zero word(s)\n", zeroStart, zeros)
+			zeros = 0
+		}
+	}
+	for addr := start; addr+WORD_SIZE <= end; addr += WORD_SIZE {
+		word, err := m.PeekWord(addr)
+		if err != nil {
+			flushZeros()
+			continue // Пропускаем окна устройств и неотображенные адреса
+		}
+		if word.Tag == TagInt && word.D.I == 0 {
+			if zeros == 0 {
+				zeroStart = addr
+			}
+			zeros++
+			continue
+		}
+		flushZeros()
+		fmt.Fprintf(bw, "0x%04X  %-20s %s\n", addr, encodeWord(word), DisassembleWord(addr, word))
+	}
+	flushZeros()
+	return bw.Flush()
+}
+
+// encodeWord возвращает кодировку слова в формате загрузчика
+func encodeWord(w Word) string {
+	switch w.Tag {
+	case TagCommand:
+		c := w.Cmd
+		addr1 := fmt.Sprintf("%04x", c.Address1)
+		if isJumpOpcode(OpCode(c.Opcode)) && c.Address2&JUMP_RELATIVE != 0 && int16(c.Address1) < 0 {
+			addr1 = fmt.Sprintf("-%x", -int(int16(c.Address1))) // Загрузчик ожидает смещение со знаком
+		}
+		return fmt.Sprintf("k %02x %02x %s %04x", c.Opcode, c.BB, addr1, c.Address2)
+	case TagFloat:
+		return "r " + strconv.FormatFloat(float64(w.D.F), 'g', -1, 32)
+	}
+	return fmt.Sprintf("i %d", w.D.I)
+}
@@ -0,0 +1,193 @@
+package vm
+
+import (
+	"fmt"
+	"os"
+)
+
+// Регистры диска (смещения относительно начала окна устройства)
+const (
+	DISK_COMMAND     = 0 * WORD_SIZE // Запись запускает команду DISK_CMD_*
+	DISK_SECTOR      = 1 * WORD_SIZE // Номер сектора
+	DISK_ADDR        = 2 * WORD_SIZE // Физический адрес буфера в памяти данных
+	DISK_STATUS      = 3 * WORD_SIZE // Результат последней команды DISK_STATUS_*
+	DISK_SECTORS     = 4 * WORD_SIZE // Число секторов (только чтение)
+	DISK_SECTOR_SIZE = 5 * WORD_SIZE // Размер сектора в байтах (только чтение)
+
+	DISK_SIZE = 6 * WORD_SIZE // Размер окна диска в байтах
+	DISK_BASE = 0xF070        // Адрес окна диска по умолчанию
+)
+
+// Команды диска
+const (
+	DISK_CMD_READ  = 1 // Прочитать сектор в буфер памяти
+	DISK_CMD_WRITE = 2 // Записать буфер памяти в сектор
+)
+
+// Значения регистра состояния диска
+const (
+	DISK_STATUS_OK          = 0 // Команда выполнена
+	DISK_STATUS_BAD_SECTOR  = 1 // Номер сектора вне геометрии диска
+	DISK_STATUS_BAD_ADDRESS = 2 // Буфер выходит за пределы памяти
+	DISK_STATUS_IO_ERROR    = 3 // Ошибка чтения или записи файла хоста
+	DISK_STATUS_BAD_COMMAND = 4 // Неизвестная команда
+)
+
+// DiskGeometry описывает геометрию диска
+type DiskGeometry struct {
+	SectorSize int // Размер сектора в байтах, кратный WORD_SIZE
+	Sectors    int // Число секторов
+}
+
+// DefaultDiskGeometry — геометрия диска по умолчанию: 128 секторов по 512 байт
+var DefaultDiskGeometry = DiskGeometry{SectorSize: 512, Sectors: 128}
+
+// Disk — блочное устройство, хранящее секторы в файле хоста. Команды выполняются
+// синхронно: сектор копируется между файлом и памятью, результат помещается в
+// регистр состояния, который программа проверяет после записи команды.
+type Disk struct {
+	file     *os.File          // Файл хоста с образом диска
+	geometry DiskGeometry      // Геометрия диска
+	mem      *Memory           // Память, с которой диск обменивается секторами
+	charge   func(n int) error // Учет записанных байт в квоте вывода процессора
+	region   *Region           // Окно регистров диска; nil — диск не отображен
+	sector   int32             // Регистр номера сектора
+	addr     int32             // Регистр адреса буфера
+	status   int32             // Регистр состояния
+}
+
+// OpenDisk открывает или создает образ диска path с геометрией geometry.
+// Файл дополняется нулями до полного размера диска.
+func OpenDisk(path string, geometry DiskGeometry) (*Disk, error) {
+	if geometry.SectorSize <= 0 || geometry.SectorSize%WORD_SIZE != 0 || geometry.Sectors <= 0 {
+		return nil, fmt.Errorf("invalid disk geometry: %d sector(s) of %d bytes", geometry.Sectors, geometry.SectorSize)
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open disk image: %v", err)
+	}
+	size := int64(geometry.SectorSize) * int64(geometry.Sectors)
+	info, err := file.Stat()
+	if err == nil && info.Size() < size {
+		err = file.Truncate(size) // Расширяем образ до размера диска
+	}
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("unable to prepare disk image: %v", err)
+	}
+	return &Disk{file: file, geometry: geometry}, nil
+}
+
+// Geometry возвращает геометрию диска
+func (d *Disk) Geometry() DiskGeometry {
+	return d.geometry
+}
+
+// Close закрывает файл образа диска
+func (d *Disk) Close() error {
+	return d.file.Close()
+}
+
+// Read читает регистр диска
+func (d *Disk) Read(addr int) (Word, error) {
+	switch addr {
+	case DISK_SECTOR:
+		return IntWord(d.sector), nil
+	case DISK_ADDR:
+		return IntWord(d.addr), nil
+	case DISK_STATUS:
+		return IntWord(d.status), nil
+	case DISK_SECTORS:
+		return IntWord(int32(d.geometry.Sectors)), nil
+	case DISK_SECTOR_SIZE:
+		return IntWord(int32(d.geometry.SectorSize)), nil
+	}
+	return Word{}, fmt.Errorf("disk register 0x%X is not readable", addr)
+}
+
+// Write записывает регистр диска; запись в DISK_COMMAND выполняет команду.
+// Превышение квоты вывода командой записи останавливает программу.
+func (d *Disk) Write(addr int, word Word) error {
+	switch addr {
+	case DISK_COMMAND:
+		status, err := d.execute(word.D.I)
+		if err != nil {
+			return err
+		}
+		d.status = status
+	case DISK_SECTOR:
+		d.sector = word.D.I
+	case DISK_ADDR:
+		d.addr = word.D.I
+	default:
+		return fmt.Errorf("disk register 0x%X is not writable", addr)
+	}
+	return nil
+}
+
+// execute выполняет команду диска и возвращает значение регистра состояния.
+// Ошибки команды не останавливают программу: она узнает о них из регистра состояния.
+// Ошибка возвращается, только если запись сектора превышает квоту вывода.
+func (d *Disk) execute(command int32) (int32, error) {
+	if command != DISK_CMD_READ && command != DISK_CMD_WRITE {
+		return DISK_STATUS_BAD_COMMAND, nil
+	}
+	if d.sector < 0 || int(d.sector) >= d.geometry.Sectors {
+		return DISK_STATUS_BAD_SECTOR, nil
+	}
+	size := d.geometry.SectorSize
+	if d.mem.checkRange("dma", int(d.addr), size) != nil {
+		return DISK_STATUS_BAD_ADDRESS, nil
+	}
+	offset := int64(d.sector) * int64(size)
+	buf := make([]byte, size)
+	if command == DISK_CMD_READ {
+		if _, err := d.file.ReadAt(buf, offset); err != nil {
+			return DISK_STATUS_IO_ERROR, nil
+		}
+		if d.mem.writeBytes(int(d.addr), buf) != nil {
+			return DISK_STATUS_BAD_ADDRESS, nil // Буфер попадает в ROM или окно устройства
+		}
+		return DISK_STATUS_OK, nil
+	}
+	if d.mem.readBytes(int(d.addr), buf) != nil {
+		return DISK_STATUS_BAD_ADDRESS, nil
+	}
+	if d.charge != nil {
+		if err := d.charge(size); err != nil {
+			return 0, err // Квота вывода исчерпана: сектор не записывается
+		}
+	}
+	if _, err := d.file.WriteAt(buf, offset); err != nil {
+		return DISK_STATUS_IO_ERROR, nil
+	}
+	return DISK_STATUS_OK, nil
+}
+
+// MapDisk подключает диск к памяти данных процессора и отображает его регистры по адресу base.
+// Открытый файл образа занимает дескриптор квоты Quotas.MaxDescriptors до UnmapDisk.
+func (p *Processor) MapDisk(base int, disk *Disk) error {
+	if disk.region != nil {
+		return fmt.Errorf("disk is already mapped at 0x%X", disk.region.Base())
+	}
+	if err := p.acquireDescriptor(); err != nil {
+		return err
+	}
+	r, err := p.memory.MapDevice("disk", base, DISK_SIZE, disk)
+	if err != nil {
+		p.releaseDescriptor()
+		return err
+	}
+	disk.mem, disk.region, disk.charge = p.memory, r, p.chargeOutput
+	return nil
+}
+
+// UnmapDisk отключает окно регистров диска и освобождает его дескриптор
+func (p *Processor) UnmapDisk(disk *Disk) {
+	if disk.region == nil || disk.mem != p.memory {
+		return
+	}
+	p.memory.unmap(disk.region)
+	disk.region, disk.charge = nil, nil
+	p.releaseDescriptor()
+}
@@ -0,0 +1,326 @@
+package vm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ExprEnv предоставляет значения идентификаторов и ячеек памяти при вычислении выражений
+type ExprEnv interface {
+	Identifier(name string) (int64, error) // Значение регистра, флага, метки или константы
+	Memory(address int64) (int64, error)   // Значение слова памяти по адресу ([addr])
+}
+
+// Expr представляет разобранное выражение мини-языка
+type Expr interface {
+	Eval(env ExprEnv) (int64, error) // Вычисляет выражение в заданном окружении
+}
+
+// numberExpr — числовой литерал
+type numberExpr struct {
+	value int64
+}
+
+func (e numberExpr) Eval(ExprEnv) (int64, error) { return e.value, nil }
+
+// identExpr — ссылка на регистр, флаг или метку
+type identExpr struct {
+	name string
+}
+
+func (e identExpr) Eval(env ExprEnv) (int64, error) { return env.Identifier(e.name) }
+
+// memoryExpr — чтение слова памяти [addr]
+type memoryExpr struct {
+	address Expr
+}
+
+func (e memoryExpr) Eval(env ExprEnv) (int64, error) {
+	addr, err := e.address.Eval(env)
+	if err != nil {
+		return 0, err
+	}
+	return env.Memory(addr)
+}
+
+// unaryExpr — унарная операция (-, !, ~)
+type unaryExpr struct {
+	op      string
+	operand Expr
+}
+
+func (e unaryExpr) Eval(env ExprEnv) (int64, error) {
+	v, err := e.operand.Eval(env)
+	if err != nil {
+		return 0, err
+	}
+	switch e.op {
+	case "-":
+		return -v, nil
+	case "~":
+		return ^v, nil
+	default: // "!"
+		return boolToInt(v == 0), nil
+	}
+}
+
+// binaryExpr — бинарная операция
+type binaryExpr struct {
+	op          string
+	left, right Expr
+}
+
+func (e binaryExpr) Eval(env ExprEnv) (int64, error) {
+	l, err := e.left.Eval(env)
+	if err != nil {
+		return 0, err
+	}
+	// Логические операции вычисляются по короткой схеме
+	if e.op == "&&" && l == 0 {
+		return 0, nil
+	}
+	if e.op == "||" && l != 0 {
+		return 1, nil
+	}
+	r, err := e.right.Eval(env)
+	if err != nil {
+		return 0, err
+	}
+	switch e.op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/", "%":
+		if r == 0 {
+			return 0, fmt.Errorf("division by zero in expression")
+		}
+		if e.op == "/" {
+			return l / r, nil
+		}
+		return l % r, nil
+	case "<<":
+		return l << uint64(r), nil
+	case ">>":
+		return l >> uint64(r), nil
+	case "&":
+		return l & r, nil
+	case "|":
+		return l | r, nil
+	case "^":
+		return l ^ r, nil
+	case "==":
+		return boolToInt(l == r), nil
+	case "!=":
+		return boolToInt(l != r), nil
+	case "<":
+		return boolToInt(l < r), nil
+	case "<=":
+		return boolToInt(l <= r), nil
+	case ">":
+		return boolToInt(l > r), nil
+	case ">=":
+		return boolToInt(l >= r), nil
+	default: // "&&", "||"
+		return boolToInt(r != 0), nil
+	}
+}
+
+// boolToInt преобразует логическое значение в 0 или 1
+func boolToInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// binaryLevels перечисляет бинарные операторы от низшего приоритета к высшему
+var binaryLevels = [][]string{
+	{"||"},
+	{"&&"},
+	{"|"},
+	{"^"},
+	{"&"},
+	{"==", "!="},
+	{"<=", ">=", "<", ">"},
+	{"<<", ">>"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+// exprParser — парсер выражений методом рекурсивного спуска
+type exprParser struct {
+	tokens []string
+	pos    int
+}
+
+// ParseExpr разбирает выражение вида `[0x120] + R1*4` или `R1 == 5 && [0x40] > 100`
+func ParseExpr(source string) (Expr, error) {
+	tokens, err := tokenizeExpr(source)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+	p := &exprParser{tokens: tokens}
+	e, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected token %q in expression", p.tokens[p.pos])
+	}
+	return e, nil
+}
+
+// peek возвращает текущий токен без его извлечения
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// parseBinary разбирает бинарные операции заданного уровня приоритета
+func (p *exprParser) parseBinary(level int) (Expr, error) {
+	if level == len(binaryLevels) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		matched := false
+		for _, candidate := range binaryLevels[level] {
+			if op == candidate {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: op, left: left, right: right}
+	}
+}
+
+// parseUnary разбирает унарные операции и первичные выражения
+func (p *exprParser) parseUnary() (Expr, error) {
+	switch tok := p.peek(); tok {
+	case "-", "!", "~":
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryExpr{op: tok, operand: operand}, nil
+	case "+":
+		p.pos++
+		return p.parseUnary()
+	case "(", "[":
+		p.pos++
+		inner, err := p.parseBinary(0)
+		if err != nil {
+			return nil, err
+		}
+		closing := ")"
+		if tok == "[" {
+			closing = "]"
+		}
+		if p.peek() != closing {
+			return nil, fmt.Errorf("expected %q in expression", closing)
+		}
+		p.pos++
+		if tok == "[" {
+			return memoryExpr{address: inner}, nil
+		}
+		return inner, nil
+	case "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		p.pos++
+		if unicode.IsDigit(rune(tok[0])) {
+			value, err := ParseNumber(tok)
+			if err != nil {
+				return nil, err
+			}
+			return numberExpr{value: value}, nil
+		}
+		if isIdentStart(rune(tok[0])) {
+			return identExpr{name: tok}, nil
+		}
+		return nil, fmt.Errorf("unexpected token %q in expression", tok)
+	}
+}
+
+// ParseNumber разбирает числовой литерал с префиксом 0x, 0b, 0o или десятичный
+func ParseNumber(text string) (int64, error) {
+	lower := strings.ToLower(text)
+	base := 10
+	switch {
+	case strings.HasPrefix(lower, "0x"):
+		base, lower = 16, lower[2:]
+	case strings.HasPrefix(lower, "0b"):
+		base, lower = 2, lower[2:]
+	case strings.HasPrefix(lower, "0o"):
+		base, lower = 8, lower[2:]
+	}
+	value, err := strconv.ParseInt(lower, base, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", text)
+	}
+	return value, nil
+}
+
+// isIdentStart проверяет, может ли символ начинать идентификатор
+func isIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_' || r == '.'
+}
+
+// tokenizeExpr разбивает исходный текст выражения на токены
+func tokenizeExpr(source string) ([]string, error) {
+	var tokens []string
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || isIdentStart(r):
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		default:
+			// Сначала пробуем двухсимвольные операторы
+			if i+1 < len(runes) {
+				two := string(runes[i : i+2])
+				switch two {
+				case "&&", "||", "==", "!=", "<=", ">=", "<<", ">>":
+					tokens = append(tokens, two)
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("+-*/%&|^!~<>()[]", r) {
+				return nil, fmt.Errorf("unexpected character %q in expression", r)
+			}
+			tokens = append(tokens, string(r))
+			i++
+		}
+	}
+	return tokens, nil
+}
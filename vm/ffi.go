@@ -0,0 +1,176 @@
+package vm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var (
+	int32Type   = reflect.TypeOf(int32(0))       // Тип целочисленного аргумента
+	float32Type = reflect.TypeOf(float32(0))     // Тип вещественного аргумента
+	intsType    = reflect.TypeOf([]int32(nil))   // Тип буфера целых чисел
+	floatsType  = reflect.TypeOf([]float32(nil)) // Тип буфера вещественных чисел
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// BindHostFunc регистрирует функцию Go как гипервызов с номером index и автоматическим маршалингом.
+//
+// Гостевая программа вызывает функцию командой HCALL index, block, где block — адрес блока
+// аргументов. Параметры int32 и float32 занимают по одному слову блока, параметры []int32 и
+// []float32 — два слова (адрес буфера в памяти гостя и его длину); изменения буфера
+// записываются обратно в память. Результаты int32/float32 записываются в блок начиная с его
+// первого слова; последним результатом функция может вернуть error.
+func (p *Processor) BindHostFunc(index uint16, fn any) error {
+	if fn == nil {
+		return fmt.Errorf("host function %d: function is nil", index)
+	}
+	fnValue := reflect.ValueOf(fn)
+	fnType := fnValue.Type()
+	if fnType.Kind() != reflect.Func {
+		return fmt.Errorf("host function %d: expected func, got %s", index, fnType)
+	}
+	if fnValue.IsNil() {
+		return fmt.Errorf("host function %d: function is nil", index)
+	}
+	if fnType.IsVariadic() {
+		return fmt.Errorf("host function %d: variadic functions are not supported", index)
+	}
+
+	// Проверяем сигнатуру заранее, чтобы ошибки привязки не проявлялись во время выполнения
+	for i := 0; i < fnType.NumIn(); i++ {
+		switch fnType.In(i) {
+		case int32Type, float32Type, intsType, floatsType:
+		default:
+			return fmt.Errorf("host function %d: unsupported parameter type %s", index, fnType.In(i))
+		}
+	}
+	for i := 0; i < fnType.NumOut(); i++ {
+		out := fnType.Out(i)
+		if out == errorType && i == fnType.NumOut()-1 {
+			continue // error допускается только последним результатом
+		}
+		if out != int32Type && out != float32Type {
+			return fmt.Errorf("host function %d: unsupported result type %s", index, out)
+		}
+	}
+
+	p.RegisterHostCall(index, func(p *Processor, block uint16) error {
+		return p.callHostFunc(fnValue, block)
+	})
+	return nil
+}
+
+// callHostFunc читает аргументы из блока, вызывает функцию и записывает результаты обратно
+func (p *Processor) callHostFunc(fn reflect.Value, block uint16) error {
+	fnType := fn.Type()
+	args := make([]reflect.Value, fnType.NumIn())
+	var buffers []hostBuffer // Буферы для обратной записи в порядке параметров
+	slot := int(block)       // Текущее слово в блоке аргументов
+
+	for i := range args {
+		word, err := p.memory.ReadWord(slot)
+		if err != nil {
+			return err // Возвращаем ошибку, если чтение аргумента не удалось
+		}
+		switch fnType.In(i) {
+		case int32Type:
+			args[i] = reflect.ValueOf(word.D.I)
+			slot += WORD_SIZE
+		case float32Type:
+			args[i] = reflect.ValueOf(word.D.F)
+			slot += WORD_SIZE
+		case intsType, floatsType:
+			lengthWord, err := p.memory.ReadWord(slot + WORD_SIZE)
+			if err != nil {
+				return err // Возвращаем ошибку, если чтение длины буфера не удалось
+			}
+			addr, length := uint16(word.D.I), int(lengthWord.D.I)
+			if length < 0 {
+				return fmt.Errorf("negative buffer length %d for parameter %d", length, i)
+			}
+			buf, err := p.readBuffer(addr, length, fnType.In(i))
+			if err != nil {
+				return err
+			}
+			args[i] = buf
+			buffers = append(buffers, hostBuffer{param: i, addr: addr})
+			slot += 2 * WORD_SIZE
+		}
+	}
+
+	results := fn.Call(args)
+
+	// Последний результат типа error прерывает выполнение до записи результатов
+	if n := len(results); n > 0 && fnType.Out(n-1) == errorType {
+		if err, _ := results[n-1].Interface().(error); err != nil {
+			return err
+		}
+		results = results[:n-1]
+	}
+
+	// Записываем измененные буферы обратно в память гостя; перекрывающиеся буферы
+	// записываются в порядке параметров, и последний параметр побеждает
+	for _, b := range buffers {
+		if err := p.writeBuffer(b.addr, args[b.param]); err != nil {
+			return err
+		}
+	}
+
+	// Записываем результаты в начало блока аргументов
+	for i, result := range results {
+		var word Word
+		if result.Type() == float32Type {
+			word = FloatWord(float32(result.Float()))
+		} else {
+			word = IntWord(int32(result.Int()))
+		}
+		if err := p.memory.WriteWord(int(block)+i*WORD_SIZE, word); err != nil {
+			return err // Возвращаем ошибку, если запись результата не удалась
+		}
+	}
+	return nil
+}
+
+// hostBuffer — буфер параметра функции хоста, записываемый обратно в память гостя
+type hostBuffer struct {
+	param int    // Номер параметра
+	addr  uint16 // Адрес буфера в памяти гостя
+}
+
+// readBuffer копирует length слов из памяти гостя в срез нужного типа. Длина
+// проверяется по размеру памяти до выделения среза, чтобы гость не мог запросить
+// выделение, которое хост не переживет.
+func (p *Processor) readBuffer(addr uint16, length int, sliceType reflect.Type) (reflect.Value, error) {
+	if int64(addr)+int64(length)*WORD_SIZE > int64(p.memory.Size()) {
+		return reflect.Value{}, &MemoryError{Operation: "read", Address: int(addr), Message: fmt.Sprintf("buffer of %d words exceeds memory size %d", length, p.memory.Size())}
+	}
+	buf := reflect.MakeSlice(sliceType, length, length)
+	for j := 0; j < length; j++ {
+		word, err := p.memory.ReadWord(int(addr) + j*WORD_SIZE)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if sliceType == floatsType {
+			buf.Index(j).SetFloat(float64(word.D.F))
+		} else {
+			buf.Index(j).SetInt(int64(word.D.I))
+		}
+	}
+	return buf, nil
+}
+
+// writeBuffer записывает содержимое среза обратно в память гостя
+func (p *Processor) writeBuffer(addr uint16, buf reflect.Value) error {
+	for j := 0; j < buf.Len(); j++ {
+		var word Word
+		if buf.Type() == floatsType {
+			word = FloatWord(float32(buf.Index(j).Float()))
+		} else {
+			word = IntWord(int32(buf.Index(j).Int()))
+		}
+		if err := p.memory.WriteWord(int(addr)+j*WORD_SIZE, word); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package vm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Ширина полосы в отчете тепловой карты
+const heatBarWidth = 20
+
+// Heatmap — счетчики выполнения команд по адресам и обращений программы к памяти
+// данных по адресам (адрес слова или байта, как его видит программа). Выборки команд
+// не считаются обращениями к данным.
+type Heatmap struct {
+	Executions []uint64 // Число выполнений команды по адресу
+	Reads      []uint64 // Число чтений по адресу
+	Writes     []uint64 // Число записей по адресу
+}
+
+// heatEntry — строка отчета тепловой карты
+type heatEntry struct {
+	address int
+	count   uint64 // Число выполнений или сумма обращений
+	reads   uint64
+	writes  uint64
+}
+
+// SetHeatmap включает или выключает подсчет выполнений и обращений к памяти для
+// тепловой карты. Счетчики сбрасываются при включении и при Reset.
+func (p *Processor) SetHeatmap(enabled bool) {
+	if !enabled {
+		p.heatmap = nil
+		p.memory.heatmap = nil
+		return
+	}
+	p.heatmap = &Heatmap{
+		Executions: make([]uint64, p.code.Size()),
+		Reads:      make([]uint64, p.memory.Size()),
+		Writes:     make([]uint64, p.memory.Size()),
+	}
+	p.memory.heatmap = p.heatmap
+}
+
+// Heatmap возвращает счетчики тепловой карты или nil, если подсчет выключен
+func (p *Processor) Heatmap() *Heatmap {
+	return p.heatmap
+}
+
+// reset обнуляет счетчики
+func (h *Heatmap) reset() {
+	if h == nil {
+		return
+	}
+	clear(h.Executions)
+	clear(h.Reads)
+	clear(h.Writes)
+}
+
+// countRead учитывает чтение по адресу address
+func (h *Heatmap) countRead(address int) {
+	if h != nil && address >= 0 && address < len(h.Reads) {
+		h.Reads[address]++
+	}
+}
+
+// countWrite учитывает запись по адресу address
+func (h *Heatmap) countWrite(address int) {
+	if h != nil && address >= 0 && address < len(h.Writes) {
+		h.Writes[address]++
+	}
+}
+
+// hottest возвращает не более top адресов с наибольшими счетчиками (0 — все) и сумму
+// счетчиков по всем адресам
+func hottest(entries []heatEntry, top int) ([]heatEntry, uint64) {
+	var total uint64
+	for _, e := range entries {
+		total += e.count
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].count > entries[j].count })
+	if top > 0 && len(entries) > top {
+		entries = entries[:top]
+	}
+	return entries, total
+}
+
+// heatBar рисует полосу длиной, пропорциональной count относительно max
+func heatBar(count, max uint64) string {
+	if max == 0 {
+		return ""
+	}
+	n := int((count*heatBarWidth + max - 1) / max)
+	return strings.Repeat("#", n)
+}
+
+// WriteHeatmap выводит отчет тепловой карты: top самых часто выполняемых команд,
+// top самых используемых адресов данных и, при подключенной отладочной информации,
+// обращения по меткам данных. top = 0 выводит все адреса.
+func (p *Processor) WriteHeatmap(w io.Writer, top int) error {
+	h := p.heatmap
+	if h == nil {
+		return fmt.Errorf("heatmap is not enabled")
+	}
+	bw := bufio.NewWriter(w)
+
+	var executed []heatEntry
+	for address, count := range h.Executions {
+		if count > 0 {
+			executed = append(executed, heatEntry{address: address, count: count})
+		}
+	}
+	distinct := len(executed)
+	executed, total := hottest(executed, top)
+	fmt.Fprintf(bw, "Instruction heatmap: %d instructions executed at %d addresses\n", total, distinct)
+	if len(executed) > 0 {
+		fmt.Fprintf(bw, "%10s %6s  %-6s  %-24s  %s\n", "count", "share", "addr", "instruction", "location")
+	}
+	for _, e := range executed {
+		text := ""
+		if word, err := p.code.PeekWord(e.address); err == nil {
+			text = DisassembleWord(e.address, word)
+		}
+		fmt.Fprintf(bw, "%10d %5.1f%%  0x%04X  %-24s  %-30s %s\n", e.count, 100*float64(e.count)/float64(total),
+			e.address, text, p.DescribeAddress(e.address), heatBar(e.count, executed[0].count))
+	}
+
+	var accessed []heatEntry
+	var reads, writes uint64
+	for address := range h.Reads {
+		r, wr := h.Reads[address], h.Writes[address]
+		if r+wr > 0 {
+			accessed = append(accessed, heatEntry{address: address, count: r + wr, reads: r, writes: wr})
+			reads += r
+			writes += wr
+		}
+	}
+	fmt.Fprintf(bw, "\nMemory heatmap: %d reads and %d writes at %d addresses\n", reads, writes, len(accessed))
+	labels := p.heatByLabel(accessed)
+	accessed, _ = hottest(accessed, top)
+	if len(accessed) > 0 {
+		fmt.Fprintf(bw, "%10s %10s  %-6s  %s\n", "reads", "writes", "addr", "location")
+	}
+	for _, e := range accessed {
+		fmt.Fprintf(bw, "%10d %10d  0x%04X  %-30s %s\n", e.reads, e.writes, e.address,
+			p.DescribeAddress(e.address), heatBar(e.count, accessed[0].count))
+	}
+
+	if len(labels) > 0 {
+		labels, _ = hottest(labels, top)
+		fmt.Fprintf(bw, "\nMemory by label:\n%10s %10s  %s\n", "reads", "writes", "label")
+		for _, e := range labels {
+			name, _ := p.debugInfo.Label(e.address)
+			fmt.Fprintf(bw, "%10d %10d  %-30s %s\n", e.reads, e.writes, name.Name, heatBar(e.count, labels[0].count))
+		}
+	}
+	return bw.Flush()
+}
+
+// heatByLabel суммирует обращения по ближайшим меткам отладочной информации, чтобы
+// было видно, какие буферы используются чаще всего
+func (p *Processor) heatByLabel(accessed []heatEntry) []heatEntry {
+	if p.debugInfo == nil {
+		return nil
+	}
+	index := make(map[int]int) // Адрес метки -> индекс в результате
+	var labels []heatEntry
+	for _, e := range accessed {
+		if _, ok := p.debugInfo.Line(e.address); !ok {
+			continue // Стек и другие адреса вне программы не относятся к ее меткам
+		}
+		label, ok := p.debugInfo.Label(e.address)
+		if !ok {
+			continue
+		}
+		i, seen := index[label.Address]
+		if !seen {
+			i = len(labels)
+			index[label.Address] = i
+			labels = append(labels, heatEntry{address: label.Address})
+		}
+		labels[i].count += e.count
+		labels[i].reads += e.reads
+		labels[i].writes += e.writes
+	}
+	return labels
+}
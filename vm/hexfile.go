@@ -0,0 +1,412 @@
+package vm
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// HexFormat — формат шестнадцатеричного образа памяти
+type HexFormat int
+
+const (
+	HexIntel   HexFormat = iota + 1 // Intel HEX (записи ":LLAAAATT...CC")
+	HexSRecord                      // Motorola S-record (записи "S1...", "S9...")
+)
+
+// String возвращает название формата
+func (f HexFormat) String() string {
+	switch f {
+	case HexIntel:
+		return "Intel HEX"
+	case HexSRecord:
+		return "S-record"
+	}
+	return "unknown"
+}
+
+// Число байтов данных в одной записи при экспорте
+const hexRecordBytes = 16
+
+// HexFormatByExtension определяет формат образа по расширению файла
+func HexFormatByExtension(filename string) (HexFormat, bool) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".hex", ".ihx", ".ihex":
+		return HexIntel, true
+	case ".srec", ".s19", ".s28", ".s37", ".mot":
+		return HexSRecord, true
+	}
+	return 0, false
+}
+
+// DetectHexFormat определяет формат файла по расширению, а если оно не распознано —
+// по первой непустой строке. Файлы в формате загрузчика не распознаются.
+func DetectHexFormat(filename string) (HexFormat, bool) {
+	if format, ok := HexFormatByExtension(filename); ok {
+		return format, true
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return 0, false
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case line[0] == ':':
+			return HexIntel, true
+		case len(line) > 1 && line[0] == 'S' && line[1] >= '0' && line[1] <= '9':
+			return HexSRecord, true // Строка "S" без цифры — конец программы загрузчика
+		}
+		return 0, false
+	}
+	return 0, false
+}
+
+// LoadHexFile загружает образ в формате Intel HEX или S-record (формат определяется
+// DetectHexFormat). Полные слова с тегом команды записываются в память команд code,
+// остальные байты — в память данных data. Возвращает точку входа из записи стартового
+// адреса или 0, если ее нет.
+func LoadHexFile(filename string, code, data *Memory) (uint16, error) {
+	format, ok := DetectHexFormat(filename)
+	if !ok {
+		return 0, fmt.Errorf("unable to detect hex format of %s", filename)
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return 0, fmt.Errorf("unable to open file: %v", err)
+	}
+	defer file.Close()
+	return ReadHex(file, format, code, data)
+}
+
+// ReadHex читает образ в формате format и записывает его в память
+func ReadHex(r io.Reader, format HexFormat, code, data *Memory) (uint16, error) {
+	bytes := make(map[int]byte)
+	var entry uint32
+	var err error
+	switch format {
+	case HexIntel:
+		entry, err = readIntelHex(r, bytes)
+	case HexSRecord:
+		entry, err = readSRecord(r, bytes)
+	default:
+		return 0, fmt.Errorf("unknown hex format %d", format)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if entry > 0xFFFF {
+		return 0, fmt.Errorf("entry point 0x%X is out of range", entry)
+	}
+	if err := storeHexBytes(bytes, code, data); err != nil {
+		return 0, err
+	}
+	code.log.Logf(SubsystemLoader, LogInfo, "Loaded hex image: %d bytes, entry 0x%X", len(bytes), entry)
+	return uint16(entry), nil
+}
+
+// storeHexBytes записывает прочитанные байты в память пословно
+func storeHexBytes(bytes map[int]byte, code, data *Memory) error {
+	words := make(map[int]bool)
+	for addr := range bytes {
+		words[addr&^(WORD_SIZE-1)] = true
+	}
+	for base := range words {
+		var buf [WORD_SIZE]byte
+		complete := true
+		for i := range buf {
+			b, ok := bytes[base+i]
+			buf[i], complete = b, complete && ok
+		}
+		if complete {
+			mem := data
+			if binary.LittleEndian.Uint64(buf[:])&tagCommand != 0 {
+				mem = code
+			}
+			if err := mem.writeBytes(base, buf[:]); err != nil {
+				return fmt.Errorf("failed to load word at 0x%X: %w", base, err)
+			}
+			continue
+		}
+		for i := range buf { // Неполное слово записываем побайтно в память данных
+			if b, ok := bytes[base+i]; ok {
+				if err := data.writeBytes(base+i, []byte{b}); err != nil {
+					return fmt.Errorf("failed to load byte at 0x%X: %w", base+i, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// parseHexRecord декодирует шестнадцатеричные цифры записи
+func parseHexRecord(digits string) ([]byte, error) {
+	if len(digits)%2 != 0 {
+		return nil, fmt.Errorf("odd number of hex digits")
+	}
+	record, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex digits: %v", err)
+	}
+	return record, nil
+}
+
+// readIntelHex читает записи Intel HEX (типы 00-05) и возвращает стартовый адрес
+func readIntelHex(r io.Reader, bytes map[int]byte) (uint32, error) {
+	var entry uint32
+	base := 0 // Смещение из записей расширенного адреса (типы 02 и 04)
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fail := func(format string, args ...interface{}) error {
+			return &CommandError{LineNumber: lineNumber, Line: line, Message: fmt.Sprintf(format, args...)}
+		}
+		if line[0] != ':' {
+			return 0, fail("record does not start with ':'")
+		}
+		record, err := parseHexRecord(line[1:])
+		if err != nil {
+			return 0, fail("%v", err)
+		}
+		if len(record) < 5 || len(record) != int(record[0])+5 {
+			return 0, fail("record length does not match its byte count")
+		}
+		var sum byte
+		for _, b := range record {
+			sum += b
+		}
+		if sum != 0 {
+			return 0, fail("checksum mismatch")
+		}
+		payload := record[4 : len(record)-1]
+		address := int(binary.BigEndian.Uint16(record[1:3]))
+		switch kind := record[3]; kind {
+		case 0x00: // Данные
+			for i, b := range payload {
+				bytes[base+address+i] = b
+			}
+		case 0x01: // Конец файла
+			return entry, nil
+		case 0x02, 0x04: // Расширенный адрес сегмента или линейный адрес
+			if len(payload) != 2 {
+				return 0, fail("extended address record requires 2 bytes")
+			}
+			base = int(binary.BigEndian.Uint16(payload)) << 16
+			if kind == 0x02 {
+				base >>= 12 // Адрес сегмента умножается на 16
+			}
+		case 0x03, 0x05: // Стартовый адрес
+			if len(payload) != 4 {
+				return 0, fail("start address record requires 4 bytes")
+			}
+			entry = binary.BigEndian.Uint32(payload)
+			if kind == 0x03 {
+				entry = entry>>16<<4 + entry&0xFFFF // CS:IP
+			}
+		default:
+			return 0, fail("unknown record type 0x%02X", kind)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("error reading file: %v", err)
+	}
+	return 0, fmt.Errorf("missing end-of-file record")
+}
+
+// readSRecord читает записи S0-S9 и возвращает стартовый адрес
+func readSRecord(r io.Reader, bytes map[int]byte) (uint32, error) {
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fail := func(format string, args ...interface{}) error {
+			return &CommandError{LineNumber: lineNumber, Line: line, Message: fmt.Sprintf(format, args...)}
+		}
+		if len(line) < 2 || line[0] != 'S' {
+			return 0, fail("record does not start with 'S'")
+		}
+		record, err := parseHexRecord(line[2:])
+		if err != nil {
+			return 0, fail("%v", err)
+		}
+		if len(record) < 2 || len(record) != int(record[0])+1 {
+			return 0, fail("record length does not match its byte count")
+		}
+		var sum byte
+		for _, b := range record[:len(record)-1] {
+			sum += b
+		}
+		if ^sum != record[len(record)-1] {
+			return 0, fail("checksum mismatch")
+		}
+		kind := line[1]
+		width := map[byte]int{'0': 2, '1': 2, '2': 3, '3': 4, '5': 2, '6': 3, '7': 4, '8': 3, '9': 2}[kind]
+		if width == 0 {
+			return 0, fail("unknown record type S%c", kind)
+		}
+		if len(record) < width+2 {
+			return 0, fail("record is too short for its address")
+		}
+		var address uint32
+		for _, b := range record[1 : 1+width] {
+			address = address<<8 | uint32(b)
+		}
+		payload := record[1+width : len(record)-1]
+		switch kind {
+		case '1', '2', '3': // Данные
+			for i, b := range payload {
+				bytes[int(address)+i] = b
+			}
+		case '7', '8', '9': // Стартовый адрес завершает файл
+			return address, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("error reading file: %v", err)
+	}
+	return 0, nil // Запись стартового адреса необязательна
+}
+
+// WriteHex записывает непустые слова памяти в формате format. Команды берутся из
+// памяти команд code, данные — из памяти данных data (для фон-неймановского процессора
+// это одна и та же память); нулевые слова и окна устройств пропускаются.
+func WriteHex(w io.Writer, format HexFormat, code, data *Memory, entry uint16) error {
+	image, err := hexImage(code, data)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	switch format {
+	case HexIntel:
+		writeIntelHex(bw, image, entry)
+	case HexSRecord:
+		writeSRecord(bw, image, entry)
+	default:
+		return fmt.Errorf("unknown hex format %d", format)
+	}
+	return bw.Flush()
+}
+
+// hexChunk — непрерывный участок образа
+type hexChunk struct {
+	address int
+	data    []byte
+}
+
+// hexImage собирает непрерывные участки непустых слов памяти
+func hexImage(code, data *Memory) ([]hexChunk, error) {
+	words := make(map[int][]byte)
+	collect := func(m *Memory, commands bool) error {
+		for addr := 0; addr+WORD_SIZE <= m.Size(); addr += WORD_SIZE {
+			buf := make([]byte, WORD_SIZE)
+			if m.readBytes(addr, buf) != nil {
+				continue // Окна устройств и неотображенные адреса
+			}
+			raw := binary.LittleEndian.Uint64(buf)
+			if raw == 0 || (raw&tagCommand != 0) != commands {
+				continue
+			}
+			if _, ok := words[addr]; ok {
+				return fmt.Errorf("code and data words overlap at 0x%X", addr)
+			}
+			words[addr] = buf
+		}
+		return nil
+	}
+	if err := collect(code, true); err != nil {
+		return nil, err
+	}
+	if err := collect(data, false); err != nil {
+		return nil, err
+	}
+
+	addresses := make([]int, 0, len(words))
+	for addr := range words {
+		addresses = append(addresses, addr)
+	}
+	sort.Ints(addresses)
+	var chunks []hexChunk
+	for _, addr := range addresses {
+		last := len(chunks) - 1
+		if last >= 0 && chunks[last].address+len(chunks[last].data) == addr {
+			chunks[last].data = append(chunks[last].data, words[addr]...)
+			continue
+		}
+		chunks = append(chunks, hexChunk{address: addr, data: words[addr]})
+	}
+	return chunks, nil
+}
+
+// forEachRecord делит участки образа на записи по hexRecordBytes байтов
+func forEachRecord(image []hexChunk, emit func(address int, data []byte)) {
+	for _, chunk := range image {
+		for i := 0; i < len(chunk.data); i += hexRecordBytes {
+			end := i + hexRecordBytes
+			if end > len(chunk.data) {
+				end = len(chunk.data)
+			}
+			emit(chunk.address+i, chunk.data[i:end])
+		}
+	}
+}
+
+// writeIntelRecord записывает одну запись Intel HEX с контрольной суммой
+func writeIntelRecord(w io.Writer, kind byte, address int, payload []byte) {
+	record := append([]byte{byte(len(payload)), byte(address >> 8), byte(address), kind}, payload...)
+	var sum byte
+	for _, b := range record {
+		sum += b
+	}
+	fmt.Fprintf(w, ":%s%02X\n", strings.ToUpper(hex.EncodeToString(record)), -sum)
+}
+
+// writeIntelHex записывает образ в формате Intel HEX
+func writeIntelHex(w io.Writer, image []hexChunk, entry uint16) {
+	forEachRecord(image, func(address int, data []byte) {
+		writeIntelRecord(w, 0x00, address, data)
+	})
+	writeIntelRecord(w, 0x05, 0, []byte{0, 0, byte(entry >> 8), byte(entry)})
+	writeIntelRecord(w, 0x01, 0, nil)
+}
+
+// writeSRecordLine записывает одну запись S-record с 16-битным адресом
+func writeSRecordLine(w io.Writer, kind byte, address int, payload []byte) {
+	record := append([]byte{byte(len(payload) + 3), byte(address >> 8), byte(address)}, payload...)
+	var sum byte
+	for _, b := range record {
+		sum += b
+	}
+	fmt.Fprintf(w, "S%c%s%02X\n", kind, strings.ToUpper(hex.EncodeToString(record)), ^sum)
+}
+
+// writeSRecord записывает образ в формате S-record: заголовок S0, данные S1,
+// число записей S5 и стартовый адрес S9
+func writeSRecord(w io.Writer, image []hexChunk, entry uint16) {
+	writeSRecordLine(w, '0', 0, []byte("vm"))
+	count := 0
+	forEachRecord(image, func(address int, data []byte) {
+		writeSRecordLine(w, '1', address, data)
+		count++
+	})
+	writeSRecordLine(w, '5', count, nil)
+	writeSRecordLine(w, '9', int(entry), nil)
+}
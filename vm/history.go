@@ -0,0 +1,136 @@
+package vm
+
+import "fmt"
+
+// memoryUndo — прежнее содержимое байтов памяти, измененных инструкцией
+type memoryUndo struct {
+	mem     *Memory
+	address int    // Физический адрес
+	old     []byte // Байты до записи
+}
+
+// undoRecord — состояние процессора перед инструкцией и отмена ее записей в память
+type undoRecord struct {
+	psw        PSW
+	registers  [NUM_REGISTERS]int32
+	fregisters [NUM_FLOAT_REGISTERS]float32
+	segment    Segment
+	callDepth  int
+	pending    []Event
+	usage      ResourceUsage
+	status     Status
+	stop       bool
+	error      bool
+	exitCode   int32
+	runErr     error
+	inputs     int          // Позиция записи ввода (см. Recording)
+	writes     []memoryUndo // Записи в память в порядке выполнения
+}
+
+// history — кольцевой буфер записей отмены последних инструкций
+type history struct {
+	records []undoRecord
+	start   int         // Индекс самой старой записи
+	count   int         // Количество записей
+	current *undoRecord // Запись выполняемой инструкции
+}
+
+// SetHistory включает запись последних n инструкций для StepBack; 0 выключает запись
+// и очищает историю. Отменяются изменения регистров, PSW, стека и памяти; состояние
+// устройств (консоль, таймер, диск, банки) не восстанавливается.
+func (p *Processor) SetHistory(n int) {
+	if n <= 0 {
+		p.history = nil
+		return
+	}
+	p.history = &history{records: make([]undoRecord, n)}
+}
+
+// HistoryLen возвращает количество инструкций, которые можно отменить
+func (p *Processor) HistoryLen() int {
+	if p.history == nil {
+		return 0
+	}
+	return p.history.count
+}
+
+// StepBack отменяет последнюю выполненную инструкцию, в том числе инструкцию,
+// остановившую программу ошибкой, и возвращает новое значение IP
+func (p *Processor) StepBack() (uint16, error) {
+	h := p.history
+	if h == nil || h.count == 0 {
+		return p.psw.IP, fmt.Errorf("no instruction history to step back")
+	}
+	h.count--
+	r := &h.records[(h.start+h.count)%len(h.records)]
+	for i := len(r.writes) - 1; i >= 0; i-- {
+		w := r.writes[i]
+		if err := w.mem.writeBytes(w.address, w.old); err != nil {
+			return p.psw.IP, fmt.Errorf("failed to restore memory at 0x%X: %v", w.address, err)
+		}
+	}
+	p.psw = r.psw
+	p.setUserMode(r.psw.UserMode)
+	p.registers, p.fregisters = r.registers, r.fregisters
+	p.memory.segment = r.segment
+	p.callDepth = r.callDepth
+	p.setPending(r.pending)
+	p.usage = r.usage
+	p.status, p.stop, p.error = r.status, r.stop, r.error
+	p.exitCode, p.runErr = r.exitCode, r.runErr
+	p.recording.restore(r.inputs) // Повторное выполнение получит тот же ввод
+	*r = undoRecord{}             // Освобождаем сохраненные байты
+	p.updateWatches()             // Выражения наблюдения показывают восстановленное состояние
+	return p.psw.IP, nil
+}
+
+// beginUndo запоминает состояние процессора перед инструкцией
+func (p *Processor) beginUndo() {
+	h := p.history
+	if h == nil {
+		return
+	}
+	var r *undoRecord
+	if h.count < len(h.records) {
+		r = &h.records[(h.start+h.count)%len(h.records)]
+		h.count++
+	} else {
+		r = &h.records[h.start] // Буфер заполнен: вытесняем самую старую запись
+		h.start = (h.start + 1) % len(h.records)
+	}
+	*r = undoRecord{
+		psw:        p.psw,
+		registers:  p.registers,
+		fregisters: p.fregisters,
+		segment:    p.memory.segment,
+		callDepth:  p.callDepth,
+		pending:    p.PendingInterrupts(),
+		usage:      p.usage,
+		status:     p.status,
+		stop:       p.stop,
+		error:      p.error,
+		exitCode:   p.exitCode,
+		runErr:     p.runErr,
+		inputs:     p.recording.mark(),
+	}
+	h.current = r
+	p.memory.journal = p.recordWrite
+	p.code.journal = p.recordWrite
+}
+
+// endUndo завершает запись инструкции
+func (p *Processor) endUndo() {
+	if p.history == nil {
+		return
+	}
+	p.history.current = nil
+	p.memory.journal = nil
+	p.code.journal = nil
+}
+
+// recordWrite сохраняет прежнее содержимое байтов перед записью в память
+func (p *Processor) recordWrite(mem *Memory, address int, old []byte) {
+	if r := p.history.current; r != nil {
+		r.writes = append(r.writes, memoryUndo{mem: mem, address: address, old: old})
+	}
+}
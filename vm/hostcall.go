@@ -0,0 +1,26 @@
+package vm
+
+// HostCallHandler обрабатывает гипервызов гостевой программы.
+// arg — эффективный адрес второго операнда команды HCALL; обработчик может
+// читать и изменять регистры через GetRegister/SetRegister и память через Memory().
+type HostCallHandler func(p *Processor, arg uint16) error
+
+// RegisterHostCall регистрирует обработчик для номера сервиса, заменяя предыдущий
+func (p *Processor) RegisterHostCall(service uint16, handler HostCallHandler) {
+	p.hostCalls[service] = handler // Сохраняем обработчик в таблице гипервызовов
+}
+
+// UnregisterHostCall удаляет обработчик для номера сервиса
+func (p *Processor) UnregisterHostCall(service uint16) {
+	delete(p.hostCalls, service) // Удаляем обработчик из таблицы гипервызовов
+}
+
+// Memory возвращает память процессора для использования обработчиками хоста
+func (p *Processor) Memory() *Memory {
+	return p.memory
+}
+
+// CodeMemory возвращает память команд; без гарвардского режима это та же память, что и Memory()
+func (p *Processor) CodeMemory() *Memory {
+	return p.code
+}
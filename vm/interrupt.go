@@ -0,0 +1,258 @@
+package vm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Размер очереди асинхронных событий от хоста
+const EVENT_QUEUE_SIZE = 64 // Константа, определяющая емкость канала событий
+
+// Таблица векторов прерываний: слово с номером irq содержит адрес обработчика (0 — обработчика нет)
+const (
+	VECTOR_TABLE_BASE = 0xE000 // Адрес таблицы векторов по умолчанию
+	NUM_VECTORS       = 16     // Количество векторов в таблице
+)
+
+// Векторы синхронных исключений (ловушек)
+const (
+	VECTOR_DIVIDE_ERROR   = 0 // Деление на ноль
+	VECTOR_INVALID_OPCODE = 1 // Недопустимый код операции
+	VECTOR_PRIVILEGE      = 2 // Нарушение привилегий в режиме пользователя
+	VECTOR_PAGE_FAULT     = 3 // Отказ страницы при трансляции адреса
+	VECTOR_BOUNDS         = 4 // Обращение за предел сегмента данных
+)
+
+// InvalidOpcodePolicy определяет реакцию процессора на недопустимую команду
+type InvalidOpcodePolicy int
+
+const (
+	InvalidOpcodeTrap InvalidOpcodePolicy = iota // Ловушка VECTOR_INVALID_OPCODE; без обработчика — остановка с ошибкой
+	InvalidOpcodeHalt                            // Штатная остановка программы, как по команде STOP
+	InvalidOpcodeSkip                            // Запись в журнал и переход к следующей команде
+)
+
+// String возвращает строковое представление политики
+func (policy InvalidOpcodePolicy) String() string {
+	switch policy {
+	case InvalidOpcodeTrap:
+		return "trap"
+	case InvalidOpcodeHalt:
+		return "halt"
+	case InvalidOpcodeSkip:
+		return "skip"
+	default:
+		return "unknown"
+	}
+}
+
+// TrapError — синхронное исключение команды. Если в таблице векторов установлен
+// обработчик, управление передается ему; иначе программа останавливается с ошибкой.
+type TrapError struct {
+	Vector uint8 // Вектор исключения
+	Err    error // Исходная ошибка
+	Fault  bool  // После IRET команда, вызвавшая исключение, выполняется повторно
+}
+
+// Error реализует интерфейс error для TrapError
+func (e *TrapError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap возвращает исходную ошибку
+func (e *TrapError) Unwrap() error {
+	return e.Err
+}
+
+// errDivideByZero создает ловушку деления на ноль
+func errDivideByZero() error {
+	return &TrapError{Vector: VECTOR_DIVIDE_ERROR, Err: fmt.Errorf("division by zero")}
+}
+
+// Event представляет асинхронное событие, доставляемое хостом в гостевую программу
+type Event struct {
+	IRQ  uint8 // Номер линии прерывания
+	Data int32 // Дополнительные данные события (например, код клавиши)
+}
+
+// RaiseInterrupt асинхронно доставляет прерывание irq в выполняющуюся программу.
+// Метод безопасен для вызова из любой горутины.
+func (p *Processor) RaiseInterrupt(irq uint8) error {
+	return p.PostEvent(Event{IRQ: irq})
+}
+
+// PostEvent помещает событие в очередь процессора, не блокируя вызывающую горутину
+func (p *Processor) PostEvent(ev Event) error {
+	select {
+	case p.events <- ev: // Канал обеспечивает синхронизацию с циклом выполнения
+		return nil
+	default:
+		return fmt.Errorf("event queue is full, dropping IRQ %d", ev.IRQ) // Очередь переполнена
+	}
+}
+
+// Events возвращает канал для отправки событий в гостевую программу
+func (p *Processor) Events() chan<- Event {
+	return p.events
+}
+
+// PendingInterrupts возвращает копию событий, принятых циклом выполнения, но еще не
+// обработанных гостем. Безопасен для вызова из любой горутины, в том числе во время Run.
+func (p *Processor) PendingInterrupts() []Event {
+	p.pendingMu.Lock()
+	defer p.pendingMu.Unlock()
+	return append([]Event(nil), p.pending...)
+}
+
+// setPending заменяет очередь необработанных прерываний
+func (p *Processor) setPending(events []Event) {
+	p.pendingMu.Lock()
+	p.pending = events
+	p.pendingMu.Unlock()
+}
+
+// pollEvents забирает все события из канала между инструкциями.
+// Вызывается только из горутины, выполняющей Run; p.pending изменяется под pendingMu.
+func (p *Processor) pollEvents() error {
+	for {
+		select {
+		case ev := <-p.events:
+			if err := p.acceptEvent(ev); err != nil {
+				return err
+			}
+		default:
+			return nil // Новых событий нет
+		}
+	}
+}
+
+// acceptEvent ставит принятое событие в очередь ожидания с учетом квоты прерываний
+func (p *Processor) acceptEvent(ev Event) error {
+	if err := p.chargeInterrupt(); err != nil {
+		return err // Превышена квота обработанных прерываний
+	}
+	p.pendingMu.Lock()
+	p.pending = append(p.pending, ev) // Запоминаем событие до его обработки гостем
+	p.pendingMu.Unlock()
+	p.log.Logf(SubsystemDevices, LogDebug, "Interrupt: IRQ %d received (data %d)", ev.IRQ, ev.Data)
+	return nil
+}
+
+// SetVectorTable задает адрес таблицы векторов прерываний
+func (p *Processor) SetVectorTable(base int) error {
+	if base < 0 || base+NUM_VECTORS*WORD_SIZE > p.memory.Size() {
+		return fmt.Errorf("vector table at 0x%X is out of memory range", base)
+	}
+	p.vectorBase = base
+	return nil
+}
+
+// VectorTable возвращает адрес таблицы векторов прерываний
+func (p *Processor) VectorTable() int {
+	return p.vectorBase
+}
+
+// vectorFor читает адрес обработчика прерывания irq; 0 означает, что обработчик не установлен
+func (p *Processor) vectorFor(irq uint8) (uint16, error) {
+	if int(irq) >= NUM_VECTORS {
+		return 0, nil // Линии за пределами таблицы не обслуживаются
+	}
+	word, err := p.memory.readPhysical("read", p.vectorBase+int(irq)*WORD_SIZE, PermRead) // Таблица векторов не транслируется MMU
+	if err != nil {
+		return 0, fmt.Errorf("failed to read interrupt vector %d: %w", irq, err)
+	}
+	return uint16(word.D.I), nil
+}
+
+// dispatchInterrupts доставляет первое ожидающее прерывание, для которого установлен
+// обработчик. Прерывания без обработчика остаются в очереди ожидания.
+func (p *Processor) dispatchInterrupts() error {
+	if !p.psw.InterruptFlag {
+		return nil // Прерывания запрещены
+	}
+	ev, handler, err := p.takeInterrupt()
+	if err != nil || handler == 0 {
+		return err
+	}
+	return p.enterInterrupt(ev.IRQ, handler, p.psw.IP)
+}
+
+// takeInterrupt извлекает из очереди ожидания первое прерывание, для которого
+// установлен обработчик; handler 0 означает, что доставлять нечего
+func (p *Processor) takeInterrupt() (Event, uint16, error) {
+	p.pendingMu.Lock()
+	defer p.pendingMu.Unlock()
+	for i, ev := range p.pending {
+		handler, err := p.vectorFor(ev.IRQ)
+		if err != nil {
+			return Event{}, 0, err
+		}
+		if handler == 0 {
+			continue
+		}
+		p.pending = append(p.pending[:i], p.pending[i+1:]...) // Прерывание обслужено
+		return ev, handler, nil
+	}
+	return Event{}, 0, nil
+}
+
+// enterInterrupt сохраняет FLAGS и адрес возврата returnIP в стеке, запрещает прерывания
+// и переходит к обработчику. Команда IRET восстанавливает сохраненное состояние.
+func (p *Processor) enterInterrupt(vector uint8, handler, returnIP uint16) error {
+	if err := p.push(IntWord(int32(p.GetFlags()))); err != nil {
+		return err
+	}
+	if err := p.push(IntWord(int32(returnIP))); err != nil {
+		return err
+	}
+	p.psw.InterruptFlag = false // Обработчик не прерывается, пока сам не выполнит EI
+	p.setUserMode(false)        // Обработчик выполняется в режиме супервизора
+	p.logf(LogDebug, "Interrupt: vector %d delivered, return to 0x%X, handler 0x%X", vector, returnIP, handler)
+	p.jumpTo(handler)
+	return nil
+}
+
+// handleTrap передает ловушку обработчику из таблицы векторов. Возвращает false, если
+// err не является ловушкой или обработчик не установлен. После IRET выполнение
+// продолжается с команды, следующей за command, а для отказов — с самой command.
+func (p *Processor) handleTrap(err error, command uint16) (bool, error) {
+	var trap *TrapError
+	if !errors.As(err, &trap) {
+		return false, nil
+	}
+	handler, verr := p.vectorFor(trap.Vector)
+	if verr != nil || handler == 0 {
+		return false, nil // Обработчика нет: ошибка остается фатальной
+	}
+	p.logf(LogDebug, "Trap: %v at 0x%X", trap.Err, command)
+	returnIP := uint16((int(command) + WORD_SIZE) % p.code.Size())
+	if trap.Fault {
+		returnIP = command // Команда будет выполнена повторно
+	}
+	return true, p.enterInterrupt(trap.Vector, handler, returnIP)
+}
+
+// SetInvalidOpcodePolicy задает реакцию на недопустимые коды операций и слова данных,
+// выбранные как команды
+func (p *Processor) SetInvalidOpcodePolicy(policy InvalidOpcodePolicy) {
+	p.invalidOpcode = policy
+}
+
+// invalidInstruction обрабатывает недопустимую команду по адресу ip согласно политике
+func (p *Processor) invalidInstruction(ip uint16, err error) error {
+	switch p.invalidOpcode {
+	case InvalidOpcodeHalt:
+		p.logf(LogError, "Halting on invalid instruction: %v", p.sourceError(int(ip), err))
+		p.stop = true // Останавливаемся без ошибки
+		return nil
+	case InvalidOpcodeSkip:
+		p.logf(LogWarn, "Skipping invalid instruction: %v", p.sourceError(int(ip), err))
+		p.psw.IP = uint16((int(ip) + WORD_SIZE) % p.code.Size())
+		return nil
+	}
+	trap := &TrapError{Vector: VECTOR_INVALID_OPCODE, Err: err}
+	if handled, trapErr := p.handleTrap(trap, ip); handled {
+		return trapErr // Ловушка передана обработчику программы
+	}
+	return err
+}
@@ -0,0 +1,80 @@
+package vm
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// InvariantError — нарушение внутренних инвариантов машины после выполнения команды.
+// Такая ошибка указывает на ошибку реализации VM, а не программы.
+type InvariantError struct {
+	IP         uint16   // Адрес команды, после которой обнаружено нарушение
+	Violations []string // Описания нарушенных инвариантов
+}
+
+// Error реализует интерфейс error для InvariantError
+func (e *InvariantError) Error() string {
+	return fmt.Sprintf("invariant violated after instruction at 0x%X: %s", e.IP, strings.Join(e.Violations, "; "))
+}
+
+// flagResult — результат, по которому команда последней установила флаги
+type flagResult struct {
+	valid    bool
+	zero     bool
+	negative bool
+}
+
+// SetCheckInvariants включает проверку инвариантов машины после каждой команды:
+// IP в пределах памяти команд, конечные значения вещественных регистров, флаги ZF и SF,
+// соответствующие результату команды, и SP внутри области стека. При нарушении
+// программа останавливается с ошибкой *InvariantError и отчетом об аварии.
+func (p *Processor) SetCheckInvariants(enabled bool) {
+	p.checkInvariants = enabled
+}
+
+// CheckInvariants проверяет инварианты текущего состояния машины
+func (p *Processor) CheckInvariants() error {
+	if err := p.invariants(p.psw.IP); err != nil {
+		return err
+	}
+	return nil
+}
+
+// invariants возвращает нарушения инвариантов после команды по адресу ip или nil
+func (p *Processor) invariants(ip uint16) *InvariantError {
+	var violations []string
+	if !p.code.IsValidAddress(int(p.psw.IP)) {
+		violations = append(violations, fmt.Sprintf("IP 0x%X is outside code memory of %d bytes", p.psw.IP, p.code.Size()))
+	}
+	for i, f := range p.fregisters {
+		if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
+			violations = append(violations, fmt.Sprintf("f%d is not finite (%v)", i, f))
+		}
+	}
+	if p.psw.ZeroFlag && p.psw.SignFlag {
+		violations = append(violations, "ZF and SF are both set")
+	}
+	if r := p.lastResult; r.valid && (p.psw.ZeroFlag != r.zero || p.psw.SignFlag != r.negative) {
+		violations = append(violations, fmt.Sprintf("flags ZF=%t SF=%t do not match the last result (zero %t, negative %t)",
+			p.psw.ZeroFlag, p.psw.SignFlag, r.zero, r.negative))
+	}
+	sp := int(p.psw.SP)
+	if sp < p.stackLimit || sp > p.stackBase {
+		violations = append(violations, fmt.Sprintf("SP 0x%X is outside the stack [0x%X-0x%X]", sp, p.stackLimit, p.stackBase))
+	} else if (p.stackBase-sp)%WORD_SIZE != 0 {
+		violations = append(violations, fmt.Sprintf("SP 0x%X is not a whole number of words below the stack base 0x%X", sp, p.stackBase))
+	}
+	if p.callDepth < 0 {
+		violations = append(violations, fmt.Sprintf("call depth %d is negative", p.callDepth))
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return &InvariantError{IP: ip, Violations: violations}
+}
+
+// recordFlagResult запоминает результат, по которому установлены ZF и SF
+func (p *Processor) recordFlagResult(zero, negative bool) {
+	p.lastResult = flagResult{valid: true, zero: zero, negative: negative}
+}
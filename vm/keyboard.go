@@ -0,0 +1,87 @@
+package vm
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// Регистры клавиатуры (смещения относительно начала окна устройства)
+const (
+	KBD_STATUS = 0 * WORD_SIZE // Состояние: биты KBD_READY и KBD_EOF
+	KBD_DATA   = 1 * WORD_SIZE // Чтение забирает следующий символ или возвращает -1
+
+	KBD_SIZE = 2 * WORD_SIZE // Размер окна клавиатуры в байтах
+	KBD_BASE = 0xF0A0        // Адрес окна клавиатуры по умолчанию
+)
+
+// Биты регистра состояния клавиатуры
+const (
+	KBD_READY = 0x01 // В буфере есть символ
+	KBD_EOF   = 0x02 // Ввод закончился
+)
+
+// Размер буфера клавиатуры
+const KBD_BUFFER_SIZE = 256 // Символы сверх буфера ждут, пока программа их прочитает
+
+// Keyboard — неблокирующее устройство ввода символов. Фоновая горутина читает ввод
+// хоста в буфер, а программа опрашивает регистр состояния и забирает символы из
+// KBD_DATA, не останавливаясь в ожидании, как IIN.
+type Keyboard struct {
+	keys chan byte   // Буфер принятых символов
+	eof  atomic.Bool // Ввод хоста закончился или завершился ошибкой
+}
+
+// NewKeyboard создает клавиатуру и запускает чтение in в фоновой горутине
+func NewKeyboard(in io.Reader) *Keyboard {
+	k := &Keyboard{keys: make(chan byte, KBD_BUFFER_SIZE)}
+	go k.readLoop(in)
+	return k
+}
+
+// readLoop переносит символы из in в буфер до конца ввода
+func (k *Keyboard) readLoop(in io.Reader) {
+	var buf [1]byte
+	for {
+		n, err := in.Read(buf[:])
+		if n > 0 {
+			k.keys <- buf[0]
+		}
+		if err != nil {
+			k.eof.Store(true)
+			return
+		}
+	}
+}
+
+// Read читает регистр клавиатуры
+func (k *Keyboard) Read(addr int) (Word, error) {
+	switch addr {
+	case KBD_STATUS:
+		var status int32
+		if len(k.keys) > 0 {
+			status |= KBD_READY
+		} else if k.eof.Load() {
+			status |= KBD_EOF // Конец ввода сообщается только после выборки всех символов
+		}
+		return IntWord(status), nil
+	case KBD_DATA:
+		select {
+		case b := <-k.keys:
+			return IntWord(int32(b)), nil
+		default:
+			return IntWord(-1), nil // Символов нет: программа не блокируется
+		}
+	}
+	return Word{}, fmt.Errorf("keyboard register 0x%X does not exist", addr)
+}
+
+// Write отклоняет запись: регистры клавиатуры доступны только для чтения
+func (k *Keyboard) Write(addr int, word Word) error {
+	return fmt.Errorf("keyboard register 0x%X is not writable", addr)
+}
+
+// MapKeyboard отображает клавиатуру в память процессора по адресу base
+func (p *Processor) MapKeyboard(base int, keyboard *Keyboard) error {
+	return p.mapInputDevice("keyboard", base, KBD_SIZE, keyboard)
+}
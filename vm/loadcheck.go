@@ -0,0 +1,141 @@
+package vm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// OverlapPolicy задает реакцию загрузчика на повторную запись адреса
+type OverlapPolicy int
+
+const (
+	OverlapAllow OverlapPolicy = iota // Поздняя запись молча заменяет раннюю (по умолчанию)
+	OverlapWarn                       // О перекрытии сообщается в LoadOptions.Warnings
+	OverlapError                      // Перекрытие — ошибка загрузки
+)
+
+// LoadOptions задает проверки загрузчика
+type LoadOptions struct {
+	Overlap  OverlapPolicy // Реакция на перекрывающиеся записи и данные в точке входа
+	Warnings io.Writer     // Получатель предупреждений при OverlapWarn; nil — предупреждения не выводятся
+}
+
+// loadKey — байт памяти, записанный загрузчиком. Адреса окна банков различаются по банку.
+type loadKey struct {
+	mem     *Memory
+	bank    int
+	address int
+}
+
+// loadRecord описывает запись слова, накрывшую байт
+type loadRecord struct {
+	line    int  // Номер строки программы
+	start   int  // Адрес начала слова
+	command bool // Записана команда
+}
+
+// loadTracker запоминает записанные загрузчиком слова для проверки перекрытий
+// и контрольной суммы
+type loadTracker struct {
+	options LoadOptions
+	written map[loadKey]loadRecord
+	words   []ImageWord     // Загруженные слова в порядке строк программы
+	index   map[loadKey]int // Последняя запись слова по начальному адресу
+	log     *Logger         // Журнал процессора, в память которого идет загрузка
+}
+
+// newLoadTracker создает трекер записей загрузчика
+func newLoadTracker(options LoadOptions) *loadTracker {
+	return &loadTracker{options: options, written: make(map[loadKey]loadRecord), index: make(map[loadKey]int)}
+}
+
+// Checksum вычисляет CRC-32 (IEEE) последовательности слов программы: для каждого
+// слова — 16-битный адрес и 64-битное представление слова в памяти (little-endian).
+// Загрузчик сравнивает ее со значением директивы x для слов в порядке строк файла.
+func Checksum(words []ImageWord) uint32 {
+	h := crc32.NewIEEE()
+	var buf [2 + WORD_SIZE]byte
+	for _, iw := range words {
+		binary.LittleEndian.PutUint16(buf[:2], uint16(iw.Address))
+		binary.LittleEndian.PutUint64(buf[2:], wordBits(iw.Word))
+		h.Write(buf[:])
+	}
+	return h.Sum32()
+}
+
+// key возвращает ключ байта address памяти mem с учетом выбранного банка
+func (t *loadTracker) key(mem *Memory, address int) loadKey {
+	k := loadKey{mem: mem, address: address}
+	if w := mem.bankWindow; w != nil && w.contains(address, 1) {
+		k.bank = mem.Bank()
+	}
+	return k
+}
+
+// write проверяет перекрытие и записывает слово word по адресу address
+func (t *loadTracker) write(mem *Memory, address int, word Word, line int) error {
+	if t.options.Overlap != OverlapAllow {
+		for i := 0; i < WORD_SIZE; i++ {
+			prev, ok := t.written[t.key(mem, address+i)]
+			if !ok {
+				continue
+			}
+			if err := t.report(line, "address 0x%X overwrites the word at 0x%X written at line %d", address, prev.start, prev.line); err != nil {
+				return err
+			}
+			break // Об одном слове сообщаем один раз
+		}
+		for i := 0; i < WORD_SIZE; i++ {
+			t.written[t.key(mem, address+i)] = loadRecord{line: line, start: address, command: word.IsCommand()}
+		}
+	}
+	if err := mem.WriteWord(address, word); err != nil {
+		return err
+	}
+	t.index[t.key(mem, address)] = len(t.words)
+	t.words = append(t.words, ImageWord{Address: address, Word: word})
+	return nil
+}
+
+// patch заменяет ранее загруженное слово после подстановки метки
+func (t *loadTracker) patch(mem *Memory, address int, word Word) error {
+	if err := mem.WriteWord(address, word); err != nil {
+		return err
+	}
+	if i, ok := t.index[t.key(mem, address)]; ok {
+		t.words[i].Word = word
+	}
+	return nil
+}
+
+// checkEntry проверяет, что по адресу точки входа загружена команда
+func (t *loadTracker) checkEntry(code *Memory, entry uint16, line int) error {
+	if t.options.Overlap == OverlapAllow {
+		return nil
+	}
+	prev, ok := t.written[t.key(code, int(entry))]
+	switch {
+	case !ok:
+		return t.report(line, "entry point 0x%X was not loaded", entry)
+	case !prev.command:
+		return t.report(line, "entry point 0x%X holds data written at line %d", entry, prev.line)
+	case prev.start != int(entry):
+		return t.report(line, "entry point 0x%X is inside the command at 0x%X written at line %d", entry, prev.start, prev.line)
+	}
+	return nil
+}
+
+// report возвращает ошибку в строгом режиме или выводит предупреждение
+func (t *loadTracker) report(line int, format string, args ...interface{}) error {
+	message := fmt.Sprintf(format, args...)
+	if t.options.Overlap == OverlapError {
+		return fmt.Errorf("%s", message)
+	}
+	if t.options.Warnings != nil {
+		fmt.Fprintf(t.options.Warnings, "warning: line %d: %s\n", line, message)
+	}
+	t.log.Logf(SubsystemLoader, LogWarn, "line %d: %s", line, message)
+	return nil
+}
@@ -0,0 +1,804 @@
+package vm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// CommandError представляет ошибку в парсинге команды
+type CommandError struct {
+	LineNumber int    // Номер строки, в которой произошла ошибка
+	Line       string // Содержимое строки, в которой произошла ошибка
+	Message    string // Сообщение об ошибке
+}
+
+// Error реализует интерфейс error для CommandError, возвращая строку с информацией об ошибке
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("Line %d: %snContent: %s", e.LineNumber, e.Message, e.Line) // Форматирует сообщение об ошибке с указанием номера строки, сообщения и содержимого строки
+}
+
+// LoadProgram загружает программу в формате загрузчика из r в память и возвращает
+// точку входа. Источником может быть файл, стандартный ввод, строка или тело HTTP-ответа.
+func LoadProgram(r io.Reader, memory *Memory) (uint16, error) {
+	return readProgram(r, memory, memory, LoadOptions{})
+}
+
+// LoadProgramFile загружает программу из текстового файла в память и возвращает точку входа
+func LoadProgramFile(filename string, memory *Memory) (uint16, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return 0, fmt.Errorf("unable to open file: %v", err)
+	}
+	defer file.Close()
+
+	return readProgram(file, memory, memory, LoadOptions{})
+}
+
+// LoadHarvardProgram загружает программу для гарвардского процессора: команды (k)
+// записываются в память команд code, а данные (i, r) — в память данных data
+func LoadHarvardProgram(filename string, code, data *Memory) (uint16, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return 0, fmt.Errorf("unable to open file: %v", err)
+	}
+	defer file.Close()
+
+	return readProgram(file, code, data, LoadOptions{})
+}
+
+// LoadProgramWithOptions загружает программу из r как LoadHarvardProgram, выполняя
+// проверки options: повторную запись адресов и наличие команды в точке входа
+func LoadProgramWithOptions(r io.Reader, code, data *Memory, options LoadOptions) (uint16, error) {
+	return readProgram(r, code, data, options)
+}
+
+// isValidOpcode проверяет, является ли опкод допустимым
+func isValidOpcode(opcode uint64) bool {
+	return opcode <= 0x4F // Возвращает true, если опкод меньше или равен 0x4F (максимально допустимый опкод)
+}
+
+// isValidBB проверяет, является ли значение BB допустимым (2 бита)
+func isValidBB(bb uint64) bool {
+	return bb <= 0x03 // Возвращает true, если значение BB меньше или равно 0x03 (максимальное значение для 2 бит)
+}
+
+// isValidAddress проверяет, является ли адрес допустимым
+func isValidAddress(addr uint64, memory *Memory) bool {
+	return int(addr) < memory.Size() // Возвращает true, если адрес меньше размера памяти (проверка на допустимость адреса)
+}
+
+// splitLoaderNumber отделяет знак и префикс системы счисления числа; без префикса
+// используется система счисления base. Префикс 0x распознается всегда, а 0b и 0o —
+// только в десятичных полях: в шестнадцатеричных "0b10" — это число 0xB10.
+func splitLoaderNumber(field string, base int) (negative bool, digits string, radix int) {
+	digits = field
+	if strings.HasPrefix(digits, "-") {
+		negative, digits = true, digits[1:]
+	} else {
+		digits = strings.TrimPrefix(digits, "+")
+	}
+	lower := strings.ToLower(digits)
+	switch {
+	case strings.HasPrefix(lower, "0x"):
+		return negative, digits[2:], 16
+	case base == 10 && strings.HasPrefix(lower, "0b"):
+		return negative, digits[2:], 2
+	case base == 10 && strings.HasPrefix(lower, "0o"):
+		return negative, digits[2:], 8
+	}
+	return negative, digits, base
+}
+
+// parseLoaderUint разбирает беззнаковое число поля загрузчика с необязательным префиксом
+func parseLoaderUint(field string, base, bitSize int) (uint64, error) {
+	negative, digits, radix := splitLoaderNumber(field, base)
+	if negative || digits == "" || strings.ContainsAny(digits[:1], "+-") {
+		return 0, fmt.Errorf("invalid unsigned number %q", field)
+	}
+	return strconv.ParseUint(digits, radix, bitSize)
+}
+
+// parseLoaderInt разбирает число со знаком поля загрузчика с необязательным префиксом
+func parseLoaderInt(field string, base, bitSize int) (int64, error) {
+	negative, digits, radix := splitLoaderNumber(field, base)
+	if digits == "" || strings.ContainsAny(digits[:1], "+-") {
+		return 0, fmt.Errorf("invalid number %q", field)
+	}
+	if negative {
+		digits = "-" + digits
+	}
+	return strconv.ParseInt(digits, radix, bitSize)
+}
+
+// isRelativeJump проверяет, является ли команда переходом с относительной адресацией
+func isRelativeJump(opcode uint64, addr2Field string, radix int) bool {
+	if !isJumpOpcode(OpCode(opcode)) {
+		return false // Относительная адресация применяется только к переходам
+	}
+	flags, err := parseLoaderUint(addr2Field, radix, 16)
+	return err == nil && flags&JUMP_RELATIVE != 0
+}
+
+// selectLoadBank выбирает банк для загрузки в памяти данных и, если она отдельная, в памяти команд
+func selectLoadBank(code, data *Memory, bank int) error {
+	if err := data.SelectBank(bank); err != nil {
+		return err
+	}
+	if code != data && code.BankCount() > 0 {
+		return code.SelectBank(bank)
+	}
+	return nil
+}
+
+// labelFixup — ссылка на метку, которая разрешается после чтения программы
+type labelFixup struct {
+	lineNumber int        // Номер строки со ссылкой
+	line       string     // Содержимое строки
+	address    int        // Адрес команды
+	bank       int        // Банк загрузки на момент записи команды
+	field      RelocField // Поле команды; 0 — точка входа (e)
+	name       string     // Имя метки
+	relative   bool       // В поле записывается смещение относительного перехода
+}
+
+// isLabelName проверяет, является ли поле именем метки. Имена, которые читаются
+// как шестнадцатеричные числа (например, "add"), остаются числами.
+func isLabelName(field string) bool {
+	if _, err := strconv.ParseUint(field, 16, 64); err == nil {
+		return false
+	}
+	return isIdentifier(field)
+}
+
+// defineLabel определяет метку name с адресом address
+func defineLabel(labels map[string]int, name string, address int) error {
+	if !isLabelName(name) {
+		return fmt.Errorf("invalid label name %q (labels must not be valid hex numbers)", name)
+	}
+	if _, ok := labels[name]; ok {
+		return fmt.Errorf("label %q is already defined", name)
+	}
+	labels[name] = address
+	return nil
+}
+
+// resolveLabels подставляет адреса меток в команды и возвращает адрес точки входа,
+// если она задана меткой, или -1
+func resolveLabels(code, data *Memory, tracker *loadTracker, labels map[string]int, fixups []labelFixup) (int, error) {
+	entry := -1
+	for _, f := range fixups {
+		target, ok := labels[f.name]
+		if !ok {
+			return 0, &CommandError{LineNumber: f.lineNumber, Line: f.line, Message: fmt.Sprintf("undefined label %q", f.name)}
+		}
+		if f.field == 0 {
+			entry = target
+			continue
+		}
+		if data.BankCount() > 0 {
+			selectLoadBank(code, data, f.bank) // Команда могла попасть в другой банк окна
+		}
+		word, err := code.PeekWord(f.address)
+		if err != nil {
+			return 0, &CommandError{LineNumber: f.lineNumber, Line: f.line, Message: err.Error()}
+		}
+		value := uint16(target)
+		if f.relative {
+			value = uint16(target - f.address) // Смещение относительно адреса команды
+		}
+		if f.field == RelocAddress1 {
+			word.Cmd.Address1 = value
+		} else {
+			word.Cmd.Address2 = value
+		}
+		if err := tracker.patch(code, f.address, word); err != nil {
+			return 0, &CommandError{LineNumber: f.lineNumber, Line: f.line, Message: fmt.Sprintf("failed to write command to memory: %v", err)}
+		}
+	}
+	return entry, nil
+}
+
+// stripLoaderComment удаляет комментарий, начинающийся с '#' вне кавычек
+func stripLoaderComment(line string) string {
+	quote := byte(0)
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0 && c == '\\':
+			i++ // Пропускаем экранированный символ
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		case quote == 0 && c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// parseCharacters разбирает операнды директивы c: строку в кавычках (каждый байт —
+// отдельное слово, без завершающего нуля) или список символов 'x' и числовых кодов
+func parseCharacters(text string) ([]int32, error) {
+	if strings.HasPrefix(text, "\"") {
+		s, err := strconv.Unquote(text)
+		if err != nil {
+			return nil, err
+		}
+		codes := make([]int32, len(s))
+		for i := 0; i < len(s); i++ {
+			codes[i] = int32(s[i])
+		}
+		return codes, nil
+	}
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("character directive requires a value")
+	}
+	codes := make([]int32, 0, len(fields))
+	for _, field := range fields {
+		if strings.HasPrefix(field, "'") {
+			s, err := strconv.Unquote(field)
+			if err != nil || len(s) != 1 {
+				return nil, fmt.Errorf("invalid character literal %s", field)
+			}
+			codes = append(codes, int32(s[0]))
+			continue
+		}
+		code, err := ParseNumber(field)
+		if err != nil {
+			return nil, err
+		}
+		if code < 0 || code > 0xFF {
+			return nil, fmt.Errorf("character code %d is out of range [0-255]", code)
+		}
+		codes = append(codes, int32(code))
+	}
+	return codes, nil
+}
+
+// parseFill разбирает директивы z count и f count value. Число слов десятичное
+// (допускаются префиксы 0x, 0b, 0o), значение — целое или вещественное число.
+func parseFill(command string, fields []string) (Word, int, error) {
+	want := 2
+	if command == "f" {
+		want = 3
+	}
+	if len(fields) < want {
+		return Word{}, 0, fmt.Errorf("%s command requires %d parameter(s)", command, want-1)
+	}
+	count, err := ParseNumber(fields[1])
+	if err != nil {
+		return Word{}, 0, fmt.Errorf("invalid count format: %v", err)
+	}
+	if count > 1<<16 {
+		return Word{}, 0, fmt.Errorf("count %d is too large", count)
+	}
+	if command == "z" {
+		return IntWord(0), int(count), nil
+	}
+	value := fields[2]
+	negative := strings.HasPrefix(value, "-")
+	if n, err := ParseNumber(strings.TrimPrefix(value, "-")); err == nil {
+		if negative {
+			n = -n
+		}
+		if n < -1<<31 || n > 1<<31-1 {
+			return Word{}, 0, fmt.Errorf("fill value %d does not fit in 32 bits", n)
+		}
+		return IntWord(int32(n)), int(count), nil
+	}
+	f, err := strconv.ParseFloat(value, 32)
+	if err != nil {
+		return Word{}, 0, fmt.Errorf("invalid fill value %q", value)
+	}
+	return FloatWord(float32(f)), int(count), nil
+}
+
+// constantEnv предоставляет выражениям директивы def ранее определенные константы
+type constantEnv map[string]int64
+
+// Identifier возвращает значение константы
+func (env constantEnv) Identifier(name string) (int64, error) {
+	if value, ok := env[name]; ok {
+		return value, nil
+	}
+	return 0, fmt.Errorf("undefined constant %q", name)
+}
+
+// Memory отклоняет чтение памяти: константы вычисляются при загрузке
+func (env constantEnv) Memory(address int64) (int64, error) {
+	return 0, fmt.Errorf("memory references are not allowed in constants")
+}
+
+// defineConstant разбирает директиву def NAME выражение. Числа в выражении десятичные,
+// если не указан префикс 0x, 0b или 0o; выражение может ссылаться на предыдущие константы.
+func defineConstant(constants map[string]int64, labels map[string]int, fields []string) error {
+	if len(fields) < 3 {
+		return fmt.Errorf("def command requires a name and a value")
+	}
+	name := fields[1]
+	if !isLabelName(name) {
+		return fmt.Errorf("invalid constant name %q (names must not be valid hex numbers)", name)
+	}
+	if _, ok := constants[name]; ok {
+		return fmt.Errorf("constant %q is already defined", name)
+	}
+	if _, ok := labels[name]; ok {
+		return fmt.Errorf("constant %q conflicts with a label", name)
+	}
+	expr, err := ParseExpr(strings.Join(fields[2:], " "))
+	if err != nil {
+		return fmt.Errorf("invalid constant value: %v", err)
+	}
+	value, err := expr.Eval(constantEnv(constants))
+	if err != nil {
+		return fmt.Errorf("invalid constant value: %v", err)
+	}
+	constants[name] = value
+	return nil
+}
+
+// substituteConstants заменяет поля-константы числами: для a, b, e и k —
+// шестнадцатеричными с префиксом 0x, для остальных директив — десятичными
+func substituteConstants(command string, fields []string, constants map[string]int64) {
+	base := 10
+	switch command {
+	case "a", "b", "e", "k":
+		base = 16
+	}
+	for i := 1; i < len(fields); i++ {
+		value, ok := constants[fields[i]]
+		switch {
+		case !ok:
+		case base == 16 && value < 0:
+			fields[i] = "-0x" + strconv.FormatInt(-value, 16) // Префикс не зависит от директивы radix
+		case base == 16:
+			fields[i] = "0x" + strconv.FormatInt(value, 16)
+		default:
+			fields[i] = strconv.FormatInt(value, 10)
+		}
+	}
+}
+
+// readProgram читает программу из r: команды загружаются в code, данные — в data
+func readProgram(r io.Reader, code, data *Memory, options LoadOptions) (uint16, error) {
+	scanner := bufio.NewScanner(r)      // Создает новый сканер для построчного чтения
+	var address int                     // Переменная для хранения текущего адреса
+	var initialIP uint16                // Переменная для хранения начального значения IP (индикатор программы)
+	var entryPointSet bool              // Флаг, указывающий, установлен ли начальный адрес
+	labels := make(map[string]int)      // Метки и их адреса
+	constants := make(map[string]int64) // Именованные константы (def)
+	radix := 16                         // Система счисления чисел без префикса в a, b, e и k
+	var fixups []labelFixup             // Ссылки на метки, разрешаемые в конце программы
+	tracker := newLoadTracker(options)  // Учет записанных слов для проверки перекрытий и контрольной суммы
+	tracker.log = code.log              // Загрузчик пишет в журнал процессора
+	var checksum int64 = -1             // Ожидаемая контрольная сумма (x) или -1
+	lineNumber := 0                     // Инициализация счетчика строк
+
+	// Чтение файла построчно
+	for scanner.Scan() {
+		lineNumber++           // Увеличиваем номер строки
+		line := scanner.Text() // Читаем текущую строку
+
+		// Удаляем встроенные комментарии
+		line = stripLoaderComment(line) // Символ # внутри кавычек комментарий не начинает
+
+		// Убираем пробелы и пропускаем пустые строки
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue // Переходим к следующей итерации цикла, если строка пустая
+		}
+
+		fields := strings.Fields(line) // Разделяем строку на поля по пробелам
+		if len(fields) < 1 {
+			continue // Пропускаем строки без команд
+		}
+
+		if name := strings.TrimSuffix(line, ":"); name != line && len(fields) == 1 { // Определение метки "name:"
+			if _, ok := constants[name]; ok {
+				return 0, &CommandError{LineNumber: lineNumber, Line: line, Message: fmt.Sprintf("label %q conflicts with a constant", name)}
+			}
+			if err := defineLabel(labels, name, address); err != nil {
+				return 0, &CommandError{LineNumber: lineNumber, Line: line, Message: err.Error()}
+			}
+			continue
+		}
+
+		command := strings.ToLower(fields[0]) // Приводим команду к нижнему регистру для нечувствительности к регистру
+		if command == "radix" {               // Система счисления адресов и полей команд: radix 10 или radix 16
+			if len(fields) != 2 || (fields[1] != "10" && fields[1] != "16") {
+				return 0, &CommandError{LineNumber: lineNumber, Line: line, Message: "radix command requires 10 or 16"}
+			}
+			radix, _ = strconv.Atoi(fields[1])
+			continue
+		}
+		if command == "def" { // Именованная константа: def NAME выражение
+			if err := defineConstant(constants, labels, fields); err != nil {
+				return 0, &CommandError{LineNumber: lineNumber, Line: line, Message: err.Error()}
+			}
+			continue
+		}
+		substituteConstants(command, fields, constants) // Константы заменяются числами в системе счисления поля
+		switch command {
+		case "a": // Обработка команды установки адреса
+			if len(fields) < 2 {
+				return 0, &CommandError{ // Если не указано значение адреса, возвращаем ошибку
+					LineNumber: lineNumber,
+					Line:       line,
+					Message:    "address command requires a value",
+				}
+			}
+			addr, err := parseLoaderInt(fields[1], radix, 32) // Парсим значение адреса (по умолчанию шестнадцатеричное)
+			if err != nil {
+				return 0, &CommandError{ // Если произошла ошибка парсинга, возвращаем ошибку
+					LineNumber: lineNumber,
+					Line:       line,
+					Message:    fmt.Sprintf("invalid address format: %v", err),
+				}
+			}
+			if !data.IsValidAddress(int(addr)) { // Проверяем, является ли адрес допустимым в пределах памяти
+				return 0, &CommandError{ // Если адрес вне допустимого диапазона, возвращаем ошибку
+					LineNumber: lineNumber,
+					Line:       line,
+					Message:    fmt.Sprintf("address 0x%X is out of valid range [0-%d]", addr, data.Size()-1),
+				}
+			}
+			address = int(addr) // Устанавливаем текущий адрес
+		case "b": // Выбор банка, в который загружаются следующие слова окна банков
+			if len(fields) < 2 {
+				return 0, &CommandError{
+					LineNumber: lineNumber,
+					Line:       line,
+					Message:    "bank command requires a value",
+				}
+			}
+			bank, err := parseLoaderInt(fields[1], radix, 32) // Номер банка в той же системе счисления, что и адреса
+			if err != nil {
+				return 0, &CommandError{
+					LineNumber: lineNumber,
+					Line:       line,
+					Message:    fmt.Sprintf("invalid bank format: %v", err),
+				}
+			}
+			if err := selectLoadBank(code, data, int(bank)); err != nil {
+				return 0, &CommandError{
+					LineNumber: lineNumber,
+					Line:       line,
+					Message:    err.Error(),
+				}
+			}
+		case "e": // Устанавливаем начальный IP (индикатор программы)
+			if len(fields) < 2 { // Проверяем, указано ли значение для начального IP
+				return 0, &CommandError{ // Если нет, возвращаем ошибку
+					LineNumber: lineNumber,                             // Номер строки с ошибкой
+					Line:       line,                                   // Содержимое строки
+					Message:    "entry point command requires a value", // Сообщение об ошибке
+				}
+			}
+			if isLabelName(fields[1]) { // Точка входа задана меткой
+				fixups = append(fixups, labelFixup{lineNumber: lineNumber, line: line, name: fields[1]})
+				entryPointSet = true
+				continue
+			}
+			ip, err := parseLoaderInt(fields[1], radix, 16) // Парсим значение начального IP (по умолчанию шестнадцатеричное)
+			if err != nil {                                 // Проверяем, произошла ли ошибка при парсинге
+				return 0, &CommandError{ // Если да, возвращаем ошибку
+					LineNumber: lineNumber,                                        // Номер строки с ошибкой
+					Line:       line,                                              // Содержимое строки
+					Message:    fmt.Sprintf("invalid initial IP format: %v", err), // Сообщение об ошибке
+				}
+			}
+			if !code.IsValidAddress(int(ip)) { // Проверяем, является ли адрес начального IP допустимым в пределах памяти
+				return 0, &CommandError{ // Если нет, возвращаем ошибку
+					LineNumber: lineNumber,                                                                      // Номер строки с ошибкой
+					Line:       line,                                                                            // Содержимое строки
+					Message:    fmt.Sprintf("entry point 0x%X is out of valid range [0-%d]", ip, code.Size()-1), // Сообщение об ошибке с указанием диапазона
+				}
+			}
+			initialIP = uint16(ip) // Устанавливаем начальный IP как значение переменной initialIP
+			entryPointSet = true   // Устанавливаем флаг entryPointSet в true, указывая, что начальный IP установлен
+
+		case "i": // Обработка команды установки целочисленного значения
+			if len(fields) < 2 { // Проверяем, указано ли значение для целочисленной команды
+				return 0, &CommandError{ // Если нет, возвращаем ошибку
+					LineNumber: lineNumber,                         // Номер строки с ошибкой
+					Line:       line,                               // Содержимое строки
+					Message:    "integer command requires a value", // Сообщение об ошибке
+				}
+			}
+			value, err := parseLoaderInt(fields[1], 10, 64) // Парсим значение как целое число (по умолчанию десятичное)
+			if err == nil && (value < -1<<31 || value > 1<<32-1) {
+				err = fmt.Errorf("value %s does not fit in 32 bits", fields[1]) // Беззнаковые значения вроде 0xFFFFFFFF допустимы
+			}
+			if err != nil { // Проверяем, произошла ли ошибка при парсинге
+				return 0, &CommandError{ // Если да, возвращаем ошибку
+					LineNumber: lineNumber,                                     // Номер строки с ошибкой
+					Line:       line,                                           // Содержимое строки
+					Message:    fmt.Sprintf("invalid integer format: %v", err), // Сообщение об ошибке
+				}
+			}
+			word := IntWord(int32(value))                                          // Создаем объект Word с целочисленным значением
+			if err := tracker.write(data, address, word, lineNumber); err != nil { // Пытаемся записать слово в память по текущему адресу
+				return 0, &CommandError{ // Если произошла ошибка записи, возвращаем ошибку
+					LineNumber: lineNumber,                                                // Номер строки с ошибкой
+					Line:       line,                                                      // Содержимое строки
+					Message:    fmt.Sprintf("failed to write integer to memory: %v", err), // Сообщение об ошибке
+				}
+			}
+			address += WORD_SIZE // Переходим к следующему слову памяти
+		case "r": // Обработка команды для записи значения с плавающей запятой
+			if len(fields) < 2 { // Проверяем, указано ли значение для команды с плавающей запятой
+				return 0, &CommandError{ // Если значение отсутствует, возвращаем ошибку
+					LineNumber: lineNumber,                       // Номер строки с ошибкой
+					Line:       line,                             // Содержимое строки
+					Message:    "float command requires a value", // Сообщение об ошибке
+				}
+			}
+			value, err := strconv.ParseFloat(fields[1], 32) // Парсим значение как число с плавающей запятой (32 бита)
+			if err != nil {                                 // Проверяем, произошла ли ошибка при парсинге
+				return 0, &CommandError{ // Если ошибка есть, возвращаем её
+					LineNumber: lineNumber,                                   // Номер строки с ошибкой
+					Line:       line,                                         // Содержимое строки
+					Message:    fmt.Sprintf("invalid float format: %v", err), // Сообщение об ошибке с описанием проблемы
+				}
+			}
+			word := FloatWord(float32(value))                                      // Создаем объект Word с плавающим значением, преобразованным в float32
+			if err := tracker.write(data, address, word, lineNumber); err != nil { // Пытаемся записать слово в память по текущему адресу
+				return 0, &CommandError{ // Если произошла ошибка записи, возвращаем её
+					LineNumber: lineNumber,                                              // Номер строки с ошибкой
+					Line:       line,                                                    // Содержимое строки
+					Message:    fmt.Sprintf("failed to write float to memory: %v", err), // Сообщение об ошибке с описанием проблемы
+				}
+			}
+			address += WORD_SIZE // Переходим к следующему слову памяти
+		case "c": // Символы по одному в слово: строка в кавычках, 'x' или коды символов
+			text := strings.TrimSpace(line[1:])
+			if !strings.HasPrefix(text, "\"") {
+				text = strings.Join(fields[1:], " ") // Коды символов могут быть константами
+			}
+			codes, err := parseCharacters(text)
+			if err != nil {
+				return 0, &CommandError{
+					LineNumber: lineNumber,
+					Line:       line,
+					Message:    fmt.Sprintf("invalid character format: %v", err),
+				}
+			}
+			for _, code := range codes {
+				if err := tracker.write(data, address, IntWord(code), lineNumber); err != nil {
+					return 0, &CommandError{
+						LineNumber: lineNumber,
+						Line:       line,
+						Message:    fmt.Sprintf("failed to write character to memory: %v", err),
+					}
+				}
+				address += WORD_SIZE // Переходим к следующему слову памяти
+			}
+		case "z", "f": // Заполнение count слов нулями (z count) или значением (f count value)
+			word, count, err := parseFill(command, fields)
+			if err == nil && (count < 0 || address+count*WORD_SIZE > data.Size()) {
+				err = fmt.Errorf("%d word(s) at 0x%X exceed memory size %d", count, address, data.Size())
+			}
+			if err != nil {
+				return 0, &CommandError{
+					LineNumber: lineNumber,
+					Line:       line,
+					Message:    err.Error(),
+				}
+			}
+			for i := 0; i < count; i++ {
+				if err := tracker.write(data, address, word, lineNumber); err != nil {
+					return 0, &CommandError{
+						LineNumber: lineNumber,
+						Line:       line,
+						Message:    fmt.Sprintf("failed to fill memory: %v", err),
+					}
+				}
+				address += WORD_SIZE // Переходим к следующему слову памяти
+			}
+		case "t": // Строка в кавычках, упакованная по CHARS_PER_WORD символов в слово
+			text, err := strconv.Unquote(strings.TrimSpace(line[1:])) // Кавычки позволяют использовать пробелы и escape-последовательности
+			if err != nil {
+				return 0, &CommandError{
+					LineNumber: lineNumber,
+					Line:       line,
+					Message:    fmt.Sprintf("invalid string format: %v", err),
+				}
+			}
+			for _, word := range PackString(text) {
+				if err := tracker.write(data, address, word, lineNumber); err != nil {
+					return 0, &CommandError{
+						LineNumber: lineNumber,
+						Line:       line,
+						Message:    fmt.Sprintf("failed to write string to memory: %v", err),
+					}
+				}
+				address += WORD_SIZE // Переходим к следующему слову памяти
+			}
+		case "k": // Обработка команды "k"
+			if len(fields) < 5 { // Проверяем, достаточно ли параметров (минимум 4 параметра)
+				return 0, &CommandError{ // Если параметров недостаточно, возвращаем ошибку
+					LineNumber: lineNumber,                                                                                     // Номер строки с ошибкой
+					Line:       line,                                                                                           // Содержимое строки
+					Message:    fmt.Sprintf("command requires 4 parameters (opcode, bb, addr1, addr2), got %d", len(fields)-1), // Сообщение об ошибке с количеством переданных параметров
+				}
+			}
+
+			// Парсинг операционного кода (opcode)
+			opcode, err := parseLoaderUint(fields[1], radix, 8) // Преобразуем второй параметр (по умолчанию шестнадцатеричный) в 8-битное целое число
+			if err != nil {                                     // Проверяем, произошла ли ошибка при парсинге
+				return 0, &CommandError{ // Если ошибка есть, возвращаем её
+					LineNumber: lineNumber,                                    // Номер строки с ошибкой
+					Line:       line,                                          // Содержимое строки
+					Message:    fmt.Sprintf("invalid opcode format: %v", err), // Сообщение об ошибке с описанием проблемы
+				}
+			}
+
+			if !isValidOpcode(opcode) { // Проверяем, является ли код операции допустимым
+				return 0, &CommandError{ // Если код недопустим, возвращаем ошибку
+					LineNumber: lineNumber,                                                                 // Номер строки с ошибкой
+					Line:       line,                                                                       // Содержимое строки
+					Message:    fmt.Sprintf("opcode value 0x%X is out of valid range [0x00-0x4F]", opcode), // Сообщение об ошибке с диапазоном допустимых значений
+				}
+			}
+
+			// Парсинг значения BB
+			bb, err := parseLoaderUint(fields[2], radix, 8) // Преобразуем третий параметр (по умолчанию шестнадцатеричный) в 8-битное целое число
+			if err != nil {                                 // Проверяем, произошла ли ошибка при парсинге
+				return 0, &CommandError{ // Если ошибка есть, возвращаем её
+					LineNumber: lineNumber,                                // Номер строки с ошибкой
+					Line:       line,                                      // Содержимое строки
+					Message:    fmt.Sprintf("invalid bb format: %v", err), // Сообщение об ошибке с описанием проблемы
+				}
+			}
+
+			if !isValidBB(bb) { // Проверяем, является ли значение BB допустимым
+				return 0, &CommandError{ // Если значение недопустимо, возвращаем ошибку
+					LineNumber: lineNumber,                                                       // Номер строки с ошибкой
+					Line:       line,                                                             // Содержимое строки
+					Message:    fmt.Sprintf("BB value 0x%X exceeds 2-bit range [0x00-0x03]", bb), // Сообщение об ошибке с диапазоном допустимых значений
+				}
+			}
+
+			// Относительный переход: addr1 содержит знаковое смещение от адреса команды
+			var addr1 uint64
+			fixup := labelFixup{lineNumber: lineNumber, line: line, address: address, bank: data.Bank()}
+			if isLabelName(fields[3]) { // Адрес метки подставляется после чтения программы
+				fixup.field, fixup.name = RelocAddress1, fields[3]
+				fixup.relative = isRelativeJump(opcode, fields[4], radix)
+				fixups = append(fixups, fixup)
+			} else if isRelativeJump(opcode, fields[4], radix) {
+				offset, err := parseLoaderInt(fields[3], radix, 32) // Преобразуем смещение со знаком (по умолчанию шестнадцатеричное)
+				if err != nil {
+					return 0, &CommandError{
+						LineNumber: lineNumber,
+						Line:       line,
+						Message:    fmt.Sprintf("invalid relative offset format: %v", err),
+					}
+				}
+				limit := int64(1) << (ADDRESS1_BITS - 1)
+				if offset < -limit || offset >= limit {
+					return 0, &CommandError{
+						LineNumber: lineNumber,
+						Line:       line,
+						Message:    fmt.Sprintf("relative offset %d is out of valid range [%d-%d]", offset, -limit, limit-1),
+					}
+				}
+				addr1 = uint64(offset) & (1<<ADDRESS1_BITS - 1) // Кодируем смещение в дополнительном коде
+			} else {
+				// Парсинг адресов
+				addr1, err = parseLoaderUint(fields[3], radix, 16) // Преобразуем четвертый параметр (по умолчанию шестнадцатеричный) в 16-битное целое число
+				if err != nil {                                    // Проверяем, произошла ли ошибка при парсинге
+					return 0, &CommandError{ // Если ошибка есть, возвращаем её
+						LineNumber: lineNumber,                                   // Номер строки с ошибкой
+						Line:       line,                                         // Содержимое строки
+						Message:    fmt.Sprintf("invalid addr1 format: %v", err), // Сообщение об ошибке с описанием проблемы
+					}
+				}
+			}
+
+			if !isValidAddress(addr1, data) { // Проверяем, является ли адрес addr1 допустимым в пределах памяти
+				return 0, &CommandError{ // Если адрес недопустим, возвращаем ошибку
+					LineNumber: lineNumber,                                                                   // Номер строки с ошибкой
+					Line:       line,                                                                         // Содержимое строки
+					Message:    fmt.Sprintf("addr1 0x%X is out of valid range [0-%d]", addr1, data.Size()-1), // Сообщение об ошибке с диапазоном допустимых значений
+				}
+			}
+
+			var addr2 uint64
+			if isLabelName(fields[4]) {
+				fixup.field, fixup.name, fixup.relative = RelocAddress2, fields[4], false
+				fixups = append(fixups, fixup)
+			} else if addr2, err = parseLoaderUint(fields[4], radix, 16); err != nil { // Преобразуем пятый параметр (по умолчанию шестнадцатеричный) в 16-битное целое число
+				return 0, &CommandError{ // Если ошибка есть, возвращаем её
+					LineNumber: lineNumber,                                   // Номер строки с ошибкой
+					Line:       line,                                         // Содержимое строки
+					Message:    fmt.Sprintf("invalid addr2 format: %v", err), // Сообщение об ошибке с описанием проблемы
+				}
+			}
+
+			if !isValidAddress(addr2, data) { // Проверяем, является ли адрес addr2 допустимым в пределах памяти
+				return 0, &CommandError{ // Если адрес недопустим, возвращаем ошибку
+					LineNumber: lineNumber,                                                                   // Номер строки с ошибкой
+					Line:       line,                                                                         // Содержимое строки
+					Message:    fmt.Sprintf("addr2 0x%X is out of valid range [0-%d]", addr2, data.Size()-1), // Сообщение об ошибке с диапазоном допустимых значений
+				}
+			}
+
+			word := CommandWord(CommandData{ // Создаем слово команды для записи в память
+				Opcode:   uint8(opcode), // Устанавливаем код операции как uint8
+				BB:       uint8(bb),     // Устанавливаем значение BB как uint8
+				Address1: uint16(addr1), // Устанавливаем первый адрес как uint16
+				Address2: uint16(addr2), // Устанавливаем второй адрес как uint16
+			})
+
+			if err := tracker.write(code, address, word, lineNumber); err != nil { // Пытаемся записать слово в память по текущему адресу
+				return 0, &CommandError{ // Если произошла ошибка записи, возвращаем её
+					LineNumber: lineNumber,                                                // Номер строки с ошибкой
+					Line:       line,                                                      // Содержимое строки
+					Message:    fmt.Sprintf("failed to write command to memory: %v", err), // Сообщение об ошибке с описанием проблемы записи в память
+				}
+			}
+			address += WORD_SIZE // Переходим к следующему слову памяти
+		case "x": // Контрольная сумма CRC-32 всех загруженных слов, проверяется по команде s
+			if len(fields) != 2 {
+				return 0, &CommandError{LineNumber: lineNumber, Line: line, Message: "checksum command requires a value"}
+			}
+			if checksum >= 0 {
+				return 0, &CommandError{LineNumber: lineNumber, Line: line, Message: "checksum is already set"}
+			}
+			value, err := parseLoaderUint(fields[1], 16, 32)
+			if err != nil {
+				return 0, &CommandError{LineNumber: lineNumber, Line: line, Message: fmt.Sprintf("invalid checksum format: %v", err)}
+			}
+			checksum = int64(value)
+		case "s": // Обработка команды "s", которая обозначает конец программы
+			if !entryPointSet {
+				return 0, &CommandError{
+					LineNumber: lineNumber,
+					Line:       line,
+					Message:    "program ended without setting entry point (e command)",
+				}
+			}
+			ip, err := resolveLabels(code, data, tracker, labels, fixups)
+			if err != nil {
+				return 0, err
+			}
+			if ip >= 0 {
+				initialIP = uint16(ip) // Точка входа задана меткой
+			}
+			if data.BankCount() > 0 {
+				selectLoadBank(code, data, 0) // Программа начинает работу с банком 0
+			}
+			if sum := Checksum(tracker.words); checksum >= 0 && uint32(checksum) != sum {
+				return 0, &CommandError{LineNumber: lineNumber, Line: line, Message: fmt.Sprintf("checksum mismatch: expected 0x%08X, computed 0x%08X", checksum, sum)}
+			}
+			if err := tracker.checkEntry(code, initialIP, lineNumber); err != nil {
+				return 0, &CommandError{LineNumber: lineNumber, Line: line, Message: err.Error()}
+			}
+			code.log.Logf(SubsystemLoader, LogInfo, "Loaded program: %d words, %d labels, entry 0x%X", len(tracker.words), len(labels), initialIP)
+			return initialIP, nil
+
+		default:
+			return 0, &CommandError{
+				LineNumber: lineNumber,
+				Line:       line,
+				Message:    fmt.Sprintf("unknown command type: %s", fields[0]),
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("error reading file: %v", err)
+	}
+
+	return 0, &CommandError{
+		LineNumber: lineNumber,
+		Line:       "",
+		Message:    "program file ended without 's' command",
+	}
+}
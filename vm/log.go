@@ -0,0 +1,415 @@
+package vm
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogLevel — уровень важности сообщения журнала
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota // Подробности каждой команды и обращения
+	LogInfo                  // Запуск, остановка, подключение устройств
+	LogWarn                  // Потерянные прерывания, пропущенные команды
+	LogError                 // Ошибки, остановившие программу
+	LogOff                   // Журнал подсистемы выключен
+)
+
+// Subsystem — подсистема, сообщения которой фильтруются отдельно
+type Subsystem int
+
+const (
+	SubsystemProcessor Subsystem = iota // Выполнение команд, прерывания, точки останова
+	SubsystemMemory                     // Регионы, банки, отклоненные обращения
+	SubsystemLoader                     // Загрузка программ
+	SubsystemDevices                    // Устройства ввода-вывода и таймеры
+	numSubsystems
+)
+
+// Уровень журнала по умолчанию: подробности команд выключены
+const DefaultLogLevel = LogInfo
+
+// Размер очереди асинхронного журнала; при заполнении очереди запись ждет
+const LOG_QUEUE_SIZE = 4096
+
+// LogFormat — формат строк журнала
+type LogFormat int
+
+const (
+	LogText LogFormat = iota // Строки для чтения человеком (по умолчанию)
+	LogJSON                  // Один объект JSON на строку для jq и систем сбора журналов
+)
+
+// logEntry — событие журнала в формате LogJSON
+type logEntry struct {
+	Time      string `json:"time"`
+	Level     string `json:"level"`
+	Subsystem string `json:"subsystem"`
+	IP        *int   `json:"ip,omitempty"`     // Адрес выполняемой команды или текущий IP
+	Opcode    string `json:"opcode,omitempty"` // Мнемоника выполняемой команды
+	Message   string `json:"message"`
+	Fields    []any  `json:"fields,omitempty"` // Значения, подставленные в сообщение
+}
+
+var logLevelNames = [...]string{"debug", "info", "warn", "error", "off"}
+
+var subsystemNames = [numSubsystems]string{"processor", "memory", "loader", "devices"}
+
+func (l LogLevel) String() string {
+	if l < LogDebug || l > LogOff {
+		return fmt.Sprintf("LogLevel(%d)", int(l))
+	}
+	return logLevelNames[l]
+}
+
+func (s Subsystem) String() string {
+	if s < 0 || s >= numSubsystems {
+		return fmt.Sprintf("Subsystem(%d)", int(s))
+	}
+	return subsystemNames[s]
+}
+
+// ParseLogLevel разбирает имя уровня: debug, info, warn, error или off
+func ParseLogLevel(name string) (LogLevel, error) {
+	for i, n := range logLevelNames {
+		if strings.EqualFold(name, n) {
+			return LogLevel(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q: use debug, info, warn, error or off", name)
+}
+
+// ParseSubsystem разбирает имя подсистемы: processor, memory, loader или devices
+func ParseSubsystem(name string) (Subsystem, error) {
+	for i, n := range subsystemNames {
+		if strings.EqualFold(name, n) {
+			return Subsystem(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log subsystem %q: use processor, memory, loader or devices", name)
+}
+
+// Logger — журнал с уровнями, настраиваемыми по подсистемам. Сообщения уровня
+// LogError пишутся в журнал ошибок с префиксом "ERROR: ", остальные — в журнал
+// выполнения с уровнем и подсистемой; в формате LogJSON те же журналы получают по
+// объекту JSON на событие. Методы nil-журнала ничего не делают.
+type Logger struct {
+	levels    [numSubsystems]LogLevel
+	out       *log.Logger // Журнал выполнения
+	errors    *log.Logger // Журнал ошибок
+	format    LogFormat
+	rawOut    io.Writer                      // Получатель строк JSON журнала выполнения
+	rawErrors io.Writer                      // Получатель строк JSON журнала ошибок
+	context   func() (ip int, opcode string) // Состояние процессора для событий JSON; nil — не выводится
+	accept    func() bool                    // Фильтр отладочных сообщений; nil — пропускаются все
+	mu        sync.Mutex                     // Сериализует строки JSON
+	queue     chan logRecord                 // Очередь асинхронной записи; nil — строки пишутся сразу
+	stopped   chan struct{}                  // Закрывается, когда фоновая горутина записала очередь
+}
+
+// logRecord — строка журнала, ожидающая записи фоновой горутиной
+type logRecord struct {
+	w       *logWriter
+	data    []byte
+	flushed chan struct{} // Маркер Flush: закрывается, когда предыдущие строки записаны
+}
+
+// logWriter направляет строки журнала в w через очередь журнала
+type logWriter struct {
+	l   *Logger
+	w   io.Writer
+	buf *bufio.Writer // Буфер фоновой горутины
+}
+
+func (lw *logWriter) Write(p []byte) (int, error) {
+	lw.l.write(lw, p)
+	return len(p), nil
+}
+
+// NewLogger создает синхронный журнал, пишущий сообщения в out, а ошибки — в errors.
+// Все подсистемы получают уровень DefaultLogLevel. nil-получатель отбрасывает сообщения.
+func NewLogger(out, errors io.Writer) *Logger {
+	l := &Logger{}
+	if out != nil {
+		l.rawOut = &logWriter{l: l, w: out, buf: bufio.NewWriter(out)}
+		l.out = log.New(l.rawOut, "", log.LstdFlags)
+	}
+	if errors != nil {
+		l.rawErrors = &logWriter{l: l, w: errors, buf: bufio.NewWriter(errors)}
+		l.errors = log.New(l.rawErrors, "ERROR: ", log.LstdFlags)
+	}
+	l.SetAllLevels(DefaultLogLevel)
+	return l
+}
+
+// fork создает журнал с теми же уровнями и форматом, который пишет в те же
+// получатели через очередь l и начинает текстовые строки с prefix
+func (l *Logger) fork(prefix string) *Logger {
+	f := &Logger{levels: l.levels, format: l.format, rawOut: l.rawOut, rawErrors: l.rawErrors}
+	if l.out != nil {
+		f.out = log.New(l.rawOut, prefix, log.LstdFlags|log.Lmsgprefix)
+	}
+	if l.errors != nil {
+		f.errors = log.New(l.rawErrors, "ERROR: "+prefix, log.LstdFlags)
+	}
+	return f
+}
+
+// SetAsync включает или выключает асинхронную запись. В асинхронном режиме строки
+// форматируются в момент события, а в файлы их пишет фоновая горутина, поэтому
+// запись не замедляет выполнение команд. Синхронный режим пишет строку до возврата
+// из вызова и удобен, когда важен порядок строк журнала относительно другого вывода.
+// Режим переключается, пока процессор не выполняет программу.
+func (l *Logger) SetAsync(enabled bool) {
+	if l == nil || enabled == (l.queue != nil) {
+		return
+	}
+	if !enabled {
+		l.Close()
+		return
+	}
+	l.queue = make(chan logRecord, LOG_QUEUE_SIZE)
+	l.stopped = make(chan struct{})
+	go l.drain(l.queue, l.stopped)
+}
+
+// Async сообщает, включена ли асинхронная запись
+func (l *Logger) Async() bool {
+	return l != nil && l.queue != nil
+}
+
+// drain записывает строки из очереди, пока очередь не закрыта. Строки копятся в
+// буферах и сбрасываются в файлы, когда очередь опустела или получен маркер Flush.
+func (l *Logger) drain(queue <-chan logRecord, stopped chan<- struct{}) {
+	flush := func() {
+		for _, w := range []io.Writer{l.rawOut, l.rawErrors} {
+			if lw, ok := w.(*logWriter); ok {
+				lw.buf.Flush()
+			}
+		}
+	}
+	for r := range queue {
+		if r.w != nil {
+			r.w.buf.Write(r.data)
+		}
+		if r.flushed != nil || len(queue) == 0 {
+			flush()
+		}
+		if r.flushed != nil {
+			close(r.flushed)
+		}
+	}
+	flush()
+	close(stopped)
+}
+
+// write записывает строку сразу или ставит ее копию в очередь
+func (l *Logger) write(w *logWriter, p []byte) {
+	if l.queue == nil {
+		w.w.Write(p)
+		return
+	}
+	l.queue <- logRecord{w: w, data: append([]byte(nil), p...)}
+}
+
+// Flush ждет, пока фоновая горутина запишет все поставленные в очередь строки
+func (l *Logger) Flush() {
+	if l == nil || l.queue == nil {
+		return
+	}
+	flushed := make(chan struct{})
+	l.queue <- logRecord{flushed: flushed}
+	<-flushed
+}
+
+// Close записывает очередь и останавливает фоновую горутину; после Close журнал
+// пишет синхронно
+func (l *Logger) Close() {
+	if l == nil || l.queue == nil {
+		return
+	}
+	close(l.queue)
+	<-l.stopped
+	l.queue = nil
+}
+
+// SetLevel задает наименьший записываемый уровень подсистемы
+func (l *Logger) SetLevel(s Subsystem, level LogLevel) {
+	if l != nil && s >= 0 && s < numSubsystems {
+		l.levels[s] = level
+	}
+}
+
+// SetAllLevels задает уровень всех подсистем
+func (l *Logger) SetAllLevels(level LogLevel) {
+	for s := range numSubsystems {
+		l.SetLevel(s, level)
+	}
+}
+
+// Level возвращает уровень подсистемы
+func (l *Logger) Level(s Subsystem) LogLevel {
+	if l == nil || s < 0 || s >= numSubsystems {
+		return LogOff
+	}
+	return l.levels[s]
+}
+
+// Enabled сообщает, будет ли записано сообщение уровня level подсистемы s. Вызывающий
+// код проверяет его, чтобы не форматировать отброшенные сообщения.
+func (l *Logger) Enabled(s Subsystem, level LogLevel) bool {
+	if level >= LogOff || level < l.Level(s) {
+		return false
+	}
+	if level >= LogError {
+		return l.rawErrors != nil
+	}
+	return l.rawOut != nil // Журнал без получателя не тратит время на форматирование
+}
+
+// Logf записывает сообщение, если уровень подсистемы это позволяет
+func (l *Logger) Logf(s Subsystem, level LogLevel, format string, args ...any) {
+	if !l.Enabled(s, level) {
+		return
+	}
+	if level < LogInfo && l.accept != nil && !l.accept() {
+		return // Сообщение о команде, не прошедшей фильтр трассы
+	}
+	if l.format == LogJSON {
+		l.writeJSON(s, level, format, args)
+		return
+	}
+	if level >= LogError {
+		if l.errors != nil {
+			l.errors.Printf(format, args...)
+		}
+		return
+	}
+	if l.out != nil {
+		l.out.Printf("%-5s %s: %s", strings.ToUpper(level.String()), s, fmt.Sprintf(format, args...))
+	}
+}
+
+// SetFormat задает формат строк журнала
+func (l *Logger) SetFormat(format LogFormat) {
+	if l != nil {
+		l.format = format
+	}
+}
+
+// Format возвращает формат строк журнала
+func (l *Logger) Format() LogFormat {
+	if l == nil {
+		return LogText
+	}
+	return l.format
+}
+
+// ParseLogFormat разбирает имя формата: text или json
+func ParseLogFormat(name string) (LogFormat, error) {
+	switch strings.ToLower(name) {
+	case "text":
+		return LogText, nil
+	case "json":
+		return LogJSON, nil
+	}
+	return 0, fmt.Errorf("unknown log format %q: use text or json", name)
+}
+
+// writeJSON записывает событие одной строкой JSON: ошибки — в журнал ошибок,
+// остальные уровни — в журнал выполнения
+func (l *Logger) writeJSON(s Subsystem, level LogLevel, format string, args []any) {
+	w := l.rawOut
+	if level >= LogError {
+		w = l.rawErrors
+	}
+	if w == nil {
+		return
+	}
+	e := logEntry{
+		Time:      time.Now().Format(time.RFC3339Nano),
+		Level:     level.String(),
+		Subsystem: s.String(),
+		Message:   fmt.Sprintf(format, args...),
+	}
+	for _, arg := range args {
+		if err, ok := arg.(error); ok {
+			arg = err.Error() // Ошибки сериализуются текстом, а не пустым объектом
+		}
+		e.Fields = append(e.Fields, arg)
+	}
+	if l.context != nil {
+		ip, opcode := l.context()
+		e.IP, e.Opcode = &ip, opcode
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		e.Fields = nil // Значение, которое нельзя сериализовать, остается только в тексте сообщения
+		line, _ = json.Marshal(e)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	w.Write(append(line, '\n'))
+}
+
+// Configure применяет спецификацию уровней вида "info,memory=debug,devices=off":
+// элемент без имени подсистемы задает уровень всех подсистем, элементы применяются
+// по порядку
+func (l *Logger) Configure(spec string) error {
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, levelName, found := strings.Cut(item, "=")
+		if !found {
+			level, err := ParseLogLevel(item)
+			if err != nil {
+				return err
+			}
+			l.SetAllLevels(level)
+			continue
+		}
+		s, err := ParseSubsystem(strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+		level, err := ParseLogLevel(strings.TrimSpace(levelName))
+		if err != nil {
+			return err
+		}
+		l.SetLevel(s, level)
+	}
+	return nil
+}
+
+// Logger возвращает журнал процессора; он же используется памятью процессора
+func (p *Processor) Logger() *Logger {
+	return p.log
+}
+
+// SetLogLevel задает уровень журнала подсистемы s
+func (p *Processor) SetLogLevel(s Subsystem, level LogLevel) {
+	p.log.SetLevel(s, level)
+}
+
+// logContext возвращает адрес и мнемонику выполняемой команды для событий JSON; вне
+// выполнения команды — текущий IP без мнемоники
+func (p *Processor) logContext() (ip int, opcode string) {
+	if p.current < 0 {
+		return int(p.psw.IP), ""
+	}
+	return p.current, OpCode(p.fetched.Opcode).String()
+}
+
+// logf записывает сообщение подсистемы процессора
+func (p *Processor) logf(level LogLevel, format string, args ...any) {
+	p.log.Logf(SubsystemProcessor, level, format, args...)
+}
@@ -0,0 +1,347 @@
+package vm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Размер слова памяти в байтах
+const WORD_SIZE = 8 // Константа, определяющая количество байт в одном слове
+
+// Разрядность поля Address1 в закодированной команде
+const ADDRESS1_BITS = 16 // Константа, определяющая количество бит первого адреса команды
+
+// Формат 64-битного слова команды (младшие биты справа):
+//
+//	биты 0-15  — Address2
+//	биты 16-31 — Address1
+//	биты 32-39 — код операции
+//	биты 40-41 — BB
+//	бит 63     — тег команды
+//
+// Слово данных хранит 32-битное значение в младших четырех байтах; бит 62
+// отмечает вещественное число. Теги позволяют отличить данные от команд
+// независимо от значения, поэтому отрицательные числа и STOP сохраняются без искажений.
+const (
+	cmdAddress1Shift = 16      // Сдвиг поля Address1
+	cmdOpcodeShift   = 32      // Сдвиг поля кода операции
+	cmdBBShift       = 40      // Сдвиг поля BB
+	tagFloat         = 1 << 62 // Тег вещественного числа
+	tagCommand       = 1 << 63 // Тег команды
+)
+
+// Memory представляет память виртуальной машины
+type Memory struct {
+	data        []byte                                   // Массив байтов для хранения данных памяти
+	size        int                                      // Размер памяти в байтах
+	stats       MemoryStats                              // Счетчики обращений и ошибок
+	initialized bool                                     // Флаг, указывающий, инициализирована ли память
+	regions     []*Region                                // Таблица регионов физической карты памяти
+	ram         *Region                                  // Основной регион RAM, созданный вместе с памятью
+	strictAlign bool                                     // Запрещает невыровненный доступ к словам
+	protections []*Protection                            // Таблица диапазонов с правами доступа
+	userMode    bool                                     // Процессор работает в режиме пользователя: устройства недоступны
+	mmu         *MMU                                     // Трансляция виртуальных адресов; nil — адреса физические
+	segment     Segment                                  // Сегмент данных; нулевой предел — сегментация выключена
+	journal     func(m *Memory, address int, old []byte) // Получатель прежних байтов при записи (история StepBack)
+	heatmap     *Heatmap                                 // Счетчики обращений для тепловой карты; nil — не считаются
+	cache       *Cache                                   // Модель кэша данных; nil — выключена
+	log         *Logger                                  // Журнал процессора; nil — сообщения не пишутся
+	banks       [][]byte                                 // Хранилища переключаемых банков
+	bankWindow  *Region                                  // Окно, через которое виден выбранный банк
+	bank        int                                      // Номер выбранного банка
+}
+
+// NewMemory создает новый экземпляр Memory с заданным размером
+func NewMemory(size int) *Memory {
+	// Проверяем, является ли размер памяти допустимым (больше 0)
+	if size <= 0 {
+		panic("attempted to create memory with invalid size") // Вызываем панику при недопустимом размере
+	}
+	m := &Memory{
+		data:        make([]byte, size), // Инициализируем массив байтов заданного размера
+		size:        size,               // Устанавливаем размер памяти
+		initialized: true,               // Устанавливаем флаг инициализации в true
+	}
+	// Основная RAM занимает все адресное пространство и может быть перекрыта другими регионами
+	m.ram = &Region{Name: "ram", Kind: RegionRAM, data: m.data, size: size, attached: true}
+	m.regions = []*Region{m.ram}
+	return m
+}
+
+// Size возвращает размер памяти в байтах
+func (m *Memory) Size() int {
+	return m.size // Возвращаем размер памяти
+}
+
+// IsValidAddress проверяет, находится ли адрес в пределах допустимого диапазона
+func (m *Memory) IsValidAddress(address int) bool {
+	return address >= 0 && address < m.size // Проверяем, что адрес не отрицательный и меньше размера памяти
+}
+
+// WordLimit возвращает адрес, следующий за последним адресом, по которому помещается целое слово
+func (m *Memory) WordLimit() int {
+	return m.size - WORD_SIZE + 1
+}
+
+// isWordAligned проверяет, выровнен ли адрес по границе слова (WORD_SIZE байт)
+func (m *Memory) isWordAligned(address int) bool {
+	return address%WORD_SIZE == 0 // Проверяем, делится ли адрес на размер слова без остатка
+}
+
+// SetStrictAlignment включает или выключает строгую проверку выравнивания слов.
+// По умолчанию невыровненный доступ разрешен для совместимости со старыми программами.
+func (m *Memory) SetStrictAlignment(enabled bool) {
+	m.strictAlign = enabled
+}
+
+// checkAlignment возвращает ошибку, если в строгом режиме адрес слова не выровнен
+func (m *Memory) checkAlignment(operation string, address int) error {
+	if m.strictAlign && !m.isWordAligned(address) {
+		return &AlignmentError{Operation: operation, Address: address}
+	}
+	return nil
+}
+
+// WriteWord записывает слово в память по заданному адресу с проверкой границ
+func (m *Memory) WriteWord(address int, word Word) error {
+	if err := m.checkAlignment("write", address); err != nil {
+		m.countError(-1, WORD_SIZE) // Физический адрес еще не известен
+		return err
+	}
+	m.heatmap.countWrite(address) // Тепловая карта считает адреса, которые видит программа
+	address, err := m.translate("write", address, WORD_SIZE, PermWrite)
+	if err != nil {
+		m.countError(-1, WORD_SIZE)
+		return err
+	}
+	if err := m.checkPermission("write", address, WORD_SIZE, PermWrite); err != nil {
+		m.countError(address, WORD_SIZE) // Увеличиваем счетчик ошибок
+		return err
+	}
+
+	// Обращение к окну устройства передается устройству
+	if r := m.deviceAt(address); r != nil {
+		if m.userMode {
+			m.countError(address, WORD_SIZE) // Увеличиваем счетчик ошибок
+			return errDeviceAccess("write", address, r)
+		}
+		if err := r.writeDevice(address, word); err != nil {
+			m.countError(address, WORD_SIZE) // Увеличиваем счетчик ошибок
+			return err
+		}
+		m.countAccess(accessWordWrite, address, WORD_SIZE) // Увеличиваем счетчик обращений к памяти
+		return nil
+	}
+
+	// Преобразуем слово в массив байтов
+	rawValue := wordBits(word)
+	var bytes [WORD_SIZE]byte
+	binary.LittleEndian.PutUint64(bytes[:], rawValue)
+
+	// Записываем байты в память
+	if err := m.writeBytes(address, bytes[:]); err != nil { // Копируем слово по указанному адресу
+		m.countError(address, WORD_SIZE) // Увеличиваем счетчик ошибок
+		return err
+	}
+	m.countAccess(accessWordWrite, address, WORD_SIZE) // Увеличиваем счетчик обращений к памяти
+	return nil                                         // Возвращаем nil, если ошибок не было
+}
+
+// wordBits возвращает 64-битное представление слова в памяти (тег и значение)
+func wordBits(word Word) uint64 {
+	switch word.Tag {
+	case TagCommand: // Если это команда
+		return tagCommand |
+			uint64(word.Cmd.BB)<<cmdBBShift | // Сдвигаем BB на 40 бит
+			uint64(word.Cmd.Opcode)<<cmdOpcodeShift | // Сдвигаем код операции на 32 бита
+			uint64(word.Cmd.Address1)<<cmdAddress1Shift | // Сдвигаем Address1 на 16 бит
+			uint64(word.Cmd.Address2) // Добавляем Address2
+	case TagFloat: // Если это вещественное число
+		return tagFloat | uint64(math.Float32bits(word.D.F))
+	}
+	return uint64(uint32(word.D.I)) // Целое число
+}
+
+// ReadWord читает слово из памяти по заданному адресу с проверкой границ
+func (m *Memory) ReadWord(address int) (Word, error) {
+	return m.readWord("read", address, PermRead)
+}
+
+// readWord читает слово по виртуальному адресу после проверки выравнивания
+func (m *Memory) readWord(operation string, address int, need Permission) (Word, error) {
+	if err := m.checkAlignment(operation, address); err != nil {
+		m.countError(-1, WORD_SIZE) // Физический адрес еще не известен
+		return Word{}, err
+	}
+	if need == PermRead {
+		m.heatmap.countRead(address) // Выборки команд не считаются обращениями к данным
+	}
+	address, err := m.translate(operation, address, WORD_SIZE, need)
+	if err != nil {
+		m.countError(-1, WORD_SIZE) // Физический адрес еще не известен
+		return Word{}, err
+	}
+	return m.readPhysical(operation, address, need)
+}
+
+// readPhysical читает слово по физическому адресу с проверкой права доступа need
+func (m *Memory) readPhysical(operation string, address int, need Permission) (Word, error) {
+	if err := m.checkPermission(operation, address, WORD_SIZE, need); err != nil {
+		m.countError(address, WORD_SIZE) // Увеличиваем счетчик ошибок
+		return Word{}, err
+	}
+	var word Word
+	var err error
+	if r := m.deviceAt(address); r != nil {
+		if m.userMode {
+			m.countError(address, WORD_SIZE) // Увеличиваем счетчик ошибок
+			return Word{}, errDeviceAccess(operation, address, r)
+		}
+		word, err = r.readDevice(address) // Обращение к окну устройства передается устройству
+	} else {
+		word, err = m.PeekWord(address) // Читаем и декодируем слово
+	}
+	if err != nil {
+		m.countError(address, WORD_SIZE) // Увеличиваем счетчик ошибок
+		return Word{}, err
+	}
+	if need == PermExec {
+		m.countAccess(accessFetch, address, WORD_SIZE) // Выборки команд учитываются отдельно от чтений данных
+	} else {
+		m.countAccess(accessWordRead, address, WORD_SIZE) // Увеличиваем счетчик обращений к памяти
+	}
+	return word, nil // Возвращаем считанное слово и nil, если ошибок не было
+}
+
+// PeekWord читает слово без учета в счетчиках обращений (для отладчика и инструментов)
+func (m *Memory) PeekWord(address int) (Word, error) {
+	// Читаем слово из памяти
+	var bytes [WORD_SIZE]byte
+	if err := m.readBytes(address, bytes[:]); err != nil { // Копируем слово из памяти по указанному адресу
+		return Word{}, err
+	}
+
+	// Преобразуем байты в слово по тегу
+	rawValue := binary.LittleEndian.Uint64(bytes[:]) // Преобразуем байты в целое число
+	return wordFromBits(rawValue), nil               // Возвращаем считанное слово и nil, если ошибок не было
+}
+
+// peekData читает слово данных по виртуальному адресу так же, как команда программы
+// (сегмент и MMU), но без побочных эффектов: чтение не попадает в счетчики, тепловую
+// карту и кэш, а отказ страницы не запоминается в MMU. Окна устройств не читаются,
+// так как чтение регистра устройства может изменить его состояние.
+func (m *Memory) peekData(address int) (Word, error) {
+	const operation = "peek"
+	if err := m.checkAlignment(operation, address); err != nil {
+		return Word{}, err
+	}
+	physical, err := m.segment.apply(operation, address, WORD_SIZE)
+	if err != nil {
+		return Word{}, err
+	}
+	if m.mmu != nil && m.mmu.enabled {
+		virtual := physical
+		var cause int
+		if physical, cause, err = m.mmu.lookup(operation, virtual, WORD_SIZE, false); cause != 0 {
+			return Word{}, faultError(operation, virtual, cause)
+		} else if err != nil {
+			return Word{}, err
+		}
+	}
+	if r := m.deviceAt(physical); r != nil {
+		return Word{}, &MemoryError{Operation: operation, Address: physical, Message: fmt.Sprintf("device %q cannot be read without side effects", r.Name)}
+	}
+	return m.PeekWord(physical)
+}
+
+// wordFromBits декодирует слово из 64-битного представления в памяти (обратно wordBits)
+func wordFromBits(rawValue uint64) Word {
+	var word Word
+	switch {
+	case rawValue&tagCommand != 0: // Если это команда
+		word.Tag = TagCommand
+		word.Cmd.Opcode = uint8(rawValue >> cmdOpcodeShift)      // Извлекаем код операции
+		word.Cmd.BB = uint8((rawValue >> cmdBBShift) & 0x03)     // Извлекаем BB
+		word.Cmd.Address1 = uint16(rawValue >> cmdAddress1Shift) // Извлекаем Address1
+		word.Cmd.Address2 = uint16(rawValue)                     // Извлекаем Address2
+	case rawValue&tagFloat != 0: // Если это вещественное число
+		word.SetFloat(math.Float32frombits(uint32(rawValue)))
+	default: // Если это целое число
+		word.SetInt(int32(uint32(rawValue)))
+	}
+	return word
+}
+
+// WriteByteAt записывает один байт в память по заданному адресу
+func (m *Memory) WriteByteAt(address int, value byte) error {
+	m.heatmap.countWrite(address)
+	address, err := m.translate("write", address, 1, PermWrite)
+	if err != nil {
+		m.countError(-1, 1) // Физический адрес еще не известен
+		return err
+	}
+	if err := m.checkPermission("write", address, 1, PermWrite); err != nil {
+		m.countError(address, 1) // Увеличиваем счетчик ошибок
+		return err
+	}
+	if err := m.writeBytes(address, []byte{value}); err != nil { // Записываем значение байта по указанному адресу
+		m.countError(address, 1) // Увеличиваем счетчик ошибок
+		return err
+	}
+	m.countAccess(accessByteWrite, address, 1) // Увеличиваем счетчик обращений к памяти
+	return nil                                 // Возвращаем nil, если ошибок не было
+}
+
+// ReadByteAt считывает один байт из памяти по заданному адресу
+func (m *Memory) ReadByteAt(address int) (byte, error) {
+	m.heatmap.countRead(address)
+	address, err := m.translate("read", address, 1, PermRead)
+	if err != nil {
+		m.countError(-1, 1) // Физический адрес еще не известен
+		return 0, err
+	}
+	if err := m.checkPermission("read", address, 1, PermRead); err != nil {
+		m.countError(address, 1) // Увеличиваем счетчик ошибок
+		return 0, err
+	}
+	var value [1]byte
+	if err := m.readBytes(address, value[:]); err != nil { // Считываем байт по указанному адресу
+		m.countError(address, 1) // Увеличиваем счетчик ошибок
+		return 0, err
+	}
+	m.countAccess(accessByteRead, address, 1) // Увеличиваем счетчик обращений к памяти
+	return value[0], nil                      // Возвращаем считанный байт и nil, если ошибок не было
+}
+
+// Clear сбрасывает все ячейки памяти в ноль
+func (m *Memory) Clear() {
+	for i := range m.data { // Проходим по всем элементам массива данных
+		m.data[i] = 0 // Устанавливаем значение каждого элемента в 0
+	}
+	for _, bank := range m.banks { // Очищаем переключаемые банки
+		for i := range bank {
+			bank[i] = 0
+		}
+	}
+	m.stats = MemoryStats{} // Сбрасываем счетчики обращений и ошибок
+	for _, r := range m.regions {
+		r.stats = MemoryStats{}
+	}
+}
+
+// GetAccessCount возвращает общее количество обращений к памяти
+func (m *Memory) GetAccessCount() int {
+	return m.stats.Accesses() // Возвращаем текущее значение счетчика обращений к памяти
+}
+
+// GetErrorCount возвращает общее количество ошибок при доступе к памяти
+func (m *Memory) GetErrorCount() int {
+	return m.stats.Errors // Возвращаем текущее значение счетчика ошибок
+}
+
+// Close корректно закрывает ресурсы памяти
+func (m *Memory) Close() {
+	m.initialized = false // Устанавливаем флаг инициализации в false, чтобы указать, что память больше не используется
+}
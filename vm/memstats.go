@@ -0,0 +1,102 @@
+package vm
+
+// MemoryStats — счетчики обращений к памяти или к одному ее региону
+type MemoryStats struct {
+	WordReads  int // Чтения слов данных
+	WordWrites int // Записи слов
+	ByteReads  int // Чтения байтов
+	ByteWrites int // Записи байтов
+	Fetches    int // Выборки команд
+	Errors     int // Отклоненные обращения
+}
+
+// Reads возвращает общее число чтений данных (слов и байтов)
+func (s MemoryStats) Reads() int {
+	return s.WordReads + s.ByteReads
+}
+
+// Writes возвращает общее число записей (слов и байтов)
+func (s MemoryStats) Writes() int {
+	return s.WordWrites + s.ByteWrites
+}
+
+// Accesses возвращает общее число успешных обращений, включая выборки команд
+func (s MemoryStats) Accesses() int {
+	return s.Reads() + s.Writes() + s.Fetches
+}
+
+// Sub возвращает разность счетчиков: обращения, выполненные после снимка base
+func (s MemoryStats) Sub(base MemoryStats) MemoryStats {
+	return MemoryStats{
+		WordReads:  s.WordReads - base.WordReads,
+		WordWrites: s.WordWrites - base.WordWrites,
+		ByteReads:  s.ByteReads - base.ByteReads,
+		ByteWrites: s.ByteWrites - base.ByteWrites,
+		Fetches:    s.Fetches - base.Fetches,
+		Errors:     s.Errors - base.Errors,
+	}
+}
+
+// accessKind — вид обращения к памяти для счетчиков
+type accessKind int
+
+const (
+	accessWordRead accessKind = iota
+	accessWordWrite
+	accessByteRead
+	accessByteWrite
+	accessFetch
+)
+
+// add учитывает одно обращение вида kind
+func (s *MemoryStats) add(kind accessKind) {
+	switch kind {
+	case accessWordRead:
+		s.WordReads++
+	case accessWordWrite:
+		s.WordWrites++
+	case accessByteRead:
+		s.ByteReads++
+	case accessByteWrite:
+		s.ByteWrites++
+	case accessFetch:
+		s.Fetches++
+	}
+}
+
+// Stats возвращает счетчики обращений к памяти с создания или последнего Clear
+func (m *Memory) Stats() MemoryStats {
+	return m.stats
+}
+
+// Stats возвращает счетчики обращений к региону
+func (r *Region) Stats() MemoryStats {
+	return r.stats
+}
+
+// countAccess учитывает успешное обращение по физическому адресу address в памяти,
+// в регионе, которому принадлежит адрес, и в модели кэша
+func (m *Memory) countAccess(kind accessKind, address, n int) {
+	m.stats.add(kind)
+	r := m.findRegion(address, n)
+	if r != nil {
+		r.stats.add(kind)
+	}
+	if m.cache != nil && kind != accessFetch && (r == nil || r.device == nil) {
+		m.cache.access(address, kind == accessWordWrite || kind == accessByteWrite) // Устройства не кэшируются
+	}
+}
+
+// countError учитывает отклоненное обращение. Если физический адрес известен
+// (address >= 0), ошибка учитывается и в его регионе.
+func (m *Memory) countError(address, n int) {
+	m.stats.Errors++
+	if address < 0 {
+		m.log.Logf(SubsystemMemory, LogDebug, "Rejected %d-byte access before address translation", n)
+		return
+	}
+	m.log.Logf(SubsystemMemory, LogDebug, "Rejected %d-byte access at 0x%X", n, address)
+	if r := m.findRegion(address, n); r != nil {
+		r.stats.Errors++
+	}
+}
@@ -0,0 +1,183 @@
+package vm
+
+import "fmt"
+
+// Страничная организация виртуального адресного пространства
+const (
+	PAGE_SHIFT = 8               // Число бит смещения внутри страницы
+	PAGE_SIZE  = 1 << PAGE_SHIFT // Размер страницы в байтах
+	NUM_PAGES  = 256             // Число страниц в 16-битном виртуальном адресном пространстве
+)
+
+// Биты элемента таблицы страниц. Старшие биты элемента (без младших PAGE_SHIFT)
+// содержат физический адрес кадра, выровненный по границе страницы.
+const (
+	PTE_PRESENT = 0x01 // Страница отображена в физическую память
+	PTE_WRITE   = 0x02 // Запись в страницу разрешена
+)
+
+// Регистры MMU (смещения относительно начала окна устройства)
+const (
+	MMU_CTRL        = 0 * WORD_SIZE // Управление: бит 0 — трансляция включена
+	MMU_PTBR        = 1 * WORD_SIZE // Физический адрес таблицы страниц
+	MMU_FAULT_ADDR  = 2 * WORD_SIZE // Виртуальный адрес последнего отказа страницы
+	MMU_FAULT_CAUSE = 3 * WORD_SIZE // Причина последнего отказа страницы
+
+	MMU_SIZE = 4 * WORD_SIZE // Размер окна MMU в байтах
+	MMU_BASE = 0xF040        // Адрес окна MMU по умолчанию
+)
+
+// Бит регистра управления MMU
+const MMU_ENABLE = 0x01 // Виртуальные адреса транслируются через таблицу страниц
+
+// Причины отказа страницы (регистр MMU_FAULT_CAUSE)
+const (
+	FAULT_NOT_PRESENT = 1 // Страница не отображена
+	FAULT_READ_ONLY   = 2 // Запись в страницу без PTE_WRITE
+)
+
+// MMU транслирует виртуальные адреса в физические по таблице страниц из NUM_PAGES слов,
+// расположенной в физической памяти по адресу PTBR. Обращение к неотображенной странице
+// или запись в страницу только для чтения вызывает ловушку VECTOR_PAGE_FAULT; после IRET
+// команда, вызвавшая отказ, выполняется повторно.
+type MMU struct {
+	mem        *Memory // Память, в которой расположена таблица страниц
+	enabled    bool    // Трансляция включена
+	ptbr       int     // Физический адрес таблицы страниц
+	faultAddr  int     // Виртуальный адрес последнего отказа
+	faultCause int     // Причина последнего отказа
+}
+
+// Read читает регистр MMU
+func (u *MMU) Read(addr int) (Word, error) {
+	switch addr {
+	case MMU_CTRL:
+		if u.enabled {
+			return IntWord(MMU_ENABLE), nil
+		}
+		return IntWord(0), nil
+	case MMU_PTBR:
+		return IntWord(int32(u.ptbr)), nil
+	case MMU_FAULT_ADDR:
+		return IntWord(int32(u.faultAddr)), nil
+	case MMU_FAULT_CAUSE:
+		return IntWord(int32(u.faultCause)), nil
+	}
+	return Word{}, fmt.Errorf("mmu register 0x%X does not exist", addr)
+}
+
+// Write записывает регистр MMU; регистры отказа доступны только для чтения
+func (u *MMU) Write(addr int, word Word) error {
+	switch addr {
+	case MMU_CTRL:
+		u.SetEnabled(word.D.I&MMU_ENABLE != 0)
+		return nil
+	case MMU_PTBR:
+		return u.SetPageTable(int(word.D.I))
+	}
+	return fmt.Errorf("mmu register 0x%X is not writable", addr)
+}
+
+// SetPageTable задает физический адрес таблицы страниц
+func (u *MMU) SetPageTable(base int) error {
+	if base < 0 || base+NUM_PAGES*WORD_SIZE > u.mem.Size() {
+		return fmt.Errorf("page table at 0x%X is out of memory range", base)
+	}
+	u.ptbr = base
+	return nil
+}
+
+// PageTable возвращает физический адрес таблицы страниц
+func (u *MMU) PageTable() int {
+	return u.ptbr
+}
+
+// SetEnabled включает или выключает трансляцию адресов
+func (u *MMU) SetEnabled(enabled bool) {
+	u.enabled = enabled
+}
+
+// Enabled сообщает, включена ли трансляция адресов
+func (u *MMU) Enabled() bool {
+	return u.enabled
+}
+
+// Fault возвращает виртуальный адрес и причину последнего отказа страницы
+func (u *MMU) Fault() (address, cause int) {
+	return u.faultAddr, u.faultCause
+}
+
+// translate переводит виртуальный адрес диапазона [address, address+n) в физический
+func (u *MMU) translate(operation string, address, n int, write bool) (int, error) {
+	physical, cause, err := u.lookup(operation, address, n, write)
+	if cause != 0 {
+		return 0, u.fault(operation, address, cause)
+	}
+	return physical, err
+}
+
+// lookup переводит адрес по таблице страниц, ничего не меняя в MMU; при отказе
+// страницы возвращает его причину FAULT_*
+func (u *MMU) lookup(operation string, address, n int, write bool) (int, int, error) {
+	if address < 0 || address+n > NUM_PAGES*PAGE_SIZE {
+		return 0, 0, &MemoryError{Operation: operation, Address: address, Message: "virtual address out of range"}
+	}
+	offset := address & (PAGE_SIZE - 1)
+	if offset+n > PAGE_SIZE {
+		return 0, 0, &MemoryError{Operation: operation, Address: address, Message: "access crosses page boundary"}
+	}
+	pte, err := u.mem.PeekWord(u.ptbr + (address>>PAGE_SHIFT)*WORD_SIZE)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read page table entry: %w", err)
+	}
+	entry := int(uint32(pte.D.I))
+	switch {
+	case entry&PTE_PRESENT == 0:
+		return 0, FAULT_NOT_PRESENT, nil
+	case write && entry&PTE_WRITE == 0:
+		return 0, FAULT_READ_ONLY, nil
+	}
+	return entry&^(PAGE_SIZE-1) | offset, 0, nil
+}
+
+// faultError описывает отказ страницы с причиной cause
+func faultError(operation string, address, cause int) *MemoryError {
+	message := "page not present"
+	if cause == FAULT_READ_ONLY {
+		message = "page is read-only"
+	}
+	return &MemoryError{Operation: operation, Address: address, Message: "page fault: " + message}
+}
+
+// fault запоминает причину отказа и создает ловушку VECTOR_PAGE_FAULT
+func (u *MMU) fault(operation string, address, cause int) error {
+	u.faultAddr = address
+	u.faultCause = cause
+	return &TrapError{Vector: VECTOR_PAGE_FAULT, Err: faultError(operation, address, cause), Fault: true}
+}
+
+// translate переводит адрес обращения к памяти с правом need: адрес данных сначала
+// проверяется и смещается сегментом, затем транслируется через MMU, если он подключен
+func (m *Memory) translate(operation string, address, n int, need Permission) (int, error) {
+	if need != PermExec {
+		var err error
+		if address, err = m.segment.apply(operation, address, n); err != nil {
+			return 0, err
+		}
+	}
+	if m.mmu == nil || !m.mmu.enabled {
+		return address, nil
+	}
+	return m.mmu.translate(operation, address, n, need == PermWrite)
+}
+
+// MapMMU создает MMU, отображает его регистры в память по адресу base и подключает
+// трансляцию к памяти данных. В гарвардском режиме выборка команд не транслируется.
+func (p *Processor) MapMMU(base int) (*MMU, error) {
+	u := &MMU{mem: p.memory}
+	if _, err := p.memory.MapDevice("mmu", base, MMU_SIZE, u); err != nil {
+		return nil, err
+	}
+	p.memory.mmu = u
+	return u, nil
+}
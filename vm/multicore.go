@@ -0,0 +1,223 @@
+package vm
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+// Параметры многоядерной машины
+const (
+	MAX_CORES       = 16    // Наибольшее число ядер
+	CORE_STACK_SIZE = 0x400 // Размер стека каждого ядра в байтах
+	DEFAULT_QUANTUM = 1     // Инструкций ядра подряд до переключения по умолчанию
+)
+
+// Machine — несколько ядер, разделяющих память команд и данных, устройства и
+// отладочную информацию первого ядра. У каждого ядра свои регистры, PSW, стек,
+// сегмент данных, очередь прерываний и генератор RND; номер ядра загружает
+// команда CPUID.
+//
+// Модель согласованности — последовательная: ядра выполняются в одной горутине по
+// очереди, каждая инструкция целиком (включая чтение, изменение и запись слова)
+// видна остальным ядрам до следующей инструкции, и порядок обращений одного ядра
+// не переставляется. Гонки возникают только между инструкциями, поэтому
+// последовательность LOAD, ADDR, STORE над общим словом может потерять обновление,
+// а XCHG, CAS и XADD атомарны и подходят для блокировок. Порядок переключения задается
+// квантом и расписанием (по кругу или псевдослучайно), поэтому запуск с тем же
+// расписанием воспроизводит ту же гонку.
+//
+// Обращения к окнам устройств (MMIO) выполняются синхронно в момент инструкции и
+// упорядочены с обычными обращениями к памяти: запись в регистр команды диска
+// завершает обмен с памятью до следующей инструкции любого ядра. Команда FENCE —
+// полный барьер: при последовательной модели она ничего не переставляет, но
+// завершает отложенные записи устройств хоста, реализующих Fencer, поэтому
+// переносимые программы ставят ее между записью данных и флагом готовности.
+//
+// Прерывания таймера и других устройств доставляются первому ядру. SLEEP по часам
+// хоста и ввод, ожидающий хоста, останавливают всю машину.
+type Machine struct {
+	cores   []*Processor
+	quantum int        // Инструкций ядра подряд до переключения
+	random  *rand.Rand // Псевдослучайное расписание; nil — по кругу
+}
+
+// CoreError — ошибка, остановившая одно из ядер машины
+type CoreError struct {
+	Core int   // Номер ядра
+	Err  error // Ошибка выполнения инструкции
+}
+
+// Error реализует интерфейс error для CoreError
+func (e *CoreError) Error() string {
+	return fmt.Sprintf("core %d: %v", e.Core, e.Err)
+}
+
+// Unwrap возвращает исходную ошибку
+func (e *CoreError) Unwrap() error {
+	return e.Err
+}
+
+// NewMachine создает машину из primary и count-1 новых ядер, разделяющих его память.
+// Если ядер больше одного, каждому выделяется стек CORE_STACK_SIZE байт под стеком
+// предыдущего ядра, начиная с корня стека primary; ядро i получает начальное
+// значение RND на i больше, чем primary. Ядра создаются после настройки primary: они
+// наследуют квоты, журнал, часы, ввод и вывод.
+func NewMachine(primary *Processor, count int) (*Machine, error) {
+	if count < 1 || count > MAX_CORES {
+		return nil, fmt.Errorf("invalid core count %d (1-%d)", count, MAX_CORES)
+	}
+	if primary.coreID != 0 || primary.shared {
+		return nil, fmt.Errorf("processor is already a core of another machine")
+	}
+	m := &Machine{cores: []*Processor{primary}, quantum: DEFAULT_QUANTUM}
+	for id := 1; id < count; id++ {
+		m.cores = append(m.cores, primary.newCore(id))
+	}
+	if count > 1 {
+		top := primary.stackBase
+		for id, core := range m.cores {
+			base := top - id*CORE_STACK_SIZE
+			if err := core.SetStack(base, base-CORE_STACK_SIZE); err != nil {
+				return nil, fmt.Errorf("core %d: %v", id, err)
+			}
+		}
+	}
+	return m, nil
+}
+
+// newCore создает ядро id, разделяющее с p память, устройства и отладочную информацию
+func (p *Processor) newCore(id int) *Processor {
+	c := &Processor{
+		In:            p.In,
+		Out:           p.Out,
+		memory:        p.memory,
+		code:          p.code,
+		log:           p.log.fork(fmt.Sprintf("[core %d] ", id)),
+		commandMap:    make(map[OpCode]CommandConstructor),
+		quotas:        p.quotas,
+		events:        make(chan Event, EVENT_QUEUE_SIZE),
+		hostCalls:     p.hostCalls,
+		current:       -1,
+		stackBase:     p.stackBase,
+		stackLimit:    p.stackLimit,
+		vectorBase:    p.vectorBase,
+		debugInfo:     p.debugInfo,
+		invalidOpcode: p.invalidOpcode,
+		virtualClock:  p.virtualClock,
+		coreID:        id,
+		shared:        true,
+	}
+	c.log.context = c.logContext
+	c.log.accept = c.logAccepts
+	c.control.cond = sync.NewCond(&c.control.mu)
+	c.SetSeed(p.seed + int64(id)) // Ядра получают разные последовательности RND
+	c.initializeCommandMap()
+	return c
+}
+
+// CoreID возвращает номер ядра, загружаемый командой CPUID
+func (p *Processor) CoreID() int {
+	return p.coreID
+}
+
+// Cores возвращает ядра машины; первое ядро — процессор, переданный NewMachine
+func (m *Machine) Cores() []*Processor {
+	return m.cores
+}
+
+// SetQuantum задает число инструкций, которые ядро выполняет подряд до переключения
+func (m *Machine) SetQuantum(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("invalid quantum %d", n)
+	}
+	m.quantum = n
+	return nil
+}
+
+// SetRandomSchedule включает псевдослучайный выбор следующего ядра с начальным
+// значением seed; одинаковое значение воспроизводит то же чередование
+func (m *Machine) SetRandomSchedule(seed int64) {
+	m.random = rand.New(rand.NewSource(seed))
+}
+
+// Reset сбрасывает ядра: ядро i начинает с entries[i], ядра без адреса — с entries[0]
+func (m *Machine) Reset(entries ...uint16) error {
+	if len(entries) == 0 || len(entries) > len(m.cores) {
+		return fmt.Errorf("machine has %d core(s), got %d entry point(s)", len(m.cores), len(entries))
+	}
+	for id, core := range m.cores {
+		entry := entries[0]
+		if id < len(entries) {
+			entry = entries[id]
+		}
+		core.Reset(entry)
+		core.segment = Segment{}
+	}
+	m.cores[0].attach()
+	return nil
+}
+
+// Run выполняет ядра до остановки всех ядер. Если ядро останавливается ошибкой или
+// квотой, остальные ядра прекращают выполнение и возвращается *CoreError.
+func (m *Machine) Run() error {
+	return m.RunContext(context.Background())
+}
+
+// RunContext выполняет ядра как Run, проверяя ctx между инструкциями. При отмене ctx
+// возвращается ctx.Err(), и повторный вызов продолжает выполнение.
+func (m *Machine) RunContext(ctx context.Context) error {
+	done := ctx.Done()
+	next := 0
+	for {
+		running := m.running()
+		if len(running) == 0 {
+			return nil
+		}
+		core := running[next%len(running)]
+		if m.random != nil {
+			core = running[m.random.Intn(len(running))]
+		}
+		core.attach()
+		for i := 0; i < m.quantum && !core.Stopped(); i++ {
+			select {
+			case <-done:
+				core.detach()
+				return ctx.Err()
+			default:
+			}
+			if err := core.step(); err != nil {
+				core.detach()
+				return &CoreError{Core: core.coreID, Err: err}
+			}
+		}
+		core.detach()
+		if core.Stopped() {
+			continue // Следующее по кругу ядро сдвинулось на место остановленного
+		}
+		next = (next + 1) % len(running)
+	}
+}
+
+// running возвращает ядра, программа которых не остановлена
+func (m *Machine) running() []*Processor {
+	var cores []*Processor
+	for _, core := range m.cores {
+		if !core.Stopped() {
+			cores = append(cores, core)
+		}
+	}
+	return cores
+}
+
+// attach переключает общую память на сегмент и режим привилегий ядра
+func (p *Processor) attach() {
+	p.memory.segment = p.segment
+	p.setUserMode(p.psw.UserMode)
+}
+
+// detach запоминает сегмент ядра перед переключением на другое ядро
+func (p *Processor) detach() {
+	p.segment = p.memory.segment
+}
@@ -0,0 +1,469 @@
+package vm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RelocField — поле слова, которое исправляет компоновщик
+type RelocField int
+
+const (
+	RelocAddress1 RelocField = iota + 1 // Поле Address1 команды
+	RelocAddress2                       // Поле Address2 команды
+	RelocWord                           // Целое слово данных
+)
+
+// Названия полей перемещений в объектном файле
+var relocFieldNames = map[RelocField]string{
+	RelocAddress1: "addr1",
+	RelocAddress2: "addr2",
+	RelocWord:     "word",
+}
+
+// ObjectSymbol — символ объектного модуля
+type ObjectSymbol struct {
+	Name    string // Имя символа
+	Section string // Секция (SectionText, SectionData); "" — абсолютная константа
+	Value   int64  // Смещение в секции или значение константы
+	Global  bool   // Символ виден другим модулям
+}
+
+// Relocation описывает слово, значение которого зависит от адреса секции или символа
+type Relocation struct {
+	Section string     // Секция исправляемого слова
+	Offset  int        // Смещение слова в секции в байтах
+	Field   RelocField // Исправляемое поле
+	Symbol  string     // Внешний символ или секция этого модуля (".text", ".data")
+	Addend  int64      // Слагаемое к адресу символа
+}
+
+// ObjectLine связывает слово секции со строкой исходного текста
+type ObjectLine struct {
+	Section string // Секция слова
+	Offset  int    // Смещение слова в секции в байтах
+	Line    int    // Номер строки исходного текста
+}
+
+// Object — перемещаемый объектный модуль: секции команд и данных, таблица символов,
+// перемещения и имя символа точки входа
+type Object struct {
+	Name        string         // Имя модуля (файла) для сообщений об ошибках
+	Text        []Word         // Секция команд
+	Data        []Word         // Секция данных
+	Symbols     []ObjectSymbol // Символы модуля
+	Relocations []Relocation   // Перемещения
+	Entry       string         // Символ точки входа; "" — не задан
+	Source      string         // Файл исходного текста для отладочной информации
+	Lines       []ObjectLine   // Строки исходного текста слов секций
+}
+
+// sortSymbols упорядочивает символы по секции и смещению
+func sortSymbols(symbols []ObjectSymbol) {
+	sort.Slice(symbols, func(i, j int) bool {
+		if symbols[i].Section != symbols[j].Section {
+			return symbols[i].Section < symbols[j].Section
+		}
+		if symbols[i].Value != symbols[j].Value {
+			return symbols[i].Value < symbols[j].Value
+		}
+		return symbols[i].Name < symbols[j].Name
+	})
+}
+
+// AssembleObjectFile ассемблирует файл с исходным текстом в объектный модуль
+func AssembleObjectFile(filename string) (*Object, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file: %v", err)
+	}
+	defer file.Close()
+	obj, err := AssembleObject(file)
+	if err != nil {
+		return nil, err
+	}
+	obj.Name, obj.Source = filename, filename
+	return obj, nil
+}
+
+// WriteObject записывает модуль в текстовом объектном формате:
+//
+//	section text|data       ; слова секции в формате загрузчика (k, i, r)
+//	symbol name section value [global]
+//	reloc section offset addr1|addr2|word symbol addend
+//	entry name
+//	source "file"           ; отладочная информация: файл исходного текста
+//	line section offset line
+func (obj *Object) WriteObject(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "; vm object")
+	for _, section := range []struct {
+		name  string
+		words []Word
+	}{{SectionText, obj.Text}, {SectionData, obj.Data}} {
+		fmt.Fprintf(bw, "section %s\n", section.name)
+		for _, word := range section.words {
+			fmt.Fprintln(bw, encodeWord(word))
+		}
+	}
+	for _, sym := range obj.Symbols {
+		section := sym.Section
+		if section == "" {
+			section = "abs"
+		}
+		fmt.Fprintf(bw, "symbol %s %s %d", sym.Name, section, sym.Value)
+		if sym.Global {
+			fmt.Fprint(bw, " global")
+		}
+		fmt.Fprintln(bw)
+	}
+	for _, r := range obj.Relocations {
+		fmt.Fprintf(bw, "reloc %s 0x%04x %s %s %d\n", r.Section, r.Offset, relocFieldNames[r.Field], r.Symbol, r.Addend)
+	}
+	if obj.Entry != "" {
+		fmt.Fprintf(bw, "entry %s\n", obj.Entry)
+	}
+	if obj.Source != "" {
+		fmt.Fprintf(bw, "source %s\n", strconv.Quote(obj.Source))
+	}
+	for _, l := range obj.Lines {
+		fmt.Fprintf(bw, "line %s 0x%04x %d\n", l.Section, l.Offset, l.Line)
+	}
+	return bw.Flush()
+}
+
+// ReadObjectFile читает объектный модуль из файла
+func ReadObjectFile(filename string) (*Object, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file: %v", err)
+	}
+	defer file.Close()
+	obj, err := ReadObject(file)
+	if err != nil {
+		return nil, err
+	}
+	obj.Name = filename
+	return obj, nil
+}
+
+// ReadObject читает модуль в текстовом объектном формате (см. WriteObject)
+func ReadObject(r io.Reader) (*Object, error) {
+	obj := &Object{}
+	section := ""
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		fields := strings.Fields(stripAsmComment(line))
+		if len(fields) == 0 {
+			continue
+		}
+		fail := func(format string, args ...interface{}) error {
+			return &CommandError{LineNumber: lineNumber, Line: line, Message: fmt.Sprintf(format, args...)}
+		}
+		switch fields[0] {
+		case "section":
+			if len(fields) != 2 || (fields[1] != SectionText && fields[1] != SectionData) {
+				return nil, fail("section must be %q or %q", SectionText, SectionData)
+			}
+			section = fields[1]
+		case "k", "i", "r":
+			if section == "" {
+				return nil, fail("word outside of a section")
+			}
+			word, err := parseObjectWord(fields)
+			if err != nil {
+				return nil, fail("%v", err)
+			}
+			if section == SectionText {
+				obj.Text = append(obj.Text, word)
+			} else {
+				obj.Data = append(obj.Data, word)
+			}
+		case "symbol":
+			if len(fields) < 4 || len(fields) > 5 || (len(fields) == 5 && fields[4] != "global") {
+				return nil, fail("symbol requires a name, a section, a value and an optional \"global\"")
+			}
+			value, err := ParseNumber(fields[3])
+			if err != nil {
+				return nil, fail("%v", err)
+			}
+			sym := ObjectSymbol{Name: fields[1], Section: fields[2], Value: value, Global: len(fields) == 5}
+			switch sym.Section {
+			case "abs":
+				sym.Section = ""
+			case SectionText, SectionData:
+			default:
+				return nil, fail("unknown symbol section %q", sym.Section)
+			}
+			obj.Symbols = append(obj.Symbols, sym)
+		case "reloc":
+			if len(fields) != 6 {
+				return nil, fail("reloc requires a section, an offset, a field, a symbol and an addend")
+			}
+			offset, err := ParseNumber(fields[2])
+			if err != nil {
+				return nil, fail("%v", err)
+			}
+			addend, err := ParseNumber(strings.TrimPrefix(fields[5], "-"))
+			if err != nil {
+				return nil, fail("%v", err)
+			}
+			if strings.HasPrefix(fields[5], "-") {
+				addend = -addend
+			}
+			r := Relocation{Section: fields[1], Offset: int(offset), Symbol: fields[4], Addend: addend}
+			for field, name := range relocFieldNames {
+				if name == fields[3] {
+					r.Field = field
+				}
+			}
+			if r.Field == 0 {
+				return nil, fail("unknown relocation field %q", fields[3])
+			}
+			obj.Relocations = append(obj.Relocations, r)
+		case "entry":
+			if len(fields) != 2 {
+				return nil, fail("entry requires a symbol name")
+			}
+			obj.Entry = fields[1]
+		case "source":
+			source, err := strconv.Unquote(strings.TrimSpace(strings.TrimSpace(line)[len("source"):]))
+			if err != nil {
+				return nil, fail("invalid source file name: %v", err)
+			}
+			obj.Source = source
+		case "line":
+			if len(fields) != 4 || (fields[1] != SectionText && fields[1] != SectionData) {
+				return nil, fail("line requires a section, an offset and a line number")
+			}
+			offset, err := ParseNumber(fields[2])
+			if err != nil {
+				return nil, fail("%v", err)
+			}
+			number, err := strconv.Atoi(fields[3])
+			if err != nil {
+				return nil, fail("invalid line number: %v", err)
+			}
+			obj.Lines = append(obj.Lines, ObjectLine{Section: fields[1], Offset: int(offset), Line: number})
+		default:
+			return nil, fail("unknown object record %q", fields[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading file: %v", err)
+	}
+	for _, r := range obj.Relocations {
+		words := obj.Text
+		if r.Section == SectionData {
+			words = obj.Data
+		} else if r.Section != SectionText {
+			return nil, fmt.Errorf("relocation in unknown section %q", r.Section)
+		}
+		if r.Offset < 0 || r.Offset%WORD_SIZE != 0 || r.Offset/WORD_SIZE >= len(words) {
+			return nil, fmt.Errorf("relocation offset 0x%X is outside section %s", r.Offset, r.Section)
+		}
+	}
+	return obj, nil
+}
+
+// parseObjectWord разбирает слово в формате загрузчика (k, i, r)
+func parseObjectWord(fields []string) (Word, error) {
+	switch fields[0] {
+	case "i":
+		if len(fields) != 2 {
+			return Word{}, fmt.Errorf("integer word requires a value")
+		}
+		value, err := strconv.ParseInt(fields[1], 10, 32)
+		if err != nil {
+			return Word{}, fmt.Errorf("invalid integer format: %v", err)
+		}
+		return IntWord(int32(value)), nil
+	case "r":
+		if len(fields) != 2 {
+			return Word{}, fmt.Errorf("float word requires a value")
+		}
+		value, err := strconv.ParseFloat(fields[1], 32)
+		if err != nil {
+			return Word{}, fmt.Errorf("invalid float format: %v", err)
+		}
+		return FloatWord(float32(value)), nil
+	}
+	if len(fields) != 5 {
+		return Word{}, fmt.Errorf("command requires 4 parameters (opcode, bb, addr1, addr2), got %d", len(fields)-1)
+	}
+	var values [4]int64
+	for i, field := range fields[1:] {
+		value, err := strconv.ParseInt(field, 16, 32)
+		if err != nil {
+			return Word{}, fmt.Errorf("invalid command field %q: %v", field, err)
+		}
+		values[i] = value
+	}
+	if values[0] < 0 || values[0] > 0xFF || values[1] < 0 || values[1] > 3 {
+		return Word{}, fmt.Errorf("invalid opcode or bb value")
+	}
+	return CommandWord(CommandData{
+		Opcode:   uint8(values[0]),
+		BB:       uint8(values[1]),
+		Address1: uint16(values[2]), // Отрицательное смещение относительного перехода — в дополнительном коде
+		Address2: uint16(values[3]),
+	}), nil
+}
+
+// Link компонует модули в образ: секции команд всех модулей размещаются подряд с адреса 0,
+// за ними — секции данных. Глобальные символы разрешают внешние ссылки между модулями.
+// Точка входа — символ из директивы .entry (допускается в одном модуле), иначе глобальный
+// символ start, иначе начало первой секции команд.
+func Link(objects []*Object) (*Image, error) {
+	bases := make([]map[string]int, len(objects))
+	address := 0
+	for i, obj := range objects {
+		bases[i] = map[string]int{SectionText: address}
+		address += len(obj.Text) * WORD_SIZE
+	}
+	for i, obj := range objects {
+		bases[i][SectionData] = address
+		address += len(obj.Data) * WORD_SIZE
+	}
+	if address > 1<<16 {
+		return nil, fmt.Errorf("linked program takes %d bytes, more than the 16-bit address space", address)
+	}
+
+	// Символы модулей и глобальные символы
+	locals := make([]map[string]int64, len(objects))
+	globals := make(map[string]int64)
+	owners := make(map[string]string)
+	for i, obj := range objects {
+		locals[i] = make(map[string]int64)
+		for _, sym := range obj.Symbols {
+			value := sym.Value
+			if sym.Section != "" {
+				value += int64(bases[i][sym.Section])
+			}
+			locals[i][sym.Name] = value
+			if !sym.Global {
+				continue
+			}
+			if owner, ok := owners[sym.Name]; ok {
+				return nil, fmt.Errorf("symbol %q is defined in both %s and %s", sym.Name, owner, objectName(obj, i))
+			}
+			globals[sym.Name], owners[sym.Name] = value, objectName(obj, i)
+		}
+	}
+	lookup := func(i int, name string) (int64, error) {
+		if section := strings.TrimPrefix(name, "."); section != name {
+			if base, ok := bases[i][section]; ok {
+				return int64(base), nil
+			}
+		}
+		if value, ok := locals[i][name]; ok {
+			return value, nil
+		}
+		if value, ok := globals[name]; ok {
+			return value, nil
+		}
+		return 0, fmt.Errorf("%s: undefined symbol %q", objectName(objects[i], i), name)
+	}
+
+	img := &Image{Labels: globals, Debug: &DebugInfo{}}
+	for i, obj := range objects {
+		for _, sym := range obj.Symbols {
+			if sym.Section != "" {
+				img.Debug.Labels = append(img.Debug.Labels, DebugLabel{Name: sym.Name, Address: bases[i][sym.Section] + int(sym.Value)})
+			}
+		}
+		source := obj.Source
+		if source == "" {
+			source = objectName(obj, i)
+		}
+		for _, l := range obj.Lines {
+			img.Debug.Lines = append(img.Debug.Lines, DebugLine{Address: bases[i][l.Section] + l.Offset, File: source, Line: l.Line})
+		}
+		sections := map[string][]Word{
+			SectionText: append([]Word(nil), obj.Text...),
+			SectionData: append([]Word(nil), obj.Data...),
+		}
+		for _, r := range obj.Relocations {
+			target, err := lookup(i, r.Symbol)
+			if err != nil {
+				return nil, err
+			}
+			if err := relocate(sections[r.Section], r, target+r.Addend); err != nil {
+				return nil, fmt.Errorf("%s: %v", objectName(obj, i), err)
+			}
+		}
+		for _, section := range []string{SectionText, SectionData} {
+			for j, word := range sections[section] {
+				img.Words = append(img.Words, ImageWord{Address: bases[i][section] + j*WORD_SIZE, Word: word})
+			}
+		}
+	}
+	sort.SliceStable(img.Words, func(i, j int) bool { return img.Words[i].Address < img.Words[j].Address })
+	img.Debug.sort()
+
+	// Точка входа
+	entry := int64(0)
+	entryObject := -1
+	for i, obj := range objects {
+		if obj.Entry == "" {
+			continue
+		}
+		if entryObject >= 0 {
+			return nil, fmt.Errorf("entry point is set in both %s and %s", objectName(objects[entryObject], entryObject), objectName(obj, i))
+		}
+		value, err := lookup(i, obj.Entry)
+		if err != nil {
+			return nil, err
+		}
+		entry, entryObject = value, i
+	}
+	if start, ok := globals["start"]; ok && entryObject < 0 {
+		entry = start
+	}
+	if entry < 0 || entry > 0xFFFF {
+		return nil, fmt.Errorf("entry point 0x%X is out of range", entry)
+	}
+	img.Entry = uint16(entry)
+	return img, nil
+}
+
+// relocate записывает значение value в поле слова, описанного перемещением
+func relocate(words []Word, r Relocation, value int64) error {
+	word := &words[r.Offset/WORD_SIZE]
+	switch r.Field {
+	case RelocWord:
+		if value < -1<<31 || value > 1<<32-1 {
+			return fmt.Errorf("relocated value %d does not fit in 32 bits", value)
+		}
+		*word = IntWord(int32(value))
+		return nil
+	}
+	if !word.IsCommand() {
+		return fmt.Errorf("relocation of a command field at %s+0x%X targets a data word", r.Section, r.Offset)
+	}
+	if value < -0x8000 || value > 0xFFFF {
+		return fmt.Errorf("relocated value %d does not fit in 16 bits", value)
+	}
+	if r.Field == RelocAddress1 {
+		word.Cmd.Address1 = uint16(value)
+	} else {
+		word.Cmd.Address2 = uint16(value)
+	}
+	return nil
+}
+
+// objectName возвращает имя модуля или его номер
+func objectName(obj *Object, i int) string {
+	if obj.Name != "" {
+		return obj.Name
+	}
+	return fmt.Sprintf("object %d", i)
+}
@@ -0,0 +1,289 @@
+package vm // Определяет пакет виртуальной машины
+
+import "strings"
+
+// OpCode представляет доступные коды операций
+type OpCode uint8 // Определяет новый тип OpCode на основе uint8
+
+const ( // Начало определения констант для кодов операций
+	STOP   OpCode = iota // Код операции для остановки выполнения
+	IADD                 // Код операции для целочисленного сложения
+	ISUB                 // Код операции для целочисленного вычитания
+	IMUL                 // Код операции для целочисленного умножения
+	IDIV                 // Код операции для целочисленного деления
+	IMOD                 // Код операции для вычисления остатка от деления
+	CMP                  // Код операции для сравнения значений
+	IIN                  // Код операции для ввода целого числа
+	IOUT                 // Код операции для вывода целого числа
+	RADD                 // Код операции для сложения вещественных чисел
+	RSUB                 // Код операции для вычитания вещественных чисел
+	RMUL                 // Код операции для умножения вещественных чисел
+	RDIV                 // Код операции для деления вещественных чисел
+	RCMP                 // Код операции для сравнения вещественных чисел
+	RIN                  // Код операции для ввода вещественного числа
+	ROUT                 // Код операции для вывода вещественного числа
+	GO                   // Код операции для перехода к указанному адресу
+	JZ                   // Код операции для перехода, если ноль (условный переход)
+	JG                   // Код операции для перехода, если больше (условный переход)
+	JL                   // Код операции для перехода, если меньше (условный переход)
+	AND                  // Код операции для логического И
+	OR                   // Код операции для логического ИЛИ
+	XOR                  // Код операции для логического исключающего ИЛИ
+	NOT                  // Код операции для логического отрицания
+	CALL                 // Код операции для вызова функции
+	RET                  // Код операции для возврата из функции
+	LOAD                 // Загружает значение в регистр
+	STORE                // Сохраняет значение регистра в память
+	ADDR                 // Складывает значения двух регистров и сохраняет результат в одном из них
+	SUBR                 // Вычитает значение одного регистра из другого и сохраняет результат в одном из них
+	MOVR                 // Перемещает значение из одного регистра в другой
+	HCALL                // Вызывает обработчик, зарегистрированный приложением хоста
+	MAP                  // Подключает, отключает или переносит регион карты памяти
+	PUSH                 // Помещает слово памяти в стек
+	POP                  // Извлекает слово из стека в память
+	PUSHR                // Помещает значение регистра в стек
+	POPR                 // Извлекает значение из стека в регистр
+	JNE                  // Код операции для перехода, если не ноль (условный переход)
+	JGE                  // Код операции для перехода, если больше или равно (условный переход)
+	JLE                  // Код операции для перехода, если меньше или равно (условный переход)
+	DIVMOD               // Код операции для деления с получением частного и остатка
+	INC                  // Увеличивает слово памяти на 1
+	DEC                  // Уменьшает слово памяти на 1
+	NEG                  // Меняет знак слова памяти
+	INCR                 // Увеличивает значение регистра на 1
+	DECR                 // Уменьшает значение регистра на 1
+	NEGR                 // Меняет знак значения регистра
+	ITOF                 // Преобразует целое число в вещественное
+	FTOI                 // Преобразует вещественное число в целое с заданным округлением
+	RSQRT                // Вычисляет квадратный корень вещественного числа
+	RPOW                 // Возводит вещественное число в степень
+	RLOG                 // Вычисляет натуральный логарифм вещественного числа
+	REXP                 // Вычисляет экспоненту вещественного числа
+	FLOAD                // Загружает вещественное число в вещественный регистр
+	FSTORE               // Сохраняет значение вещественного регистра в память
+	FADDR                // Складывает значения двух вещественных регистров
+	FSUBR                // Вычитает значение одного вещественного регистра из другого
+	FMULR                // Умножает значения двух вещественных регистров
+	FDIVR                // Делит значение одного вещественного регистра на другой
+	FMOVR                // Перемещает значение из одного вещественного регистра в другой
+	IRET                 // Возвращает управление из обработчика прерывания
+	EI                   // Разрешает аппаратные прерывания
+	DI                   // Запрещает аппаратные прерывания
+	INT                  // Программное прерывание через таблицу векторов
+	SETSEG               // Задает базу и предел сегмента данных из регистров a1 и a2
+	RND                  // Записывает псевдослучайное целое или вещественное число в память
+	TIME                 // Загружает в регистр прошедшее время выполнения
+	SLEEP                // Приостанавливает выполнение на заданное число миллисекунд
+	CIN                  // Код операции для ввода символа
+	COUT                 // Код операции для вывода символа
+	SOUT                 // Код операции для вывода строки, завершенной нулем
+	SIN                  // Код операции для ввода строки с ограничением длины
+	MEMCPY               // Копирует блок слов памяти
+	MEMSET               // Заполняет блок слов памяти значением
+	SCMP                 // Сравнивает два блока слов памяти
+	CPUID                // Загружает в регистр номер ядра
+	XCHG                 // Атомарно обменивает значения регистра и слова памяти
+	CAS                  // Атомарно сравнивает слово памяти с a1 и при равенстве записывает a2
+	XADD                 // Атомарно прибавляет регистр к слову памяти, возвращая прежнее значение
+	FENCE                // Барьер памяти: упорядочивает обращения к памяти и устройствам
+)
+
+// String возвращает строковое представление кода операции OpCode
+func (op OpCode) String() string {
+	// Начинаем оператор switch для определения строкового представления кода операции
+	switch op {
+	case STOP: // Если код операции равен STOP
+		return "STOP" // Возвращаем строку "STOP"
+	case IADD: // Если код операции равен IADD
+		return "IADD" // Возвращаем строку "IADD"
+	case ISUB: // Если код операции равен ISUB
+		return "ISUB" // Возвращаем строку "ISUB"
+	case IMUL: // Если код операции равен IMUL
+		return "IMUL" // Возвращаем строку "IMUL"
+	case IDIV: // Если код операции равен IDIV
+		return "IDIV" // Возвращаем строку "IDIV"
+	case IMOD: // Если код операции равен IMOD
+		return "IMOD" // Возвращаем строку "IMOD"
+	case CMP: // Если код операции равен CMP
+		return "CMP" // Возвращаем строку "CMP"
+	case IIN: // Если код операции равен IIN
+		return "IIN" // Возвращаем строку "IIN"
+	case IOUT: // Если код операции равен IOUT
+		return "IOUT" // Возвращаем строку "IOUT"
+	case RADD: // Если код операции равен RADD
+		return "RADD" // Возвращаем строку "RADD"
+	case RSUB: // Если код операции равен RSUB
+		return "RSUB" // Возвращаем строку "RSUB"
+	case RMUL: // Если код операции равен RMUL
+		return "RMUL" // Возвращаем строку "RMUL"
+	case RDIV: // Если код операции равен RDIV
+		return "RDIV" // Возвращаем строку "RDIV"
+	case RCMP: // Если код операции равен RCMP
+		return "RCMP" // Возвращаем строку "RCMP"
+	case RIN: // Если код операции равен RIN
+		return "RIN" // Возвращаем строку "RIN"
+	case ROUT: // Если код операции равен ROUT
+		return "ROUT" // Возвращаем строку "ROUT"
+	case GO: // Если код операции равен GO
+		return "GO" // Возвращаем строку "GO"
+	case JZ: // Если код операции равен JZ
+		return "JZ" // Возвращаем строку "JZ"
+	case JG: // Если код операции равен JG
+		return "JG" // Возвращаем строку "JG"
+	case JL: // Если код операции равен JL
+		return "JL" // Возвращаем строку "JL"
+	case AND: // Если код операции равен AND
+		return "AND" // Возвращаем строку "AND"
+	case OR: // Если код операции равен OR
+		return "OR" // Возвращаем строку "OR"
+	case XOR: // Если код операции равен XOR
+		return "XOR" // Возвращаем строку "XOR"
+	case NOT: // Если код операции равен NOT
+		return "NOT" // Возвращаем строку "NOT"
+	case CALL: // Если код операции равен CALL
+		return "CALL" // Возвращаем строку "CALL"
+	case RET: // Если код операции равен RET
+		return "RET" // Возвращаем строку "RET"
+	case LOAD: // Если код операции равен LOAD
+		return "LOAD" // Возвращаем строку "LOAD"
+	case STORE: // Если код операции равен STORE
+		return "STORE" // Возвращаем строку "STORE"
+	case ADDR: // Если код операции равен ADDR
+		return "ADDR" // Возвращаем строку "ADDR"
+	case SUBR: // Если код операции равен SUBR
+		return "SUBR" // Возвращаем строку "SUBR"
+	case MOVR: // Если код операции равен MOVR
+		return "MOVR" // Возвращаем строку "MOVR"
+	case HCALL: // Если код операции равен HCALL
+		return "HCALL" // Возвращаем строку "HCALL"
+	case MAP: // Если код операции равен MAP
+		return "MAP" // Возвращаем строку "MAP"
+	case PUSH: // Если код операции равен PUSH
+		return "PUSH" // Возвращаем строку "PUSH"
+	case POP: // Если код операции равен POP
+		return "POP" // Возвращаем строку "POP"
+	case PUSHR: // Если код операции равен PUSHR
+		return "PUSHR" // Возвращаем строку "PUSHR"
+	case POPR: // Если код операции равен POPR
+		return "POPR" // Возвращаем строку "POPR"
+	case JNE: // Если код операции равен JNE
+		return "JNE" // Возвращаем строку "JNE"
+	case JGE: // Если код операции равен JGE
+		return "JGE" // Возвращаем строку "JGE"
+	case JLE: // Если код операции равен JLE
+		return "JLE" // Возвращаем строку "JLE"
+	case DIVMOD: // Если код операции равен DIVMOD
+		return "DIVMOD" // Возвращаем строку "DIVMOD"
+	case INC: // Если код операции равен INC
+		return "INC" // Возвращаем строку "INC"
+	case DEC: // Если код операции равен DEC
+		return "DEC" // Возвращаем строку "DEC"
+	case NEG: // Если код операции равен NEG
+		return "NEG" // Возвращаем строку "NEG"
+	case INCR: // Если код операции равен INCR
+		return "INCR" // Возвращаем строку "INCR"
+	case DECR: // Если код операции равен DECR
+		return "DECR" // Возвращаем строку "DECR"
+	case NEGR: // Если код операции равен NEGR
+		return "NEGR" // Возвращаем строку "NEGR"
+	case ITOF: // Если код операции равен ITOF
+		return "ITOF" // Возвращаем строку "ITOF"
+	case FTOI: // Если код операции равен FTOI
+		return "FTOI" // Возвращаем строку "FTOI"
+	case RSQRT: // Если код операции равен RSQRT
+		return "RSQRT" // Возвращаем строку "RSQRT"
+	case RPOW: // Если код операции равен RPOW
+		return "RPOW" // Возвращаем строку "RPOW"
+	case RLOG: // Если код операции равен RLOG
+		return "RLOG" // Возвращаем строку "RLOG"
+	case REXP: // Если код операции равен REXP
+		return "REXP" // Возвращаем строку "REXP"
+	case FLOAD: // Если код операции равен FLOAD
+		return "FLOAD" // Возвращаем строку "FLOAD"
+	case FSTORE: // Если код операции равен FSTORE
+		return "FSTORE" // Возвращаем строку "FSTORE"
+	case FADDR: // Если код операции равен FADDR
+		return "FADDR" // Возвращаем строку "FADDR"
+	case FSUBR: // Если код операции равен FSUBR
+		return "FSUBR" // Возвращаем строку "FSUBR"
+	case FMULR: // Если код операции равен FMULR
+		return "FMULR" // Возвращаем строку "FMULR"
+	case FDIVR: // Если код операции равен FDIVR
+		return "FDIVR" // Возвращаем строку "FDIVR"
+	case FMOVR: // Если код операции равен FMOVR
+		return "FMOVR" // Возвращаем строку "FMOVR"
+	case IRET: // Если код операции равен IRET
+		return "IRET" // Возвращаем строку "IRET"
+	case EI: // Если код операции равен EI
+		return "EI" // Возвращаем строку "EI"
+	case DI: // Если код операции равен DI
+		return "DI" // Возвращаем строку "DI"
+	case INT: // Если код операции равен INT
+		return "INT" // Возвращаем строку "INT"
+	case SETSEG: // Если код операции равен SETSEG
+		return "SETSEG" // Возвращаем строку "SETSEG"
+	case RND: // Если код операции равен RND
+		return "RND" // Возвращаем строку "RND"
+	case TIME: // Если код операции равен TIME
+		return "TIME" // Возвращаем строку "TIME"
+	case SLEEP: // Если код операции равен SLEEP
+		return "SLEEP" // Возвращаем строку "SLEEP"
+	case CIN: // Если код операции равен CIN
+		return "CIN" // Возвращаем строку "CIN"
+	case COUT: // Если код операции равен COUT
+		return "COUT" // Возвращаем строку "COUT"
+	case SOUT: // Если код операции равен SOUT
+		return "SOUT" // Возвращаем строку "SOUT"
+	case SIN: // Если код операции равен SIN
+		return "SIN" // Возвращаем строку "SIN"
+	case MEMCPY: // Если код операции равен MEMCPY
+		return "MEMCPY" // Возвращаем строку "MEMCPY"
+	case MEMSET: // Если код операции равен MEMSET
+		return "MEMSET" // Возвращаем строку "MEMSET"
+	case SCMP: // Если код операции равен SCMP
+		return "SCMP" // Возвращаем строку "SCMP"
+	case CPUID: // Если код операции равен CPUID
+		return "CPUID" // Возвращаем строку "CPUID"
+	case XCHG: // Если код операции равен XCHG
+		return "XCHG" // Возвращаем строку "XCHG"
+	case CAS: // Если код операции равен CAS
+		return "CAS" // Возвращаем строку "CAS"
+	case XADD: // Если код операции равен XADD
+		return "XADD" // Возвращаем строку "XADD"
+	case FENCE: // Если код операции равен FENCE
+		return "FENCE" // Возвращаем строку "FENCE"
+	default: // Обработка случая, если ни один из выше перечисленных случаев не совпадает
+		return "UNKNOWN" // Возвращаем строку "UNKNOWN", если код не распознан
+	}
+}
+
+// ParseOpCode возвращает код операции по мнемонике (без учета регистра)
+func ParseOpCode(name string) (OpCode, bool) {
+	upper := strings.ToUpper(name)
+	for op := 0; op < 256; op++ {
+		if OpCode(op).String() == upper {
+			return OpCode(op), true // Мнемоника найдена
+		}
+	}
+	return 0, false // Мнемоника не распознана
+}
+
+// isJumpOpcode проверяет, является ли код операции переходом по адресу Address1
+func isJumpOpcode(op OpCode) bool {
+	switch op {
+	case GO, JZ, JG, JL, JNE, JGE, JLE, CALL:
+		return true
+	default:
+		return false
+	}
+}
+
+// isPrivilegedOpcode проверяет, доступна ли команда только в режиме супервизора
+func isPrivilegedOpcode(op OpCode) bool {
+	switch op {
+	case STOP, EI, DI, IRET, MAP, SETSEG:
+		return true
+	default:
+		return false
+	}
+}
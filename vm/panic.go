@@ -0,0 +1,37 @@
+package vm
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// InternalError — паника в реализации команды или устройства, перехваченная при
+// выполнении инструкции. Программа останавливается с ошибкой, как при любой
+// другой ошибке выполнения, а хост-процесс продолжает работу.
+type InternalError struct {
+	IP    uint16 // Адрес команды, при выполнении которой произошла паника
+	Value any    // Значение, переданное в panic
+	Stack []byte // Стек вызовов в момент паники
+}
+
+func (e *InternalError) Error() string {
+	return fmt.Sprintf("internal error at 0x%X: %v", e.IP, e.Value)
+}
+
+// Unwrap возвращает значение паники, если оно было ошибкой (например, runtime.Error)
+func (e *InternalError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// protect выполняет команду по адресу currentIP и превращает панику в InternalError
+func (p *Processor) protect(currentIP uint16) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			internal := &InternalError{IP: currentIP, Value: r, Stack: debug.Stack()}
+			p.logf(LogError, "Recovered panic at 0x%X: %v\n%s", currentIP, r, internal.Stack)
+			err = internal
+		}
+	}()
+	return p.executeInstruction(currentIP)
+}
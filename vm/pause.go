@@ -0,0 +1,81 @@
+package vm
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// runControl синхронизирует Run с вызовами Pause и Resume из других горутин
+type runControl struct {
+	requested atomic.Bool // Быстрая проверка запроса паузы в цикле Run
+	mu        sync.Mutex
+	cond      *sync.Cond
+	paused    bool // Запрошена пауза
+	running   bool // Run выполняется
+	parked    bool // Run ожидает Resume между инструкциями
+}
+
+// Pause приостанавливает Run после текущей инструкции и ждет, пока процессор
+// остановится между инструкциями (или Run завершится). После возврата состояние
+// процессора и памяти можно читать из вызывающей горутины до вызова Resume.
+// Run, начатый во время паузы, ждет Resume перед первой инструкцией.
+func (p *Processor) Pause() {
+	c := &p.control
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused = true
+	c.requested.Store(true)
+	for c.running && !c.parked {
+		c.cond.Wait()
+	}
+}
+
+// Resume продолжает выполнение, приостановленное Pause
+func (p *Processor) Resume() {
+	c := &p.control
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused = false
+	c.requested.Store(false)
+	c.cond.Broadcast()
+}
+
+// Paused сообщает, запрошена ли пауза
+func (p *Processor) Paused() bool {
+	return p.control.requested.Load()
+}
+
+// setRunning отмечает начало и конец Run для ожидающих вызовов Pause
+func (c *runControl) setRunning(running bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.running = running
+	c.cond.Broadcast()
+}
+
+// checkpoint вызывается Run между инструкциями и ждет Resume, если запрошена пауза.
+// Отмена или истечение срока ctx прерывает ожидание: возвращается ctx.Err(), а
+// пауза остается запрошенной.
+func (c *runControl) checkpoint(ctx context.Context) error {
+	if !c.requested.Load() {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.paused {
+		stop := context.AfterFunc(ctx, func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.cond.Broadcast() // Будим ожидание, чтобы оно увидело отмену ctx
+		})
+		defer stop()
+	}
+	for c.paused && ctx.Err() == nil {
+		c.parked = true
+		c.cond.Broadcast() // Сообщаем Pause, что процессор остановился
+		c.cond.Wait()
+	}
+	c.parked = false
+	return ctx.Err()
+}
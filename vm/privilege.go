@@ -0,0 +1,35 @@
+package vm
+
+import "fmt"
+
+// UserMode сообщает, выполняется ли программа в режиме пользователя
+func (p *Processor) UserMode() bool {
+	return p.psw.UserMode
+}
+
+// setUserMode переключает уровень привилегий процессора и памяти.
+// Перейти в режим пользователя программа может только командой IRET с установленным
+// битом 0x0100 в сохраненных FLAGS; обратно — через прерывание, ловушку или INT.
+func (p *Processor) setUserMode(user bool) {
+	p.psw.UserMode = user
+	p.memory.userMode = user // Окна устройств недоступны в режиме пользователя
+	p.code.userMode = user
+}
+
+// checkPrivilege возвращает ловушку VECTOR_PRIVILEGE, если привилегированная
+// команда выполняется в режиме пользователя
+func (p *Processor) checkPrivilege(op OpCode) error {
+	if p.psw.UserMode && isPrivilegedOpcode(op) {
+		return &TrapError{Vector: VECTOR_PRIVILEGE, Err: fmt.Errorf("privileged instruction %s in user mode", op)}
+	}
+	return nil
+}
+
+// errDeviceAccess создает ловушку обращения к окну устройства из режима пользователя
+func errDeviceAccess(operation string, address int, r *Region) error {
+	return &TrapError{Vector: VECTOR_PRIVILEGE, Err: &MemoryError{
+		Operation: operation,
+		Address:   address,
+		Message:   fmt.Sprintf("device %q is accessible only in supervisor mode", r.Name),
+	}}
+}
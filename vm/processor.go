@@ -0,0 +1,835 @@
+// Package vm реализует учебную виртуальную машину: память, процессор, набор команд
+// и загрузчик программ. Пакет можно встраивать в другие приложения Go:
+//
+//	p, err := vm.New()
+//	ip, err := vm.LoadProgramFile("program.txt", p.Memory())
+//	p.Reset(ip)
+//	p.Run()
+package vm
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+)
+
+// Number of address registers (a1, a2)
+const NUM_REGISTERS = 2 // Константа, определяющая количество регистров адреса (a1 и a2)
+
+// Number of floating-point registers (f0-f3)
+const NUM_FLOAT_REGISTERS = 4 // Константа, определяющая количество вещественных регистров (f0-f3)
+
+// CommandConstructor function type for creating commands
+type CommandConstructor func(bb uint8, addr1, addr2 uint16) Command // Определение типа функции для создания команд
+
+// Status describes why the processor stopped
+type Status int
+
+const (
+	StatusRunning        Status = iota // Процессор выполняет программу или еще не запускался
+	StatusHalted                       // Программа завершилась командой STOP
+	StatusError                        // Выполнение прервано ошибкой
+	StatusResourceLimit                // Выполнение прервано превышением квоты ресурсов
+	StatusBudgetExceeded               // Исчерпан лимит инструкций (Quotas.MaxInstructions)
+)
+
+// String возвращает строковое представление статуса
+func (s Status) String() string {
+	switch s {
+	case StatusRunning:
+		return "running"
+	case StatusHalted:
+		return "halted"
+	case StatusError:
+		return "error"
+	case StatusResourceLimit:
+		return "resource limit exceeded"
+	case StatusBudgetExceeded:
+		return "budget exceeded"
+	default:
+		return "unknown"
+	}
+}
+
+// PSW represents the Program Status Word
+type PSW struct {
+	IP           uint16 // Указатель на текущую инструкцию (Instruction Pointer)
+	SignFlag     bool   // Флаг знака (отрицательное/положительное значение)
+	CarryFlag    bool   // Флаг переноса (перенос из старшего бита)
+	OverflowFlag bool   // Флаг переполнения (переполнение арифметической операции)
+	ZeroFlag     bool   // Флаг нуля (результат операции равен нулю)
+	SP           uint16 // Указатель стека (адрес последнего помещенного в стек слова)
+
+	InterruptFlag bool // Флаг разрешения аппаратных прерываний
+	UserMode      bool // Режим пользователя; false — режим супервизора
+}
+
+// Processor represents the virtual machine processor
+type Processor struct {
+	In  io.Reader // Ввод команд IIN, RIN, CIN и SIN; по умолчанию os.Stdin
+	Out io.Writer // Вывод команд IOUT, ROUT, COUT, SOUT и приглашений ввода; по умолчанию os.Stdout
+
+	memory       *Memory                       // Указатель на объект памяти виртуальной машины
+	code         *Memory                       // Память команд; совпадает с memory, если гарвардский режим не выбран
+	psw          PSW                           // Программное слово состояния (Program Status Word)
+	registers    [NUM_REGISTERS]int32          // Массив регистров для хранения значений a1 и a2
+	fregisters   [NUM_FLOAT_REGISTERS]float32  // Массив вещественных регистров f0-f3
+	error        bool                          // Флаг, указывающий на наличие ошибки
+	stop         bool                          // Флаг, указывающий на остановку процессора
+	logFile      *os.File                      // Указатель на файл для записи логов выполнения
+	errorLogFile *os.File                      // Указатель на файл для записи логов ошибок
+	log          *Logger                       // Журнал с уровнями по подсистемам
+	commandMap   map[OpCode]CommandConstructor // мапа команд, связывающая коды операций с конструкторами команд
+	status       Status                        // Статус завершения последнего запуска
+	quotas       Quotas                        // Квоты ресурсов на один запуск
+	usage        ResourceUsage                 // Потребление ресурсов за текущий запуск
+	events       chan Event                    // Канал асинхронных событий от хоста
+	pending      []Event                       // Принятые, но еще не обработанные прерывания
+	pendingMu    sync.Mutex                    // Защищает pending: PendingInterrupts читают из других горутин
+	hostCalls    map[uint16]HostCallHandler    // Обработчики гипервызовов, зарегистрированные хостом
+	watches      []*Watch                      // Выражения наблюдения, вычисляемые после каждого шага
+	breakpoints  map[uint16]*breakpoint        // Точки останова по адресам команд
+	nextWatchID  int                           // Последний выданный идентификатор выражения наблюдения
+	callDepth    int                           // Глубина вложенности подпрограмм (CALL увеличивает, RET уменьшает)
+	tickers      []Ticker                      // Устройства, получающие такт после каждой инструкции
+	jumped       bool                          // Флаг, указывающий, что текущая команда изменила IP
+	fetched      CommandData                   // Последняя выбранная команда
+	current      int                           // Адрес выбранной и выполняемой команды; -1 вне команды
+	stackBase    int                           // Корень стека: SP пустого стека
+	stackLimit   int                           // Наименьший адрес, доступный стеку
+	exitCode     int32                         // Код завершения, заданный командой STOP
+	runErr       error                         // Ошибка, остановившая последний запуск
+	vectorBase   int                           // Адрес таблицы векторов прерываний
+	debugInfo    *DebugInfo                    // Отладочная информация загруженной программы
+
+	invalidOpcode InvalidOpcodePolicy // Реакция на недопустимую команду
+
+	control runControl // Пауза и продолжение Run из других горутин
+	history *history   // История инструкций для StepBack; nil — не записывается
+
+	recording   *Recording   // Запись недетерминированного ввода; nil — ввод не записывается
+	trace       *tracer      // Двоичная трасса выполнения; nil — трасса не пишется
+	traceFilter *TraceFilter // Фильтр журнала команд и трассы; nil — все команды
+	onStep      StepHandler  // Получатель событий о выполненных командах; nil — события не создаются
+
+	checkInvariants bool        // Проверять инварианты машины после каждой команды
+	lastResult      flagResult  // Результат, по которому текущая команда установила флаги
+	heatmap         *Heatmap    // Счетчики тепловой карты; nil — не считаются
+	cache           *Cache      // Модель кэша данных; nil — выключена
+	coverage        *coverage   // Покрытие команд; nil — не учитывается
+	stats           Stats       // Переходы и время выполнения для Stats
+	memoryBase      MemoryStats // Счетчики памяти данных на момент Reset
+
+	seed   int64           // Начальное значение генератора псевдослучайных чисел
+	random *countingSource // Источник генератора, считающий выданные значения
+	rng    *rand.Rand      // Генератор псевдослучайных чисел команды RND
+
+	virtualClock bool      // Время команды TIME вычисляется по числу инструкций
+	sleptMillis  int64     // Виртуальные миллисекунды, добавленные командой SLEEP
+	startTime    time.Time // Момент запуска программы по часам хоста
+
+	coreID  int     // Номер ядра, возвращаемый CPUID; 0 — первое ядро
+	shared  bool    // Память принадлежит первому ядру и не закрывается Close
+	segment Segment // Сегмент данных ядра, пока общую память использует другое ядро
+}
+
+// Файлы журналов по умолчанию в рабочем каталоге
+const (
+	DEFAULT_EXECUTION_LOG = "vm_execution.log"
+	DEFAULT_ERROR_LOG     = "vm_error.log"
+)
+
+// Options — параметры создания процессора. Нулевое значение соответствует New:
+// журналы пишутся в файлы по умолчанию в рабочем каталоге.
+type Options struct {
+	Harvard bool // Отдельная память команд, как у NewHarvard
+
+	LogOutput    io.Writer // Получатель журнала выполнения вместо файла
+	LogFile      string    // Файл журнала выполнения (перезаписывается); "" — DEFAULT_EXECUTION_LOG
+	Quiet        bool      // Журнал выполнения не ведется, и его файл не создается
+	ErrorOutput  io.Writer // Получатель журнала ошибок вместо файла; io.Discard выключает журнал
+	ErrorLogFile string    // Файл журнала ошибок (дополняется); "" — DEFAULT_ERROR_LOG
+
+	In  io.Reader // Ввод команд ввода; nil — os.Stdin
+	Out io.Writer // Вывод команд вывода; nil — os.Stdout
+}
+
+// New creates a new Processor instance
+func New() (*Processor, error) {
+	return NewWithOptions(Options{})
+}
+
+// NewWithOptions создает процессор с параметрами options. Файлы журналов
+// открываются только для журналов, получатель которых не задан и которые не выключены.
+func NewWithOptions(options Options) (*Processor, error) {
+	var out, errOut io.Writer
+	var logFile, errorLogFile *os.File
+	var err error
+	switch {
+	case options.Quiet: // Журнал выполнения выключен: файловая система не затрагивается
+	case options.LogOutput != nil:
+		out = options.LogOutput
+	default:
+		// Открываем файл для записи логов выполнения с флагами создания, записи и обрезки файла
+		logFile, err = os.OpenFile(cmp.Or(options.LogFile, DEFAULT_EXECUTION_LOG), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open execution log: %v", err) // Возвращаем ошибку, если не удалось открыть файл лога
+		}
+		out = logFile
+	}
+
+	if options.ErrorOutput != nil {
+		if options.ErrorOutput != io.Discard {
+			errOut = options.ErrorOutput
+		}
+	} else {
+		// Открываем файл для записи логов ошибок с флагами создания, записи и добавления в конец файла
+		errorLogFile, err = os.OpenFile(cmp.Or(options.ErrorLogFile, DEFAULT_ERROR_LOG), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			if logFile != nil {
+				logFile.Close() // Закрываем файл логов выполнения в случае ошибки
+			}
+			return nil, fmt.Errorf("failed to open error log: %v", err) // Возвращаем ошибку при неудачном открытии файла лога ошибок
+		}
+		errOut = errorLogFile
+	}
+
+	// Создаем новый экземпляр процессора с инициализацией памяти и логирования
+	p := &Processor{
+		memory:       NewMemory(65536),                    // Инициализация памяти размером 65536 байт
+		log:          NewLogger(out, errOut),              // Журнал выполнения и журнал ошибок
+		logFile:      logFile,                             // Сохранение указателя на файл логов выполнения
+		errorLogFile: errorLogFile,                        // Сохранение указателя на файл логов ошибок
+		commandMap:   make(map[OpCode]CommandConstructor), // Инициализация мапы команд
+		events:       make(chan Event, EVENT_QUEUE_SIZE),  // Инициализация канала событий
+		hostCalls:    make(map[uint16]HostCallHandler),    // Инициализация таблицы гипервызовов
+	}
+	p.memory.log = p.log // Память пишет в журнал процессора
+	p.In, p.Out = options.In, options.Out
+	if p.In == nil {
+		p.In = os.Stdin // Команды ввода читают стандартный ввод
+	}
+	if p.Out == nil {
+		p.Out = os.Stdout // Команды вывода пишут в стандартный вывод
+	}
+	p.log.context = p.logContext
+	p.log.accept = p.logAccepts
+	p.log.SetAsync(true) // Запись журнала в файлы не задерживает выполнение команд
+	p.current = -1
+
+	p.code = p.memory // По умолчанию команды и данные находятся в общей памяти
+	p.control.cond = sync.NewCond(&p.control.mu)
+
+	// Стек растет вниз от последнего слова памяти
+	p.stackBase = p.memory.WordLimit() - 1
+	p.vectorBase = VECTOR_TABLE_BASE // Таблица векторов по умолчанию
+	p.SetSeed(DEFAULT_SEED)          // Детерминированная последовательность RND по умолчанию
+	p.psw.SP = uint16(p.stackBase)
+
+	// Инициализация мапы команд
+	p.initializeCommandMap()
+	if options.Harvard {
+		p.code = NewMemory(65536) // Отдельная память команд того же размера
+		p.code.log = p.log
+	}
+	return p, nil // Возвращаем указатель на созданный процессор и nil (без ошибок)
+}
+
+// NewHarvard создает процессор гарвардской архитектуры: команды выбираются из
+// отдельной памяти команд (CodeMemory), а команды LOAD/STORE и арифметика работают
+// с памятью данных (Memory). Запись данных не может повредить программу.
+func NewHarvard() (*Processor, error) {
+	return NewWithOptions(Options{Harvard: true})
+}
+
+// IsHarvard сообщает, разделены ли память команд и память данных
+func (p *Processor) IsHarvard() bool {
+	return p.code != p.memory
+}
+
+// Run выполняет программу до остановки или точки останова. Возвращает nil после STOP,
+// *BreakpointHit перед командой с точкой останова (кроме первой команды запуска,
+// чтобы повторный Run продолжил выполнение) или ошибку, остановившую программу.
+func (p *Processor) Run() error {
+	return p.RunContext(context.Background())
+}
+
+// RunContext выполняет программу как Run, проверяя ctx между инструкциями. При отмене
+// или истечении срока ctx, в том числе во время паузы, выполнение прекращается перед
+// очередной инструкцией и возвращается ctx.Err(); программа не считается завершенной, и повторный вызов
+// продолжает ее с той же команды.
+func (p *Processor) RunContext(ctx context.Context) error {
+	p.logf(LogInfo, "Starting program execution") // Логируем начало выполнения программы
+	p.control.setRunning(true)
+	defer p.control.setRunning(false)
+	started := time.Now()
+	defer func() { p.stats.WallTime += time.Since(started) }()
+	// Цикл выполнения программы до тех пор, пока не будет установлена остановка или ошибка
+	for first := true; !p.stop && !p.error; first = false {
+		if err := p.interruption(ctx); err != nil {
+			return err
+		}
+		if !first && p.ShouldBreak(p.psw.IP) {
+			p.logf(LogInfo, "Breakpoint at 0x%X", p.psw.IP)
+			return &BreakpointHit{Address: p.psw.IP, Condition: p.BreakpointCondition(p.psw.IP)}
+		}
+		// Выполняем следующую инструкцию и проверяем на наличие ошибки
+		if err := p.step(); err != nil {
+			return err // Выходим из цикла
+		}
+	}
+	return nil
+}
+
+// interruption вызывается между инструкциями: ждет Resume, если другая горутина
+// вызвала Pause, и возвращает ctx.Err() при отмене или истечении срока ctx, в том
+// числе во время паузы
+func (p *Processor) interruption(ctx context.Context) error {
+	err := p.control.checkpoint(ctx)
+	if err == nil {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		default:
+		}
+	}
+	if err != nil {
+		p.logf(LogInfo, "Execution interrupted at 0x%X: %v", p.psw.IP, err)
+	}
+	return err
+}
+
+// step выполняет одну инструкцию, обрабатывает ошибку выполнения и обновляет статус
+func (p *Processor) step() error {
+	if err := p.chargeInstruction(); err != nil { // Бесконечный цикл останавливается по лимиту инструкций
+		p.logf(LogError, "Guest halted: %v", err)
+		p.status = StatusBudgetExceeded
+		p.stop = true
+		p.runErr = err
+		return err
+	}
+	p.beginUndo()
+	defer p.endUndo()
+	if err := p.executeNextInstruction(); err != nil {
+		var limitErr *ResourceLimitError
+		if errors.As(err, &limitErr) { // Превышение квоты останавливает гостя с отдельным статусом
+			p.logf(LogError, "Guest halted: %v", err)
+			p.status = StatusResourceLimit
+			p.stop = true
+			p.runErr = err // Запоминаем ошибку для Err и отчета о сбое
+			return err
+		}
+		p.logf(LogError, "Error executing instruction: %v", err) // Логируем ошибку выполнения инструкции
+		p.error = true                                           // Устанавливаем флаг ошибки
+		p.status = StatusError                                   // Запоминаем статус ошибки
+		p.runErr = err                                           // Запоминаем ошибку для Err
+		if p.log.Enabled(SubsystemProcessor, LogError) {         // Отчет не строится, если журнал ошибок выключен
+			p.logf(LogError, "Crash report:\n%s", p.CrashReport())
+		}
+		return err
+	}
+	p.usage.Instructions++ // Считаем выполненную инструкцию для команды TIME
+	if p.jumped {
+		p.stats.Branches++ // Команда передала управление
+	}
+	p.tickDevices()   // Передаем такт таймерам и другим устройствам
+	p.updateWatches() // Пересчитываем выражения наблюдения после шага
+	if p.stop && p.status == StatusRunning {
+		p.status = StatusHalted // Программа завершилась штатно
+	}
+	return nil
+}
+
+// PSW возвращает копию слова состояния процессора
+func (p *Processor) PSW() PSW {
+	return p.psw
+}
+
+// Err возвращает ошибку, остановившую последний запуск, или nil
+func (p *Processor) Err() error {
+	return p.runErr
+}
+
+// ExitCode возвращает код завершения, заданный программой в команде STOP
+func (p *Processor) ExitCode() int32 {
+	return p.exitCode
+}
+
+// Status возвращает статус завершения последнего запуска
+func (p *Processor) Status() Status {
+	return p.status
+}
+
+func (p *Processor) executeNextInstruction() error {
+	// Принимаем события, доставленные хостом с момента предыдущей инструкции
+	if err := p.pollEvents(); err != nil {
+		return err
+	}
+	// Передаем управление обработчику ожидающего прерывания, если прерывания разрешены
+	if err := p.dispatchInterrupts(); err != nil {
+		return err
+	}
+
+	currentIP := p.psw.IP // Получаем текущий адрес инструкций
+	flags := p.psw.traceFlags()
+	var before registerState
+	if p.onStep != nil {
+		before = p.registerState() // Изменения регистров вычисляются только для получателя событий
+	}
+	if p.heatmap != nil && int(currentIP) < len(p.heatmap.Executions) {
+		p.heatmap.Executions[currentIP]++
+	}
+	if p.coverage != nil && int(currentIP) < len(p.coverage.executed) {
+		p.coverage.executed[currentIP] = true
+	}
+	p.lastResult = flagResult{}
+	err := p.protect(currentIP) // Паника в реализации команды становится ошибкой выполнения
+	if err == nil && p.checkInvariants {
+		if invErr := p.invariants(currentIP); invErr != nil {
+			err = invErr // Нарушение останавливает программу как ошибка выполнения
+		}
+	}
+	if p.trace != nil && (err != nil || p.traceFilter.Match(currentIP, OpCode(p.fetched.Opcode))) {
+		r := TraceRecord{IP: currentIP, Cmd: p.fetched, FlagsBefore: flags, FlagsAfter: p.psw.traceFlags()}
+		if err != nil {
+			r.FlagsAfter |= TRACE_FAULT
+		}
+		p.trace.record(r)
+	}
+	if err != nil {
+		err = p.sourceError(int(currentIP), err) // Дополняем ошибку местом в исходном тексте
+	}
+	if p.onStep != nil {
+		p.onStep(StepEvent{IP: currentIP, Cmd: p.fetched, Changes: before.changes(p.registerState()), Err: err})
+	}
+	return err
+}
+
+// executeInstruction выбирает и выполняет команду по адресу currentIP
+func (p *Processor) executeInstruction(currentIP uint16) error {
+	// Проверяем, является ли текущий адрес допустимым
+	if !p.code.IsValidAddress(int(currentIP)) {
+		return fmt.Errorf("invalid instruction pointer: 0x%X", currentIP) // Возвращаем ошибку с недопустимым адресом
+	}
+
+	p.fetched = CommandData{}
+	word, err := p.code.FetchWord(int(currentIP)) // Выбираем команду из памяти команд с проверкой права на выполнение
+	if err != nil {
+		if handled, trapErr := p.handleTrap(err, currentIP); handled {
+			return trapErr // Отказ страницы при выборке передан обработчику программы
+		}
+		return fmt.Errorf("failed to read instruction: %w", err) // Возвращаем ошибку при чтении инструкции
+	}
+
+	p.jumped = false           // Сбрасываем флаг перехода перед выполнением команды
+	p.fetched = word.Cmd       // Запоминаем команду для Step и трассы
+	p.current = int(currentIP) // События журнала относятся к этой команде
+	defer func() { p.current = -1 }()
+
+	// Слово данных не может быть выполнено как команда
+	if !word.IsCommand() {
+		return p.invalidInstruction(currentIP, fmt.Errorf("word at 0x%X is data, not an instruction", currentIP))
+	}
+
+	// Проверяем, существует ли конструктор для данной операции в мапе команд
+	if constructor, exists := p.commandMap[OpCode(word.Cmd.Opcode)]; exists {
+		cmd := constructor(word.Cmd.BB, word.Cmd.Address1, word.Cmd.Address2) // Создаем команду на основе прочитанного слова
+		err := p.checkPrivilege(OpCode(word.Cmd.Opcode))                      // Привилегированные команды недоступны в режиме пользователя
+		if err == nil {
+			err = cmd.Execute(p)
+		}
+		if err != nil {
+			if handled, trapErr := p.handleTrap(err, currentIP); handled {
+				return trapErr // Ловушка передана обработчику программы
+			}
+			return fmt.Errorf("error executing instruction at 0x%X: %w", currentIP, err) // Возвращаем ошибку выполнения команды
+		}
+	} else {
+		return p.invalidInstruction(currentIP, fmt.Errorf("invalid opcode at 0x%X: %d", currentIP, word.Cmd.Opcode)) // Обрабатываем недопустимый код операции согласно политике
+	}
+
+	// Проверяем, была ли выполнена команда STOP
+	if word.Cmd.Opcode == uint8(STOP) {
+		p.stop = true // Устанавливаем флаг остановки
+	} else if !p.jumped {
+		// Обновляем указатель инструкций для следующей команды с учетом размера памяти
+		p.psw.IP = uint16((int(currentIP) + WORD_SIZE) % p.code.Size())
+	}
+
+	return nil // Возвращаем nil, если ошибок не было
+}
+
+// извлекает значение регистра по его индексу
+func (p *Processor) GetRegister(index uint8) (int32, error) {
+	// Проверяем, что индекс находится в допустимом диапазоне
+	if index >= NUM_REGISTERS {
+		return 0, fmt.Errorf("invalid register index: %d", index) // Возвращаем ошибку, если индекс недействителен
+	}
+	return p.registers[index], nil // Возвращаем значение регистра и nil (без ошибок)
+}
+
+// устанавливает значение регистра по его индексу
+func (p *Processor) SetRegister(index uint8, value int32) error {
+	// Проверяем, что индекс находится в допустимом диапазоне
+	if index >= NUM_REGISTERS {
+		return fmt.Errorf("invalid register index: %d", index) // Возвращаем ошибку, если индекс недействителен
+	}
+	p.registers[index] = value // Устанавливаем значение регистра по указанному индексу
+	return nil                 // Возвращаем nil (без ошибок)
+}
+
+// GetFloatRegister извлекает значение вещественного регистра по его индексу
+func (p *Processor) GetFloatRegister(index uint8) (float32, error) {
+	if index >= NUM_FLOAT_REGISTERS {
+		return 0, fmt.Errorf("invalid float register index: %d", index) // Возвращаем ошибку, если индекс недействителен
+	}
+	return p.fregisters[index], nil // Возвращаем значение регистра и nil (без ошибок)
+}
+
+// SetFloatRegister устанавливает значение вещественного регистра по его индексу
+func (p *Processor) SetFloatRegister(index uint8, value float32) error {
+	if index >= NUM_FLOAT_REGISTERS {
+		return fmt.Errorf("invalid float register index: %d", index) // Возвращаем ошибку, если индекс недействителен
+	}
+	p.fregisters[index] = value // Устанавливаем значение регистра по указанному индексу
+	return nil                  // Возвращаем nil (без ошибок)
+}
+
+// jumpTo передает управление по адресу; IP не увеличивается после такой команды
+func (p *Processor) jumpTo(address uint16) {
+	p.psw.IP = address // Устанавливаем новый указатель инструкций
+	p.jumped = true    // Отмечаем, что команда изменила IP
+}
+
+// флаг знака
+func (p *Processor) SetSignFlag(negative bool) {
+	p.psw.SignFlag = negative // Устанавливаем флаг знака в соответствии с переданным значением
+}
+
+// флаг переноса
+func (p *Processor) SetCarryFlag(carry bool) {
+	p.psw.CarryFlag = carry // Устанавливаем флаг переноса в соответствии с переданным значением
+}
+
+// флаг переполнения
+func (p *Processor) SetOverflowFlag(overflow bool) {
+	p.psw.OverflowFlag = overflow // Устанавливаем флаг переполнения в соответствии с переданным значением
+}
+
+// флаг нуля
+func (p *Processor) SetZeroFlag(zero bool) {
+	p.psw.ZeroFlag = zero // Устанавливаем флаг нуля в соответствии с переданным значением
+}
+
+func (p *Processor) UpdateArithmeticFlags(result int32, hasCarry, hasOverflow bool) {
+	p.SetSignFlag(result < 0)      // Устанавливаем флаг знака в зависимости от результата операции
+	p.SetZeroFlag(result == 0)     // Устанавливаем флаг нуля в зависимости от результата операции
+	p.SetCarryFlag(hasCarry)       // Устанавливаем флаг переноса в зависимости от наличия переноса
+	p.SetOverflowFlag(hasOverflow) // Устанавливаем флаг переполнения в зависимости от наличия переполнения
+	p.recordFlagResult(result == 0, result < 0)
+}
+
+func (p *Processor) UpdateFloatFlags(result float32) {
+	// Устанавливаем флаг знака в зависимости от того, отрицательный ли результат
+	p.SetSignFlag(result < 0)
+	// Устанавливаем флаг нуля, если результат равен нулю
+	p.SetZeroFlag(result == 0)
+	// Для операций с плавающей точкой флаги переноса и переполнения не имеют смысла
+	p.SetCarryFlag(false)
+	p.SetOverflowFlag(false)
+	p.recordFlagResult(result == 0, result < 0)
+}
+
+func (p *Processor) GetFlags() uint16 {
+	var flags uint16 // Объявляем переменную для хранения флагов
+	// Проверяем, установлен ли флаг знака, и если да, устанавливаем соответствующий бит в переменной flags
+	if p.psw.SignFlag {
+		flags |= 0x8000
+	}
+	// Проверяем, установлен ли флаг переполнения
+	if p.psw.OverflowFlag {
+		flags |= 0x0800
+	}
+	// Проверяем, установлен ли флаг нуля
+	if p.psw.ZeroFlag {
+		flags |= 0x0400
+	}
+	// Проверяем, разрешены ли прерывания
+	if p.psw.InterruptFlag {
+		flags |= 0x0200
+	}
+	// Проверяем, выполняется ли программа в режиме пользователя
+	if p.psw.UserMode {
+		flags |= 0x0100
+	}
+	// Проверяем, установлен ли флаг переноса
+	if p.psw.CarryFlag {
+		flags |= 0x0001
+	}
+	return flags // Возвращаем значение переменной flags
+}
+
+func (p *Processor) SetFlags(flags uint16) {
+	// Устанавливаем флаг знака на основе старшего бита переменной flags
+	p.psw.SignFlag = (flags & 0x8000) != 0
+	// Устанавливаем флаг переполнения на основе второго старшего бита
+	p.psw.OverflowFlag = (flags & 0x0800) != 0
+	// Устанавливаем флаг нуля на основе третьего старшего бита
+	p.psw.ZeroFlag = (flags & 0x0400) != 0
+	// Устанавливаем флаг разрешения прерываний
+	p.psw.InterruptFlag = (flags & 0x0200) != 0
+	// Устанавливаем уровень привилегий
+	p.setUserMode((flags & 0x0100) != 0)
+	// Устанавливаем флаг переноса на основе младшего бита
+	p.psw.CarryFlag = (flags & 0x0001) != 0
+}
+
+// SetStrictAlignment включает режим, в котором невыровненный доступ к слову
+// останавливает программу с ошибкой AlignmentError
+func (p *Processor) SetStrictAlignment(enabled bool) {
+	p.memory.SetStrictAlignment(enabled)
+	p.code.SetStrictAlignment(enabled)
+}
+
+func (p *Processor) initializeCommandMap() {
+	// Инициализируем команду STOP в мапе команд
+	p.commandMap[STOP] = func(bb uint8, addr1, addr2 uint16) Command { return NewHalt(bb, addr1, addr2) }
+	// Инициализируем команду IADD в мапе команд
+	p.commandMap[IADD] = func(bb uint8, addr1, addr2 uint16) Command { return NewAddInt(bb, addr1, addr2) }
+	// Инициализируем команду ISUB в мапе команд
+	p.commandMap[ISUB] = func(bb uint8, addr1, addr2 uint16) Command { return NewSubInt(bb, addr1, addr2) }
+	// Инициализируем команду IMUL в мапе команд
+	p.commandMap[IMUL] = func(bb uint8, addr1, addr2 uint16) Command { return NewMulInt(bb, addr1, addr2) }
+	// Инициализируем команду IDIV в мапе команд
+	p.commandMap[IDIV] = func(bb uint8, addr1, addr2 uint16) Command { return NewDivInt(bb, addr1, addr2) }
+	// Инициализируем команду IMOD в мапе команд
+	p.commandMap[IMOD] = func(bb uint8, addr1, addr2 uint16) Command { return NewModInt(bb, addr1, addr2) }
+	// Инициализируем команду DIVMOD в мапе команд
+	p.commandMap[DIVMOD] = func(bb uint8, addr1, addr2 uint16) Command { return NewDivModInt(bb, addr1, addr2) }
+	// Инициализируем команду INC в мапе команд
+	p.commandMap[INC] = func(bb uint8, addr1, addr2 uint16) Command { return NewIncrementInt(bb, addr1, addr2) }
+	// Инициализируем команду DEC в мапе команд
+	p.commandMap[DEC] = func(bb uint8, addr1, addr2 uint16) Command { return NewDecrementInt(bb, addr1, addr2) }
+	// Инициализируем команду NEG в мапе команд
+	p.commandMap[NEG] = func(bb uint8, addr1, addr2 uint16) Command { return NewNegateInt(bb, addr1, addr2) }
+	// Инициализируем команду INCR в мапе команд
+	p.commandMap[INCR] = func(bb uint8, addr1, addr2 uint16) Command { return NewIncrementRegister(bb, addr1, addr2) }
+	// Инициализируем команду DECR в мапе команд
+	p.commandMap[DECR] = func(bb uint8, addr1, addr2 uint16) Command { return NewDecrementRegister(bb, addr1, addr2) }
+	// Инициализируем команду NEGR в мапе команд
+	p.commandMap[NEGR] = func(bb uint8, addr1, addr2 uint16) Command { return NewNegateRegister(bb, addr1, addr2) }
+	// Инициализируем команду CMP в мапе команд
+	p.commandMap[CMP] = func(bb uint8, addr1, addr2 uint16) Command { return NewCompareInt(bb, addr1, addr2) }
+	// Инициализируем команду IIN в мапе команд
+	p.commandMap[IIN] = func(bb uint8, addr1, addr2 uint16) Command { return NewInputInt(bb, addr1, addr2) }
+	// Инициализируем команду IOUT в мапе команд
+	p.commandMap[IOUT] = func(bb uint8, addr1, addr2 uint16) Command { return NewOutputInt(bb, addr1, addr2) }
+	// Инициализируем команду RADD в мапе команд
+	p.commandMap[RADD] = func(bb uint8, addr1, addr2 uint16) Command { return NewAddFloat(bb, addr1, addr2) }
+	// Инициализируем команду RSUB в мапе команд
+	p.commandMap[RSUB] = func(bb uint8, addr1, addr2 uint16) Command { return NewSubFloat(bb, addr1, addr2) }
+	// Инициализируем команду RMUL в мапе команд
+	p.commandMap[RMUL] = func(bb uint8, addr1, addr2 uint16) Command { return NewMulFloat(bb, addr1, addr2) }
+	// Инициализируем команду RDIV в мапе команд
+	p.commandMap[RDIV] = func(bb uint8, addr1, addr2 uint16) Command { return NewDivFloat(bb, addr1, addr2) }
+	// Инициализируем команду RCMP в мапе команд
+	p.commandMap[RCMP] = func(bb uint8, addr1, addr2 uint16) Command { return NewCompareFloat(bb, addr1, addr2) }
+	// Инициализируем команду RIN в мапе команд
+	p.commandMap[RIN] = func(bb uint8, addr1, addr2 uint16) Command { return NewInputFloat(bb, addr1, addr2) }
+	// Инициализируем команду ROUT в мапе команд
+	p.commandMap[ROUT] = func(bb uint8, addr1, addr2 uint16) Command { return NewOutputFloat(bb, addr1, addr2) }
+	// Инициализируем команду GO в мапе команд
+	p.commandMap[GO] = func(bb uint8, addr1, addr2 uint16) Command { return NewJump(bb, addr1, addr2) }
+	// Инициализируем команду JZ в мапе команд
+	p.commandMap[JZ] = func(bb uint8, addr1, addr2 uint16) Command { return NewJumpZero(bb, addr1, addr2) }
+	// Инициализируем команду JG в мапе команд
+	p.commandMap[JG] = func(bb uint8, addr1, addr2 uint16) Command { return NewJumpGreater(bb, addr1, addr2) }
+	// Инициализируем команду JL в мапе команд
+	p.commandMap[JL] = func(bb uint8, addr1, addr2 uint16) Command { return NewJumpLess(bb, addr1, addr2) }
+	// Инициализируем команду JNE в мапе команд
+	p.commandMap[JNE] = func(bb uint8, addr1, addr2 uint16) Command { return NewJumpNotZero(bb, addr1, addr2) }
+	// Инициализируем команду JGE в мапе команд
+	p.commandMap[JGE] = func(bb uint8, addr1, addr2 uint16) Command { return NewJumpGreaterEqual(bb, addr1, addr2) }
+	// Инициализируем команду JLE в мапе команд
+	p.commandMap[JLE] = func(bb uint8, addr1, addr2 uint16) Command { return NewJumpLessEqual(bb, addr1, addr2) }
+	// Инициализируем команду AND в мапе команд
+	p.commandMap[AND] = func(bb uint8, addr1, addr2 uint16) Command { return NewBitwiseAnd(bb, addr1, addr2) }
+	// Инициализируем команду OR в мапе команд
+	p.commandMap[OR] = func(bb uint8, addr1, addr2 uint16) Command { return NewBitwiseOr(bb, addr1, addr2) }
+	// Инициализируем команду XOR в мапе команд
+	p.commandMap[XOR] = func(bb uint8, addr1, addr2 uint16) Command { return NewBitwiseXor(bb, addr1, addr2) }
+	// Инициализируем команду NOT в мапе команд
+	p.commandMap[NOT] = func(bb uint8, addr1, addr2 uint16) Command { return NewBitwiseNot(bb, addr1, addr2) }
+	// Инициализируем команду LOAD в мапе команд
+	p.commandMap[LOAD] = func(bb uint8, addr1, addr2 uint16) Command { return NewLoadRegister(bb, addr1, addr2) }
+	// Инициализируем команду STORE в мапе команд
+	p.commandMap[STORE] = func(bb uint8, addr1, addr2 uint16) Command { return NewStoreRegister(bb, addr1, addr2) }
+	// Инициализируем команду ADDR в мапе команд
+	p.commandMap[ADDR] = func(bb uint8, addr1, addr2 uint16) Command { return NewAddRegisters(bb, addr1, addr2) }
+	// Инициализируем команду SUBR в мапе команд
+	p.commandMap[SUBR] = func(bb uint8, addr1, addr2 uint16) Command { return NewSubtractRegisters(bb, addr1, addr2) }
+	// Инициализируем команду CALL в мапе команд
+	p.commandMap[CALL] = func(bb uint8, addr1, addr2 uint16) Command { return NewCallSubroutine(bb, addr1, addr2) }
+	// Инициализируем команду RET в мапе команд
+	p.commandMap[RET] = func(bb uint8, addr1, addr2 uint16) Command { return NewReturn(bb, addr1, addr2) }
+	// Инициализируем команду MOVR в мапе команд
+	p.commandMap[MOVR] = func(bb uint8, addr1, addr2 uint16) Command { return NewMoveRegister(bb, addr1, addr2) }
+	// Инициализируем команду HCALL в мапе команд
+	p.commandMap[HCALL] = func(bb uint8, addr1, addr2 uint16) Command { return NewHostCall(bb, addr1, addr2) }
+	// Инициализируем команду MAP в мапе команд
+	p.commandMap[MAP] = func(bb uint8, addr1, addr2 uint16) Command { return NewMapRegion(bb, addr1, addr2) }
+	// Инициализируем команду PUSH в мапе команд
+	p.commandMap[PUSH] = func(bb uint8, addr1, addr2 uint16) Command { return NewPushMemory(bb, addr1, addr2) }
+	// Инициализируем команду POP в мапе команд
+	p.commandMap[POP] = func(bb uint8, addr1, addr2 uint16) Command { return NewPopMemory(bb, addr1, addr2) }
+	// Инициализируем команду PUSHR в мапе команд
+	p.commandMap[PUSHR] = func(bb uint8, addr1, addr2 uint16) Command { return NewPushRegister(bb, addr1, addr2) }
+	// Инициализируем команду POPR в мапе команд
+	p.commandMap[POPR] = func(bb uint8, addr1, addr2 uint16) Command { return NewPopRegister(bb, addr1, addr2) }
+	// Инициализируем команду ITOF в мапе команд
+	p.commandMap[ITOF] = func(bb uint8, addr1, addr2 uint16) Command { return NewIntToFloat(bb, addr1, addr2) }
+	// Инициализируем команду FTOI в мапе команд
+	p.commandMap[FTOI] = func(bb uint8, addr1, addr2 uint16) Command { return NewFloatToInt(bb, addr1, addr2) }
+	// Инициализируем команду RSQRT в мапе команд
+	p.commandMap[RSQRT] = func(bb uint8, addr1, addr2 uint16) Command { return NewSqrtFloat(bb, addr1, addr2) }
+	// Инициализируем команду RPOW в мапе команд
+	p.commandMap[RPOW] = func(bb uint8, addr1, addr2 uint16) Command { return NewPowFloat(bb, addr1, addr2) }
+	// Инициализируем команду RLOG в мапе команд
+	p.commandMap[RLOG] = func(bb uint8, addr1, addr2 uint16) Command { return NewLogFloat(bb, addr1, addr2) }
+	// Инициализируем команду REXP в мапе команд
+	p.commandMap[REXP] = func(bb uint8, addr1, addr2 uint16) Command { return NewExpFloat(bb, addr1, addr2) }
+	// Инициализируем команду FLOAD в мапе команд
+	p.commandMap[FLOAD] = func(bb uint8, addr1, addr2 uint16) Command { return NewLoadFloatRegister(bb, addr1, addr2) }
+	// Инициализируем команду FSTORE в мапе команд
+	p.commandMap[FSTORE] = func(bb uint8, addr1, addr2 uint16) Command { return NewStoreFloatRegister(bb, addr1, addr2) }
+	// Инициализируем команду FADDR в мапе команд
+	p.commandMap[FADDR] = func(bb uint8, addr1, addr2 uint16) Command { return NewAddFloatRegisters(bb, addr1, addr2) }
+	// Инициализируем команду FSUBR в мапе команд
+	p.commandMap[FSUBR] = func(bb uint8, addr1, addr2 uint16) Command { return NewSubFloatRegisters(bb, addr1, addr2) }
+	// Инициализируем команду FMULR в мапе команд
+	p.commandMap[FMULR] = func(bb uint8, addr1, addr2 uint16) Command { return NewMulFloatRegisters(bb, addr1, addr2) }
+	// Инициализируем команду FDIVR в мапе команд
+	p.commandMap[FDIVR] = func(bb uint8, addr1, addr2 uint16) Command { return NewDivFloatRegisters(bb, addr1, addr2) }
+	// Инициализируем команду FMOVR в мапе команд
+	p.commandMap[FMOVR] = func(bb uint8, addr1, addr2 uint16) Command { return NewMoveFloatRegister(bb, addr1, addr2) }
+	// Инициализируем команду IRET в мапе команд
+	p.commandMap[IRET] = func(bb uint8, addr1, addr2 uint16) Command { return NewInterruptReturn(bb, addr1, addr2) }
+	// Инициализируем команду EI в мапе команд
+	p.commandMap[EI] = func(bb uint8, addr1, addr2 uint16) Command { return NewEnableInterrupts(bb, addr1, addr2) }
+	// Инициализируем команду DI в мапе команд
+	p.commandMap[DI] = func(bb uint8, addr1, addr2 uint16) Command { return NewDisableInterrupts(bb, addr1, addr2) }
+	// Инициализируем команду INT в мапе команд
+	p.commandMap[INT] = func(bb uint8, addr1, addr2 uint16) Command { return NewSoftwareInterrupt(bb, addr1, addr2) }
+	// Инициализируем команду SETSEG в мапе команд
+	p.commandMap[SETSEG] = func(bb uint8, addr1, addr2 uint16) Command { return NewLoadSegment(bb, addr1, addr2) }
+	// Инициализируем команду RND в мапе команд
+	p.commandMap[RND] = func(bb uint8, addr1, addr2 uint16) Command { return NewRandomNumber(bb, addr1, addr2) }
+	// Инициализируем команду TIME в мапе команд
+	p.commandMap[TIME] = func(bb uint8, addr1, addr2 uint16) Command { return NewReadClock(bb, addr1, addr2) }
+	// Инициализируем команду SLEEP в мапе команд
+	p.commandMap[SLEEP] = func(bb uint8, addr1, addr2 uint16) Command { return NewSleep(bb, addr1, addr2) }
+	// Инициализируем команду CIN в мапе команд
+	p.commandMap[CIN] = func(bb uint8, addr1, addr2 uint16) Command { return NewInputChar(bb, addr1, addr2) }
+	// Инициализируем команду COUT в мапе команд
+	p.commandMap[COUT] = func(bb uint8, addr1, addr2 uint16) Command { return NewOutputChar(bb, addr1, addr2) }
+	// Инициализируем команду SOUT в мапе команд
+	p.commandMap[SOUT] = func(bb uint8, addr1, addr2 uint16) Command { return NewOutputString(bb, addr1, addr2) }
+	// Инициализируем команду SIN в мапе команд
+	p.commandMap[SIN] = func(bb uint8, addr1, addr2 uint16) Command { return NewInputString(bb, addr1, addr2) }
+	// Инициализируем команду MEMCPY в мапе команд
+	p.commandMap[MEMCPY] = func(bb uint8, addr1, addr2 uint16) Command { return NewMemoryCopy(bb, addr1, addr2) }
+	// Инициализируем команду MEMSET в мапе команд
+	p.commandMap[MEMSET] = func(bb uint8, addr1, addr2 uint16) Command { return NewMemoryFill(bb, addr1, addr2) }
+	// Инициализируем команду SCMP в мапе команд
+	p.commandMap[SCMP] = func(bb uint8, addr1, addr2 uint16) Command { return NewCompareBlock(bb, addr1, addr2) }
+	// Инициализируем команду CPUID в мапе команд
+	p.commandMap[CPUID] = func(bb uint8, addr1, addr2 uint16) Command { return NewCoreID(bb, addr1, addr2) }
+	// Инициализируем команду XCHG в мапе команд
+	p.commandMap[XCHG] = func(bb uint8, addr1, addr2 uint16) Command { return NewExchange(bb, addr1, addr2) }
+	// Инициализируем команду CAS в мапе команд
+	p.commandMap[CAS] = func(bb uint8, addr1, addr2 uint16) Command { return NewCompareAndSwap(bb, addr1, addr2) }
+	// Инициализируем команду XADD в мапе команд
+	p.commandMap[XADD] = func(bb uint8, addr1, addr2 uint16) Command { return NewFetchAdd(bb, addr1, addr2) }
+	// Инициализируем команду FENCE в мапе команд
+	p.commandMap[FENCE] = func(bb uint8, addr1, addr2 uint16) Command { return NewFence(bb, addr1, addr2) }
+}
+
+func (p *Processor) Reset(initialIP uint16) {
+	// Проверяем, является ли начальный адрес допустимым
+	if !p.code.IsValidAddress(int(initialIP)) {
+		// Логируем сообщение об ошибке с недопустимым адресом
+		p.logf(LogWarn, "Invalid initial IP: 0x%X", initialIP)
+		p.error = true // Устанавливаем флаг ошибки
+		return         // Завершаем выполнение функции
+	}
+	descriptors := p.usage.OpenDescriptors // Отображенные диски остаются открытыми между запусками
+
+	p.psw.IP = initialIP         // Устанавливаем начальный адрес инструкций
+	p.psw.SignFlag = false       // Сбрасываем флаг знака
+	p.psw.CarryFlag = false      // Сбрасываем флаг переноса
+	p.psw.OverflowFlag = false   // Сбрасываем флаг переполнения
+	p.psw.ZeroFlag = false       // Сбрасываем флаг нуля
+	p.psw.InterruptFlag = false  // Запрещаем прерывания до команды EI
+	p.setUserMode(false)         // Программа начинает работу в режиме супервизора
+	p.memory.segment = Segment{} // Сегментация выключена до команды SETSEG
+	p.rng.Seed(p.seed)           // Повторный запуск получает ту же последовательность RND
+	p.startTime = time.Now()     // Отсчет времени команды TIME начинается с запуска
+	p.sleptMillis = 0            // Сбрасываем время, проведенное в SLEEP по виртуальным часам
+	p.error = false              // Сбрасываем флаг ошибки
+	p.stop = false               // Сбрасываем флаг остановки
+	p.status = StatusRunning     // Сбрасываем статус завершения
+	p.usage = ResourceUsage{}    // Сбрасываем счетчики потребления ресурсов
+	p.setPending(nil)            // Сбрасываем необработанные прерывания
+	p.callDepth = 0              // Сбрасываем глубину вложенности подпрограмм
+	p.exitCode = 0               // Сбрасываем код завершения
+	p.runErr = nil               // Сбрасываем ошибку последнего запуска
+	p.recording.rewind()         // Запись ввода начинается с начала запуска
+	p.heatmap.reset()            // Тепловая карта считает только текущий запуск
+	p.cache.reset()              // Кэш начинает запуск пустым
+	p.coverage.start(p.code)     // Покрытие считается для загруженной программы
+	p.stats = Stats{}            // Статистика считается с запуска программы
+	if p.history != nil {
+		p.SetHistory(len(p.history.records)) // История прежнего запуска не отменяется
+	}
+	p.memoryBase = p.memory.Stats() // Обращения к памяти для Stats считаются с этого снимка
+	p.usage.OpenDescriptors = descriptors
+
+	// Сбрасываем регистры (a1, a2)
+	p.registers[0] = 0 // Регистру a1 присваиваем 0
+	p.registers[1] = 0 // Регистру a2 присваиваем 0
+
+	// Сбрасываем вещественные регистры (f0-f3)
+	p.fregisters = [NUM_FLOAT_REGISTERS]float32{}
+
+	// Устанавливаем указатель стека на корень стека
+	p.psw.SP = uint16(p.stackBase)
+
+	p.updateWatches() // Выражения наблюдения пересчитываются для нового запуска
+
+	// Логируем сообщение о сбросе процессора с начальным адресом инструкций
+	p.logf(LogInfo, "Processor reset with initial IP: 0x%X", initialIP)
+}
+func (p *Processor) Close() {
+	p.log.Close() // Дописываем очередь журнала до закрытия файлов
+	if p.logFile != nil {
+		p.logFile.Close() // Закрываем файл лога, если он открыт
+	}
+	if p.errorLogFile != nil {
+		p.errorLogFile.Close() // Закрываем файл лога ошибок, если он открыт
+	}
+	if p.shared {
+		return // Общую память закрывает первое ядро
+	}
+	if p.memory != nil {
+		p.memory.Close() // Закрываем память, если она инициализирована
+	}
+	if p.code != nil && p.code != p.memory {
+		p.code.Close() // Закрываем отдельную память команд
+	}
+}
@@ -0,0 +1,100 @@
+package vm
+
+import "fmt"
+
+// Permission задает права доступа к диапазону адресов
+type Permission uint8
+
+const (
+	PermRead  Permission = 1 << iota // Чтение слов
+	PermWrite                        // Запись слов
+	PermExec                         // Выборка команд
+
+	PermRW  = PermRead | PermWrite            // Данные
+	PermRX  = PermRead | PermExec             // Код, защищенный от записи
+	PermRWX = PermRead | PermWrite | PermExec // Доступ без ограничений
+)
+
+// String возвращает права в виде "rwx" с прочерками на месте запрещенных операций
+func (perm Permission) String() string {
+	s := []byte("---")
+	if perm&PermRead != 0 {
+		s[0] = 'r'
+	}
+	if perm&PermWrite != 0 {
+		s[1] = 'w'
+	}
+	if perm&PermExec != 0 {
+		s[2] = 'x'
+	}
+	return string(s)
+}
+
+// Protection описывает диапазон адресов [Base, Base+Size) с правами доступа Perm
+type Protection struct {
+	Name string     // Имя диапазона
+	Base int        // Начальный адрес
+	Size int        // Размер в байтах
+	Perm Permission // Разрешенные операции
+}
+
+// overlaps проверяет, пересекается ли диапазон [address, address+n) с защищенным диапазоном
+func (pr *Protection) overlaps(address, n int) bool {
+	return address < pr.Base+pr.Size && pr.Base < address+n
+}
+
+// Protect объявляет диапазон адресов с правами perm. Адреса вне объявленных диапазонов
+// доступны без ограничений; при пересечении диапазонов действует объявленный позже.
+// Повторный вызов с тем же именем заменяет прежний диапазон.
+func (m *Memory) Protect(name string, base, size int, perm Permission) error {
+	if size <= 0 || base < 0 || base+size > m.size {
+		return fmt.Errorf("protection %q [0x%X, +%d) is out of memory range", name, base, size)
+	}
+	m.Unprotect(name)
+	m.protections = append(m.protections, &Protection{Name: name, Base: base, Size: size, Perm: perm})
+	return nil
+}
+
+// Unprotect удаляет диапазон по имени; возвращает false, если диапазон не найден
+func (m *Memory) Unprotect(name string) bool {
+	for i, pr := range m.protections {
+		if pr.Name == name {
+			m.protections = append(m.protections[:i], m.protections[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Protections возвращает копию таблицы защищенных диапазонов
+func (m *Memory) Protections() []Protection {
+	list := make([]Protection, len(m.protections))
+	for i, pr := range m.protections {
+		list[i] = *pr
+	}
+	return list
+}
+
+// checkPermission проверяет, что операция need разрешена для диапазона [address, address+n)
+func (m *Memory) checkPermission(operation string, address, n int, need Permission) error {
+	for i := len(m.protections) - 1; i >= 0; i-- {
+		pr := m.protections[i]
+		if !pr.overlaps(address, n) {
+			continue
+		}
+		if pr.Perm&need == 0 {
+			return &MemoryError{
+				Operation: operation,
+				Address:   address,
+				Message:   fmt.Sprintf("access denied by protection %q (%s)", pr.Name, pr.Perm),
+			}
+		}
+		return nil // Действует последний объявленный диапазон
+	}
+	return nil
+}
+
+// FetchWord читает команду по адресу address с проверкой права на выполнение
+func (m *Memory) FetchWord(address int) (Word, error) {
+	return m.readWord("fetch", address, PermExec)
+}
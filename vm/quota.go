@@ -0,0 +1,88 @@
+package vm
+
+import "fmt"
+
+// Quotas задает ограничения ресурсов на один запуск программы (0 означает отсутствие ограничения)
+type Quotas struct {
+	MaxOutputBytes  int // Максимальное количество байт, выведенных на консоль и в файлы
+	MaxDescriptors  int // Максимальное количество одновременно открытых файлов хоста (образов дисков); задается до MapDisk
+	MaxInterrupts   int // Максимальное количество обработанных прерываний устройств
+	MaxInstructions int // Максимальное количество инструкций; при исчерпании статус StatusBudgetExceeded
+}
+
+// ResourceUsage содержит фактическое потребление ресурсов за текущий запуск
+type ResourceUsage struct {
+	OutputBytes     int // Количество выведенных байт
+	OpenDescriptors int // Количество открытых в данный момент дескрипторов; сохраняется при Reset
+	Interrupts      int // Количество обработанных прерываний
+	Instructions    int // Количество выполненных инструкций
+}
+
+// ResourceLimitError возвращается, когда гостевая программа превышает квоту ресурса
+type ResourceLimitError struct {
+	Resource string // Название исчерпанного ресурса
+	Limit    int    // Установленный лимит
+	Used     int    // Значение, которое было бы достигнуто при выполнении операции
+}
+
+// Error реализует интерфейс error для ResourceLimitError
+func (e *ResourceLimitError) Error() string {
+	return fmt.Sprintf("resource limit exceeded: %s (limit %d, requested %d)", e.Resource, e.Limit, e.Used)
+}
+
+// SetQuotas устанавливает квоты ресурсов для последующих запусков
+func (p *Processor) SetQuotas(q Quotas) {
+	p.quotas = q // Сохраняем квоты в процессоре
+}
+
+// GetQuotas возвращает текущие квоты ресурсов
+func (p *Processor) GetQuotas() Quotas {
+	return p.quotas
+}
+
+// GetResourceUsage возвращает потребление ресурсов за текущий запуск
+func (p *Processor) GetResourceUsage() ResourceUsage {
+	return p.usage
+}
+
+// chargeOutput учитывает n байт вывода и возвращает ошибку при превышении квоты
+func (p *Processor) chargeOutput(n int) error {
+	if p.quotas.MaxOutputBytes > 0 && p.usage.OutputBytes+n > p.quotas.MaxOutputBytes {
+		return &ResourceLimitError{Resource: "output bytes", Limit: p.quotas.MaxOutputBytes, Used: p.usage.OutputBytes + n}
+	}
+	p.usage.OutputBytes += n // Увеличиваем счетчик выведенных байт
+	return nil
+}
+
+// acquireDescriptor резервирует дескриптор и возвращает ошибку при превышении квоты
+func (p *Processor) acquireDescriptor() error {
+	if p.quotas.MaxDescriptors > 0 && p.usage.OpenDescriptors+1 > p.quotas.MaxDescriptors {
+		return &ResourceLimitError{Resource: "open descriptors", Limit: p.quotas.MaxDescriptors, Used: p.usage.OpenDescriptors + 1}
+	}
+	p.usage.OpenDescriptors++ // Увеличиваем счетчик открытых дескрипторов
+	return nil
+}
+
+// releaseDescriptor освобождает ранее зарезервированный дескриптор
+func (p *Processor) releaseDescriptor() {
+	if p.usage.OpenDescriptors > 0 {
+		p.usage.OpenDescriptors-- // Уменьшаем счетчик открытых дескрипторов
+	}
+}
+
+// chargeInterrupt учитывает обработанное прерывание и возвращает ошибку при превышении квоты
+func (p *Processor) chargeInterrupt() error {
+	if p.quotas.MaxInterrupts > 0 && p.usage.Interrupts+1 > p.quotas.MaxInterrupts {
+		return &ResourceLimitError{Resource: "interrupts", Limit: p.quotas.MaxInterrupts, Used: p.usage.Interrupts + 1}
+	}
+	p.usage.Interrupts++ // Увеличиваем счетчик обработанных прерываний
+	return nil
+}
+
+// chargeInstruction проверяет, что лимит инструкций позволяет выполнить еще одну
+func (p *Processor) chargeInstruction() error {
+	if p.quotas.MaxInstructions > 0 && p.usage.Instructions >= p.quotas.MaxInstructions {
+		return &ResourceLimitError{Resource: "instructions", Limit: p.quotas.MaxInstructions, Used: p.usage.Instructions + 1}
+	}
+	return nil
+}
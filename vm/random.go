@@ -0,0 +1,50 @@
+package vm
+
+import "math/rand"
+
+// Начальное значение генератора псевдослучайных чисел по умолчанию
+const DEFAULT_SEED = 1 // Константа, обеспечивающая воспроизводимость запусков
+
+// Флаг поля Address2 команды RND
+const RND_FLOAT = 0x01 // Результат — вещественное число из [0, 1)
+
+// SetSeed задает начальное значение генератора команды RND. Одинаковое значение дает
+// одинаковую последовательность чисел, а Reset перезапускает ее с начала.
+func (p *Processor) SetSeed(seed int64) {
+	p.seed = seed
+	p.random = &countingSource{src: rand.NewSource(seed)}
+	p.rng = rand.New(p.random)
+}
+
+// Seed возвращает начальное значение генератора псевдослучайных чисел
+func (p *Processor) Seed() int64 {
+	return p.seed
+}
+
+// countingSource — источник псевдослучайных чисел, считающий выданные значения.
+// Снимок состояния хранит начальное значение и число выдач, а LoadState
+// восстанавливает генератор, повторяя выдачи заново.
+type countingSource struct {
+	src   rand.Source // Исходный источник
+	draws uint64      // Число значений, выданных после последнего Seed
+}
+
+// Int63 возвращает очередное значение источника
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.src.Int63()
+}
+
+// Seed перезапускает источник с начальным значением seed
+func (s *countingSource) Seed(seed int64) {
+	s.src.Seed(seed)
+	s.draws = 0
+}
+
+// restoreRandom перезапускает генератор и пропускает draws значений
+func (p *Processor) restoreRandom(seed int64, draws uint64) {
+	p.SetSeed(seed)
+	for range draws {
+		p.random.Int63()
+	}
+}
@@ -0,0 +1,282 @@
+package vm
+
+import (
+	"fmt"
+	"sync"
+)
+
+// RegionKind определяет тип региона физической карты памяти
+type RegionKind int
+
+const (
+	RegionRAM    RegionKind = iota // Оперативная память, доступная для чтения и записи
+	RegionROM                      // Постоянная память, запись в которую запрещена
+	RegionDevice                   // Окно регистров устройства
+	RegionShared                   // Окно памяти, разделяемое с приложением хоста
+)
+
+// String возвращает строковое представление типа региона
+func (k RegionKind) String() string {
+	switch k {
+	case RegionRAM:
+		return "RAM"
+	case RegionROM:
+		return "ROM"
+	case RegionDevice:
+		return "DEVICE"
+	case RegionShared:
+		return "SHARED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Region описывает один регион физической карты памяти.
+//
+// Правила синхронизации для разделяемых окон: каждое обращение гостя к региону выполняется
+// под мьютексом региона, поэтому отдельное слово всегда читается и записывается целиком.
+// Хост, изменяющий или читающий срез во время работы процессора, должен держать блокировку
+// (Lock/Unlock); для согласованного обмена блоками данных хост и гость договариваются
+// о флаге готовности внутри самого окна.
+type Region struct {
+	Name     string      // Имя региона в таблице
+	Kind     RegionKind  // Тип региона
+	base     int         // Текущий начальный адрес региона
+	data     []byte      // Хранилище региона (для разделяемых окон — срез хоста)
+	size     int         // Размер региона в байтах
+	device   Device      // Устройство, обслуживающее окно (только для RegionDevice)
+	attached bool        // Подключен ли регион к адресному пространству
+	stats    MemoryStats // Счетчики обращений к региону
+	mu       sync.Mutex  // Мьютекс, сериализующий доступ хоста и гостя
+}
+
+// SharedWindow — регион, разделяемый с приложением хоста
+type SharedWindow = Region
+
+// Base возвращает начальный адрес региона
+func (r *Region) Base() int {
+	return r.base
+}
+
+// Size возвращает размер региона в байтах
+func (r *Region) Size() int {
+	return r.size
+}
+
+// Attached сообщает, подключен ли регион к адресному пространству
+func (r *Region) Attached() bool {
+	return r.attached
+}
+
+// Lock захватывает регион для монопольного доступа хоста
+func (r *Region) Lock() {
+	r.mu.Lock()
+}
+
+// Unlock освобождает регион после доступа хоста
+func (r *Region) Unlock() {
+	r.mu.Unlock()
+}
+
+// contains проверяет, попадает ли диапазон [address, address+n) в регион целиком
+func (r *Region) contains(address, n int) bool {
+	return address >= r.base && address+n <= r.base+r.size
+}
+
+// overlaps проверяет, пересекается ли диапазон [address, address+n) с регионом
+func (r *Region) overlaps(address, n int) bool {
+	return address < r.base+r.size && r.base < address+n
+}
+
+// AddRegion регистрирует регион в таблице и подключает его по адресу base.
+// Регион перекрывает ранее подключенные регионы (например, основную RAM), но не
+// может пересекаться с другими перекрывающими регионами.
+func (m *Memory) AddRegion(name string, kind RegionKind, base int, data []byte) (*Region, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("region %q: cannot map empty region", name)
+	}
+	return m.addRegion(&Region{Name: name, Kind: kind, data: data, size: len(data)}, base)
+}
+
+// addRegion регистрирует подготовленный регион и подключает его по адресу base
+func (m *Memory) addRegion(r *Region, base int) (*Region, error) {
+	if m.FindRegion(r.Name) != nil {
+		return nil, fmt.Errorf("region %q already exists", r.Name)
+	}
+	m.regions = append(m.regions, r) // Регистрируем регион в таблице
+	if err := m.attach(r, base); err != nil {
+		m.regions = m.regions[:len(m.regions)-1] // Откатываем регистрацию
+		return nil, err
+	}
+	return r, nil
+}
+
+// AddRAM добавляет регион оперативной памяти заданного размера
+func (m *Memory) AddRAM(name string, base, size int) (*Region, error) {
+	return m.AddRegion(name, RegionRAM, base, make([]byte, size))
+}
+
+// AddROM добавляет регион постоянной памяти с заданным содержимым
+func (m *Memory) AddROM(name string, base int, contents []byte) (*Region, error) {
+	return m.AddRegion(name, RegionROM, base, append([]byte(nil), contents...))
+}
+
+// MapShared отображает buf в адреса гостя начиная с base без копирования данных
+func (m *Memory) MapShared(base int, buf []byte) (*SharedWindow, error) {
+	return m.AddRegion(fmt.Sprintf("shared@0x%X", base), RegionShared, base, buf)
+}
+
+// UnmapShared отключает окно и удаляет его из таблицы регионов
+func (m *Memory) UnmapShared(w *SharedWindow) {
+	m.unmap(w)
+}
+
+// unmap отключает регион и удаляет его из таблицы регионов
+func (m *Memory) unmap(r *Region) {
+	for i, existing := range m.regions {
+		if existing == r {
+			r.attached = false
+			m.regions = append(m.regions[:i], m.regions[i+1:]...)
+			return
+		}
+	}
+}
+
+// Regions возвращает таблицу регионов в порядке регистрации
+func (m *Memory) Regions() []*Region {
+	return append([]*Region(nil), m.regions...)
+}
+
+// FindRegion ищет регион по имени
+func (m *Memory) FindRegion(name string) *Region {
+	for _, r := range m.regions {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// AttachRegion подключает ранее отключенный регион по адресу base
+func (m *Memory) AttachRegion(name string, base int) error {
+	r := m.FindRegion(name)
+	if r == nil {
+		return fmt.Errorf("region %q not found", name)
+	}
+	if r.attached {
+		return fmt.Errorf("region %q is already attached", name)
+	}
+	return m.attach(r, base)
+}
+
+// DetachRegion отключает регион, оставляя его в таблице для повторного подключения
+func (m *Memory) DetachRegion(name string) error {
+	r := m.FindRegion(name)
+	if r == nil {
+		return fmt.Errorf("region %q not found", name)
+	}
+	r.attached = false
+	return nil
+}
+
+// RebaseRegion переносит подключенный регион на новый начальный адрес
+func (m *Memory) RebaseRegion(name string, base int) error {
+	r := m.FindRegion(name)
+	if r == nil {
+		return fmt.Errorf("region %q not found", name)
+	}
+	wasAttached := r.attached
+	r.attached = false // Отключаем регион, чтобы он не мешал проверке пересечений
+	if err := m.attach(r, base); err != nil {
+		r.attached = wasAttached // Восстанавливаем прежнее состояние при ошибке
+		return err
+	}
+	return nil
+}
+
+// attach проверяет границы и пересечения, затем подключает регион по адресу base
+func (m *Memory) attach(r *Region, base int) error {
+	if base < 0 || base+r.size > m.size {
+		return fmt.Errorf("region %q [0x%X-0x%X] is out of memory range", r.Name, base, base+r.size-1)
+	}
+	for _, other := range m.regions {
+		if other != r && other.attached && other != m.ram && other.overlaps(base, r.size) {
+			return fmt.Errorf("region %q at 0x%X overlaps region %q", r.Name, base, other.Name)
+		}
+	}
+	r.base = base
+	r.attached = true
+	subsystem := SubsystemMemory
+	if r.device != nil {
+		subsystem = SubsystemDevices // Подключение устройства относится к журналу устройств
+	}
+	m.log.Logf(subsystem, LogInfo, "Mapped %s region %q at 0x%X-0x%X", r.Kind, r.Name, base, base+r.size-1)
+	return nil
+}
+
+// findRegion возвращает подключенный регион, целиком содержащий диапазон.
+// Регионы, подключенные позже, имеют приоритет над основной RAM.
+func (m *Memory) findRegion(address, n int) *Region {
+	for i := len(m.regions) - 1; i >= 0; i-- {
+		if r := m.regions[i]; r.attached && r.contains(address, n) {
+			return r
+		}
+	}
+	return nil
+}
+
+// checkRange проверяет, что диапазон [address, address+n) целиком лежит в адресном пространстве
+func (m *Memory) checkRange(operation string, address, n int) error {
+	if address < 0 || n <= 0 || address > m.size-n {
+		return &MemoryError{
+			Operation: operation,
+			Address:   address,
+			Message:   fmt.Sprintf("access of %d byte(s) is out of range [0x0-0x%X]", n, m.size-1),
+		}
+	}
+	return nil
+}
+
+// readBytes копирует len(dst) байт начиная с address через карту регионов
+func (m *Memory) readBytes(address int, dst []byte) error {
+	if err := m.checkRange("read", address, len(dst)); err != nil {
+		return err
+	}
+	r := m.findRegion(address, len(dst))
+	if r == nil {
+		return &MemoryError{Operation: "read", Address: address, Message: "no region mapped"}
+	}
+	if r.device != nil {
+		return &MemoryError{Operation: "read", Address: address, Message: fmt.Sprintf("device %q supports only word access", r.Name)}
+	}
+	r.mu.Lock()
+	copy(dst, r.data[address-r.base:])
+	r.mu.Unlock()
+	return nil
+}
+
+// writeBytes копирует src в память начиная с address через карту регионов
+func (m *Memory) writeBytes(address int, src []byte) error {
+	if err := m.checkRange("write", address, len(src)); err != nil {
+		return err
+	}
+	r := m.findRegion(address, len(src))
+	if r == nil {
+		return &MemoryError{Operation: "write", Address: address, Message: "no region mapped"}
+	}
+	if r.device != nil {
+		return &MemoryError{Operation: "write", Address: address, Message: fmt.Sprintf("device %q supports only word access", r.Name)}
+	}
+	if r.Kind == RegionROM {
+		return &MemoryError{Operation: "write", Address: address, Message: fmt.Sprintf("region %q is read-only", r.Name)}
+	}
+	r.mu.Lock()
+	if m.journal != nil {
+		old := make([]byte, len(src))
+		copy(old, r.data[address-r.base:])
+		m.journal(m, address, old) // Прежние байты для отмены инструкции (StepBack)
+	}
+	copy(r.data[address-r.base:], src)
+	r.mu.Unlock()
+	return nil
+}
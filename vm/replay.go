@@ -0,0 +1,256 @@
+package vm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Источники недетерминированного ввода в записи выполнения. Чтения регистров устройств
+// ввода записываются как "устройство+смещение", например "keyboard+0x8".
+const (
+	InputIIN  = "iin"  // Строка, введенная командой IIN
+	InputRIN  = "rin"  // Строка, введенная командой RIN
+	InputCIN  = "cin"  // Код символа команды CIN (-1 в конце ввода)
+	InputSIN  = "sin"  // Строка команды SIN
+	InputRND  = "rnd"  // Результат команды RND
+	InputTIME = "time" // Миллисекунды команды TIME
+)
+
+// InputEvent — одно недетерминированное значение, полученное программой
+type InputEvent struct {
+	Kind  string // Источник значения
+	Value string // Значение в текстовом виде
+	EOF   bool   // Ввод закончился (для InputSIN)
+}
+
+// Recording — запись недетерминированного ввода программы: строк stdin, чисел RND,
+// показаний часов и чтений устройств ввода. В режиме записи процессор дописывает
+// в нее полученные значения, в режиме воспроизведения берет их из нее по порядку,
+// поэтому программа выполняется так же, как при записи.
+type Recording struct {
+	Events []InputEvent // События в порядке получения
+	replay bool         // Значения берутся из записи
+	next   int          // Следующее воспроизводимое событие
+}
+
+// NewRecording создает пустую запись для режима записи
+func NewRecording() *Recording {
+	return &Recording{}
+}
+
+// Replaying сообщает, воспроизводится ли запись
+func (r *Recording) Replaying() bool {
+	return r.replay
+}
+
+// Remaining возвращает количество еще не воспроизведенных событий
+func (r *Recording) Remaining() int {
+	if !r.replay {
+		return 0
+	}
+	return len(r.Events) - r.next
+}
+
+// SetRecording подключает запись ввода: запись, созданная NewRecording, пополняется,
+// прочитанная ReadRecording воспроизводится. nil отключает запись.
+func (p *Processor) SetRecording(r *Recording) {
+	p.recording = r
+}
+
+// Recording возвращает подключенную запись ввода или nil
+func (p *Processor) Recording() *Recording {
+	return p.recording
+}
+
+// input возвращает недетерминированное значение источника kind. При воспроизведении
+// значение берется из записи и read не вызывается; иначе значение read дописывается
+// в подключенную запись.
+func (p *Processor) input(kind string, read func() (InputEvent, error)) (InputEvent, error) {
+	r := p.recording
+	if r != nil && r.replay {
+		if r.next >= len(r.Events) {
+			return InputEvent{}, fmt.Errorf("replay exhausted: program requested %s after %d events", kind, len(r.Events))
+		}
+		ev := r.Events[r.next]
+		if ev.Kind != kind {
+			return InputEvent{}, fmt.Errorf("replay diverged at event %d: program requested %s, recording has %s", r.next+1, kind, ev.Kind)
+		}
+		r.next++
+		return ev, nil
+	}
+	ev, err := read()
+	if err != nil {
+		return InputEvent{}, err
+	}
+	ev.Kind = kind
+	if r != nil {
+		r.Events = append(r.Events, ev)
+	}
+	return ev, nil
+}
+
+// inputInt возвращает целое недетерминированное значение источника kind
+func (p *Processor) inputInt(kind string, read func() (int64, error)) (int64, error) {
+	ev, err := p.input(kind, func() (InputEvent, error) {
+		v, err := read()
+		return InputEvent{Value: strconv.FormatInt(v, 10)}, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseInt(ev.Value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid recorded %s value %q", kind, ev.Value)
+	}
+	return v, nil
+}
+
+// rewind возвращает запись к началу запуска: воспроизведение начинается сначала,
+// а режим записи начинает запись заново
+func (r *Recording) rewind() {
+	if r == nil {
+		return
+	}
+	r.next = 0
+	if !r.replay {
+		r.Events = nil
+	}
+}
+
+// mark возвращает позицию записи для отмены инструкции
+func (r *Recording) mark() int {
+	if r == nil {
+		return 0
+	}
+	if r.replay {
+		return r.next
+	}
+	return len(r.Events)
+}
+
+// restore возвращает запись к позиции mark
+func (r *Recording) restore(mark int) {
+	if r == nil {
+		return
+	}
+	if r.replay {
+		r.next = mark
+	} else if mark < len(r.Events) {
+		r.Events = r.Events[:mark]
+	}
+}
+
+// inputDevice передает чтения регистров устройства ввода через запись выполнения
+type inputDevice struct {
+	p    *Processor
+	name string
+	dev  Device
+}
+
+// mapInputDevice отображает устройство ввода так, чтобы его чтения записывались
+// и воспроизводились
+func (p *Processor) mapInputDevice(name string, base, size int, dev Device) error {
+	_, err := p.memory.MapDevice(name, base, size, &inputDevice{p: p, name: name, dev: dev})
+	return err
+}
+
+// Read читает регистр устройства или берет значение из воспроизводимой записи
+func (d *inputDevice) Read(addr int) (Word, error) {
+	v, err := d.p.inputInt(fmt.Sprintf("%s+0x%X", d.name, addr), func() (int64, error) {
+		word, err := d.dev.Read(addr)
+		return int64(word.D.I), err
+	})
+	if err != nil {
+		return Word{}, err
+	}
+	return IntWord(int32(v)), nil
+}
+
+// Write передает запись устройству
+func (d *inputDevice) Write(addr int, word Word) error {
+	return d.dev.Write(addr, word)
+}
+
+// Write записывает события в текстовом формате: источник и значение, строки в кавычках.
+//
+//	; vm input recording
+//	iin "42"
+//	rnd 1298498081
+//	sin eof
+func (r *Recording) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "; vm input recording")
+	for _, ev := range r.Events {
+		switch {
+		case ev.EOF:
+			fmt.Fprintf(bw, "%s eof\n", ev.Kind)
+		case ev.Kind == InputIIN || ev.Kind == InputRIN || ev.Kind == InputSIN:
+			fmt.Fprintf(bw, "%s %s\n", ev.Kind, strconv.Quote(ev.Value))
+		default:
+			fmt.Fprintf(bw, "%s %s\n", ev.Kind, ev.Value)
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteRecordingFile сохраняет запись в файл
+func WriteRecordingFile(filename string, r *Recording) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("unable to create file: %v", err)
+	}
+	if err := r.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// LoadRecordingFile читает файл записи для воспроизведения
+func LoadRecordingFile(filename string) (*Recording, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file: %v", err)
+	}
+	defer file.Close()
+	return ReadRecording(file)
+}
+
+// ReadRecording читает запись в формате Recording.Write для воспроизведения
+func ReadRecording(r io.Reader) (*Recording, error) {
+	rec := &Recording{replay: true}
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		text := strings.TrimSpace(line)
+		if text == "" || strings.HasPrefix(text, ";") {
+			continue
+		}
+		kind, rest := splitFirst(text)
+		if rest == "" {
+			return nil, &CommandError{LineNumber: lineNumber, Line: line, Message: fmt.Sprintf("event %q requires a value", kind)}
+		}
+		ev := InputEvent{Kind: kind, Value: rest}
+		switch {
+		case rest == "eof":
+			ev.Value, ev.EOF = "", true
+		case strings.HasPrefix(rest, `"`):
+			value, err := strconv.Unquote(rest)
+			if err != nil {
+				return nil, &CommandError{LineNumber: lineNumber, Line: line, Message: fmt.Sprintf("invalid string: %v", err)}
+			}
+			ev.Value = value
+		}
+		rec.Events = append(rec.Events, ev)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading file: %v", err)
+	}
+	return rec, nil
+}
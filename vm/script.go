@@ -0,0 +1,73 @@
+package vm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// InputExhaustedError — программа запросила больше ввода, чем задано сценарием
+type InputExhaustedError struct {
+	Lines int // Число строк сценария, которые уже были прочитаны
+}
+
+// Error реализует интерфейс error для InputExhaustedError
+func (e *InputExhaustedError) Error() string {
+	return fmt.Sprintf("program requested more input than provided (%d lines)", e.Lines)
+}
+
+// ScriptedInput — заранее заданный ввод программы, по одному значению в строке.
+// В отличие от обычного конца ввода, чтение после последней строки возвращает
+// InputExhaustedError, и программа останавливается с ошибкой. Используется для
+// автоматической проверки программ.
+type ScriptedInput struct {
+	r     *strings.Reader
+	lines int // Прочитанные переводы строк
+}
+
+// NewScriptedInput создает сценарий ввода из текста. Последняя строка может не
+// заканчиваться переводом строки.
+func NewScriptedInput(text string) *ScriptedInput {
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n" // Последнее значение читается как полная строка
+	}
+	return &ScriptedInput{r: strings.NewReader(text)}
+}
+
+// LoadScriptedInput читает сценарий ввода из файла
+func LoadScriptedInput(filename string) (*ScriptedInput, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read input script: %v", err)
+	}
+	return NewScriptedInput(string(data)), nil
+}
+
+// Read читает сценарий; после его конца возвращает InputExhaustedError
+func (s *ScriptedInput) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	s.lines += bytes.Count(p[:n], []byte{'\n'})
+	if err == io.EOF {
+		return n, &InputExhaustedError{Lines: s.lines}
+	}
+	return n, err
+}
+
+// Remaining возвращает число непрочитанных байт сценария
+func (s *ScriptedInput) Remaining() int {
+	return s.r.Len()
+}
+
+// RunScripted выполняет программу с вводом input и возвращает весь ее вывод, включая
+// приглашения ввода. Ошибка выполнения, в том числе InputExhaustedError, возвращается
+// вместе с выводом, полученным до остановки. In и Out процессора восстанавливаются.
+func (p *Processor) RunScripted(input string) (output string, err error) {
+	var out bytes.Buffer
+	in, prevOut := p.In, p.Out
+	p.In, p.Out = NewScriptedInput(input), &out
+	defer func() { p.In, p.Out = in, prevOut }()
+	err = p.Run()
+	return out.String(), err
+}
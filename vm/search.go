@@ -0,0 +1,170 @@
+package vm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// SearchMatch описывает найденное совпадение в памяти
+type SearchMatch struct {
+	Address int  // Адрес совпадения
+	Word    Word // Слово по адресу совпадения
+}
+
+// SearchInt ищет слова данных с заданным целочисленным значением
+func (m *Memory) SearchInt(value int32) []SearchMatch {
+	return m.searchWords(func(w Word) bool { return w.Tag == TagInt && w.D.I == value })
+}
+
+// SearchFloat ищет слова данных с заданным вещественным значением
+func (m *Memory) SearchFloat(value float32) []SearchMatch {
+	return m.searchWords(func(w Word) bool { return w.Tag == TagFloat && w.D.F == value })
+}
+
+// SearchInstruction ищет команды, удовлетворяющие условию
+func (m *Memory) SearchInstruction(match func(CommandData) bool) []SearchMatch {
+	return m.searchWords(func(w Word) bool { return w.IsCommand() && match(w.Cmd) })
+}
+
+// SearchJumpsTo ищет все переходы и вызовы с целевым адресом target
+func (m *Memory) SearchJumpsTo(target uint16) []SearchMatch {
+	return m.SearchInstruction(func(c CommandData) bool {
+		return isJumpOpcode(OpCode(c.Opcode)) && c.Address1 == target
+	})
+}
+
+// SearchBytes ищет последовательность байтов и возвращает адреса ее начала
+func (m *Memory) SearchBytes(pattern []byte) []SearchMatch {
+	var matches []SearchMatch
+	if len(pattern) == 0 {
+		return nil
+	}
+	buf := make([]byte, len(pattern))
+	for addr := 0; addr+len(pattern) <= m.size; addr++ {
+		if err := m.readBytes(addr, buf); err != nil {
+			continue // Пропускаем неотображенные адреса
+		}
+		if bytes.Equal(buf, pattern) {
+			word, _ := m.PeekWord(addr)
+			matches = append(matches, SearchMatch{Address: addr, Word: word})
+		}
+	}
+	return matches
+}
+
+// searchWords перебирает все адреса памяти и возвращает слова, удовлетворяющие условию
+func (m *Memory) searchWords(match func(Word) bool) []SearchMatch {
+	var matches []SearchMatch
+	for addr := 0; addr < m.WordLimit(); addr += WORD_SIZE {
+		word, err := m.PeekWord(addr)
+		if err != nil {
+			continue // Пропускаем неотображенные адреса
+		}
+		if match(word) {
+			matches = append(matches, SearchMatch{Address: addr, Word: word})
+		}
+	}
+	return matches
+}
+
+// Search выполняет поиск по текстовому запросу монитора:
+//
+//	int <значение>          — слова данных с целым значением
+//	float <значение>        — слова данных с вещественным значением
+//	bytes <hh> <hh> ...     — последовательность байтов
+//	jumps-to <адрес>        — переходы и вызовы на адрес
+//	op <мнемоника> [адрес]  — команды с кодом операции (и первым адресом)
+func (m *Memory) Search(query string) ([]SearchMatch, error) {
+	fields := strings.Fields(query)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("search query requires a kind and a value")
+	}
+	switch strings.ToLower(fields[0]) {
+	case "int", "i":
+		value, err := ParseNumber(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		return m.SearchInt(int32(value)), nil
+	case "float", "r":
+		value, err := strconv.ParseFloat(fields[1], 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid float %q", fields[1])
+		}
+		return m.SearchFloat(float32(value)), nil
+	case "bytes", "b":
+		pattern := make([]byte, 0, len(fields)-1)
+		for _, f := range fields[1:] {
+			b, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(f), "0x"), 16, 8)
+			if err != nil {
+				return nil, fmt.Errorf("invalid byte %q", f)
+			}
+			pattern = append(pattern, byte(b))
+		}
+		return m.SearchBytes(pattern), nil
+	case "jumps-to", "jump", "j":
+		target, err := ParseNumber(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		return m.SearchJumpsTo(uint16(target)), nil
+	case "op":
+		op, ok := ParseOpCode(fields[1])
+		if !ok {
+			return nil, fmt.Errorf("unknown mnemonic %q", fields[1])
+		}
+		var addr1 int64 = -1
+		if len(fields) > 2 {
+			value, err := ParseNumber(fields[2])
+			if err != nil {
+				return nil, err
+			}
+			addr1 = value
+		}
+		return m.SearchInstruction(func(c CommandData) bool {
+			return OpCode(c.Opcode) == op && (addr1 < 0 || int64(c.Address1) == addr1)
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown search kind %q", fields[0])
+	}
+}
+
+// PrintSearchResults выводит совпадения с radius соседними словами контекста с каждой стороны
+func (m *Memory) PrintSearchResults(w io.Writer, matches []SearchMatch, radius int) {
+	if len(matches) == 0 {
+		fmt.Fprintln(w, "No matches found")
+		return
+	}
+	for _, match := range matches {
+		for addr := match.Address - radius*WORD_SIZE; addr <= match.Address+radius*WORD_SIZE; addr += WORD_SIZE {
+			if !m.IsValidAddress(addr) {
+				continue
+			}
+			word, err := m.PeekWord(addr)
+			if err != nil {
+				continue
+			}
+			marker := "  "
+			if addr == match.Address {
+				marker = "=>" // Отмечаем найденный адрес
+			}
+			fmt.Fprintf(w, "%s %04X: %s\n", marker, addr, describeWord(word))
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "%d match(es)\n", len(matches))
+}
+
+// describeWord возвращает краткое текстовое описание слова памяти
+func describeWord(w Word) string {
+	switch w.Tag {
+	case TagCommand:
+		return fmt.Sprintf("%-5s bb=%d 0x%04X 0x%04X", OpCode(w.Cmd.Opcode), w.Cmd.BB, w.Cmd.Address1, w.Cmd.Address2)
+	case TagFloat:
+		return fmt.Sprintf("float %g", w.D.F)
+	}
+	return fmt.Sprintf("data  %d (0x%08X)", w.D.I, uint32(w.D.I))
+}
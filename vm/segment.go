@@ -0,0 +1,51 @@
+package vm
+
+import "fmt"
+
+// Segment описывает сегмент данных: адрес данных A допустим, если A+размер <= Limit,
+// и обращается к ячейке Base+A. Выборка команд и таблица векторов не сегментируются.
+type Segment struct {
+	Base  int // Начало сегмента
+	Limit int // Размер сегмента в байтах; 0 — сегментация выключена
+}
+
+// apply проверяет диапазон [address, address+n) по пределу сегмента и прибавляет базу
+func (s Segment) apply(operation string, address, n int) (int, error) {
+	if s.Limit == 0 {
+		return address, nil
+	}
+	if address < 0 || address+n > s.Limit {
+		return 0, &TrapError{Vector: VECTOR_BOUNDS, Err: &MemoryError{
+			Operation: operation,
+			Address:   address,
+			Message:   fmt.Sprintf("outside segment [0x%X, +0x%X)", s.Base, s.Limit),
+		}}
+	}
+	return s.Base + address, nil
+}
+
+// SetSegment задает сегмент данных памяти; limit 0 выключает сегментацию
+func (m *Memory) SetSegment(base, limit int) error {
+	if limit < 0 || base < 0 || base+limit > m.size {
+		return fmt.Errorf("segment [0x%X, +0x%X) is out of memory range", base, limit)
+	}
+	m.segment = Segment{Base: base, Limit: limit}
+	return nil
+}
+
+// Segment возвращает текущий сегмент данных памяти
+func (m *Memory) Segment() Segment {
+	return m.segment
+}
+
+// SetSegment задает базу и предел сегмента данных процессора. Сегмент действует в обоих
+// режимах, включая стек и кадры прерываний, поэтому обработчик, которому нужна вся память,
+// сам переключает сегмент командой SETSEG и восстанавливает его перед IRET.
+func (p *Processor) SetSegment(base, limit int) error {
+	return p.memory.SetSegment(base, limit)
+}
+
+// Segment возвращает текущий сегмент данных процессора
+func (p *Processor) Segment() Segment {
+	return p.memory.Segment()
+}
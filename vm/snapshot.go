@@ -0,0 +1,464 @@
+package vm
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Сигнатура и версия файла снимка состояния
+const STATE_MAGIC = "VMSTATE\x01"
+
+// stateDevice — устройство, регистры которого сохраняются в снимке состояния.
+// Консоль и клавиатура не сохраняются: их состояние — поток ввода хоста.
+type stateDevice interface {
+	saveState() []int32     // Значения регистров устройства
+	loadState(regs []int32) // Восстановление регистров; длина совпадает с saveState
+}
+
+// stateHeader — заголовок файла снимка фиксированного размера (little-endian). За ним
+// следуют текст ошибки, отладочная информация, образ памяти данных, образ памяти
+// команд (в гарвардском режиме), содержимое банков памяти, необработанные прерывания
+// и записи устройств (deviceHeader, имя и регистры).
+type stateHeader struct {
+	Magic        [8]byte
+	IP, SP       uint16
+	Flags        uint8 // Флаги PSW в кодировке трассы (TRACE_ZF...TRACE_UM)
+	Status       uint8
+	Stopped      uint8 // Флаг остановки процессора
+	Failed       uint8 // Флаг ошибки процессора
+	Registers    [NUM_REGISTERS]int32
+	FRegisters   [NUM_FLOAT_REGISTERS]float32
+	ExitCode     int32
+	SegmentBase  int32
+	SegmentLimit int32
+	CallDepth    int32
+	StackBase    int32
+	StackLimit   int32
+	VectorBase   int32
+	Instructions uint64
+	OutputBytes  uint64
+	Interrupts   uint64
+	Descriptors  uint64
+	Seed         int64
+	RandomDraws  uint64 // Значения, выданные генератором RND после Seed
+	HostMillis   int64  // Время с запуска программы по часам хоста
+	SleptMillis  int64  // Время SLEEP по виртуальным часам
+	Bank         int32  // Банк, видимый в окне
+	BankCount    uint32
+	BankSize     uint32
+	PendingCount uint32
+	DeviceCount  uint32
+	ErrorLen     uint32
+	DebugLen     uint32
+	MemorySize   uint32
+	CodeSize     uint32 // 0 — память команд совпадает с памятью данных
+}
+
+// deviceHeader — заголовок записи устройства в снимке
+type deviceHeader struct {
+	Base    int32  // Адрес окна устройства
+	NameLen uint32 // Длина имени региона устройства
+	Count   uint32 // Число регистров
+}
+
+// SaveState записывает снимок состояния процессора: память, регистры, PSW, флаги
+// остановки и ошибки, счетчики, генератор RND и регистры устройств. Снимок
+// снимается между инструкциями, пока программа не выполняется; LoadState
+// продолжает программу с той же команды, в том числе после перезапуска хоста.
+func (p *Processor) SaveState(w io.Writer) error {
+	var debug bytes.Buffer
+	if p.debugInfo != nil {
+		if err := p.debugInfo.Write(&debug); err != nil {
+			return err
+		}
+	}
+	var message string
+	if p.runErr != nil {
+		message = p.runErr.Error()
+	}
+	memory := p.memory.image()
+	var code []byte
+	if p.IsHarvard() {
+		code = p.code.image()
+	}
+	pending := append(p.PendingInterrupts(), p.queuedEvents()...)
+	devices := p.memory.stateDevices()
+
+	h := stateHeader{
+		IP:           p.psw.IP,
+		SP:           p.psw.SP,
+		Flags:        p.psw.traceFlags(),
+		Status:       uint8(p.status),
+		Registers:    p.registers,
+		FRegisters:   p.fregisters,
+		ExitCode:     p.exitCode,
+		SegmentBase:  int32(p.memory.segment.Base),
+		SegmentLimit: int32(p.memory.segment.Limit),
+		CallDepth:    int32(p.callDepth),
+		StackBase:    int32(p.stackBase),
+		StackLimit:   int32(p.stackLimit),
+		VectorBase:   int32(p.vectorBase),
+		Instructions: uint64(p.usage.Instructions),
+		OutputBytes:  uint64(p.usage.OutputBytes),
+		Interrupts:   uint64(p.usage.Interrupts),
+		Descriptors:  uint64(p.usage.OpenDescriptors),
+		Seed:         p.seed,
+		RandomDraws:  p.random.draws,
+		HostMillis:   time.Since(p.startTime).Milliseconds(),
+		SleptMillis:  p.sleptMillis,
+		Bank:         int32(p.memory.bank),
+		BankCount:    uint32(len(p.memory.banks)),
+		PendingCount: uint32(len(pending)),
+		DeviceCount:  uint32(len(devices)),
+		ErrorLen:     uint32(len(message)),
+		DebugLen:     uint32(debug.Len()),
+		MemorySize:   uint32(len(memory)),
+		CodeSize:     uint32(len(code)),
+	}
+	copy(h.Magic[:], STATE_MAGIC)
+	if p.stop {
+		h.Stopped = 1
+	}
+	if p.error {
+		h.Failed = 1
+	}
+	if len(p.memory.banks) > 0 {
+		h.BankSize = uint32(len(p.memory.banks[0]))
+	}
+	if err := binary.Write(w, binary.LittleEndian, &h); err != nil {
+		return err
+	}
+	parts := [][]byte{[]byte(message), debug.Bytes(), memory, code}
+	parts = append(parts, p.memory.banks...)
+	for _, part := range parts {
+		if _, err := w.Write(part); err != nil {
+			return err
+		}
+	}
+	if err := binary.Write(w, binary.LittleEndian, pending); err != nil {
+		return err
+	}
+	for _, r := range devices {
+		regs := r.device.(stateDevice).saveState()
+		dh := deviceHeader{Base: int32(r.base), NameLen: uint32(len(r.Name)), Count: uint32(len(regs))}
+		if err := binary.Write(w, binary.LittleEndian, &dh); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, r.Name); err != nil {
+			return err
+		}
+		if err := binary.Write(w, binary.LittleEndian, regs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// savedState — снимок состояния, прочитанный из файла
+type savedState struct {
+	header  stateHeader
+	message string     // Текст ошибки, остановившей программу
+	debug   *DebugInfo // Отладочная информация или nil
+	memory  []byte     // Образ памяти данных
+	code    []byte     // Образ памяти команд в гарвардском режиме, иначе nil
+	banks   [][]byte   // Содержимое банков памяти
+	pending []Event    // Необработанные прерывания
+	devices []savedDevice
+}
+
+// savedDevice — регистры устройства в снимке
+type savedDevice struct {
+	name string // Имя региона устройства
+	base int    // Адрес окна устройства
+	regs []int32
+}
+
+// readState читает снимок, записанный SaveState
+func readState(r io.Reader) (*savedState, error) {
+	s := &savedState{}
+	h := &s.header
+	if err := binary.Read(r, binary.LittleEndian, h); err != nil || string(h.Magic[:]) != STATE_MAGIC {
+		return nil, fmt.Errorf("not a vm state file")
+	}
+	read := func(n uint32, what string) ([]byte, error) {
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, fmt.Errorf("truncated state file: %s: %v", what, err)
+		}
+		return buf, nil
+	}
+	message, err := read(h.ErrorLen, "error message")
+	if err != nil {
+		return nil, err
+	}
+	s.message = string(message)
+	debug, err := read(h.DebugLen, "debug info")
+	if err != nil {
+		return nil, err
+	}
+	if len(debug) > 0 {
+		if s.debug, err = ReadDebugInfo(bytes.NewReader(debug)); err != nil {
+			return nil, fmt.Errorf("invalid debug info in state file: %v", err)
+		}
+	}
+	if s.memory, err = read(h.MemorySize, "memory"); err != nil {
+		return nil, err
+	}
+	if h.CodeSize > 0 {
+		if s.code, err = read(h.CodeSize, "code memory"); err != nil {
+			return nil, err
+		}
+	}
+	s.banks = make([][]byte, h.BankCount)
+	for i := range s.banks {
+		if s.banks[i], err = read(h.BankSize, "memory banks"); err != nil {
+			return nil, err
+		}
+	}
+	s.pending = make([]Event, h.PendingCount)
+	if err := binary.Read(r, binary.LittleEndian, s.pending); err != nil {
+		return nil, fmt.Errorf("truncated state file: pending interrupts: %v", err)
+	}
+	s.devices = make([]savedDevice, h.DeviceCount)
+	for i := range s.devices {
+		var dh deviceHeader
+		if err := binary.Read(r, binary.LittleEndian, &dh); err != nil {
+			return nil, fmt.Errorf("truncated state file: devices: %v", err)
+		}
+		name, err := read(dh.NameLen, "devices")
+		if err != nil {
+			return nil, err
+		}
+		regs := make([]int32, dh.Count)
+		if err := binary.Read(r, binary.LittleEndian, regs); err != nil {
+			return nil, fmt.Errorf("truncated state file: devices: %v", err)
+		}
+		s.devices[i] = savedDevice{name: string(name), base: int(dh.Base), regs: regs}
+	}
+	return s, nil
+}
+
+// core возвращает регистры, PSW и память снимка в виде дампа
+func (s *savedState) core() *Core {
+	h := &s.header
+	c := &Core{
+		PSW:          PSW{IP: h.IP, SP: h.SP},
+		Registers:    h.Registers,
+		FRegisters:   h.FRegisters,
+		Segment:      Segment{Base: int(h.SegmentBase), Limit: int(h.SegmentLimit)},
+		CallDepth:    int(h.CallDepth),
+		Status:       Status(h.Status),
+		ExitCode:     h.ExitCode,
+		Error:        s.message,
+		Instructions: int(h.Instructions),
+		Memory:       s.memory,
+		Code:         s.code,
+		Debug:        s.debug,
+	}
+	c.PSW.setTraceFlags(h.Flags)
+	return c
+}
+
+// LoadState восстанавливает снимок, записанный SaveState. Процессор должен быть
+// создан с той же конфигурацией памяти и теми же устройствами, что и сохраненный;
+// Run продолжает программу с сохраненной команды. Программа, остановленная лимитом
+// инструкций, продолжается; сохраненный счетчик инструкций учитывается в новом лимите.
+func (p *Processor) LoadState(r io.Reader) error {
+	s, err := readState(r)
+	if err != nil {
+		return err
+	}
+	h := &s.header
+	if (s.code != nil) != p.IsHarvard() {
+		return fmt.Errorf("state and processor memory layouts differ: use a harvard processor for a harvard state and vice versa")
+	}
+	if len(s.memory) != p.memory.Size() || (s.code != nil && len(s.code) != p.code.Size()) {
+		return fmt.Errorf("state memory size %d does not match processor memory size %d", len(s.memory), p.memory.Size())
+	}
+	if len(s.banks) != len(p.memory.banks) || (len(s.banks) > 0 && int(h.BankSize) != len(p.memory.banks[0])) {
+		return fmt.Errorf("state has %d memory bank(s), processor has %d", len(s.banks), len(p.memory.banks))
+	}
+	devices := make([]stateDevice, len(s.devices))
+	for i, d := range s.devices {
+		region := p.memory.findRegion(d.base, WORD_SIZE)
+		if region == nil || region.Name != d.name || region.base != d.base {
+			return fmt.Errorf("state device %q at 0x%X is not mapped", d.name, d.base)
+		}
+		dev, ok := region.device.(stateDevice)
+		if !ok || len(dev.saveState()) != len(d.regs) {
+			return fmt.Errorf("state device %q at 0x%X does not match the mapped device", d.name, d.base)
+		}
+		devices[i] = dev
+	}
+
+	// Снимок проверен: изменяем процессор
+	for i, bank := range s.banks {
+		copy(p.memory.banks[i], bank)
+	}
+	if len(s.banks) > 0 {
+		if err := p.memory.SelectBank(int(h.Bank)); err != nil {
+			return err
+		}
+	}
+	p.memory.restoreImage(s.memory)
+	if s.code != nil {
+		p.code.restoreImage(s.code)
+	}
+	for i, dev := range devices {
+		dev.loadState(s.devices[i].regs)
+	}
+	p.psw = PSW{IP: h.IP, SP: h.SP}
+	p.psw.setTraceFlags(h.Flags)
+	p.setUserMode(p.psw.UserMode)
+	p.registers, p.fregisters = h.Registers, h.FRegisters
+	p.memory.segment = Segment{Base: int(h.SegmentBase), Limit: int(h.SegmentLimit)}
+	p.callDepth = int(h.CallDepth)
+	p.stackBase, p.stackLimit = int(h.StackBase), int(h.StackLimit)
+	p.vectorBase = int(h.VectorBase)
+	p.status, p.exitCode = Status(h.Status), h.ExitCode
+	p.stop, p.error = h.Stopped != 0, h.Failed != 0
+	p.runErr = nil
+	if s.message != "" {
+		p.runErr = errors.New(s.message)
+	}
+	p.usage = ResourceUsage{
+		Instructions:    int(h.Instructions),
+		OutputBytes:     int(h.OutputBytes),
+		Interrupts:      int(h.Interrupts),
+		OpenDescriptors: p.usage.OpenDescriptors, // Дескрипторы заняты дисками, отображенными до загрузки
+	}
+	if p.status == StatusBudgetExceeded {
+		// Остановка по лимиту инструкций не завершает программу: она продолжается, а
+		// выполненные инструкции учитываются в лимите, заданном для продолжения
+		p.status, p.stop, p.runErr = StatusRunning, false, nil
+	}
+	p.setPending(s.pending)
+	p.restoreRandom(h.Seed, h.RandomDraws)
+	p.startTime = time.Now().Add(-time.Duration(h.HostMillis) * time.Millisecond)
+	p.sleptMillis = h.SleptMillis
+	if s.debug != nil {
+		p.debugInfo = s.debug
+	}
+	p.heatmap.reset()
+	p.coverage.start(p.code)
+	p.stats = Stats{}
+	if p.history != nil {
+		p.SetHistory(len(p.history.records)) // История до снимка не отменяется
+	}
+	p.memoryBase = p.memory.Stats()
+	p.logf(LogInfo, "State restored at IP 0x%X after %d instruction(s)", p.psw.IP, p.usage.Instructions)
+	return nil
+}
+
+// queuedEvents возвращает события, ожидающие в канале, не забирая их из очереди
+func (p *Processor) queuedEvents() []Event {
+	var events []Event
+	for range len(p.events) {
+		select {
+		case ev := <-p.events:
+			events = append(events, ev)
+		default:
+		}
+	}
+	for _, ev := range events {
+		p.PostEvent(ev) // Возвращаем события в очередь в прежнем порядке
+	}
+	return events
+}
+
+// stateDevices возвращает регионы подключенных устройств, сохраняемых в снимке
+func (m *Memory) stateDevices() []*Region {
+	var regions []*Region
+	for _, r := range m.regions {
+		if _, ok := r.device.(stateDevice); ok && r.attached {
+			regions = append(regions, r)
+		}
+	}
+	return regions
+}
+
+// SaveStateFile записывает снимок состояния процессора в файл
+func (p *Processor) SaveStateFile(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("unable to create file: %v", err)
+	}
+	if err := p.SaveState(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// LoadStateFile восстанавливает снимок состояния процессора из файла
+func (p *Processor) LoadStateFile(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("unable to open file: %v", err)
+	}
+	defer file.Close()
+	return p.LoadState(file)
+}
+
+// LoadSnapshotFile читает дамп памяти (Core.Write) или снимок состояния (SaveState)
+// и возвращает его регистры, PSW и память в виде дампа
+func LoadSnapshotFile(filename string) (*Core, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file: %v", err)
+	}
+	defer file.Close()
+	r := bufio.NewReader(file)
+	switch magic, _ := r.Peek(len(CORE_MAGIC)); string(magic) {
+	case CORE_MAGIC:
+		return ReadCore(r)
+	case STATE_MAGIC:
+		s, err := readState(r)
+		if err != nil {
+			return nil, err
+		}
+		return s.core(), nil
+	}
+	return nil, fmt.Errorf("not a vm core or state file")
+}
+
+// saveState возвращает регистры таймера
+func (t *Timer) saveState() []int32 {
+	return []int32{t.ctrl, t.reload, t.count, int32(t.irq)}
+}
+
+// loadState восстанавливает регистры таймера
+func (t *Timer) loadState(regs []int32) {
+	t.ctrl, t.reload, t.count, t.irq = regs[0], regs[1], regs[2], uint8(regs[3])
+}
+
+// saveState возвращает регистры MMU
+func (u *MMU) saveState() []int32 {
+	var enabled int32
+	if u.enabled {
+		enabled = 1
+	}
+	return []int32{enabled, int32(u.ptbr), int32(u.faultAddr), int32(u.faultCause)}
+}
+
+// loadState восстанавливает регистры MMU
+func (u *MMU) loadState(regs []int32) {
+	u.enabled = regs[0] != 0
+	u.ptbr, u.faultAddr, u.faultCause = int(regs[1]), int(regs[2]), int(regs[3])
+}
+
+// saveState возвращает регистры диска; секторы уже хранятся в файле образа
+func (d *Disk) saveState() []int32 {
+	return []int32{d.sector, d.addr, d.status}
+}
+
+// loadState восстанавливает регистры диска
+func (d *Disk) loadState(regs []int32) {
+	d.sector, d.addr, d.status = regs[0], regs[1], regs[2]
+}
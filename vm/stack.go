@@ -0,0 +1,63 @@
+package vm
+
+import "fmt"
+
+// StackError описывает нарушение границ стека
+type StackError struct {
+	Operation string // Операция со стеком (push или pop)
+	SP        uint16 // Значение указателя стека в момент ошибки
+}
+
+// Error реализует интерфейс error для StackError
+func (e *StackError) Error() string {
+	if e.Operation == "push" {
+		return fmt.Sprintf("stack overflow at SP 0x%X", e.SP)
+	}
+	return fmt.Sprintf("stack underflow at SP 0x%X", e.SP)
+}
+
+// push помещает слово в стек, растущий вниз
+func (p *Processor) push(word Word) error {
+	newSP := int(p.psw.SP) - WORD_SIZE
+	if newSP < p.stackLimit { // Стек не может выйти за нижнюю границу
+		return &StackError{Operation: "push", SP: p.psw.SP}
+	}
+	if err := p.memory.WriteWord(newSP, word); err != nil {
+		return err // Возвращаем ошибку, если запись в память не удалась
+	}
+	p.psw.SP = uint16(newSP) // Сдвигаем указатель стека на новую вершину
+	return nil
+}
+
+// pop извлекает слово с вершины стека
+func (p *Processor) pop() (Word, error) {
+	if int(p.psw.SP) >= p.stackBase { // Стек пуст
+		return Word{}, &StackError{Operation: "pop", SP: p.psw.SP}
+	}
+	word, err := p.memory.ReadWord(int(p.psw.SP))
+	if err != nil {
+		return Word{}, err // Возвращаем ошибку, если чтение из памяти не удалось
+	}
+	p.psw.SP += WORD_SIZE // Сдвигаем указатель стека к корню
+	return word, nil
+}
+
+// SetStack задает корень стека base и нижнюю границу limit; SP устанавливается в base.
+// Стек занимает адреса [limit, base), слово по адресу base не используется.
+func (p *Processor) SetStack(base, limit int) error {
+	if base < 0 || base >= p.memory.WordLimit() {
+		return fmt.Errorf("stack base 0x%X is out of memory range", base)
+	}
+	if limit < 0 || limit > base {
+		return fmt.Errorf("stack limit 0x%X must be in range [0-0x%X]", limit, base)
+	}
+	p.stackBase = base      // Запоминаем корень стека
+	p.stackLimit = limit    // Запоминаем нижнюю границу стека
+	p.psw.SP = uint16(base) // Стек становится пустым
+	return nil
+}
+
+// GetSP возвращает текущее значение указателя стека
+func (p *Processor) GetSP() uint16 {
+	return p.psw.SP
+}
@@ -0,0 +1,74 @@
+package vm
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// MemoryChange — изменение слова памяти
+type MemoryChange struct {
+	Code     bool // Слово памяти команд гарвардского режима, иначе памяти данных
+	Address  int
+	Old, New Word
+}
+
+// StateDiff — различия двух состояний машины: регистры, флаги и слова памяти
+type StateDiff struct {
+	Registers []RegisterChange // IP, регистры, SP и флаги
+	Memory    []MemoryChange   // Слова памяти в порядке адресов: сначала данные, затем команды
+}
+
+// Empty сообщает, совпадают ли состояния
+func (d *StateDiff) Empty() bool {
+	return len(d.Registers) == 0 && len(d.Memory) == 0
+}
+
+// DiffCores сравнивает два дампа или снимка (LoadSnapshotFile) и возвращает
+// изменения от before к after. Окна устройств в образах памяти записаны нулями и
+// не сравниваются.
+func DiffCores(before, after *Core) (*StateDiff, error) {
+	if (before.Code != nil) != (after.Code != nil) || len(before.Memory) != len(after.Memory) || len(before.Code) != len(after.Code) {
+		return nil, fmt.Errorf("states have different memory layouts")
+	}
+	d := &StateDiff{}
+	if before.PSW.IP != after.PSW.IP {
+		d.Registers = append(d.Registers, RegisterChange{Name: "IP", Old: float64(before.PSW.IP), New: float64(after.PSW.IP)})
+	}
+	d.Registers = append(d.Registers, coreRegisterState(before).changes(coreRegisterState(after))...)
+	d.Memory = append(diffImages(before.Memory, after.Memory, false), diffImages(before.Code, after.Code, true)...)
+	return d, nil
+}
+
+// coreRegisterState возвращает регистры, SP и флаги дампа
+func coreRegisterState(c *Core) registerState {
+	return registerState{registers: c.Registers, fregisters: c.FRegisters, sp: c.PSW.SP, flags: c.PSW.traceFlags()}
+}
+
+// diffImages сравнивает образы памяти одинакового размера по словам
+func diffImages(before, after []byte, code bool) []MemoryChange {
+	var changes []MemoryChange
+	for address := 0; address+WORD_SIZE <= len(after); address += WORD_SIZE {
+		old := binary.LittleEndian.Uint64(before[address:])
+		cur := binary.LittleEndian.Uint64(after[address:])
+		if old != cur {
+			changes = append(changes, MemoryChange{Code: code, Address: address, Old: wordFromBits(old), New: wordFromBits(cur)})
+		}
+	}
+	return changes
+}
+
+// StepDiff выполняет одну инструкцию, как Step, и возвращает изменения регистров,
+// флагов и памяти. Если инструкция остановила программу ошибкой, возвращаются и
+// изменения, и ошибка.
+func (p *Processor) StepDiff() (*StateDiff, error) {
+	if p.stop || p.error {
+		return nil, fmt.Errorf("processor is not running")
+	}
+	before := p.Core()
+	err := p.step()
+	d, diffErr := DiffCores(before, p.Core())
+	if diffErr != nil {
+		return nil, diffErr
+	}
+	return d, err
+}
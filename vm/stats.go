@@ -0,0 +1,34 @@
+package vm
+
+import "time"
+
+// Stats — статистика выполнения программы с последнего Reset
+type Stats struct {
+	Instructions int           // Выполненные инструкции
+	MemoryReads  int           // Чтения памяти данных (без выборки команд)
+	MemoryWrites int           // Записи в память данных
+	Branches     int           // Выполненные переходы: команды, изменившие IP
+	Interrupts   int           // Обработанные прерывания
+	WallTime     time.Duration // Время, проведенное в Run и RunContext
+	Memory       MemoryStats   // Обращения к памяти данных по видам, включая ошибки
+}
+
+// InstructionsPerSecond возвращает скорость выполнения по WallTime или 0, если время
+// не измерено
+func (s Stats) InstructionsPerSecond() float64 {
+	if s.WallTime <= 0 {
+		return 0
+	}
+	return float64(s.Instructions) / s.WallTime.Seconds()
+}
+
+// Stats возвращает статистику выполнения с последнего Reset
+func (p *Processor) Stats() Stats {
+	s := p.stats
+	s.Instructions = p.usage.Instructions
+	s.Interrupts = p.usage.Interrupts
+	s.Memory = p.memory.Stats().Sub(p.memoryBase)
+	s.MemoryReads = s.Memory.Reads()
+	s.MemoryWrites = s.Memory.Writes()
+	return s
+}
@@ -0,0 +1,97 @@
+package vm
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Исходные тексты стандартной библиотеки подпрограмм
+//
+//go:embed stdlib/*.asm
+var stdlibSources embed.FS
+
+// StandardLibrary ассемблирует модули стандартной библиотеки (каталог stdlib).
+// Подпрограммы вызываются командой CALL, принимают аргументы в регистрах a1, a2
+// и возвращают результат в a1.
+func StandardLibrary() ([]*Object, error) {
+	entries, err := stdlibSources.ReadDir("stdlib")
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	var library []*Object
+	for _, entry := range entries {
+		name := path.Join("stdlib", entry.Name())
+		file, err := stdlibSources.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		obj, err := AssembleObject(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		obj.Name, obj.Source = name, name
+		library = append(library, obj)
+	}
+	return library, nil
+}
+
+// LinkWithLibrary компонует модули objects, добавляя из library только те модули,
+// которые определяют еще не разрешенные глобальные символы (как архив в компоновщиках
+// хоста). Модули библиотеки размещаются после модулей программы.
+func LinkWithLibrary(objects, library []*Object) (*Image, error) {
+	selected := append([]*Object(nil), objects...)
+	used := make([]bool, len(library))
+	for {
+		undefined := undefinedSymbols(selected)
+		added := false
+		for i, lib := range library {
+			if used[i] {
+				continue
+			}
+			for _, sym := range lib.Symbols {
+				if sym.Global && undefined[sym.Name] {
+					selected = append(selected, lib)
+					used[i], added = true, true
+					break
+				}
+			}
+		}
+		if !added {
+			return Link(selected)
+		}
+	}
+}
+
+// undefinedSymbols возвращает внешние символы модулей, не определенные ни в одном из них
+func undefinedSymbols(objects []*Object) map[string]bool {
+	defined := make(map[string]bool)
+	for _, obj := range objects {
+		for _, sym := range obj.Symbols {
+			if sym.Global {
+				defined[sym.Name] = true
+			}
+		}
+	}
+	undefined := make(map[string]bool)
+	for _, obj := range objects {
+		local := make(map[string]bool)
+		for _, sym := range obj.Symbols {
+			local[sym.Name] = true
+		}
+		references := []string{obj.Entry}
+		for _, r := range obj.Relocations {
+			references = append(references, r.Symbol)
+		}
+		for _, name := range references {
+			if name != "" && !strings.HasPrefix(name, ".") && !local[name] && !defined[name] {
+				undefined[name] = true
+			}
+		}
+	}
+	return undefined
+}
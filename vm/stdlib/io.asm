@@ -0,0 +1,17 @@
+; Стандартная библиотека: вывод строк
+;
+; newline — выводит перевод строки
+; puts    — выводит строку по адресу из a1 и перевод строки
+
+        .global newline, puts
+        .text
+newline:
+        COUT  [nl]
+        RET
+puts:
+        SOUT  [a1]
+        CALL  newline
+        RET
+
+        .data
+nl:     .int  10
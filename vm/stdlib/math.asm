@@ -0,0 +1,39 @@
+; Стандартная библиотека: целочисленные функции
+;
+; abs — a1 = |a1|
+; min — a1 = min(a1, a2)
+; max — a1 = max(a1, a2)
+; Остальные регистры сохраняются.
+
+        .global abs, min, max
+        .text
+abs:
+        STORE [left], a1
+        CMP   [left], [zero]
+        JGE   abs_done
+        NEGR  a1
+abs_done:
+        RET
+
+min:
+        STORE [left], a1
+        STORE [right], a2
+        CMP   [left], [right]
+        JLE   min_done
+        MOVR  a1, a2
+min_done:
+        RET
+
+max:
+        STORE [left], a1
+        STORE [right], a2
+        CMP   [left], [right]
+        JGE   max_done
+        MOVR  a1, a2
+max_done:
+        RET
+
+        .data
+left:   .int  0
+right:  .int  0
+zero:   .int  0
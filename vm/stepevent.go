@@ -0,0 +1,73 @@
+package vm
+
+// StepEvent описывает одну выполненную команду: ее адрес, код и изменения регистров
+type StepEvent struct {
+	IP      uint16           // Адрес команды
+	Cmd     CommandData      // Выбранная команда; нулевая, если выборка не удалась
+	Changes []RegisterChange // Регистры, SP и флаги, измененные командой
+	Err     error            // Ошибка, остановившая программу на этой команде
+}
+
+// RegisterChange — изменение регистра, указателя стека или флага. Целые значения
+// и флаги (0 или 1) представлены точно.
+type RegisterChange struct {
+	Name     string // a1, a2, f0-f3, SP, ZF, SF, CF, OF, IF или UM; в StateDiff также IP
+	Old, New float64
+}
+
+// StepHandler получает событие после каждой выполненной команды
+type StepHandler func(StepEvent)
+
+// SetStepHandler устанавливает получателя событий о выполненных командах; nil
+// отключает события. Обработчик вызывается в горутине, выполняющей программу, и
+// может заблокироваться, чтобы замедлить выполнение до скорости получателя.
+func (p *Processor) SetStepHandler(h StepHandler) {
+	p.onStep = h
+}
+
+// registerState — регистры, SP и флаги, которые сравниваются для StepEvent
+type registerState struct {
+	registers  [NUM_REGISTERS]int32
+	fregisters [NUM_FLOAT_REGISTERS]float32
+	sp         uint16
+	flags      uint8 // Флаги в порядке traceFlags
+}
+
+// Имена флагов в порядке битов traceFlags
+var stepFlagNames = [...]string{"ZF", "SF", "CF", "OF", "IF", "UM"}
+
+// Имена регистров в событиях
+var (
+	stepRegisterNames      = [NUM_REGISTERS]string{"a1", "a2"}
+	stepFloatRegisterNames = [NUM_FLOAT_REGISTERS]string{"f0", "f1", "f2", "f3"}
+)
+
+// registerState возвращает текущие значения регистров, SP и флагов
+func (p *Processor) registerState() registerState {
+	return registerState{registers: p.registers, fregisters: p.fregisters, sp: p.psw.SP, flags: p.psw.traceFlags()}
+}
+
+// changes перечисляет значения, отличающиеся в состоянии after
+func (before registerState) changes(after registerState) []RegisterChange {
+	var changes []RegisterChange
+	for i, v := range after.registers {
+		if v != before.registers[i] {
+			changes = append(changes, RegisterChange{Name: stepRegisterNames[i], Old: float64(before.registers[i]), New: float64(v)})
+		}
+	}
+	for i, v := range after.fregisters {
+		if v != before.fregisters[i] && !(v != v && before.fregisters[i] != before.fregisters[i]) { // NaN остается NaN
+			changes = append(changes, RegisterChange{Name: stepFloatRegisterNames[i], Old: float64(before.fregisters[i]), New: float64(v)})
+		}
+	}
+	if after.sp != before.sp {
+		changes = append(changes, RegisterChange{Name: "SP", Old: float64(before.sp), New: float64(after.sp)})
+	}
+	for i, name := range stepFlagNames {
+		old, cur := before.flags>>i&1, after.flags>>i&1
+		if old != cur {
+			changes = append(changes, RegisterChange{Name: name, Old: float64(old), New: float64(cur)})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,97 @@
+package vm
+
+import (
+	"context"
+	"fmt"
+)
+
+// Step выполняет ровно одну инструкцию и возвращает ее код операции и новое значение IP.
+// Если перед инструкцией было принято прерывание, выполняется первая команда обработчика.
+func (p *Processor) Step() (OpCode, uint16, error) {
+	if p.stop || p.error {
+		return 0, p.psw.IP, fmt.Errorf("processor is not running")
+	}
+	err := p.step()
+	return OpCode(p.fetched.Opcode), p.psw.IP, err
+}
+
+// Stopped сообщает, остановлена ли программа (командой STOP, ошибкой или квотой)
+func (p *Processor) Stopped() bool {
+	return p.stop || p.error
+}
+
+// StepOver выполняет одну инструкцию; если это CALL, подпрограмма выполняется до возврата.
+// Точка останова внутри подпрограммы прерывает выполнение с *BreakpointHit.
+func (p *Processor) StepOver() error {
+	return p.StepOverContext(context.Background())
+}
+
+// StepOverContext выполняет StepOver, проверяя ctx между инструкциями подпрограммы,
+// как RunContext
+func (p *Processor) StepOverContext(ctx context.Context) error {
+	if p.stop || p.error {
+		return fmt.Errorf("processor is not running")
+	}
+	// Запоминаем инструкцию и глубину вложенности до шага
+	word, err := p.code.PeekWord(int(p.psw.IP))
+	if err != nil {
+		return err
+	}
+	depth := p.callDepth
+	if err := p.step(); err != nil {
+		return err
+	}
+	if OpCode(word.Cmd.Opcode) != CALL {
+		return nil // Обычная инструкция: достаточно одного шага
+	}
+	// Выполняем вызванную подпрограмму, пока она не вернет управление
+	return p.runWhile(ctx, func() bool { return p.callDepth > depth })
+}
+
+// StepOut выполняет программу до возврата из текущей подпрограммы. Точка останова
+// прерывает выполнение с *BreakpointHit, кроме точки на текущей команде.
+func (p *Processor) StepOut() error {
+	return p.StepOutContext(context.Background())
+}
+
+// StepOutContext выполняет StepOut, проверяя ctx между инструкциями, как RunContext
+func (p *Processor) StepOutContext(ctx context.Context) error {
+	if p.stop || p.error {
+		return fmt.Errorf("processor is not running")
+	}
+	depth := p.callDepth
+	if depth == 0 {
+		return fmt.Errorf("not inside a subroutine")
+	}
+	// Первая команда выполняется без проверки точки останова, как в RunContext
+	if err := p.step(); err != nil {
+		return err
+	}
+	return p.runWhile(ctx, func() bool { return p.callDepth >= depth })
+}
+
+// CallDepth возвращает текущую глубину вложенности подпрограмм
+func (p *Processor) CallDepth() int {
+	return p.callDepth
+}
+
+// runWhile выполняет инструкции, пока выполняется условие и процессор не остановлен.
+// Как RunContext, перед каждой инструкцией ждет Resume при паузе, проверяет ctx и
+// возвращает *BreakpointHit перед командой с точкой останова.
+func (p *Processor) runWhile(ctx context.Context, cond func() bool) error {
+	p.control.setRunning(true)
+	defer p.control.setRunning(false)
+	for !p.stop && !p.error && cond() {
+		if err := p.interruption(ctx); err != nil {
+			return err
+		}
+		if p.ShouldBreak(p.psw.IP) {
+			p.logf(LogInfo, "Breakpoint at 0x%X", p.psw.IP)
+			return &BreakpointHit{Address: p.psw.IP, Condition: p.BreakpointCondition(p.psw.IP)}
+		}
+		if err := p.step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package vm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Упаковка строк в память: каждое слово данных хранит до CHARS_PER_WORD байт строки
+// в 32-битном значении, первый символ — в младшем байте. Строка завершается нулевым
+// байтом; если длина кратна CHARS_PER_WORD, терминатором служит следующее нулевое слово.
+const CHARS_PER_WORD = 4 // Количество символов в одном слове
+
+// PackString упаковывает строку вместе с завершающим нулем в слова данных
+func PackString(s string) []Word {
+	data := append([]byte(s), 0)
+	words := make([]Word, 0, (len(data)+CHARS_PER_WORD-1)/CHARS_PER_WORD)
+	for i := 0; i < len(data); i += CHARS_PER_WORD {
+		var value uint32
+		for j := 0; j < CHARS_PER_WORD && i+j < len(data); j++ {
+			value |= uint32(data[i+j]) << (8 * j)
+		}
+		words = append(words, IntWord(int32(value)))
+	}
+	return words
+}
+
+// readString читает упакованную строку, начиная со слова по адресу address
+func (p *Processor) readString(address int) (string, error) {
+	var sb strings.Builder
+	for {
+		word, err := p.memory.ReadWord(address)
+		if err != nil {
+			return "", err // Строка не завершена до конца памяти или адрес недопустим
+		}
+		value := uint32(word.D.I)
+		for j := 0; j < CHARS_PER_WORD; j++ {
+			c := byte(value >> (8 * j))
+			if c == 0 {
+				return sb.String(), nil
+			}
+			sb.WriteByte(c)
+		}
+		address += WORD_SIZE
+	}
+}
+
+// writeString записывает строку, упакованную PackString, начиная с адреса address
+func (p *Processor) writeString(address int, s string) error {
+	for _, word := range PackString(s) {
+		if err := p.memory.WriteWord(address, word); err != nil {
+			return err
+		}
+		address += WORD_SIZE
+	}
+	return nil
+}
+
+// readLine читает строку ввода до перевода строки по одному байту, чтобы не забрать
+// данные следующих команд. В строку попадает не более limit байт, остаток строки
+// отбрасывается. eof сообщает, что ввод закончился до получения хотя бы одного байта.
+func readLine(in io.Reader, limit int) (line string, eof bool, err error) {
+	var sb strings.Builder
+	var buf [1]byte
+	read := 0
+	for {
+		n, rerr := in.Read(buf[:])
+		if n == 1 {
+			read++
+			if buf[0] == '\n' {
+				break
+			}
+			if sb.Len() < limit {
+				sb.WriteByte(buf[0])
+			}
+			continue
+		}
+		if rerr == io.EOF {
+			if read == 0 {
+				return "", true, nil
+			}
+			break
+		}
+		if rerr != nil {
+			return "", false, rerr
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\r"), false, nil
+}
+
+// readInputLine читает строку ввода команд IIN и RIN из p.In. Строка читается по
+// байту, поэтому следующие строки ввода остаются для следующих команд.
+func (p *Processor) readInputLine() (InputEvent, error) {
+	line, _, err := readLine(p.In, bufio.MaxScanTokenSize)
+	if err != nil {
+		return InputEvent{}, fmt.Errorf("failed to read input: %w", err)
+	}
+	return InputEvent{Value: line}, nil
+}
@@ -0,0 +1,121 @@
+package vm
+
+import "fmt"
+
+// Регистры таймера (смещения относительно начала окна устройства)
+const (
+	TIMER_CTRL   = 0 * WORD_SIZE // Управление: бит 0 — включен, бит 1 — периодический режим
+	TIMER_RELOAD = 1 * WORD_SIZE // Период в инструкциях
+	TIMER_COUNT  = 2 * WORD_SIZE // Оставшееся число инструкций до срабатывания
+	TIMER_IRQ    = 3 * WORD_SIZE // Номер линии прерывания
+
+	TIMER_SIZE = 4 * WORD_SIZE // Размер окна таймера в байтах
+	TIMER_BASE = 0xF020        // Адрес окна таймера по умолчанию
+)
+
+// Биты регистра управления таймером
+const (
+	TIMER_ENABLE   = 0x01 // Таймер считает инструкции
+	TIMER_PERIODIC = 0x02 // После срабатывания счетчик перезагружается из TIMER_RELOAD
+)
+
+// Ticker — устройство, которое получает такт после каждой выполненной инструкции
+type Ticker interface {
+	Tick()
+}
+
+// Timer — программируемый таймер, считающий выполненные инструкции и
+// выставляющий прерывание при истечении счетчика.
+// Регистры изменяются только из горутины, выполняющей программу.
+type Timer struct {
+	ctrl   int32           // Регистр управления
+	reload int32           // Период в инструкциях
+	count  int32           // Текущее значение счетчика
+	irq    uint8           // Линия прерывания
+	raise  func(irq uint8) // Доставка прерывания процессору
+}
+
+// Read читает регистр таймера
+func (t *Timer) Read(addr int) (Word, error) {
+	switch addr {
+	case TIMER_CTRL:
+		return IntWord(t.ctrl), nil
+	case TIMER_RELOAD:
+		return IntWord(t.reload), nil
+	case TIMER_COUNT:
+		return IntWord(t.count), nil
+	case TIMER_IRQ:
+		return IntWord(int32(t.irq)), nil
+	}
+	return Word{}, fmt.Errorf("timer register 0x%X does not exist", addr)
+}
+
+// Write записывает регистр таймера. Включение таймера с нулевым счетчиком
+// загружает счетчик из TIMER_RELOAD.
+func (t *Timer) Write(addr int, word Word) error {
+	value := word.D.I
+	switch addr {
+	case TIMER_CTRL:
+		t.ctrl = value
+		if t.ctrl&TIMER_ENABLE != 0 && t.count <= 0 {
+			t.count = t.reload
+		}
+	case TIMER_RELOAD:
+		if value < 0 {
+			return fmt.Errorf("negative timer period %d", value)
+		}
+		t.reload = value
+	case TIMER_COUNT:
+		t.count = value
+	case TIMER_IRQ:
+		t.irq = uint8(value)
+	default:
+		return fmt.Errorf("timer register 0x%X does not exist", addr)
+	}
+	return nil
+}
+
+// Tick уменьшает счетчик и выставляет прерывание, когда он достигает нуля
+func (t *Timer) Tick() {
+	if t.ctrl&TIMER_ENABLE == 0 || t.count <= 0 {
+		return
+	}
+	t.count--
+	if t.count > 0 {
+		return
+	}
+	if t.ctrl&TIMER_PERIODIC != 0 {
+		t.count = t.reload // Периодический режим: начинаем новый отсчет
+	} else {
+		t.ctrl &^= TIMER_ENABLE // Однократный режим: таймер выключается
+	}
+	if t.raise != nil {
+		t.raise(t.irq)
+	}
+}
+
+// MapTimer создает таймер, отображает его в память по адресу base и подключает к тактам процессора
+func (p *Processor) MapTimer(base int) (*Timer, error) {
+	t := &Timer{raise: func(irq uint8) {
+		if err := p.RaiseInterrupt(irq); err != nil {
+			p.log.Logf(SubsystemDevices, LogWarn, "Timer: %v", err) // Прерывание потеряно из-за переполнения очереди
+		}
+	}}
+	if _, err := p.memory.MapDevice("timer", base, TIMER_SIZE, t); err != nil {
+		return nil, err
+	}
+	p.AddTicker(t)
+	return t, nil
+}
+
+// AddTicker подключает устройство к тактам процессора
+func (p *Processor) AddTicker(t Ticker) {
+	p.tickers = append(p.tickers, t)
+}
+
+// tickDevices передает такт всем подключенным устройствам
+func (p *Processor) tickDevices() {
+	for _, t := range p.tickers {
+		t.Tick()
+	}
+}
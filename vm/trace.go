@@ -0,0 +1,180 @@
+package vm
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Двоичная трасса выполнения: заголовок TRACE_MAGIC, затем по записи фиксированного
+// размера TRACE_RECORD_SIZE на каждую выполненную инструкцию (little-endian):
+//
+//	0-1  IP команды
+//	2    код операции
+//	3    режим BB
+//	4-5  Address1
+//	6-7  Address2
+//	8    флаги до команды (TRACE_ZF...TRACE_UM)
+//	9    флаги после команды; TRACE_FAULT — команда завершилась ошибкой
+const (
+	TRACE_MAGIC       = "VMTRACE\x01" // Сигнатура и версия формата
+	TRACE_RECORD_SIZE = 10            // Размер записи в байтах
+)
+
+// Биты флагов в записи трассы
+const (
+	TRACE_ZF    = 0x01 // Флаг нуля
+	TRACE_SF    = 0x02 // Флаг знака
+	TRACE_CF    = 0x04 // Флаг переноса
+	TRACE_OF    = 0x08 // Флаг переполнения
+	TRACE_IF    = 0x10 // Прерывания разрешены
+	TRACE_UM    = 0x20 // Режим пользователя
+	TRACE_FAULT = 0x80 // Команда завершилась ошибкой (только во флагах после команды)
+)
+
+// Размер буфера записи трассы
+const traceBufferSize = 64 * 1024
+
+// TraceRecord — запись трассы об одной выполненной инструкции
+type TraceRecord struct {
+	IP          uint16      // Адрес команды
+	Cmd         CommandData // Выбранная команда; нулевая, если выборка не удалась
+	FlagsBefore uint8       // Флаги до выполнения
+	FlagsAfter  uint8       // Флаги после выполнения и TRACE_FAULT
+}
+
+// Fault сообщает, завершилась ли команда ошибкой
+func (r TraceRecord) Fault() bool {
+	return r.FlagsAfter&TRACE_FAULT != 0
+}
+
+// String описывает запись: адрес, дизассемблированную команду и изменение флагов
+func (r TraceRecord) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "0x%04X  %-24s %s", r.IP, DisassembleWord(int(r.IP), CommandWord(r.Cmd)), describeFlags(r.FlagsBefore))
+	if after := r.FlagsAfter &^ TRACE_FAULT; after != r.FlagsBefore {
+		fmt.Fprintf(&b, " -> %s", describeFlags(after))
+	}
+	if r.Fault() {
+		b.WriteString("  fault")
+	}
+	return b.String()
+}
+
+// describeFlags описывает флаги как в отладчике: установленные — прописными буквами
+func describeFlags(flags uint8) string {
+	names := []string{"zf", "sf", "cf", "of", "if", "um"}
+	for i := range names {
+		if flags&(1<<i) != 0 {
+			names[i] = strings.ToUpper(names[i])
+		}
+	}
+	return strings.Join(names, " ")
+}
+
+// traceFlags упаковывает флаги PSW для трассы
+func (psw PSW) traceFlags() uint8 {
+	var f uint8
+	for i, set := range []bool{psw.ZeroFlag, psw.SignFlag, psw.CarryFlag, psw.OverflowFlag, psw.InterruptFlag, psw.UserMode} {
+		if set {
+			f |= 1 << i
+		}
+	}
+	return f
+}
+
+// tracer записывает трассу в буферизованный поток
+type tracer struct {
+	w   *bufio.Writer
+	buf [TRACE_RECORD_SIZE]byte
+	err error // Первая ошибка записи; после нее трасса не пишется
+}
+
+// SetTrace включает запись двоичной трассы выполнения в w и записывает заголовок;
+// nil выключает трассу. Записи буферизуются: после запуска вызовите FlushTrace.
+func (p *Processor) SetTrace(w io.Writer) error {
+	if p.trace != nil {
+		if err := p.FlushTrace(); err != nil {
+			return err
+		}
+	}
+	if w == nil {
+		p.trace = nil
+		return nil
+	}
+	t := &tracer{w: bufio.NewWriterSize(w, traceBufferSize)}
+	if _, err := t.w.WriteString(TRACE_MAGIC); err != nil {
+		return fmt.Errorf("failed to write trace header: %v", err)
+	}
+	p.trace = t
+	return nil
+}
+
+// FlushTrace дописывает буферизованные записи трассы и возвращает первую ошибку записи
+func (p *Processor) FlushTrace() error {
+	t := p.trace
+	if t == nil {
+		return nil
+	}
+	if t.err == nil {
+		t.err = t.w.Flush()
+	}
+	if t.err != nil {
+		return fmt.Errorf("failed to write trace: %v", t.err)
+	}
+	return nil
+}
+
+// record записывает выполненную инструкцию
+func (t *tracer) record(r TraceRecord) {
+	if t.err != nil {
+		return
+	}
+	binary.LittleEndian.PutUint16(t.buf[0:], r.IP)
+	t.buf[2] = r.Cmd.Opcode
+	t.buf[3] = r.Cmd.BB
+	binary.LittleEndian.PutUint16(t.buf[4:], r.Cmd.Address1)
+	binary.LittleEndian.PutUint16(t.buf[6:], r.Cmd.Address2)
+	t.buf[8] = r.FlagsBefore
+	t.buf[9] = r.FlagsAfter
+	_, t.err = t.w.Write(t.buf[:])
+}
+
+// TraceReader читает двоичную трассу, записанную SetTrace
+type TraceReader struct {
+	r   *bufio.Reader
+	buf [TRACE_RECORD_SIZE]byte
+}
+
+// NewTraceReader проверяет заголовок трассы и возвращает читатель записей
+func NewTraceReader(r io.Reader) (*TraceReader, error) {
+	br := bufio.NewReaderSize(r, traceBufferSize)
+	magic := make([]byte, len(TRACE_MAGIC))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != TRACE_MAGIC {
+		return nil, fmt.Errorf("not a vm trace file")
+	}
+	return &TraceReader{r: br}, nil
+}
+
+// Next возвращает следующую запись трассы или io.EOF в конце трассы
+func (tr *TraceReader) Next() (TraceRecord, error) {
+	if _, err := io.ReadFull(tr.r, tr.buf[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return TraceRecord{}, fmt.Errorf("truncated trace record")
+		}
+		return TraceRecord{}, err
+	}
+	return TraceRecord{
+		IP: binary.LittleEndian.Uint16(tr.buf[0:]),
+		Cmd: CommandData{
+			Opcode:   tr.buf[2],
+			BB:       tr.buf[3],
+			Address1: binary.LittleEndian.Uint16(tr.buf[4:]),
+			Address2: binary.LittleEndian.Uint16(tr.buf[6:]),
+		},
+		FlagsBefore: tr.buf[8],
+		FlagsAfter:  tr.buf[9],
+	}, nil
+}
@@ -0,0 +1,112 @@
+package vm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TraceFilter ограничивает журнал выполнения и двоичную трассу командами с
+// заданными кодами операций и адресами. Пустой набор условий пропускает все команды.
+type TraceFilter struct {
+	Opcodes  map[OpCode]bool // Коды операций; пусто — любые
+	IPRanges []AddressRange  // Адреса команд; пусто — любые
+}
+
+// AddressRange — диапазон адресов [Start, End] включительно
+type AddressRange struct {
+	Start, End int
+}
+
+// ParseTraceFilter разбирает фильтр вида "opcode=IADD,opcode=CALL,ip=0x100-0x140".
+// Условия одного вида объединяются через "или", условия разных видов — через "и".
+// Адрес ip задается одним числом или диапазоном "начало-конец".
+func ParseTraceFilter(spec string) (*TraceFilter, error) {
+	f := &TraceFilter{}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, value, found := strings.Cut(item, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid trace filter %q: expected opcode=NAME or ip=START-END", item)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "opcode", "op":
+			op, ok := ParseOpCode(value)
+			if !ok {
+				return nil, fmt.Errorf("invalid trace filter: unknown opcode %q", value)
+			}
+			if f.Opcodes == nil {
+				f.Opcodes = make(map[OpCode]bool)
+			}
+			f.Opcodes[op] = true
+		case "ip":
+			r, err := parseAddressRange(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trace filter: %v", err)
+			}
+			f.IPRanges = append(f.IPRanges, r)
+		default:
+			return nil, fmt.Errorf("invalid trace filter: unknown key %q (use opcode or ip)", key)
+		}
+	}
+	return f, nil
+}
+
+// parseAddressRange разбирает адрес или диапазон адресов "начало-конец"
+func parseAddressRange(s string) (AddressRange, error) {
+	startText, endText, isRange := strings.Cut(s, "-")
+	start, err := strconv.ParseUint(strings.TrimSpace(startText), 0, 16)
+	if err != nil {
+		return AddressRange{}, fmt.Errorf("invalid address %q", startText)
+	}
+	end := start
+	if isRange {
+		if end, err = strconv.ParseUint(strings.TrimSpace(endText), 0, 16); err != nil {
+			return AddressRange{}, fmt.Errorf("invalid address %q", endText)
+		}
+	}
+	if end < start {
+		return AddressRange{}, fmt.Errorf("address range %q ends before it starts", s)
+	}
+	return AddressRange{Start: int(start), End: int(end)}, nil
+}
+
+// Match сообщает, проходит ли команда op по адресу ip через фильтр
+func (f *TraceFilter) Match(ip uint16, op OpCode) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.Opcodes) > 0 && !f.Opcodes[op] {
+		return false
+	}
+	if len(f.IPRanges) == 0 {
+		return true
+	}
+	for _, r := range f.IPRanges {
+		if int(ip) >= r.Start && int(ip) <= r.End {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTraceFilter ограничивает фильтром f отладочные сообщения журнала о выполняемой
+// команде и записи двоичной трассы. Сообщения уровня LogInfo и выше, события вне
+// команд и записи трассы об ошибках не фильтруются. nil снимает фильтр.
+func (p *Processor) SetTraceFilter(f *TraceFilter) {
+	p.traceFilter = f
+}
+
+// TraceFilter возвращает установленный фильтр или nil
+func (p *Processor) TraceFilter() *TraceFilter {
+	return p.traceFilter
+}
+
+// logAccepts сообщает, пропускает ли фильтр сообщение о текущей команде
+func (p *Processor) logAccepts() bool {
+	return p.current < 0 || p.traceFilter.Match(uint16(p.current), OpCode(p.fetched.Opcode))
+}
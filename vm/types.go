@@ -0,0 +1,97 @@
+package vm
+
+import (
+	"fmt"
+	"math"
+)
+
+// Data представляет структуру, подобную объединению, для хранения различных типов данных
+type Data struct {
+	I int32   // Целочисленное значение (32-битное знаковое целое)
+	F float32 // Значение с плавающей запятой (32-битное)
+}
+
+// CommandData представляет структуру команды
+type CommandData struct {
+	Opcode   uint8  // Код операции (8 бит)
+	BB       uint8  // 2 бита для BB (включает режим регистра)
+	Address1 uint16 // Первый адрес/индекс регистра (16 бит)
+	Address2 uint16 // Второй адрес/индекс регистра (16 бит)
+}
+
+// WordTag определяет, что хранится в слове памяти
+type WordTag uint8
+
+const (
+	TagInt     WordTag = iota // Целое число (значение по умолчанию)
+	TagFloat                  // Вещественное число
+	TagCommand                // Команда
+)
+
+// Word представляет объединение Data и CommandData
+type Word struct {
+	Tag WordTag     // Тег, сохраняемый в памяти вместе со значением
+	D   Data        // Поле для хранения данных типа Data
+	Cmd CommandData // Поле для хранения данных типа CommandData
+}
+
+// IntWord создает слово данных с целым значением
+func IntWord(value int32) Word {
+	var w Word
+	w.SetInt(value)
+	return w
+}
+
+// FloatWord создает слово данных с вещественным значением
+func FloatWord(value float32) Word {
+	var w Word
+	w.SetFloat(value)
+	return w
+}
+
+// CommandWord создает слово команды
+func CommandWord(cmd CommandData) Word {
+	return Word{Tag: TagCommand, Cmd: cmd}
+}
+
+// IsCommand сообщает, содержит ли слово команду
+func (w Word) IsCommand() bool {
+	return w.Tag == TagCommand
+}
+
+// SetInt записывает в слово целое значение; поле F отражает те же 32 бита
+func (w *Word) SetInt(value int32) {
+	w.Tag = TagInt
+	w.D.I = value
+	w.D.F = math.Float32frombits(uint32(value))
+}
+
+// SetFloat записывает в слово вещественное значение; поле I отражает те же 32 бита
+func (w *Word) SetFloat(value float32) {
+	w.Tag = TagFloat
+	w.D.F = value
+	w.D.I = int32(math.Float32bits(value))
+}
+
+// MemoryError представляет ошибки доступа к памяти
+type MemoryError struct {
+	Operation string // Описание операции, вызвавшей ошибку
+	Address   int    // Адрес, по которому произошла ошибка
+	Message   string // Сообщение об ошибке
+}
+
+// Error реализует интерфейс error для MemoryError
+func (e *MemoryError) Error() string {
+	return fmt.Sprintf("memory %s at 0x%X: %s", e.Operation, e.Address, e.Message)
+}
+
+// AlignmentError сообщает о невыровненном доступе к слову в строгом режиме
+type AlignmentError struct {
+	Operation string // Операция, вызвавшая ошибку (read или write)
+	Address   int    // Невыровненный адрес
+}
+
+// Error реализует интерфейс error для AlignmentError
+func (e *AlignmentError) Error() string {
+	return fmt.Sprintf("unaligned word %s at 0x%X (word size %d)", e.Operation, e.Address, WORD_SIZE)
+}
@@ -0,0 +1,128 @@
+package vm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Watch описывает выражение наблюдения, вычисляемое после каждого шага
+type Watch struct {
+	ID      int    // Идентификатор выражения
+	Source  string // Исходный текст выражения
+	Value   int64  // Последнее вычисленное значение
+	Err     error  // Ошибка последнего вычисления
+	Changed bool   // Изменилось ли значение на последнем шаге
+	expr    Expr   // Разобранное выражение
+}
+
+// processorEnv связывает выражения с состоянием процессора
+type processorEnv struct {
+	p *Processor
+}
+
+// Identifier возвращает значение регистра, флага или адрес метки по имени
+func (env processorEnv) Identifier(name string) (int64, error) {
+	switch strings.ToUpper(name) {
+	case "R0", "A1":
+		return int64(env.p.registers[0]), nil
+	case "R1", "A2":
+		return int64(env.p.registers[1]), nil
+	case "F0", "F1", "F2", "F3":
+		return int64(env.p.fregisters[name[1]-'0']), nil // Вещественный регистр усекается до целого
+	case "IP":
+		return int64(env.p.psw.IP), nil
+	case "ZF":
+		return boolToInt(env.p.psw.ZeroFlag), nil
+	case "SF":
+		return boolToInt(env.p.psw.SignFlag), nil
+	case "CF":
+		return boolToInt(env.p.psw.CarryFlag), nil
+	case "OF":
+		return boolToInt(env.p.psw.OverflowFlag), nil
+	case "IF":
+		return boolToInt(env.p.psw.InterruptFlag), nil
+	case "UM":
+		return boolToInt(env.p.psw.UserMode), nil
+	case "SEGB":
+		return int64(env.p.memory.segment.Base), nil
+	case "SEGL":
+		return int64(env.p.memory.segment.Limit), nil
+	case "FLAGS":
+		return int64(env.p.GetFlags()), nil
+	}
+	if address, ok := env.p.debugInfo.LabelAddress(name); ok {
+		return int64(address), nil // Метка из отладочной информации
+	}
+	return 0, fmt.Errorf("unknown identifier %q", name)
+}
+
+// Memory возвращает целочисленное значение слова памяти. Выражения вычисляются после
+// каждого шага, поэтому чтение не должно оставлять следов: см. Memory.peekData.
+func (env processorEnv) Memory(address int64) (int64, error) {
+	if !env.p.memory.IsValidAddress(int(address)) {
+		return 0, fmt.Errorf("address 0x%X is out of range", address)
+	}
+	word, err := env.p.memory.peekData(int(address))
+	if err != nil {
+		return 0, err
+	}
+	return int64(word.D.I), nil
+}
+
+// Evaluate разбирает и вычисляет выражение в текущем состоянии процессора
+func (p *Processor) Evaluate(source string) (int64, error) {
+	e, err := ParseExpr(source)
+	if err != nil {
+		return 0, err
+	}
+	return e.Eval(processorEnv{p})
+}
+
+// AddWatch добавляет выражение наблюдения и возвращает его идентификатор
+func (p *Processor) AddWatch(source string) (int, error) {
+	e, err := ParseExpr(source)
+	if err != nil {
+		return 0, err
+	}
+	p.nextWatchID++
+	w := &Watch{ID: p.nextWatchID, Source: source, expr: e}
+	w.Value, w.Err = e.Eval(processorEnv{p}) // Вычисляем начальное значение
+	p.watches = append(p.watches, w)
+	return w.ID, nil
+}
+
+// RemoveWatch удаляет выражение наблюдения по идентификатору
+func (p *Processor) RemoveWatch(id int) bool {
+	for i, w := range p.watches {
+		if w.ID == id {
+			p.watches = append(p.watches[:i], p.watches[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Watches возвращает копии всех выражений наблюдения с последними значениями
+func (p *Processor) Watches() []Watch {
+	result := make([]Watch, len(p.watches))
+	for i, w := range p.watches {
+		result[i] = *w
+	}
+	return result
+}
+
+// updateWatches пересчитывает выражения наблюдения после выполненной инструкции
+func (p *Processor) updateWatches() {
+	for _, w := range p.watches {
+		value, err := w.expr.Eval(processorEnv{p})
+		w.Changed = value != w.Value || (err == nil) != (w.Err == nil)
+		w.Value, w.Err = value, err
+		if w.Changed {
+			if err != nil {
+				p.logf(LogDebug, "Watch #%d: %s = <%v>", w.ID, w.Source, err)
+			} else {
+				p.logf(LogDebug, "Watch #%d: %s = %d", w.ID, w.Source, value)
+			}
+		}
+	}
+}
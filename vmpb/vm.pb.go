@@ -0,0 +1,1594 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: vmpb/vm.proto
+
+package vmpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Формат текста программы
+type ProgramFormat int32
+
+const (
+	ProgramFormat_PROGRAM_FORMAT_ASM    ProgramFormat = 0 // Исходный текст ассемблера
+	ProgramFormat_PROGRAM_FORMAT_LOADER ProgramFormat = 1 // Формат загрузчика
+)
+
+// Enum value maps for ProgramFormat.
+var (
+	ProgramFormat_name = map[int32]string{
+		0: "PROGRAM_FORMAT_ASM",
+		1: "PROGRAM_FORMAT_LOADER",
+	}
+	ProgramFormat_value = map[string]int32{
+		"PROGRAM_FORMAT_ASM":    0,
+		"PROGRAM_FORMAT_LOADER": 1,
+	}
+)
+
+func (x ProgramFormat) Enum() *ProgramFormat {
+	p := new(ProgramFormat)
+	*p = x
+	return p
+}
+
+func (x ProgramFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProgramFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_vmpb_vm_proto_enumTypes[0].Descriptor()
+}
+
+func (ProgramFormat) Type() protoreflect.EnumType {
+	return &file_vmpb_vm_proto_enumTypes[0]
+}
+
+func (x ProgramFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProgramFormat.Descriptor instead.
+func (ProgramFormat) EnumDescriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{0}
+}
+
+// Память команд или данных
+type MemorySpace int32
+
+const (
+	MemorySpace_MEMORY_SPACE_DATA MemorySpace = 0
+	MemorySpace_MEMORY_SPACE_CODE MemorySpace = 1
+)
+
+// Enum value maps for MemorySpace.
+var (
+	MemorySpace_name = map[int32]string{
+		0: "MEMORY_SPACE_DATA",
+		1: "MEMORY_SPACE_CODE",
+	}
+	MemorySpace_value = map[string]int32{
+		"MEMORY_SPACE_DATA": 0,
+		"MEMORY_SPACE_CODE": 1,
+	}
+)
+
+func (x MemorySpace) Enum() *MemorySpace {
+	p := new(MemorySpace)
+	*p = x
+	return p
+}
+
+func (x MemorySpace) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MemorySpace) Descriptor() protoreflect.EnumDescriptor {
+	return file_vmpb_vm_proto_enumTypes[1].Descriptor()
+}
+
+func (MemorySpace) Type() protoreflect.EnumType {
+	return &file_vmpb_vm_proto_enumTypes[1]
+}
+
+func (x MemorySpace) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MemorySpace.Descriptor instead.
+func (MemorySpace) EnumDescriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{1}
+}
+
+// Что хранится в слове памяти
+type WordTag int32
+
+const (
+	WordTag_WORD_TAG_INT     WordTag = 0
+	WordTag_WORD_TAG_FLOAT   WordTag = 1
+	WordTag_WORD_TAG_COMMAND WordTag = 2
+)
+
+// Enum value maps for WordTag.
+var (
+	WordTag_name = map[int32]string{
+		0: "WORD_TAG_INT",
+		1: "WORD_TAG_FLOAT",
+		2: "WORD_TAG_COMMAND",
+	}
+	WordTag_value = map[string]int32{
+		"WORD_TAG_INT":     0,
+		"WORD_TAG_FLOAT":   1,
+		"WORD_TAG_COMMAND": 2,
+	}
+)
+
+func (x WordTag) Enum() *WordTag {
+	p := new(WordTag)
+	*p = x
+	return p
+}
+
+func (x WordTag) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WordTag) Descriptor() protoreflect.EnumDescriptor {
+	return file_vmpb_vm_proto_enumTypes[2].Descriptor()
+}
+
+func (WordTag) Type() protoreflect.EnumType {
+	return &file_vmpb_vm_proto_enumTypes[2]
+}
+
+func (x WordTag) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WordTag.Descriptor instead.
+func (WordTag) EnumDescriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{2}
+}
+
+// Поведение при заполненной очереди событий клиента
+type Backpressure int32
+
+const (
+	Backpressure_BACKPRESSURE_BLOCK Backpressure = 0 // Выполнение ждет клиента
+	Backpressure_BACKPRESSURE_DROP  Backpressure = 1 // События пропускаются, клиент получает DroppedEvent
+)
+
+// Enum value maps for Backpressure.
+var (
+	Backpressure_name = map[int32]string{
+		0: "BACKPRESSURE_BLOCK",
+		1: "BACKPRESSURE_DROP",
+	}
+	Backpressure_value = map[string]int32{
+		"BACKPRESSURE_BLOCK": 0,
+		"BACKPRESSURE_DROP":  1,
+	}
+)
+
+func (x Backpressure) Enum() *Backpressure {
+	p := new(Backpressure)
+	*p = x
+	return p
+}
+
+func (x Backpressure) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Backpressure) Descriptor() protoreflect.EnumDescriptor {
+	return file_vmpb_vm_proto_enumTypes[3].Descriptor()
+}
+
+func (Backpressure) Type() protoreflect.EnumType {
+	return &file_vmpb_vm_proto_enumTypes[3]
+}
+
+func (x Backpressure) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Backpressure.Descriptor instead.
+func (Backpressure) EnumDescriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{3}
+}
+
+type LoadProgramRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"` // Текст программы
+	Format        ProgramFormat          `protobuf:"varint,2,opt,name=format,proto3,enum=vm.v1.ProgramFormat" json:"format,omitempty"`
+	Input         string                 `protobuf:"bytes,3,opt,name=input,proto3" json:"input,omitempty"` // Ввод программы, по одному значению в строке
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoadProgramRequest) Reset() {
+	*x = LoadProgramRequest{}
+	mi := &file_vmpb_vm_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadProgramRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadProgramRequest) ProtoMessage() {}
+
+func (x *LoadProgramRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vmpb_vm_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadProgramRequest.ProtoReflect.Descriptor instead.
+func (*LoadProgramRequest) Descriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LoadProgramRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *LoadProgramRequest) GetFormat() ProgramFormat {
+	if x != nil {
+		return x.Format
+	}
+	return ProgramFormat_PROGRAM_FORMAT_ASM
+}
+
+func (x *LoadProgramRequest) GetInput() string {
+	if x != nil {
+		return x.Input
+	}
+	return ""
+}
+
+type RunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunRequest) Reset() {
+	*x = RunRequest{}
+	mi := &file_vmpb_vm_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunRequest) ProtoMessage() {}
+
+func (x *RunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vmpb_vm_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunRequest.ProtoReflect.Descriptor instead.
+func (*RunRequest) Descriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{1}
+}
+
+type PauseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseRequest) Reset() {
+	*x = PauseRequest{}
+	mi := &file_vmpb_vm_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseRequest) ProtoMessage() {}
+
+func (x *PauseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vmpb_vm_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseRequest.ProtoReflect.Descriptor instead.
+func (*PauseRequest) Descriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{2}
+}
+
+type StepRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         uint32                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"` // Число команд; 0 — одна
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StepRequest) Reset() {
+	*x = StepRequest{}
+	mi := &file_vmpb_vm_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StepRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StepRequest) ProtoMessage() {}
+
+func (x *StepRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vmpb_vm_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StepRequest.ProtoReflect.Descriptor instead.
+func (*StepRequest) Descriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StepRequest) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type ResetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetRequest) Reset() {
+	*x = ResetRequest{}
+	mi := &file_vmpb_vm_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetRequest) ProtoMessage() {}
+
+func (x *ResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vmpb_vm_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetRequest.ProtoReflect.Descriptor instead.
+func (*ResetRequest) Descriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{4}
+}
+
+type GetStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStateRequest) Reset() {
+	*x = GetStateRequest{}
+	mi := &file_vmpb_vm_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStateRequest) ProtoMessage() {}
+
+func (x *GetStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vmpb_vm_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStateRequest.ProtoReflect.Descriptor instead.
+func (*GetStateRequest) Descriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{5}
+}
+
+// Флаги PSW
+type Flags struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Zf            bool                   `protobuf:"varint,1,opt,name=zf,proto3" json:"zf,omitempty"`
+	Sf            bool                   `protobuf:"varint,2,opt,name=sf,proto3" json:"sf,omitempty"`
+	Cf            bool                   `protobuf:"varint,3,opt,name=cf,proto3" json:"cf,omitempty"`
+	Of            bool                   `protobuf:"varint,4,opt,name=of,proto3" json:"of,omitempty"`
+	If            bool                   `protobuf:"varint,5,opt,name=if,proto3" json:"if,omitempty"`
+	Um            bool                   `protobuf:"varint,6,opt,name=um,proto3" json:"um,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Flags) Reset() {
+	*x = Flags{}
+	mi := &file_vmpb_vm_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Flags) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Flags) ProtoMessage() {}
+
+func (x *Flags) ProtoReflect() protoreflect.Message {
+	mi := &file_vmpb_vm_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Flags.ProtoReflect.Descriptor instead.
+func (*Flags) Descriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Flags) GetZf() bool {
+	if x != nil {
+		return x.Zf
+	}
+	return false
+}
+
+func (x *Flags) GetSf() bool {
+	if x != nil {
+		return x.Sf
+	}
+	return false
+}
+
+func (x *Flags) GetCf() bool {
+	if x != nil {
+		return x.Cf
+	}
+	return false
+}
+
+func (x *Flags) GetOf() bool {
+	if x != nil {
+		return x.Of
+	}
+	return false
+}
+
+func (x *Flags) GetIf() bool {
+	if x != nil {
+		return x.If
+	}
+	return false
+}
+
+func (x *Flags) GetUm() bool {
+	if x != nil {
+		return x.Um
+	}
+	return false
+}
+
+// Состояние процессора и программы
+type State struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Status         string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`    // running, halted, error, ...
+	Running        bool                   `protobuf:"varint,2,opt,name=running,proto3" json:"running,omitempty"` // Программа выполняется в фоне
+	Ip             uint32                 `protobuf:"varint,3,opt,name=ip,proto3" json:"ip,omitempty"`
+	Sp             uint32                 `protobuf:"varint,4,opt,name=sp,proto3" json:"sp,omitempty"`
+	Registers      []int32                `protobuf:"varint,5,rep,packed,name=registers,proto3" json:"registers,omitempty"`                                  // a1, a2
+	FloatRegisters []float32              `protobuf:"fixed32,6,rep,packed,name=float_registers,json=floatRegisters,proto3" json:"float_registers,omitempty"` // f0-f3
+	Flags          *Flags                 `protobuf:"bytes,7,opt,name=flags,proto3" json:"flags,omitempty"`
+	Next           string                 `protobuf:"bytes,8,opt,name=next,proto3" json:"next,omitempty"` // Команда по адресу IP
+	CallDepth      int32                  `protobuf:"varint,9,opt,name=call_depth,json=callDepth,proto3" json:"call_depth,omitempty"`
+	Instructions   int64                  `protobuf:"varint,10,opt,name=instructions,proto3" json:"instructions,omitempty"`
+	ExitCode       int32                  `protobuf:"varint,11,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	Error          string                 `protobuf:"bytes,12,opt,name=error,proto3" json:"error,omitempty"`
+	Output         string                 `protobuf:"bytes,13,opt,name=output,proto3" json:"output,omitempty"` // Вывод программы с момента загрузки или сброса
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *State) Reset() {
+	*x = State{}
+	mi := &file_vmpb_vm_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *State) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*State) ProtoMessage() {}
+
+func (x *State) ProtoReflect() protoreflect.Message {
+	mi := &file_vmpb_vm_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use State.ProtoReflect.Descriptor instead.
+func (*State) Descriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *State) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *State) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *State) GetIp() uint32 {
+	if x != nil {
+		return x.Ip
+	}
+	return 0
+}
+
+func (x *State) GetSp() uint32 {
+	if x != nil {
+		return x.Sp
+	}
+	return 0
+}
+
+func (x *State) GetRegisters() []int32 {
+	if x != nil {
+		return x.Registers
+	}
+	return nil
+}
+
+func (x *State) GetFloatRegisters() []float32 {
+	if x != nil {
+		return x.FloatRegisters
+	}
+	return nil
+}
+
+func (x *State) GetFlags() *Flags {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+func (x *State) GetNext() string {
+	if x != nil {
+		return x.Next
+	}
+	return ""
+}
+
+func (x *State) GetCallDepth() int32 {
+	if x != nil {
+		return x.CallDepth
+	}
+	return 0
+}
+
+func (x *State) GetInstructions() int64 {
+	if x != nil {
+		return x.Instructions
+	}
+	return 0
+}
+
+func (x *State) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *State) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *State) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+type ReadMemoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Space         MemorySpace            `protobuf:"varint,1,opt,name=space,proto3,enum=vm.v1.MemorySpace" json:"space,omitempty"`
+	Address       uint32                 `protobuf:"varint,2,opt,name=address,proto3" json:"address,omitempty"`
+	Count         uint32                 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"` // Число слов; 0 — одно
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadMemoryRequest) Reset() {
+	*x = ReadMemoryRequest{}
+	mi := &file_vmpb_vm_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadMemoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadMemoryRequest) ProtoMessage() {}
+
+func (x *ReadMemoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vmpb_vm_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadMemoryRequest.ProtoReflect.Descriptor instead.
+func (*ReadMemoryRequest) Descriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ReadMemoryRequest) GetSpace() MemorySpace {
+	if x != nil {
+		return x.Space
+	}
+	return MemorySpace_MEMORY_SPACE_DATA
+}
+
+func (x *ReadMemoryRequest) GetAddress() uint32 {
+	if x != nil {
+		return x.Address
+	}
+	return 0
+}
+
+func (x *ReadMemoryRequest) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type Word struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       uint32                 `protobuf:"varint,1,opt,name=address,proto3" json:"address,omitempty"`
+	Tag           WordTag                `protobuf:"varint,2,opt,name=tag,proto3,enum=vm.v1.WordTag" json:"tag,omitempty"`
+	Int           int32                  `protobuf:"varint,3,opt,name=int,proto3" json:"int,omitempty"`
+	Float         float32                `protobuf:"fixed32,4,opt,name=float,proto3" json:"float,omitempty"`
+	Text          string                 `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"` // Дизассемблированное слово
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Word) Reset() {
+	*x = Word{}
+	mi := &file_vmpb_vm_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Word) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Word) ProtoMessage() {}
+
+func (x *Word) ProtoReflect() protoreflect.Message {
+	mi := &file_vmpb_vm_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Word.ProtoReflect.Descriptor instead.
+func (*Word) Descriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Word) GetAddress() uint32 {
+	if x != nil {
+		return x.Address
+	}
+	return 0
+}
+
+func (x *Word) GetTag() WordTag {
+	if x != nil {
+		return x.Tag
+	}
+	return WordTag_WORD_TAG_INT
+}
+
+func (x *Word) GetInt() int32 {
+	if x != nil {
+		return x.Int
+	}
+	return 0
+}
+
+func (x *Word) GetFloat() float32 {
+	if x != nil {
+		return x.Float
+	}
+	return 0
+}
+
+func (x *Word) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type ReadMemoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Words         []*Word                `protobuf:"bytes,1,rep,name=words,proto3" json:"words,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadMemoryResponse) Reset() {
+	*x = ReadMemoryResponse{}
+	mi := &file_vmpb_vm_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadMemoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadMemoryResponse) ProtoMessage() {}
+
+func (x *ReadMemoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vmpb_vm_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadMemoryResponse.ProtoReflect.Descriptor instead.
+func (*ReadMemoryResponse) Descriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ReadMemoryResponse) GetWords() []*Word {
+	if x != nil {
+		return x.Words
+	}
+	return nil
+}
+
+// Записываемое значение: целое или вещественное
+type Value struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Value:
+	//
+	//	*Value_Int
+	//	*Value_Float
+	Value         isValue_Value `protobuf_oneof:"value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Value) Reset() {
+	*x = Value{}
+	mi := &file_vmpb_vm_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Value) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Value) ProtoMessage() {}
+
+func (x *Value) ProtoReflect() protoreflect.Message {
+	mi := &file_vmpb_vm_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Value.ProtoReflect.Descriptor instead.
+func (*Value) Descriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Value) GetValue() isValue_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *Value) GetInt() int32 {
+	if x != nil {
+		if x, ok := x.Value.(*Value_Int); ok {
+			return x.Int
+		}
+	}
+	return 0
+}
+
+func (x *Value) GetFloat() float32 {
+	if x != nil {
+		if x, ok := x.Value.(*Value_Float); ok {
+			return x.Float
+		}
+	}
+	return 0
+}
+
+type isValue_Value interface {
+	isValue_Value()
+}
+
+type Value_Int struct {
+	Int int32 `protobuf:"varint,1,opt,name=int,proto3,oneof"`
+}
+
+type Value_Float struct {
+	Float float32 `protobuf:"fixed32,2,opt,name=float,proto3,oneof"`
+}
+
+func (*Value_Int) isValue_Value() {}
+
+func (*Value_Float) isValue_Value() {}
+
+type WriteMemoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Space         MemorySpace            `protobuf:"varint,1,opt,name=space,proto3,enum=vm.v1.MemorySpace" json:"space,omitempty"`
+	Address       uint32                 `protobuf:"varint,2,opt,name=address,proto3" json:"address,omitempty"`
+	Values        []*Value               `protobuf:"bytes,3,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WriteMemoryRequest) Reset() {
+	*x = WriteMemoryRequest{}
+	mi := &file_vmpb_vm_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WriteMemoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WriteMemoryRequest) ProtoMessage() {}
+
+func (x *WriteMemoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vmpb_vm_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WriteMemoryRequest.ProtoReflect.Descriptor instead.
+func (*WriteMemoryRequest) Descriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WriteMemoryRequest) GetSpace() MemorySpace {
+	if x != nil {
+		return x.Space
+	}
+	return MemorySpace_MEMORY_SPACE_DATA
+}
+
+func (x *WriteMemoryRequest) GetAddress() uint32 {
+	if x != nil {
+		return x.Address
+	}
+	return 0
+}
+
+func (x *WriteMemoryRequest) GetValues() []*Value {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type WriteMemoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Written       uint32                 `protobuf:"varint,1,opt,name=written,proto3" json:"written,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WriteMemoryResponse) Reset() {
+	*x = WriteMemoryResponse{}
+	mi := &file_vmpb_vm_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WriteMemoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WriteMemoryResponse) ProtoMessage() {}
+
+func (x *WriteMemoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vmpb_vm_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WriteMemoryResponse.ProtoReflect.Descriptor instead.
+func (*WriteMemoryResponse) Descriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WriteMemoryResponse) GetWritten() uint32 {
+	if x != nil {
+		return x.Written
+	}
+	return 0
+}
+
+type EventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Buffer        uint32                 `protobuf:"varint,1,opt,name=buffer,proto3" json:"buffer,omitempty"` // Очередь событий клиента; 0 — по умолчанию
+	Mode          Backpressure           `protobuf:"varint,2,opt,name=mode,proto3,enum=vm.v1.Backpressure" json:"mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventsRequest) Reset() {
+	*x = EventsRequest{}
+	mi := &file_vmpb_vm_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventsRequest) ProtoMessage() {}
+
+func (x *EventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vmpb_vm_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventsRequest.ProtoReflect.Descriptor instead.
+func (*EventsRequest) Descriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *EventsRequest) GetBuffer() uint32 {
+	if x != nil {
+		return x.Buffer
+	}
+	return 0
+}
+
+func (x *EventsRequest) GetMode() Backpressure {
+	if x != nil {
+		return x.Mode
+	}
+	return Backpressure_BACKPRESSURE_BLOCK
+}
+
+// Изменение регистра, SP или флага (0 или 1)
+type RegisterChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Old           float64                `protobuf:"fixed64,2,opt,name=old,proto3" json:"old,omitempty"`
+	New           float64                `protobuf:"fixed64,3,opt,name=new,proto3" json:"new,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterChange) Reset() {
+	*x = RegisterChange{}
+	mi := &file_vmpb_vm_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterChange) ProtoMessage() {}
+
+func (x *RegisterChange) ProtoReflect() protoreflect.Message {
+	mi := &file_vmpb_vm_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterChange.ProtoReflect.Descriptor instead.
+func (*RegisterChange) Descriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RegisterChange) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RegisterChange) GetOld() float64 {
+	if x != nil {
+		return x.Old
+	}
+	return 0
+}
+
+func (x *RegisterChange) GetNew() float64 {
+	if x != nil {
+		return x.New
+	}
+	return 0
+}
+
+// Выполненная команда
+type StepEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            uint32                 `protobuf:"varint,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Opcode        string                 `protobuf:"bytes,2,opt,name=opcode,proto3" json:"opcode,omitempty"`
+	Text          string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	Changes       []*RegisterChange      `protobuf:"bytes,4,rep,name=changes,proto3" json:"changes,omitempty"`
+	Output        string                 `protobuf:"bytes,5,opt,name=output,proto3" json:"output,omitempty"` // Вывод команды
+	Error         string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StepEvent) Reset() {
+	*x = StepEvent{}
+	mi := &file_vmpb_vm_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StepEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StepEvent) ProtoMessage() {}
+
+func (x *StepEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_vmpb_vm_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StepEvent.ProtoReflect.Descriptor instead.
+func (*StepEvent) Descriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *StepEvent) GetIp() uint32 {
+	if x != nil {
+		return x.Ip
+	}
+	return 0
+}
+
+func (x *StepEvent) GetOpcode() string {
+	if x != nil {
+		return x.Opcode
+	}
+	return ""
+}
+
+func (x *StepEvent) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *StepEvent) GetChanges() []*RegisterChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *StepEvent) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *StepEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// События, пропущенные из-за заполненной очереди клиента
+type DroppedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DroppedEvent) Reset() {
+	*x = DroppedEvent{}
+	mi := &file_vmpb_vm_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DroppedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DroppedEvent) ProtoMessage() {}
+
+func (x *DroppedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_vmpb_vm_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DroppedEvent.ProtoReflect.Descriptor instead.
+func (*DroppedEvent) Descriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DroppedEvent) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type Event struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*Event_Step
+	//	*Event_Dropped
+	Event         isEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_vmpb_vm_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_vmpb_vm_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_vmpb_vm_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Event) GetEvent() isEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *Event) GetStep() *StepEvent {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Step); ok {
+			return x.Step
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetDropped() *DroppedEvent {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Dropped); ok {
+			return x.Dropped
+		}
+	}
+	return nil
+}
+
+type isEvent_Event interface {
+	isEvent_Event()
+}
+
+type Event_Step struct {
+	Step *StepEvent `protobuf:"bytes,1,opt,name=step,proto3,oneof"`
+}
+
+type Event_Dropped struct {
+	Dropped *DroppedEvent `protobuf:"bytes,2,opt,name=dropped,proto3,oneof"`
+}
+
+func (*Event_Step) isEvent_Event() {}
+
+func (*Event_Dropped) isEvent_Event() {}
+
+var File_vmpb_vm_proto protoreflect.FileDescriptor
+
+const file_vmpb_vm_proto_rawDesc = "" +
+	"\n" +
+	"\rvmpb/vm.proto\x12\x05vm.v1\"p\n" +
+	"\x12LoadProgramRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12,\n" +
+	"\x06format\x18\x02 \x01(\x0e2\x14.vm.v1.ProgramFormatR\x06format\x12\x14\n" +
+	"\x05input\x18\x03 \x01(\tR\x05input\"\f\n" +
+	"\n" +
+	"RunRequest\"\x0e\n" +
+	"\fPauseRequest\"#\n" +
+	"\vStepRequest\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\rR\x05count\"\x0e\n" +
+	"\fResetRequest\"\x11\n" +
+	"\x0fGetStateRequest\"g\n" +
+	"\x05Flags\x12\x0e\n" +
+	"\x02zf\x18\x01 \x01(\bR\x02zf\x12\x0e\n" +
+	"\x02sf\x18\x02 \x01(\bR\x02sf\x12\x0e\n" +
+	"\x02cf\x18\x03 \x01(\bR\x02cf\x12\x0e\n" +
+	"\x02of\x18\x04 \x01(\bR\x02of\x12\x0e\n" +
+	"\x02if\x18\x05 \x01(\bR\x02if\x12\x0e\n" +
+	"\x02um\x18\x06 \x01(\bR\x02um\"\xe6\x02\n" +
+	"\x05State\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
+	"\arunning\x18\x02 \x01(\bR\arunning\x12\x0e\n" +
+	"\x02ip\x18\x03 \x01(\rR\x02ip\x12\x0e\n" +
+	"\x02sp\x18\x04 \x01(\rR\x02sp\x12\x1c\n" +
+	"\tregisters\x18\x05 \x03(\x05R\tregisters\x12'\n" +
+	"\x0ffloat_registers\x18\x06 \x03(\x02R\x0efloatRegisters\x12\"\n" +
+	"\x05flags\x18\a \x01(\v2\f.vm.v1.FlagsR\x05flags\x12\x12\n" +
+	"\x04next\x18\b \x01(\tR\x04next\x12\x1d\n" +
+	"\n" +
+	"call_depth\x18\t \x01(\x05R\tcallDepth\x12\"\n" +
+	"\finstructions\x18\n" +
+	" \x01(\x03R\finstructions\x12\x1b\n" +
+	"\texit_code\x18\v \x01(\x05R\bexitCode\x12\x14\n" +
+	"\x05error\x18\f \x01(\tR\x05error\x12\x16\n" +
+	"\x06output\x18\r \x01(\tR\x06output\"m\n" +
+	"\x11ReadMemoryRequest\x12(\n" +
+	"\x05space\x18\x01 \x01(\x0e2\x12.vm.v1.MemorySpaceR\x05space\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\rR\aaddress\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\rR\x05count\"~\n" +
+	"\x04Word\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\rR\aaddress\x12 \n" +
+	"\x03tag\x18\x02 \x01(\x0e2\x0e.vm.v1.WordTagR\x03tag\x12\x10\n" +
+	"\x03int\x18\x03 \x01(\x05R\x03int\x12\x14\n" +
+	"\x05float\x18\x04 \x01(\x02R\x05float\x12\x12\n" +
+	"\x04text\x18\x05 \x01(\tR\x04text\"7\n" +
+	"\x12ReadMemoryResponse\x12!\n" +
+	"\x05words\x18\x01 \x03(\v2\v.vm.v1.WordR\x05words\"<\n" +
+	"\x05Value\x12\x12\n" +
+	"\x03int\x18\x01 \x01(\x05H\x00R\x03int\x12\x16\n" +
+	"\x05float\x18\x02 \x01(\x02H\x00R\x05floatB\a\n" +
+	"\x05value\"~\n" +
+	"\x12WriteMemoryRequest\x12(\n" +
+	"\x05space\x18\x01 \x01(\x0e2\x12.vm.v1.MemorySpaceR\x05space\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\rR\aaddress\x12$\n" +
+	"\x06values\x18\x03 \x03(\v2\f.vm.v1.ValueR\x06values\"/\n" +
+	"\x13WriteMemoryResponse\x12\x18\n" +
+	"\awritten\x18\x01 \x01(\rR\awritten\"P\n" +
+	"\rEventsRequest\x12\x16\n" +
+	"\x06buffer\x18\x01 \x01(\rR\x06buffer\x12'\n" +
+	"\x04mode\x18\x02 \x01(\x0e2\x13.vm.v1.BackpressureR\x04mode\"H\n" +
+	"\x0eRegisterChange\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03old\x18\x02 \x01(\x01R\x03old\x12\x10\n" +
+	"\x03new\x18\x03 \x01(\x01R\x03new\"\xa6\x01\n" +
+	"\tStepEvent\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\rR\x02ip\x12\x16\n" +
+	"\x06opcode\x18\x02 \x01(\tR\x06opcode\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\x12/\n" +
+	"\achanges\x18\x04 \x03(\v2\x15.vm.v1.RegisterChangeR\achanges\x12\x16\n" +
+	"\x06output\x18\x05 \x01(\tR\x06output\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\"$\n" +
+	"\fDroppedEvent\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\"i\n" +
+	"\x05Event\x12&\n" +
+	"\x04step\x18\x01 \x01(\v2\x10.vm.v1.StepEventH\x00R\x04step\x12/\n" +
+	"\adropped\x18\x02 \x01(\v2\x13.vm.v1.DroppedEventH\x00R\adroppedB\a\n" +
+	"\x05event*B\n" +
+	"\rProgramFormat\x12\x16\n" +
+	"\x12PROGRAM_FORMAT_ASM\x10\x00\x12\x19\n" +
+	"\x15PROGRAM_FORMAT_LOADER\x10\x01*;\n" +
+	"\vMemorySpace\x12\x15\n" +
+	"\x11MEMORY_SPACE_DATA\x10\x00\x12\x15\n" +
+	"\x11MEMORY_SPACE_CODE\x10\x01*E\n" +
+	"\aWordTag\x12\x10\n" +
+	"\fWORD_TAG_INT\x10\x00\x12\x12\n" +
+	"\x0eWORD_TAG_FLOAT\x10\x01\x12\x14\n" +
+	"\x10WORD_TAG_COMMAND\x10\x02*=\n" +
+	"\fBackpressure\x12\x16\n" +
+	"\x12BACKPRESSURE_BLOCK\x10\x00\x12\x15\n" +
+	"\x11BACKPRESSURE_DROP\x10\x012\xdd\x03\n" +
+	"\x0eVirtualMachine\x126\n" +
+	"\vLoadProgram\x12\x19.vm.v1.LoadProgramRequest\x1a\f.vm.v1.State\x12&\n" +
+	"\x03Run\x12\x11.vm.v1.RunRequest\x1a\f.vm.v1.State\x12*\n" +
+	"\x05Pause\x12\x13.vm.v1.PauseRequest\x1a\f.vm.v1.State\x12(\n" +
+	"\x04Step\x12\x12.vm.v1.StepRequest\x1a\f.vm.v1.State\x12*\n" +
+	"\x05Reset\x12\x13.vm.v1.ResetRequest\x1a\f.vm.v1.State\x120\n" +
+	"\bGetState\x12\x16.vm.v1.GetStateRequest\x1a\f.vm.v1.State\x12A\n" +
+	"\n" +
+	"ReadMemory\x12\x18.vm.v1.ReadMemoryRequest\x1a\x19.vm.v1.ReadMemoryResponse\x12D\n" +
+	"\vWriteMemory\x12\x19.vm.v1.WriteMemoryRequest\x1a\x1a.vm.v1.WriteMemoryResponse\x12.\n" +
+	"\x06Events\x12\x14.vm.v1.EventsRequest\x1a\f.vm.v1.Event0\x01B\tZ\avm/vmpbb\x06proto3"
+
+var (
+	file_vmpb_vm_proto_rawDescOnce sync.Once
+	file_vmpb_vm_proto_rawDescData []byte
+)
+
+func file_vmpb_vm_proto_rawDescGZIP() []byte {
+	file_vmpb_vm_proto_rawDescOnce.Do(func() {
+		file_vmpb_vm_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_vmpb_vm_proto_rawDesc), len(file_vmpb_vm_proto_rawDesc)))
+	})
+	return file_vmpb_vm_proto_rawDescData
+}
+
+var file_vmpb_vm_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_vmpb_vm_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_vmpb_vm_proto_goTypes = []any{
+	(ProgramFormat)(0),          // 0: vm.v1.ProgramFormat
+	(MemorySpace)(0),            // 1: vm.v1.MemorySpace
+	(WordTag)(0),                // 2: vm.v1.WordTag
+	(Backpressure)(0),           // 3: vm.v1.Backpressure
+	(*LoadProgramRequest)(nil),  // 4: vm.v1.LoadProgramRequest
+	(*RunRequest)(nil),          // 5: vm.v1.RunRequest
+	(*PauseRequest)(nil),        // 6: vm.v1.PauseRequest
+	(*StepRequest)(nil),         // 7: vm.v1.StepRequest
+	(*ResetRequest)(nil),        // 8: vm.v1.ResetRequest
+	(*GetStateRequest)(nil),     // 9: vm.v1.GetStateRequest
+	(*Flags)(nil),               // 10: vm.v1.Flags
+	(*State)(nil),               // 11: vm.v1.State
+	(*ReadMemoryRequest)(nil),   // 12: vm.v1.ReadMemoryRequest
+	(*Word)(nil),                // 13: vm.v1.Word
+	(*ReadMemoryResponse)(nil),  // 14: vm.v1.ReadMemoryResponse
+	(*Value)(nil),               // 15: vm.v1.Value
+	(*WriteMemoryRequest)(nil),  // 16: vm.v1.WriteMemoryRequest
+	(*WriteMemoryResponse)(nil), // 17: vm.v1.WriteMemoryResponse
+	(*EventsRequest)(nil),       // 18: vm.v1.EventsRequest
+	(*RegisterChange)(nil),      // 19: vm.v1.RegisterChange
+	(*StepEvent)(nil),           // 20: vm.v1.StepEvent
+	(*DroppedEvent)(nil),        // 21: vm.v1.DroppedEvent
+	(*Event)(nil),               // 22: vm.v1.Event
+}
+var file_vmpb_vm_proto_depIdxs = []int32{
+	0,  // 0: vm.v1.LoadProgramRequest.format:type_name -> vm.v1.ProgramFormat
+	10, // 1: vm.v1.State.flags:type_name -> vm.v1.Flags
+	1,  // 2: vm.v1.ReadMemoryRequest.space:type_name -> vm.v1.MemorySpace
+	2,  // 3: vm.v1.Word.tag:type_name -> vm.v1.WordTag
+	13, // 4: vm.v1.ReadMemoryResponse.words:type_name -> vm.v1.Word
+	1,  // 5: vm.v1.WriteMemoryRequest.space:type_name -> vm.v1.MemorySpace
+	15, // 6: vm.v1.WriteMemoryRequest.values:type_name -> vm.v1.Value
+	3,  // 7: vm.v1.EventsRequest.mode:type_name -> vm.v1.Backpressure
+	19, // 8: vm.v1.StepEvent.changes:type_name -> vm.v1.RegisterChange
+	20, // 9: vm.v1.Event.step:type_name -> vm.v1.StepEvent
+	21, // 10: vm.v1.Event.dropped:type_name -> vm.v1.DroppedEvent
+	4,  // 11: vm.v1.VirtualMachine.LoadProgram:input_type -> vm.v1.LoadProgramRequest
+	5,  // 12: vm.v1.VirtualMachine.Run:input_type -> vm.v1.RunRequest
+	6,  // 13: vm.v1.VirtualMachine.Pause:input_type -> vm.v1.PauseRequest
+	7,  // 14: vm.v1.VirtualMachine.Step:input_type -> vm.v1.StepRequest
+	8,  // 15: vm.v1.VirtualMachine.Reset:input_type -> vm.v1.ResetRequest
+	9,  // 16: vm.v1.VirtualMachine.GetState:input_type -> vm.v1.GetStateRequest
+	12, // 17: vm.v1.VirtualMachine.ReadMemory:input_type -> vm.v1.ReadMemoryRequest
+	16, // 18: vm.v1.VirtualMachine.WriteMemory:input_type -> vm.v1.WriteMemoryRequest
+	18, // 19: vm.v1.VirtualMachine.Events:input_type -> vm.v1.EventsRequest
+	11, // 20: vm.v1.VirtualMachine.LoadProgram:output_type -> vm.v1.State
+	11, // 21: vm.v1.VirtualMachine.Run:output_type -> vm.v1.State
+	11, // 22: vm.v1.VirtualMachine.Pause:output_type -> vm.v1.State
+	11, // 23: vm.v1.VirtualMachine.Step:output_type -> vm.v1.State
+	11, // 24: vm.v1.VirtualMachine.Reset:output_type -> vm.v1.State
+	11, // 25: vm.v1.VirtualMachine.GetState:output_type -> vm.v1.State
+	14, // 26: vm.v1.VirtualMachine.ReadMemory:output_type -> vm.v1.ReadMemoryResponse
+	17, // 27: vm.v1.VirtualMachine.WriteMemory:output_type -> vm.v1.WriteMemoryResponse
+	22, // 28: vm.v1.VirtualMachine.Events:output_type -> vm.v1.Event
+	20, // [20:29] is the sub-list for method output_type
+	11, // [11:20] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_vmpb_vm_proto_init() }
+func file_vmpb_vm_proto_init() {
+	if File_vmpb_vm_proto != nil {
+		return
+	}
+	file_vmpb_vm_proto_msgTypes[11].OneofWrappers = []any{
+		(*Value_Int)(nil),
+		(*Value_Float)(nil),
+	}
+	file_vmpb_vm_proto_msgTypes[18].OneofWrappers = []any{
+		(*Event_Step)(nil),
+		(*Event_Dropped)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_vmpb_vm_proto_rawDesc), len(file_vmpb_vm_proto_rawDesc)),
+			NumEnums:      4,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vmpb_vm_proto_goTypes,
+		DependencyIndexes: file_vmpb_vm_proto_depIdxs,
+		EnumInfos:         file_vmpb_vm_proto_enumTypes,
+		MessageInfos:      file_vmpb_vm_proto_msgTypes,
+	}.Build()
+	File_vmpb_vm_proto = out.File
+	file_vmpb_vm_proto_goTypes = nil
+	file_vmpb_vm_proto_depIdxs = nil
+}
@@ -0,0 +1,453 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: vmpb/vm.proto
+
+package vmpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	VirtualMachine_LoadProgram_FullMethodName = "/vm.v1.VirtualMachine/LoadProgram"
+	VirtualMachine_Run_FullMethodName         = "/vm.v1.VirtualMachine/Run"
+	VirtualMachine_Pause_FullMethodName       = "/vm.v1.VirtualMachine/Pause"
+	VirtualMachine_Step_FullMethodName        = "/vm.v1.VirtualMachine/Step"
+	VirtualMachine_Reset_FullMethodName       = "/vm.v1.VirtualMachine/Reset"
+	VirtualMachine_GetState_FullMethodName    = "/vm.v1.VirtualMachine/GetState"
+	VirtualMachine_ReadMemory_FullMethodName  = "/vm.v1.VirtualMachine/ReadMemory"
+	VirtualMachine_WriteMemory_FullMethodName = "/vm.v1.VirtualMachine/WriteMemory"
+	VirtualMachine_Events_FullMethodName      = "/vm.v1.VirtualMachine/Events"
+)
+
+// VirtualMachineClient is the client API for VirtualMachine service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// VirtualMachine управляет одним процессором: загрузка программы, выполнение,
+// чтение состояния и памяти, поток событий о выполненных командах
+type VirtualMachineClient interface {
+	// LoadProgram загружает программу в новый процессор, останавливая текущую
+	LoadProgram(ctx context.Context, in *LoadProgramRequest, opts ...grpc.CallOption) (*State, error)
+	// Run запускает программу в фоне до STOP, ошибки или Pause
+	Run(ctx context.Context, in *RunRequest, opts ...grpc.CallOption) (*State, error)
+	// Pause останавливает фоновое выполнение перед очередной командой
+	Pause(ctx context.Context, in *PauseRequest, opts ...grpc.CallOption) (*State, error)
+	// Step выполняет команды остановленной программы
+	Step(ctx context.Context, in *StepRequest, opts ...grpc.CallOption) (*State, error)
+	// Reset перезапускает программу с точки входа
+	Reset(ctx context.Context, in *ResetRequest, opts ...grpc.CallOption) (*State, error)
+	// GetState возвращает регистры, флаги и состояние программы
+	GetState(ctx context.Context, in *GetStateRequest, opts ...grpc.CallOption) (*State, error)
+	// ReadMemory читает последовательные слова памяти
+	ReadMemory(ctx context.Context, in *ReadMemoryRequest, opts ...grpc.CallOption) (*ReadMemoryResponse, error)
+	// WriteMemory записывает значения в последовательные слова памяти
+	WriteMemory(ctx context.Context, in *WriteMemoryRequest, opts ...grpc.CallOption) (*WriteMemoryResponse, error)
+	// Events передает событие о каждой выполненной команде
+	Events(ctx context.Context, in *EventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+}
+
+type virtualMachineClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVirtualMachineClient(cc grpc.ClientConnInterface) VirtualMachineClient {
+	return &virtualMachineClient{cc}
+}
+
+func (c *virtualMachineClient) LoadProgram(ctx context.Context, in *LoadProgramRequest, opts ...grpc.CallOption) (*State, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(State)
+	err := c.cc.Invoke(ctx, VirtualMachine_LoadProgram_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *virtualMachineClient) Run(ctx context.Context, in *RunRequest, opts ...grpc.CallOption) (*State, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(State)
+	err := c.cc.Invoke(ctx, VirtualMachine_Run_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *virtualMachineClient) Pause(ctx context.Context, in *PauseRequest, opts ...grpc.CallOption) (*State, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(State)
+	err := c.cc.Invoke(ctx, VirtualMachine_Pause_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *virtualMachineClient) Step(ctx context.Context, in *StepRequest, opts ...grpc.CallOption) (*State, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(State)
+	err := c.cc.Invoke(ctx, VirtualMachine_Step_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *virtualMachineClient) Reset(ctx context.Context, in *ResetRequest, opts ...grpc.CallOption) (*State, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(State)
+	err := c.cc.Invoke(ctx, VirtualMachine_Reset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *virtualMachineClient) GetState(ctx context.Context, in *GetStateRequest, opts ...grpc.CallOption) (*State, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(State)
+	err := c.cc.Invoke(ctx, VirtualMachine_GetState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *virtualMachineClient) ReadMemory(ctx context.Context, in *ReadMemoryRequest, opts ...grpc.CallOption) (*ReadMemoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReadMemoryResponse)
+	err := c.cc.Invoke(ctx, VirtualMachine_ReadMemory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *virtualMachineClient) WriteMemory(ctx context.Context, in *WriteMemoryRequest, opts ...grpc.CallOption) (*WriteMemoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WriteMemoryResponse)
+	err := c.cc.Invoke(ctx, VirtualMachine_WriteMemory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *virtualMachineClient) Events(ctx context.Context, in *EventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &VirtualMachine_ServiceDesc.Streams[0], VirtualMachine_Events_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[EventsRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VirtualMachine_EventsClient = grpc.ServerStreamingClient[Event]
+
+// VirtualMachineServer is the server API for VirtualMachine service.
+// All implementations must embed UnimplementedVirtualMachineServer
+// for forward compatibility.
+//
+// VirtualMachine управляет одним процессором: загрузка программы, выполнение,
+// чтение состояния и памяти, поток событий о выполненных командах
+type VirtualMachineServer interface {
+	// LoadProgram загружает программу в новый процессор, останавливая текущую
+	LoadProgram(context.Context, *LoadProgramRequest) (*State, error)
+	// Run запускает программу в фоне до STOP, ошибки или Pause
+	Run(context.Context, *RunRequest) (*State, error)
+	// Pause останавливает фоновое выполнение перед очередной командой
+	Pause(context.Context, *PauseRequest) (*State, error)
+	// Step выполняет команды остановленной программы
+	Step(context.Context, *StepRequest) (*State, error)
+	// Reset перезапускает программу с точки входа
+	Reset(context.Context, *ResetRequest) (*State, error)
+	// GetState возвращает регистры, флаги и состояние программы
+	GetState(context.Context, *GetStateRequest) (*State, error)
+	// ReadMemory читает последовательные слова памяти
+	ReadMemory(context.Context, *ReadMemoryRequest) (*ReadMemoryResponse, error)
+	// WriteMemory записывает значения в последовательные слова памяти
+	WriteMemory(context.Context, *WriteMemoryRequest) (*WriteMemoryResponse, error)
+	// Events передает событие о каждой выполненной команде
+	Events(*EventsRequest, grpc.ServerStreamingServer[Event]) error
+	mustEmbedUnimplementedVirtualMachineServer()
+}
+
+// UnimplementedVirtualMachineServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedVirtualMachineServer struct{}
+
+func (UnimplementedVirtualMachineServer) LoadProgram(context.Context, *LoadProgramRequest) (*State, error) {
+	return nil, status.Error(codes.Unimplemented, "method LoadProgram not implemented")
+}
+func (UnimplementedVirtualMachineServer) Run(context.Context, *RunRequest) (*State, error) {
+	return nil, status.Error(codes.Unimplemented, "method Run not implemented")
+}
+func (UnimplementedVirtualMachineServer) Pause(context.Context, *PauseRequest) (*State, error) {
+	return nil, status.Error(codes.Unimplemented, "method Pause not implemented")
+}
+func (UnimplementedVirtualMachineServer) Step(context.Context, *StepRequest) (*State, error) {
+	return nil, status.Error(codes.Unimplemented, "method Step not implemented")
+}
+func (UnimplementedVirtualMachineServer) Reset(context.Context, *ResetRequest) (*State, error) {
+	return nil, status.Error(codes.Unimplemented, "method Reset not implemented")
+}
+func (UnimplementedVirtualMachineServer) GetState(context.Context, *GetStateRequest) (*State, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetState not implemented")
+}
+func (UnimplementedVirtualMachineServer) ReadMemory(context.Context, *ReadMemoryRequest) (*ReadMemoryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReadMemory not implemented")
+}
+func (UnimplementedVirtualMachineServer) WriteMemory(context.Context, *WriteMemoryRequest) (*WriteMemoryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method WriteMemory not implemented")
+}
+func (UnimplementedVirtualMachineServer) Events(*EventsRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Error(codes.Unimplemented, "method Events not implemented")
+}
+func (UnimplementedVirtualMachineServer) mustEmbedUnimplementedVirtualMachineServer() {}
+func (UnimplementedVirtualMachineServer) testEmbeddedByValue()                        {}
+
+// UnsafeVirtualMachineServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VirtualMachineServer will
+// result in compilation errors.
+type UnsafeVirtualMachineServer interface {
+	mustEmbedUnimplementedVirtualMachineServer()
+}
+
+func RegisterVirtualMachineServer(s grpc.ServiceRegistrar, srv VirtualMachineServer) {
+	// If the following call panics, it indicates UnimplementedVirtualMachineServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&VirtualMachine_ServiceDesc, srv)
+}
+
+func _VirtualMachine_LoadProgram_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadProgramRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualMachineServer).LoadProgram(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VirtualMachine_LoadProgram_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualMachineServer).LoadProgram(ctx, req.(*LoadProgramRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VirtualMachine_Run_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualMachineServer).Run(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VirtualMachine_Run_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualMachineServer).Run(ctx, req.(*RunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VirtualMachine_Pause_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualMachineServer).Pause(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VirtualMachine_Pause_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualMachineServer).Pause(ctx, req.(*PauseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VirtualMachine_Step_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StepRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualMachineServer).Step(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VirtualMachine_Step_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualMachineServer).Step(ctx, req.(*StepRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VirtualMachine_Reset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualMachineServer).Reset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VirtualMachine_Reset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualMachineServer).Reset(ctx, req.(*ResetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VirtualMachine_GetState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualMachineServer).GetState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VirtualMachine_GetState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualMachineServer).GetState(ctx, req.(*GetStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VirtualMachine_ReadMemory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadMemoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualMachineServer).ReadMemory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VirtualMachine_ReadMemory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualMachineServer).ReadMemory(ctx, req.(*ReadMemoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VirtualMachine_WriteMemory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WriteMemoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualMachineServer).WriteMemory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VirtualMachine_WriteMemory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualMachineServer).WriteMemory(ctx, req.(*WriteMemoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VirtualMachine_Events_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VirtualMachineServer).Events(m, &grpc.GenericServerStream[EventsRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VirtualMachine_EventsServer = grpc.ServerStreamingServer[Event]
+
+// VirtualMachine_ServiceDesc is the grpc.ServiceDesc for VirtualMachine service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VirtualMachine_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vm.v1.VirtualMachine",
+	HandlerType: (*VirtualMachineServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "LoadProgram",
+			Handler:    _VirtualMachine_LoadProgram_Handler,
+		},
+		{
+			MethodName: "Run",
+			Handler:    _VirtualMachine_Run_Handler,
+		},
+		{
+			MethodName: "Pause",
+			Handler:    _VirtualMachine_Pause_Handler,
+		},
+		{
+			MethodName: "Step",
+			Handler:    _VirtualMachine_Step_Handler,
+		},
+		{
+			MethodName: "Reset",
+			Handler:    _VirtualMachine_Reset_Handler,
+		},
+		{
+			MethodName: "GetState",
+			Handler:    _VirtualMachine_GetState_Handler,
+		},
+		{
+			MethodName: "ReadMemory",
+			Handler:    _VirtualMachine_ReadMemory_Handler,
+		},
+		{
+			MethodName: "WriteMemory",
+			Handler:    _VirtualMachine_WriteMemory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Events",
+			Handler:       _VirtualMachine_Events_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "vmpb/vm.proto",
+}
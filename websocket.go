@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GUID протокола WebSocket для ответа на рукопожатие (RFC 6455, раздел 1.3)
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Коды операций кадров WebSocket
+const (
+	wsText  = 0x1
+	wsClose = 0x8
+	wsPing  = 0x9
+	wsPong  = 0xA
+)
+
+// Наибольший принимаемый от клиента кадр: клиент присылает только управляющие кадры
+const wsMaxClientFrame = 4096
+
+// wsConn — серверная сторона соединения WebSocket. Сервер только отправляет
+// текстовые кадры; входящие кадры читаются, чтобы отвечать на ping и close.
+type wsConn struct {
+	conn    net.Conn
+	r       *bufio.Reader
+	timeout time.Duration // Срок записи кадра; клиент, не читающий поток, отключается
+	mu      sync.Mutex    // Сериализует запись кадров сообщений и ответов на ping
+}
+
+// upgradeWebSocket выполняет рукопожатие WebSocket и забирает соединение у сервера HTTP
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request, timeout time.Duration) (*wsConn, error) {
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		return nil, fmt.Errorf("websocket upgrade required")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+		return nil, fmt.Errorf("unsupported websocket handshake")
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, fmt.Errorf("connection does not support websocket")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n",
+		base64.StdEncoding.EncodeToString(sum[:]))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, r: rw.Reader, timeout: timeout}, nil
+}
+
+// headerContains сообщает, содержит ли заголовок name значение token (без учета регистра)
+func headerContains(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, item := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(item), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeFrame отправляет кадр с кодом операции op. Кадры сервера не маскируются.
+func (c *wsConn) writeFrame(op byte, payload []byte) error {
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | op // FIN: сообщение из одного кадра
+	switch n := len(payload); {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	if _, err := c.conn.Write(append(header, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// WriteText отправляет текстовое сообщение
+func (c *wsConn) WriteText(payload []byte) error {
+	return c.writeFrame(wsText, payload)
+}
+
+// readFrame читает кадр клиента и снимает с него маску
+func (c *wsConn) readFrame() (op byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	op = header[0] & 0x0F
+	n := uint64(header[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if n > wsMaxClientFrame {
+		return 0, nil, fmt.Errorf("websocket frame of %d bytes is too large", n)
+	}
+	var mask [4]byte
+	masked := header[1]&0x80 != 0
+	if masked {
+		if _, err := io.ReadFull(c.r, mask[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+	payload = make([]byte, n)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return op, payload, nil
+}
+
+// readLoop читает кадры клиента до кадра close или ошибки: отвечает на ping и
+// отбрасывает данные. Возвращается, когда клиент закрыл соединение.
+func (c *wsConn) readLoop() {
+	for {
+		op, payload, err := c.readFrame()
+		if err != nil {
+			return
+		}
+		switch op {
+		case wsClose:
+			return
+		case wsPing:
+			if c.writeFrame(wsPong, payload) != nil {
+				return
+			}
+		}
+	}
+}
+
+// Close отправляет кадр close, если это возможно, и закрывает соединение
+func (c *wsConn) Close() error {
+	c.writeFrame(wsClose, nil)
+	return c.conn.Close()
+}